@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/server"
+	"github.com/TykTechnologies/tyk-pump/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// listLengthGetter is implemented by the analytics stores able to report how many
+// records are waiting in a key.
+type listLengthGetter interface {
+	GetListLength(ctx context.Context, keyName string) (int64, error)
+}
+
+// pumpAdmin carries out the actions of the admin API on the running pumps.
+type pumpAdmin struct{}
+
+func (pumpAdmin) Pumps() []server.AdminPump {
+	adminPumps := make([]server.AdminPump, 0, len(Pumps))
+	for _, pmp := range Pumps {
+		state := getPumpState(pmp)
+		adminPump := server.AdminPump{
+			Name:   state.name,
+			Type:   pmp.GetName(),
+			Paused: state.isPaused(),
+		}
+
+		if conf, ok := SystemConfig.Pumps[state.name]; ok {
+			redacted, err := redactPumpConfig(state.name, conf)
+			if err != nil {
+				log.WithFields(logrus.Fields{
+					"prefix": mainPrefix,
+				}).Error("Couldn't redact the config of ", state.name, ": ", err)
+			} else {
+				adminPump.Config = redacted
+			}
+		}
+
+		adminPumps = append(adminPumps, adminPump)
+	}
+
+	sort.Slice(adminPumps, func(i, j int) bool {
+		return adminPumps[i].Name < adminPumps[j].Name
+	})
+
+	return adminPumps
+}
+
+// redactPumpConfig returns the redacted config of the pump name, with the meta it runs
+// with: the one of conf overridden by the env vars.
+func redactPumpConfig(name string, conf PumpConfig) (interface{}, error) {
+	redacted, err := redactConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, _, err := pumps.EffectiveMeta(pumpType(name), conf.Meta)
+	if err != nil || meta == nil {
+		return redacted, err
+	}
+	redactedMeta, err := redactConfig(meta)
+	if err != nil {
+		return nil, err
+	}
+	redacted.(map[string]interface{})["meta"] = redactedMeta
+
+	return redacted, nil
+}
+
+func (pumpAdmin) PausePump(name string) error {
+	_, state, err := findPump(name)
+	if err != nil {
+		return err
+	}
+	state.paused.Store(true)
+
+	return nil
+}
+
+func (pumpAdmin) ResumePump(name string) error {
+	_, state, err := findPump(name)
+	if err != nil {
+		return err
+	}
+	state.paused.Store(false)
+
+	return nil
+}
+
+func (pumpAdmin) SetPumpLogLevel(name, level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	pmp, _, err := findPump(name)
+	if err != nil {
+		return err
+	}
+	pmp.SetLogLevel(lvl)
+
+	return nil
+}
+
+func (pumpAdmin) Purge() error {
+	select {
+	case purgeRequests <- struct{}{}:
+	default:
+		// A purge is already pending.
+	}
+
+	return nil
+}
+
+func (pumpAdmin) QueueDepths(ctx context.Context) (map[string]int64, error) {
+	return queueDepths(ctx)
+}
+
+// queueDepths returns the number of records waiting in each analytics key, and in the
+// uptime key.
+func queueDepths(ctx context.Context) (map[string]int64, error) {
+	lengthGetter, ok := AnalyticsStore.(listLengthGetter)
+	if !ok {
+		return nil, fmt.Errorf("the temporal storage can't report its queue depths")
+	}
+
+	depths := map[string]int64{}
+	for _, analyticsKeyName := range analyticsKeyNames() {
+		for _, serializerMethod := range AnalyticsSerializers {
+			keyName := analyticsKeyName + serializerMethod.GetSuffix()
+			depth, err := lengthGetter.GetListLength(ctx, keyName)
+			if err != nil {
+				return nil, err
+			}
+			depths[keyName] = depth
+		}
+	}
+
+	if uptimeLengthGetter, ok := UptimeStorage.(listLengthGetter); ok && !SystemConfig.DontPurgeUptimeData {
+		depth, err := uptimeLengthGetter.GetListLength(ctx, storage.UptimeAnalytics_KEYNAME)
+		if err != nil {
+			return nil, err
+		}
+		depths[storage.UptimeAnalytics_KEYNAME] = depth
+	}
+
+	return depths, nil
+}
+
+// findPump returns the running pump configured under name.
+func findPump(name string) (pumps.Pump, *pumpState, error) {
+	for _, pmp := range Pumps {
+		if state := getPumpState(pmp); state.name == name {
+			return pmp, state, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s", server.ErrPumpNotFound, name)
+}
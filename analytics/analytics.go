@@ -81,6 +81,10 @@ type AnalyticsRecord struct {
 	GraphQLStats   GraphQLStats `json:"graphql_stats" bson:"-" gorm:"-:all"`
 	MCPStats       MCPStats     `json:"mcp_stats" bson:"-" gorm:"-:all"`
 	CollectionName string       `json:"-" bson:"-" gorm:"-:all"`
+	// SchemaVersion is the record schema version the producer used, see RecordSchemaVersion.
+	// Producers that predate versioning leave it unset. It's part of the payloads, not of
+	// the records the pumps write.
+	SchemaVersion int `json:"-" bson:"-" gorm:"-:all"`
 }
 
 func (a *AnalyticsRecord) TableName() string {
@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// RecordSchemaVersion is the version of the AnalyticsRecord shape this Pump works
+// with. The versions are:
+//
+//  1. Before GraphQL stats: GraphQL records are only marked with the
+//     tyk-graph-analytics tag, and there is no gateway latency.
+//  2. GraphQL stats and gateway latency, before MCP stats: MCP records are only marked
+//     with the tyk-mcp-analytics tag.
+//  3. MCP stats, original path and listen path.
+const RecordSchemaVersion = 3
+
+// ErrUnknownSchemaVersion is returned when a record was produced with a schema newer
+// than RecordSchemaVersion. Such a record can't be decoded faithfully, so it must not
+// reach the pumps.
+var ErrUnknownSchemaVersion = errors.New("unknown analytics record schema version")
+
+// Upcaster normalises a record of a given schema version to the next one.
+type Upcaster func(record *AnalyticsRecord)
+
+var (
+	upcastersMu sync.RWMutex
+	upcasters   = map[int]Upcaster{
+		1: upcastV1ToV2,
+		2: upcastV2ToV3,
+	}
+)
+
+// RegisterUpcaster registers up to normalise records of schema version from to
+// version from+1, replacing any upcaster registered for that version.
+func RegisterUpcaster(from int, up Upcaster) {
+	upcastersMu.Lock()
+	defer upcastersMu.Unlock()
+
+	upcasters[from] = up
+}
+
+// InferSchemaVersion returns the schema version record was produced with: the version
+// it carries if any, otherwise the oldest version whose shape it clearly matches. A record
+// that carries no version-specific data is indistinguishable from a current one, so it is
+// reported as RecordSchemaVersion and left as it is.
+func InferSchemaVersion(record *AnalyticsRecord) int {
+	if record.SchemaVersion != 0 {
+		return record.SchemaVersion
+	}
+
+	switch {
+	case record.MCPStats != (MCPStats{}) || record.OriginalPath != "" || record.ListenPath != "":
+		return 3
+	case record.hasTag(PredefinedTagGraphAnalytics) && !record.GraphQLStats.IsGraphQL:
+		return 1
+	case record.hasTag(PredefinedTagMCPAnalytics):
+		return 2
+	}
+
+	return RecordSchemaVersion
+}
+
+// Upcast normalises record to RecordSchemaVersion, running the registered upcaster of
+// every version between the one it was produced with and the current one. It returns
+// ErrUnknownSchemaVersion, leaving record untouched, when the record is newer than
+// RecordSchemaVersion.
+func (a *AnalyticsRecord) Upcast() error {
+	version := InferSchemaVersion(a)
+	if version > RecordSchemaVersion {
+		return fmt.Errorf("%w: %d, the newest supported is %d", ErrUnknownSchemaVersion, version, RecordSchemaVersion)
+	}
+
+	upcastersMu.RLock()
+	defer upcastersMu.RUnlock()
+
+	for ; version < RecordSchemaVersion; version++ {
+		if up, ok := upcasters[version]; ok {
+			up(a)
+		}
+	}
+
+	a.SchemaVersion = RecordSchemaVersion
+
+	return nil
+}
+
+func (a *AnalyticsRecord) hasTag(tag string) bool {
+	for _, t := range a.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// upcastV1ToV2 flags tagged GraphQL records as such, and derives the gateway latency
+// the way the gateway computes it: the part of the total not spent upstream.
+func upcastV1ToV2(record *AnalyticsRecord) {
+	if record.hasTag(PredefinedTagGraphAnalytics) {
+		record.GraphQLStats.IsGraphQL = true
+	}
+
+	if record.Latency.Gateway == 0 && record.Latency.Total > record.Latency.Upstream {
+		record.Latency.Gateway = record.Latency.Total - record.Latency.Upstream
+	}
+}
+
+// upcastV2ToV3 flags tagged MCP records as such.
+func upcastV2ToV3(record *AnalyticsRecord) {
+	if record.hasTag(PredefinedTagMCPAnalytics) {
+		record.MCPStats.IsMCP = true
+	}
+}
@@ -0,0 +1,112 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferSchemaVersion(t *testing.T) {
+	tcs := []struct {
+		testName string
+		record   AnalyticsRecord
+		expected int
+	}{
+		{
+			testName: "carried version",
+			record:   AnalyticsRecord{SchemaVersion: 2, MCPStats: MCPStats{IsMCP: true}},
+			expected: 2,
+		},
+		{
+			testName: "no version specific data",
+			record:   AnalyticsRecord{APIID: "api1"},
+			expected: RecordSchemaVersion,
+		},
+		{
+			testName: "graph tag without GraphQL stats",
+			record:   AnalyticsRecord{Tags: []string{PredefinedTagGraphAnalytics}},
+			expected: 1,
+		},
+		{
+			testName: "latency without gateway latency",
+			record:   AnalyticsRecord{Latency: Latency{Total: 10, Upstream: 8}},
+			expected: RecordSchemaVersion,
+		},
+		{
+			testName: "MCP tag without MCP stats",
+			record:   AnalyticsRecord{Tags: []string{PredefinedTagMCPAnalytics}, Latency: Latency{Total: 10, Upstream: 8, Gateway: 2}},
+			expected: 2,
+		},
+		{
+			testName: "MCP stats",
+			record:   AnalyticsRecord{Tags: []string{PredefinedTagMCPAnalytics}, MCPStats: MCPStats{IsMCP: true}},
+			expected: 3,
+		},
+		{
+			testName: "listen path",
+			record:   AnalyticsRecord{ListenPath: "/listen", Latency: Latency{Total: 10, Upstream: 8}},
+			expected: 3,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, InferSchemaVersion(&tc.record))
+		})
+	}
+}
+
+func TestAnalyticsRecord_Upcast(t *testing.T) {
+	t.Run("version 1", func(t *testing.T) {
+		record := AnalyticsRecord{
+			Tags:    []string{PredefinedTagGraphAnalytics},
+			Latency: Latency{Total: 10, Upstream: 8},
+		}
+
+		assert.NoError(t, record.Upcast())
+		assert.True(t, record.IsGraphRecord())
+		assert.Equal(t, int64(2), record.Latency.Gateway)
+		assert.Equal(t, RecordSchemaVersion, record.SchemaVersion)
+	})
+
+	t.Run("version 2", func(t *testing.T) {
+		record := AnalyticsRecord{SchemaVersion: 2, Tags: []string{PredefinedTagMCPAnalytics}}
+
+		assert.NoError(t, record.Upcast())
+		assert.True(t, record.IsMCPRecord())
+		assert.Equal(t, RecordSchemaVersion, record.SchemaVersion)
+	})
+
+	t.Run("current version", func(t *testing.T) {
+		record := AnalyticsRecord{SchemaVersion: RecordSchemaVersion, Latency: Latency{Total: 10, Upstream: 8}}
+
+		assert.NoError(t, record.Upcast())
+		assert.Equal(t, int64(0), record.Latency.Gateway, "current records are left as they are")
+	})
+
+	t.Run("no version", func(t *testing.T) {
+		record := AnalyticsRecord{Latency: Latency{Total: 10, Upstream: 8}}
+
+		assert.NoError(t, record.Upcast())
+		assert.Equal(t, int64(0), record.Latency.Gateway, "records that aren't clearly older are left as they are")
+	})
+
+	t.Run("future version", func(t *testing.T) {
+		record := AnalyticsRecord{SchemaVersion: RecordSchemaVersion + 1, APIID: "api1"}
+
+		err := record.Upcast()
+		assert.True(t, errors.Is(err, ErrUnknownSchemaVersion))
+		assert.Equal(t, RecordSchemaVersion+1, record.SchemaVersion)
+	})
+
+	t.Run("registered upcaster", func(t *testing.T) {
+		RegisterUpcaster(2, func(record *AnalyticsRecord) { record.Alias = "upcasted" })
+		t.Cleanup(func() { RegisterUpcaster(2, upcastV2ToV3) })
+
+		record := AnalyticsRecord{SchemaVersion: 2}
+
+		assert.NoError(t, record.Upcast())
+		assert.Equal(t, "upcasted", record.Alias)
+	})
+}
@@ -47,6 +47,11 @@ func (a *UptimeReportAggregateSQL) TableName() string {
 	return UptimeSQLTable
 }
 
+// IsUp reports whether the checked host was reachable and didn't answer with an error.
+func (a *UptimeReportData) IsUp() bool {
+	return !a.TCPError && !a.ServerError
+}
+
 func (a *UptimeReportData) GetObjectID() model.ObjectID {
 	return a.ID
 }
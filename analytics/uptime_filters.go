@@ -0,0 +1,30 @@
+package analytics
+
+type UptimeFilters struct {
+	// Filters uptime data by an allow list of org_ids.
+	OrgsIDs []string `json:"org_ids"`
+	// Filters uptime data by an allow list of api_ids.
+	APIIDs []string `json:"api_ids"`
+	// Filters uptime data by a block list of org_ids.
+	SkippedOrgsIDs []string `json:"skip_org_ids"`
+	// Filters uptime data by a block list of api_ids.
+	SkippedAPIIDs []string `json:"skip_api_ids"`
+}
+
+func (filters UptimeFilters) ShouldFilter(record UptimeReportData) bool {
+	switch {
+	case len(filters.SkippedAPIIDs) > 0 && stringInSlice(record.APIID, filters.SkippedAPIIDs):
+		return true
+	case len(filters.SkippedOrgsIDs) > 0 && stringInSlice(record.OrgID, filters.SkippedOrgsIDs):
+		return true
+	case len(filters.APIIDs) > 0 && !stringInSlice(record.APIID, filters.APIIDs):
+		return true
+	case len(filters.OrgsIDs) > 0 && !stringInSlice(record.OrgID, filters.OrgsIDs):
+		return true
+	}
+	return false
+}
+
+func (filters UptimeFilters) HasFilter() bool {
+	return len(filters.SkippedAPIIDs) > 0 || len(filters.SkippedOrgsIDs) > 0 || len(filters.APIIDs) > 0 || len(filters.OrgsIDs) > 0
+}
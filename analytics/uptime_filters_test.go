@@ -0,0 +1,36 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUptimeFilters_ShouldFilter(t *testing.T) {
+	record := UptimeReportData{
+		APIID: "apiid123",
+		OrgID: "orgid123",
+	}
+
+	tcs := []struct {
+		testName          string
+		filter            UptimeFilters
+		expectedFiltering bool
+	}{
+		{testName: "no filters", filter: UptimeFilters{}, expectedFiltering: false},
+		{testName: "skip_api_ids", filter: UptimeFilters{SkippedAPIIDs: []string{"apiid123"}}, expectedFiltering: true},
+		{testName: "skip_org_ids", filter: UptimeFilters{SkippedOrgsIDs: []string{"orgid123"}}, expectedFiltering: true},
+		{testName: "api_ids", filter: UptimeFilters{APIIDs: []string{"apiid123"}}, expectedFiltering: false},
+		{testName: "other api_ids", filter: UptimeFilters{APIIDs: []string{"apiid456"}}, expectedFiltering: true},
+		{testName: "org_ids", filter: UptimeFilters{OrgsIDs: []string{"orgid123"}}, expectedFiltering: false},
+		{testName: "other org_ids", filter: UptimeFilters{OrgsIDs: []string{"orgid456"}}, expectedFiltering: true},
+		{testName: "block list over allow list", filter: UptimeFilters{APIIDs: []string{"apiid123"}, SkippedAPIIDs: []string{"apiid123"}}, expectedFiltering: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expectedFiltering, tc.filter.ShouldFilter(record))
+			assert.Equal(t, tc.testName != "no filters", tc.filter.HasFilter())
+		})
+	}
+}
@@ -2,15 +2,17 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"strings"
 
 	"github.com/TykTechnologies/storage/kv"
 	"github.com/TykTechnologies/tyk-pump/logger"
 	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/quarantine"
+	"github.com/TykTechnologies/tyk-pump/server"
+	"github.com/TykTechnologies/tyk-pump/tail"
+	"github.com/TykTechnologies/tyk-pump/tracing"
 	"github.com/kelseyhightower/envconfig"
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
@@ -110,6 +112,114 @@ type PumpConfig struct {
 	DecodeRawRequest bool `json:"raw_request_decoded"`
 	// Setting this to true allows the Raw Response to be decoded from base 64 for all pumps. This is set to false by default.
 	DecodeRawResponse bool `json:"raw_response_decoded"`
+	// Set this to `true` to also write the uptime data to this pump, besides the uptime pump
+	// configured in `uptime_pump_config`. Supported by the `elasticsearch`, `kafka`,
+	// `prometheus`, `statsd` and `stdout` pumps. Uptime data isn't purged when
+	// `dont_purge_uptime_data` is `true`.
+	Uptime bool `json:"uptime"`
+	// Filters the uptime data written to this pump, the same way `filters` does for analytics
+	// records:
+	// ```{.json}
+	// "uptime_filters":{
+	//   "api_ids":[],
+	//   "org_ids":[],
+	//   "skip_api_ids":[],
+	//   "skip_org_ids":[]
+	// }
+	// ```
+	UptimeFilters analytics.UptimeFilters `json:"uptime_filters"`
+	// Stops writing to the pump for a while after too many consecutive failed writes, so a
+	// broken sink doesn't hold up every purge:
+	// ```{.json}
+	// "circuit_breaker": {
+	//   "failure_threshold": 5,
+	//   "cooldown": 30
+	// }
+	// ```
+	// Disabled by default.
+	CircuitBreaker pumps.CircuitBreakerConf `json:"circuit_breaker"`
+	// The log level of the pump, apart from the rest of the Pump, to debug a single pump. The
+	// possible values are `debug`, `info`, `warn` and `error`. Defaults to the level of the
+	// Pump, set in `log_level`.
+	LogLevel string `json:"log_level"`
+}
+
+type ReadinessConf struct {
+	// The number of seconds without a successful purge after which the Pump isn't ready.
+	// Defaults to three times `purge_delay`.
+	MaxPurgeAge int `json:"max_purge_age"`
+	// The ratio, from 0 to 1, of failed writes among its most recent ones above which a pump
+	// is unhealthy. Defaults to 0.5.
+	MaxPumpErrorRate float64 `json:"max_pump_error_rate"`
+	// The number of unhealthy pumps tolerated before the Pump isn't ready. A pump is
+	// unhealthy when its error rate is too high, its circuit breaker is open or it fails its
+	// health check. Defaults to 0.
+	MaxUnhealthyPumps int `json:"max_unhealthy_pumps"`
+}
+
+type AdminAPIConf struct {
+	// Enables the admin API. It's only served when `secret` is set too.
+	Enabled bool `json:"enabled"`
+	// The secret admin API requests must carry.
+	Secret string `json:"secret"`
+}
+
+type InstrumentationConf struct {
+	// Sends the instrumentation to StatsD.
+	StatsD StatsDSinkConf `json:"statsd"`
+	// Sends the instrumentation to DogStatsD, with tags.
+	DogStatsD DogStatsDSinkConf `json:"dogstatsd"`
+	// Exports the instrumentation as OpenTelemetry metrics over OTLP.
+	OTLP OTLPSinkConf `json:"otlp"`
+	// Writes the instrumentation as JSON lines, for environments without a metrics backend.
+	JSONLog JSONLogSinkConf `json:"json_log"`
+}
+
+type StatsDSinkConf struct {
+	Enabled bool `json:"enabled"`
+	// The `host:port` of the StatsD server.
+	Address string `json:"address"`
+	// The prefix of the metric names, without a trailing dot.
+	Prefix string `json:"prefix"`
+}
+
+type DogStatsDSinkConf struct {
+	Enabled bool `json:"enabled"`
+	// The `host:port` of the DogStatsD agent, or `unix:///path/to/socket`.
+	Address string `json:"address"`
+	// The prefix of the metric names, without a trailing dot.
+	Prefix string `json:"prefix"`
+	// The tags added to every metric.
+	Tags map[string]string `json:"tags"`
+}
+
+type OTLPSinkConf struct {
+	Enabled bool `json:"enabled"`
+	// The protocol the metrics are exported with, `grpc` or `http`. Defaults to `grpc`.
+	Exporter string `json:"exporter"`
+	// The OTLP collector the metrics are exported to, as `host:port` for `grpc` and as a
+	// URL for `http`.
+	Endpoint string `json:"endpoint"`
+	// Headers sent along with the exported metrics, such as an API key.
+	Headers map[string]string `json:"headers"`
+	// Exports the metrics without TLS.
+	Insecure bool `json:"insecure"`
+	// The number of seconds between two exports. Defaults to 10.
+	Interval int `json:"interval"`
+	// The prefix of the metric names, without a trailing dot.
+	Prefix string `json:"prefix"`
+	// The attributes added to every metric.
+	Tags map[string]string `json:"tags"`
+}
+
+type JSONLogSinkConf struct {
+	Enabled bool `json:"enabled"`
+	// The file the metrics are appended to. Defaults to the standard output.
+	Path string `json:"path"`
+	// The prefix of the metric names, without a trailing dot.
+	Prefix string `json:"prefix"`
+	// The tags added to every metric.
+	Tags map[string]string `json:"tags"`
 }
 
 type UptimeConf struct {
@@ -149,8 +259,12 @@ type UptimeConf struct {
 	// TYKCONFIGHEADEREND
 	// TYKCONFIGEXPAND
 	pumps.SQLConf
-	// Determines the uptime type. Options are `mongo` and `sql`. Defaults to `mongo`.
+	// Determines the uptime type. Options are `mongo`, `sql` and `none`. Defaults to `mongo`.
+	// With `none`, uptime data is only written to the pumps with `uptime` enabled.
 	UptimeType string `json:"uptime_type"`
+	// Filters the uptime data written to the `mongo` or `sql` uptime pump, the same way the
+	// `uptime_filters` of the pumps do.
+	UptimeFilters analytics.UptimeFilters `json:"uptime_filters"`
 }
 
 type TykPumpConfiguration struct {
@@ -196,6 +310,30 @@ type TykPumpConfiguration struct {
 	// ```
 	AnalyticsStorageConfig storage.TemporalStorageConfig `json:"analytics_storage_config"`
 
+	// Sets the serializers whose analytics keys the Pump purges. Each serializer reads its
+	// own key, the base analytics key followed by the serializer suffix. Supported values
+	// are `msgpack`, `protobuf` and `json`, and their compressed variants suffixed with
+	// `_zstd` or `_snappy`, e.g. `protobuf_zstd`. Defaults to `["msgpack", "protobuf"]`.
+	//
+	// Payloads are decoded according to their format header when they carry one, so
+	// producers using different formats can share a key.
+	AnalyticsSerializers []string `json:"analytics_serializers"`
+
+	// Keeps the analytics payloads the Pump can't decode, instead of discarding them,
+	// together with the serializer tried, the key they were read from and the error. Once
+	// whatever broke them is fixed, `tyk-pump quarantine retry` decodes them again and
+	// writes them to the pumps. For example:
+	// ```{.json}
+	// "quarantine": {
+	//   "type": "file",
+	//   "dir": "/var/lib/tyk-pump/quarantine"
+	// }
+	// ```
+	// `type` is either `file`, keeping every payload verbatim in its own file under `dir`,
+	// or `redis`, appending them to the `key_name` list (`tyk-pump-quarantine` by default)
+	// of the analytics storage.
+	Quarantine quarantine.Config `json:"quarantine"`
+
 	// Sets the type of storage from which the Pump will fetch data.
 	// The supported value is `redis`, which covers both Redis and the Redis-compatible Valkey.
 	// Pump will default to assume Redis if no alternative is provided, so this configuration can be ignored at present.
@@ -205,6 +343,25 @@ type TykPumpConfiguration struct {
 	StatsdConnectionString string `json:"statsd_connection_string"`
 	// Custom prefix value. For example separate settings for production and staging.
 	StatsdPrefix string `json:"statsd_prefix"`
+	// Sends the instrumentation of the Pump to several sinks at once, each with its own
+	// prefix and tags: StatsD, DogStatsD, OTLP metrics and JSON lines. Unlike
+	// `statsd_connection_string`, the sinks enabled here don't need `TYK_INSTRUMENTATION`.
+	// For example:
+	// ```{.json}
+	// "instrumentation": {
+	//   "dogstatsd": {
+	//     "enabled": true,
+	//     "address": "localhost:8125",
+	//     "prefix": "tyk_pump",
+	//     "tags": {"env": "production"}
+	//   },
+	//   "json_log": {
+	//     "enabled": true,
+	//     "path": "/var/log/tyk-pump/metrics.log"
+	//   }
+	// }
+	// ```
+	Instrumentation InstrumentationConf `json:"instrumentation"`
 	// Set the logger details for tyk-pump. The posible values are: `info`,`debug`,`error` and
 	// `warn`. By default, the log level is `info`.
 	LogLevel string `json:"log_level"`
@@ -212,6 +369,23 @@ type TykPumpConfiguration struct {
 	// Allowed values are `text`, `json`, or `legacy`.
 	// If not set or left empty, it defaults to `text`.
 	LogFormat logger.Format `json:"log_format"`
+	// Writes the logs to a file too, rotated by size:
+	// ```{.json}
+	// "log_file": {
+	//   "enabled": true,
+	//   "path": "/var/log/tyk-pump/pump.log",
+	//   "max_size": 100,
+	//   "max_backups": 5,
+	//   "max_age": 30,
+	//   "compress": true
+	// }
+	// ```
+	// `max_size` is in megabytes and `max_age` in days.
+	LogFile logger.FileConfig `json:"log_file"`
+	// The number of seconds identical warnings and errors are logged once in, the next one
+	// logged carrying the number of the ones dropped in its `repeated` field, so a broken
+	// sink doesn't flood the logs. Disabled by default, every line being logged.
+	LogRepeatInterval int `json:"log_repeat_interval"`
 	// TYKCONFIGHEADERSTART
 	// HEADER Health Check
 	// From v2.9.4, we have introduced a `/health` endpoint to confirm the Pump is running. You
@@ -229,6 +403,82 @@ type TykPumpConfiguration struct {
 	PurgeDelay int `json:"purge_delay"`
 	// The default port is 8083.
 	HealthCheckEndpointPort int `json:"health_check_endpoint_port"`
+	// Secures the health check server: the address it's bound to, TLS, with optional client
+	// certificate verification, and basic or bearer authentication of every route but the
+	// health and readiness probes. `private_port` moves the profiling and admin routes to a
+	// second listener, bound to localhost. For example:
+	// ```{.json}
+	// "health_check_server": {
+	//   "bind_address": "0.0.0.0",
+	//   "tls": {
+	//     "cert_file": "/certs/pump.crt",
+	//     "key_file": "/certs/pump.key",
+	//     "ca_file": "/certs/ca.crt",
+	//     "verify_client_cert": false
+	//   },
+	//   "auth": {
+	//     "type": "bearer",
+	//     "token": "change-me"
+	//   },
+	//   "private_port": 8084
+	// }
+	// ```
+	HealthCheckServer server.Config `json:"health_check_server"`
+	// The readiness endpoint, served on the health check port, reports the temporal storage
+	// connectivity, the last successful purge and the health of every pump, and returns a
+	// 503 when `readiness` says the Pump isn't ready. `/health?verbose` serves the same
+	// report. The default is "ready".
+	ReadinessEndpointName string `json:"readiness_endpoint_name"`
+	// Sets when the readiness endpoint reports the Pump isn't ready.
+	Readiness ReadinessConf `json:"readiness"`
+	// Enables the admin API, served on the health check port under `/admin`. It lists the
+	// pumps with their config, pauses and resumes them, changes their log level, triggers a
+	// purge and reports the number of records waiting in the temporal storage. Requests
+	// must carry `secret` in the `X-Tyk-Authorization` header, and every action is logged
+	// with the `admin-audit` prefix. Disabled by default.
+	AdminAPI AdminAPIConf `json:"admin_api"`
+	// Serves the metrics of the Pump itself in the Prometheus format on the health check
+	// port: the records popped per key, decoded and failed per serializer, and filtered,
+	// sent and failed per pump, the write latency per pump, the purge duration, the backlog
+	// of the temporal storage and the size of the quarantine. They are kept apart from the
+	// analytics metrics of the Prometheus pump. Disabled by default.
+	EnableMetrics bool `json:"enable_metrics"`
+	// The endpoint the metrics are served at. The default is "metrics".
+	MetricsEndpointName string `json:"metrics_endpoint_name"`
+	// Traces the purge cycles with OpenTelemetry, exported over OTLP. Each cycle is a trace,
+	// with a span per analytics key, covering its pop from the temporal storage and the
+	// decoding of its records, and a span per pump write, covering its filtering. The
+	// context a pump writes with carries the span of the write, which the pumps talking
+	// HTTP to their backend propagate. For example:
+	// ```{.json}
+	// "tracing": {
+	//   "enabled": true,
+	//   "exporter": "grpc",
+	//   "endpoint": "otel-collector:4317",
+	//   "insecure": true,
+	//   "sampling": {
+	//     "type": "TraceIDRatioBased",
+	//     "rate": 0.1
+	//   }
+	// }
+	// ```
+	Tracing tracing.Config `json:"tracing"`
+	// Streams the records being written at `/tail` on the health check port, as Server-Sent
+	// Events, for watching an API without setting up a pump. The records are streamed once
+	// filtered by each pump, tagged with its name, and can be narrowed down with the `pump`,
+	// `api_id`, `org_id`, `path_prefix`, `status_min` and `status_max` query parameters.
+	// Requests must carry `secret` in the `X-Tyk-Authorization` header. Each client is
+	// streamed at most `rate_limit` records per second, with `redact_fields` cleared, and is
+	// disconnected rather than slowing the pumps down when it falls behind. For example:
+	// ```{.json}
+	// "tail": {
+	//   "enabled": true,
+	//   "secret": "change-me",
+	//   "rate_limit": 50,
+	//   "redact_fields": ["raw_request", "raw_response", "api_key"]
+	// }
+	// ```
+	Tail tail.Config `json:"tail"`
 	// Defines maximum size (in bytes) for Raw Request and Raw Response logs, this value defaults
 	// to 0. If it is not set then tyk-pump will not trim any data and will store the full
 	// information. This can also be set at a pump level. For example:
@@ -251,6 +501,13 @@ type TykPumpConfiguration struct {
 	OmitDetailedRecording bool `json:"omit_detailed_recording"`
 	// Defines if tyk-pump should ignore all the values in configuration file. Specially useful when setting all configurations in environment variables.
 	OmitConfigFile bool `json:"omit_config_file"`
+	// A directory of pump configuration files, `*.yaml`, `*.yml` or `*.json`, merged into
+	// `pumps`. Each file maps pump names to their configuration, the way `pumps` does, so
+	// every pump can be kept in its own file. The files are merged in the order of their
+	// names, objects deeply, and a setting given different values by two files, or by a
+	// file and the configuration file, is an error. A relative path is relative to the
+	// directory of the configuration file.
+	IncludeDir string `json:"include_dir"`
 	// Expose profiling information to support debugging of Tyk Pump. This operates in the same way as for Tyk Gateway, as explained [here](/api-management/troubleshooting-debugging).
 	HTTPProfile bool `json:"enable_http_profiler"`
 	// This option was intended to decode raw request payloads from base64 for all Pumps. However, it was never implemented and therefore has no functional effect. It has now been deprecated.
@@ -265,11 +522,19 @@ type TykPumpConfiguration struct {
 	// variables, or inline values) that other configuration values can reference.
 	// This lets sensitive settings like database credentials or the admin secret
 	// be kept in an external store instead of the config file; each referenced
-	// value is resolved from its store once, at startup. Store definitions may be
+	// value is resolved from its store at startup, and again every
+	// `kv_refresh_interval` when it's set. Store definitions may be
 	// set in the config file or supplied as a JSON object through the
 	// TYK_PMP_KV_STORES environment variable; the environment overrides and adds
 	// stores by name, leaving file-defined stores it does not name untouched.
 	KV kv.Config `json:"kv"`
+	// The interval, in seconds, at which the KV references of the pump configurations are
+	// resolved again, for the pumps to pick up rotated credentials. The SQL, Mongo,
+	// Elasticsearch, Kafka and Splunk pumps reconnect with the new values of their
+	// credentials and connection settings when they change; the other settings, and the
+	// other pumps, keep the values resolved at startup. The KV stores are kept open while
+	// the Pump runs. Defaults to 0, resolving the references once, at startup.
+	KVRefreshInterval int `json:"kv_refresh_interval"`
 }
 
 // LoadConfig populates configStruct from the config file and the environment, then
@@ -281,23 +546,22 @@ type TykPumpConfiguration struct {
 // declares no KV stores, and Close is safe on nil.
 func LoadConfig(filePath *string, configStruct *TykPumpConfiguration) *kvStores {
 	if !configStruct.shouldOmitConfigFile() {
-		configuration, err := ioutil.ReadFile(*filePath)
+		doc, err := readConfigFile(*filePath)
 		if err != nil {
 			log.Error("Couldn't load configuration file: ", err)
-		}
+		} else {
+			if _, err := includePumps(doc, *filePath); err != nil {
+				log.Fatal("Couldn't include the pump configurations: ", err)
+			}
 
-		marshalErr := json.Unmarshal(configuration, &configStruct)
-		if marshalErr != nil {
-			log.Error("Couldn't unmarshal configuration: ", marshalErr)
+			marshalErr := decodeConfig(doc, configStruct)
+			if marshalErr != nil {
+				log.Error("Couldn't unmarshal configuration: ", marshalErr)
+			}
 		}
 	}
 
-	toUpperMap := make(map[string]PumpConfig)
-	for pumpName := range configStruct.Pumps {
-		upperPumpName := strings.ToUpper(pumpName)
-		toUpperMap[upperPumpName] = configStruct.Pumps[pumpName]
-	}
-	configStruct.Pumps = toUpperMap
+	configStruct.upperCasePumpNames()
 
 	overrideErr := envconfig.Process(ENV_PREVIX, configStruct)
 	if overrideErr != nil {
@@ -317,6 +581,17 @@ func LoadConfig(filePath *string, configStruct *TykPumpConfiguration) *kvStores
 	return stores
 }
 
+// upperCasePumpNames upper cases the names of the pumps, for the pump env vars to override
+// them.
+func (cfg *TykPumpConfiguration) upperCasePumpNames() {
+	toUpperMap := make(map[string]PumpConfig)
+	for pumpName := range cfg.Pumps {
+		upperPumpName := strings.ToUpper(pumpName)
+		toUpperMap[upperPumpName] = cfg.Pumps[pumpName]
+	}
+	cfg.Pumps = toUpperMap
+}
+
 func (cfg *TykPumpConfiguration) shouldOmitConfigFile() bool {
 	shouldOmit, omitEnvExist := os.LookupEnv(ENV_PREVIX + "_OMITCONFIGFILE")
 	return omitEnvExist && strings.EqualFold(shouldOmit, "true")
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readConfigFile reads the configuration file at path, YAML when its extension is .yaml or
+// .yml and JSON otherwise, into a JSON document.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if isYAML(path) {
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		// Round trip through JSON, for the values to compare equal to the ones of JSON files.
+		raw, err = json.Marshal(jsonValue(doc))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the configuration isn't an object")
+	}
+	return obj, nil
+}
+
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// jsonValue converts the maps YAML decodes keys of other types than strings into to the
+// objects of JSON.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = jsonValue(value)
+		}
+		return v
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			obj[fmt.Sprint(key)] = jsonValue(value)
+		}
+		return obj
+	case []interface{}:
+		for i, value := range v {
+			v[i] = jsonValue(value)
+		}
+		return v
+	}
+	return v
+}
+
+// decodeConfig decodes doc, a JSON document, into cfg the way the configuration file is.
+func decodeConfig(doc map[string]interface{}, cfg *TykPumpConfiguration) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, cfg)
+}
+
+// includePumps merges the pump configuration files of the `include_dir` of doc, the
+// configuration file at path, into its `pumps`. The TYK_PMP_INCLUDEDIR env var overrides
+// the directory. The pump names are upper cased, the way they are once loaded, for the
+// ones only differing by their case to be merged too. It returns the files the settings
+// of the pumps come from, by their path in doc, when there's a directory to include.
+func includePumps(doc map[string]interface{}, path string) (map[string]string, error) {
+	dir, _ := doc["include_dir"].(string)
+	if env, ok := os.LookupEnv(ENV_PREVIX + "_INCLUDEDIR"); ok {
+		dir = env
+	}
+
+	pumpsDoc, ok := doc["pumps"].(map[string]interface{})
+	if doc["pumps"] != nil && !ok {
+		return nil, errors.New("pumps isn't an object")
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(path), dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	merge := pumpsMerge{merged: map[string]interface{}{}, origins: map[string]string{}}
+	merge.add(pumpsDoc, path)
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && !isYAML(entry.Name())) {
+			continue
+		}
+
+		file := filepath.Join(dir, entry.Name())
+		fragment, err := readConfigFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		merge.add(fragment, file)
+	}
+
+	if len(merge.conflicts) > 0 {
+		return nil, errors.Join(merge.conflicts...)
+	}
+	doc["pumps"] = merge.merged
+	return merge.origins, nil
+}
+
+// pumpsMerge deeply merges pump configurations, gathering the settings given different
+// values.
+type pumpsMerge struct {
+	merged map[string]interface{}
+	// origins are the files the settings were first set in, by path.
+	origins   map[string]string
+	conflicts []error
+}
+
+// add merges pumps, the pump configurations of the file called source.
+func (m *pumpsMerge) add(pumps map[string]interface{}, source string) {
+	for _, name := range sortedKeys(pumps) {
+		m.merge(m.merged, map[string]interface{}{strings.ToUpper(name): pumps[name]}, "pumps", source)
+	}
+}
+
+func (m *pumpsMerge) merge(dst, src map[string]interface{}, path, source string) {
+	for _, key := range sortedKeys(src) {
+		keyPath := joinPath(path, key)
+		value := src[key]
+
+		current, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			m.setOrigins(value, keyPath, source)
+			continue
+		}
+
+		currentObj, currentIsObj := current.(map[string]interface{})
+		obj, isObj := value.(map[string]interface{})
+		switch {
+		case currentIsObj && isObj:
+			m.merge(currentObj, obj, keyPath, source)
+		case !reflect.DeepEqual(current, value):
+			m.conflicts = append(m.conflicts, fmt.Errorf("%s is set to different values in %s and %s", keyPath, m.origins[keyPath], source))
+		}
+	}
+}
+
+func (m *pumpsMerge) setOrigins(value interface{}, path, source string) {
+	m.origins[path] = source
+	if obj, ok := value.(map[string]interface{}); ok {
+		for key, v := range obj {
+			m.setOrigins(v, joinPath(path, key), source)
+		}
+	}
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
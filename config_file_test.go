@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pump.conf": `{"purge_delay": 10, "pumps": {"csv": {"type": "csv", "meta": {"csv_dir": "./"}}}}`,
+		"pump.yaml": "purge_delay: 10\npumps:\n  csv:\n    type: csv\n    meta:\n      csv_dir: ./\n",
+		"pump.yml":  "purge_delay: 10\npumps:\n  csv: {type: csv, meta: {csv_dir: ./}}\n",
+		"list.json": `[1, 2]`,
+		"bad.yaml":  "pumps: [",
+	})
+
+	fromJSON, err := readConfigFile(filepath.Join(dir, "pump.conf"))
+	require.NoError(t, err)
+	fromYAML, err := readConfigFile(filepath.Join(dir, "pump.yaml"))
+	require.NoError(t, err)
+	fromYML, err := readConfigFile(filepath.Join(dir, "pump.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, fromJSON, fromYAML)
+	assert.Equal(t, fromJSON, fromYML)
+
+	_, err = readConfigFile(filepath.Join(dir, "list.json"))
+	assert.EqualError(t, err, "the configuration isn't an object")
+	_, err = readConfigFile(filepath.Join(dir, "bad.yaml"))
+	assert.Error(t, err)
+	_, err = readConfigFile(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestIncludePumps(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		config      string
+		files       map[string]string
+		env         string
+		expected    map[string]interface{}
+		expectedErr string
+	}{
+		{
+			testName: "no include dir",
+			config:   `{"pumps": {"csv": {"type": "csv"}}}`,
+			expected: map[string]interface{}{"csv": map[string]interface{}{"type": "csv"}},
+		},
+		{
+			testName: "one pump per file",
+			config:   `{"include_dir": "conf.d", "pumps": {"csv": {"type": "csv"}}}`,
+			files: map[string]string{
+				"conf.d/stdout.yaml": "stdout:\n  type: stdout\n",
+				"conf.d/mongo.json":  `{"mongo": {"type": "mongo"}}`,
+				"conf.d/notes.txt":   "ignored",
+			},
+			expected: map[string]interface{}{
+				"CSV":    map[string]interface{}{"type": "csv"},
+				"STDOUT": map[string]interface{}{"type": "stdout"},
+				"MONGO":  map[string]interface{}{"type": "mongo"},
+			},
+		},
+		{
+			testName: "deep merge",
+			config:   `{"include_dir": "conf.d", "pumps": {"csv": {"type": "csv", "meta": {"csv_dir": "./"}}}}`,
+			files: map[string]string{
+				"conf.d/csv.yml": "CSV:\n  type: csv\n  timeout: 5\n  meta:\n    meta_env_prefix: CSV\n",
+			},
+			expected: map[string]interface{}{
+				"CSV": map[string]interface{}{
+					"type":    "csv",
+					"timeout": json.Number("5"),
+					"meta":    map[string]interface{}{"csv_dir": "./", "meta_env_prefix": "CSV"},
+				},
+			},
+		},
+		{
+			testName: "conflicts",
+			config:   `{"include_dir": "conf.d", "pumps": {"csv": {"type": "csv", "timeout": 5}}}`,
+			files: map[string]string{
+				"conf.d/a.yaml": "csv:\n  timeout: 10\n  meta:\n    csv_dir: ./a\n",
+				"conf.d/b.json": `{"Csv": {"timeout": 5, "meta": {"csv_dir": "./b"}}}`,
+			},
+			expectedErr: "pumps.CSV.timeout is set to different values in DIR/pump.conf and DIR/conf.d/a.yaml\n" +
+				"pumps.CSV.meta.csv_dir is set to different values in DIR/conf.d/a.yaml and DIR/conf.d/b.json",
+		},
+		{
+			testName: "include dir from the env",
+			config:   `{"include_dir": "missing"}`,
+			files: map[string]string{
+				"other/csv.yaml": "csv:\n  type: csv\n",
+			},
+			env:      "other",
+			expected: map[string]interface{}{"CSV": map[string]interface{}{"type": "csv"}},
+		},
+		{
+			testName:    "missing include dir",
+			config:      `{"include_dir": "missing"}`,
+			expectedErr: "open DIR/missing: no such file or directory",
+		},
+		{
+			testName: "invalid file",
+			config:   `{"include_dir": "conf.d"}`,
+			files: map[string]string{
+				"conf.d/csv.json": `["csv"]`,
+			},
+			expectedErr: "DIR/conf.d/csv.json: the configuration isn't an object",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tc.files)
+			writeFiles(t, dir, map[string]string{"pump.conf": tc.config})
+			if tc.env != "" {
+				t.Setenv(ENV_PREVIX+"_INCLUDEDIR", tc.env)
+			}
+
+			path := filepath.Join(dir, "pump.conf")
+			doc, err := readConfigFile(path)
+			require.NoError(t, err)
+
+			_, err = includePumps(doc, path)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, strings.ReplaceAll(tc.expectedErr, "DIR", dir))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, doc["pumps"])
+		})
+	}
+}
+
+func TestLoadConfig_YAMLWithIncludeDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pump.yaml":         "purge_delay: 10\ninclude_dir: conf.d\npumps:\n  csv:\n    type: csv\n    meta:\n      csv_dir: ./\n",
+		"conf.d/stdout.yml": "stdout:\n  type: stdout\n  timeout: 3\n",
+	})
+	t.Setenv(PUMPS_ENV_PREFIX+"_STDOUT_TIMEOUT", "7")
+
+	path := filepath.Join(dir, "pump.yaml")
+	cfg := &TykPumpConfiguration{}
+	LoadConfig(&path, cfg)
+
+	assert.Equal(t, 10, cfg.PurgeDelay)
+	assert.Len(t, cfg.Pumps, 2)
+	assert.Equal(t, "csv", cfg.Pumps["CSV"].Type)
+	assert.Equal(t, "./", cfg.Pumps["CSV"].Meta["csv_dir"])
+	assert.Equal(t, "stdout", cfg.Pumps["STDOUT"].Type)
+	assert.Equal(t, 7, cfg.Pumps["STDOUT"].Timeout, "the env vars override the included files")
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/TykTechnologies/storage/kv/resolver"
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/schema"
+	"github.com/kelseyhightower/envconfig"
+)
+
+// runConfigDump writes the effective configuration at path to w. It returns the exit code
+// of the config dump command.
+func runConfigDump(path string, w io.Writer) int {
+	if err := dumpConfig(path, w); err != nil {
+		fmt.Fprintln(w, "Couldn't load the configuration:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// dumpConfig loads the configuration at path the way LoadConfig does and writes every
+// setting it ends up with to w, the ones of the pumps as they decode their meta included,
+// with where it comes from: the configuration file, an env var, and whether its value was
+// a KV reference. The secrets are redacted, and the settings left to their zero value
+// aren't listed.
+func dumpConfig(path string, w io.Writer) error {
+	cfg := &TykPumpConfiguration{}
+	sources := configSources{path: path}
+
+	if !cfg.shouldOmitConfigFile() {
+		doc, err := readConfigFile(path)
+		if err != nil {
+			return err
+		}
+		origins, err := includePumps(doc, path)
+		if err != nil {
+			return err
+		}
+		if err := decodeConfig(doc, cfg); err != nil {
+			return err
+		}
+
+		sources.doc = doc
+		sources.origins = make(map[string]string, len(origins))
+		for settingPath, file := range origins {
+			sources.origins[strings.ToLower(settingPath)] = file
+		}
+	}
+
+	cfg.upperCasePumpNames()
+	if err := envconfig.Process(ENV_PREVIX, cfg); err != nil {
+		return err
+	}
+	if err := cfg.LoadPumpsByEnv(); err != nil {
+		return err
+	}
+
+	stores, err := resolveKVReferences(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	pumps.SetKVResolver(stores.Resolver())
+	defer pumps.SetKVResolver(nil)
+	defer stores.Close(context.Background())
+
+	for _, setting := range (schema.Reflector{TagName: "json", EnvPrefix: ENV_PREVIX}).Settings(cfg) {
+		if setting.Path[0] == "pumps" {
+			continue
+		}
+		sources.write(w, "", setting.Path, setting.Value, sources.source(setting.Path, setting.EnvVar))
+	}
+
+	names := make([]string, 0, len(cfg.Pumps))
+	for name := range cfg.Pumps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dumpPump(w, sources, name, cfg.Pumps[name])
+	}
+
+	return nil
+}
+
+// dumpPump writes the settings of the pump called name to w.
+func dumpPump(w io.Writer, sources configSources, name string, conf PumpConfig) {
+	pumpType := conf.Type
+	if pumpType == "" {
+		pumpType = name
+	}
+	fmt.Fprintf(w, "\nPump %s, of type %s:\n", name, pumpType)
+
+	pumpPath := []string{"pumps", name}
+	for _, setting := range (schema.Reflector{TagName: "json", EnvPrefix: PUMPS_ENV_PREFIX + "_" + name}).Settings(conf) {
+		if setting.Path[0] == "meta" {
+			continue
+		}
+		path := append(pumpPath, setting.Path...)
+		sources.write(w, "  ", setting.Path, setting.Value, sources.source(path, setting.EnvVar))
+	}
+
+	metaPath := append(pumpPath, "meta")
+	meta, envPrefixes, err := pumps.EffectiveMeta(pumpType, conf.Meta)
+	switch {
+	case err != nil:
+		fmt.Fprintf(w, "  Couldn't load the meta: %v\n", err)
+	case meta == nil:
+		for _, key := range sortedKeys(conf.Meta) {
+			path := append(metaPath[:len(metaPath):len(metaPath)], key)
+			sources.write(w, "  ", []string{"meta", key}, conf.Meta[key], sources.source(path))
+		}
+	default:
+		for _, setting := range (schema.Reflector{TagName: "mapstructure", EnvPrefix: envPrefixes[0]}).Settings(meta) {
+			envVars := []string{setting.EnvVar}
+			if setting.EnvVar != "" {
+				// The deprecated env vars are named after the current ones.
+				for _, prefix := range envPrefixes[1:] {
+					envVars = append(envVars, prefix+strings.TrimPrefix(setting.EnvVar, envPrefixes[0]))
+				}
+			}
+
+			path := append(metaPath[:len(metaPath):len(metaPath)], setting.Path...)
+			sources.write(w, "  ", append([]string{"meta"}, setting.Path...), setting.Value, sources.source(path, envVars...))
+		}
+	}
+}
+
+// configSources tells where the settings of the configuration come from.
+type configSources struct {
+	// path is the configuration file.
+	path string
+	// doc is the configuration file, the included pump files merged in, nil when it's
+	// omitted.
+	doc map[string]interface{}
+	// origins are the included files the settings of the pumps come from, by their lower
+	// case path.
+	origins map[string]string
+}
+
+// source returns where the setting at path, overridden by envVars in order, comes from,
+// or "" when it's left to its default.
+func (s configSources) source(path []string, envVars ...string) string {
+	for i := len(envVars) - 1; i >= 0; i-- {
+		if envVars[i] == "" {
+			continue
+		}
+		if value, ok := os.LookupEnv(envVars[i]); ok {
+			return withKVReference("env "+envVars[i], value)
+		}
+	}
+
+	value, ok := lookupPath(s.doc, path)
+	if !ok {
+		return ""
+	}
+	file := s.origins[strings.ToLower(strings.Join(path, "."))]
+	if file == "" {
+		file = s.path
+	}
+	return withKVReference("file "+file, value)
+}
+
+// write writes the setting at path to w, unless it's left to its zero value.
+func (s configSources) write(w io.Writer, indent string, path []string, value interface{}, source string) {
+	if source == "" {
+		if isZeroSetting(value) {
+			return
+		}
+		source = "default"
+	}
+
+	key := strings.Join(path, ".")
+	fmt.Fprintf(w, "%s%s = %s  # %s\n", indent, key, redactSetting(path[len(path)-1], value), source)
+}
+
+// lookupPath returns the value at path in doc, matching the keys regardless of their case
+// the way the configuration is decoded.
+func lookupPath(doc map[string]interface{}, path []string) (interface{}, bool) {
+	var value interface{} = doc
+	for _, key := range path {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		value, ok = obj[key]
+		if !ok {
+			found := false
+			for k, v := range obj {
+				if strings.EqualFold(k, key) {
+					value, found = v, true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		}
+	}
+
+	return value, true
+}
+
+// withKVReference adds to source whether value, the one it set, is a KV reference.
+func withKVReference(source string, value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err == nil && resolver.ContainsReferences(raw) {
+		return source + " via KV reference"
+	}
+
+	return source
+}
+
+// redactSetting returns value, the one of the setting called key, as JSON, redacted the
+// way the admin API redacts the configuration.
+func redactSetting(key string, value interface{}) string {
+	redacted, err := redactConfig(map[string]interface{}{key: value})
+	if err != nil {
+		return redactedValue
+	}
+
+	raw, err := json.Marshal(redacted.(map[string]interface{})[key])
+	if err != nil {
+		return redactedValue
+	}
+	return string(raw)
+}
+
+func isZeroSetting(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/gocraft/health"
+)
+
+// DogStatsDSink sends the instrumentation to DogStatsD. Unlike StatsDSink, the job and its
+// key values are sent as tags rather than in the metric names.
+type DogStatsDSink struct {
+	client *statsd.Client
+	prefix string
+	tags   map[string]string
+}
+
+func NewDogStatsDSink(conf DogStatsDSinkConf) (*DogStatsDSink, error) {
+	client, err := statsd.New(conf.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DogStatsDSink{client: client, prefix: conf.Prefix, tags: conf.Tags}, nil
+}
+
+// Close flushes the metrics still buffered and closes the connection to the agent.
+func (s *DogStatsDSink) Close() error {
+	return s.client.Close()
+}
+
+func (s *DogStatsDSink) EmitEvent(job string, event string, kvs map[string]string) {
+	s.client.Incr(sinkMetricName(s.prefix, event), s.tagList(job, kvs), 1)
+}
+
+func (s *DogStatsDSink) EmitEventErr(job string, event string, err error, kvs map[string]string) {
+	s.client.Incr(sinkMetricName(s.prefix, event, "error"), s.tagList(job, kvs), 1)
+}
+
+func (s *DogStatsDSink) EmitTiming(job string, event string, nanos int64, kvs map[string]string) {
+	s.client.Timing(sinkMetricName(s.prefix, event), time.Duration(nanos), s.tagList(job, kvs), 1)
+}
+
+func (s *DogStatsDSink) EmitGauge(job string, event string, value float64, kvs map[string]string) {
+	s.client.Gauge(sinkMetricName(s.prefix, event), value, s.tagList(job, kvs), 1)
+}
+
+func (s *DogStatsDSink) EmitComplete(job string, status health.CompletionStatus, nanos int64, kvs map[string]string) {
+	tags := append(s.tagList(job, kvs), "status:"+status.String())
+	s.client.Timing(sinkMetricName(s.prefix, "job_complete"), time.Duration(nanos), tags, 1)
+}
+
+// tagList returns the tags of the sink and the key values of job as DogStatsD tags, sorted
+// for every metric to be tagged the same way.
+func (s *DogStatsDSink) tagList(job string, kvs map[string]string) []string {
+	merged := sinkTags(s.tags, job, kvs)
+	tags := make([]string, 0, len(merged))
+	for k, v := range merged {
+		tags = append(tags, k+":"+v)
+	}
+	sort.Strings(tags)
+
+	return tags
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io"
 	"os"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/gocraft/health"
@@ -11,28 +15,77 @@ import (
 var applicationGCStats = debug.GCStats{}
 var instrument = health.NewStream()
 
+// instrumentationStops flush and stop the sinks that buffer their metrics.
+var instrumentationStops []func(context.Context) error
+
 // SetupInstrumentation handles all the intialisation of the instrumentation handler
 func SetupInstrumentation() {
-	var enabled bool
+	sinks := 0
 	//instrument.AddSink(&health.WriterSink{os.Stdout})
 	thisInstr := os.Getenv("TYK_INSTRUMENTATION")
 
-	if thisInstr == "1" {
-		enabled = true
+	if thisInstr == "1" && !SystemConfig.Instrumentation.StatsD.Enabled {
+		if SystemConfig.StatsdConnectionString == "" {
+			log.Error("Instrumentation is enabled, but no connectionstring set for statsd")
+		} else {
+			addStatsDSink(SystemConfig.StatsdConnectionString, SystemConfig.StatsdPrefix)
+			sinks++
+		}
 	}
 
-	if !enabled {
-		return
+	conf := SystemConfig.Instrumentation
+	if conf.StatsD.Enabled {
+		addStatsDSink(conf.StatsD.Address, conf.StatsD.Prefix)
+		sinks++
+	}
+
+	if conf.DogStatsD.Enabled {
+		log.Info("Sending stats to DogStatsD: ", conf.DogStatsD.Address, " with prefix: ", conf.DogStatsD.Prefix)
+		sink, err := NewDogStatsDSink(conf.DogStatsD)
+		if err != nil {
+			log.Fatal("Failed to start the DogStatsD instrumentation sink: ", err)
+		}
+		instrument.AddSink(sink)
+		instrumentationStops = append(instrumentationStops, func(context.Context) error { return sink.Close() })
+		sinks++
+	}
+
+	if conf.OTLP.Enabled {
+		log.Info("Exporting stats over OTLP to: ", conf.OTLP.Endpoint, " with prefix: ", conf.OTLP.Prefix)
+		sink, err := NewOTLPSink(context.Background(), conf.OTLP)
+		if err != nil {
+			log.Fatal("Failed to start the OTLP instrumentation sink: ", err)
+		}
+		instrument.AddSink(sink)
+		instrumentationStops = append(instrumentationStops, sink.Shutdown)
+		sinks++
 	}
 
-	if SystemConfig.StatsdConnectionString == "" {
-		log.Error("Instrumentation is enabled, but no connectionstring set for statsd")
+	if conf.JSONLog.Enabled {
+		w := io.Writer(os.Stdout)
+		if conf.JSONLog.Path != "" {
+			f, err := os.OpenFile(conf.JSONLog.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+			if err != nil {
+				log.Fatal("Failed to open the JSON log instrumentation sink: ", err)
+			}
+			w = f
+			instrumentationStops = append(instrumentationStops, func(context.Context) error { return f.Close() })
+		}
+		log.Info("Writing stats as JSON lines to: ", jsonLogDestination(conf.JSONLog.Path))
+		instrument.AddSink(NewJSONLogSink(w, conf.JSONLog))
+		sinks++
+	}
+
+	if sinks == 0 {
 		return
 	}
 
-	log.Info("Sending stats to: ", SystemConfig.StatsdConnectionString, " with prefix: ", SystemConfig.StatsdPrefix)
-	statsdSink, err := NewStatsDSink(SystemConfig.StatsdConnectionString,
-		&StatsDSinkOptions{Prefix: SystemConfig.StatsdPrefix})
+	MonitorApplicationInstrumentation()
+}
+
+func addStatsDSink(addr, prefix string) {
+	log.Info("Sending stats to: ", addr, " with prefix: ", prefix)
+	statsdSink, err := NewStatsDSink(addr, &StatsDSinkOptions{Prefix: prefix})
 
 	if err != nil {
 		log.Fatal("Failed to start StatsD check: ", err)
@@ -41,8 +94,68 @@ func SetupInstrumentation() {
 
 	log.Info("StatsD instrumentation sink started")
 	instrument.AddSink(statsdSink)
+	instrumentationStops = append(instrumentationStops, func(context.Context) error {
+		statsdSink.Drain()
+		return nil
+	})
+}
 
-	MonitorApplicationInstrumentation()
+func jsonLogDestination(path string) string {
+	if path == "" {
+		return "stdout"
+	}
+	return path
+}
+
+// StopInstrumentation flushes the metrics the sinks still hold and stops them.
+func StopInstrumentation(ctx context.Context) error {
+	var errs []error
+	for _, stop := range instrumentationStops {
+		if err := stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	instrumentationStops = nil
+
+	return errors.Join(errs...)
+}
+
+// sinkMetricName joins prefix and the parts of a metric name with dots, replacing the
+// characters the metric backends don't accept.
+func sinkMetricName(prefix string, parts ...string) string {
+	b := strings.Builder{}
+	for _, part := range append([]string{prefix}, parts...) {
+		if part == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		for _, c := range part {
+			if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '_' || c == '.' || c == '-' {
+				b.WriteRune(c)
+			} else {
+				b.WriteByte('_')
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// sinkTags merges the tags of a sink with the key values of a job, which take precedence,
+// and tags them with the job.
+func sinkTags(tags map[string]string, job string, kvs map[string]string) map[string]string {
+	merged := make(map[string]string, len(tags)+len(kvs)+1)
+	for k, v := range tags {
+		merged[k] = v
+	}
+	for k, v := range kvs {
+		merged[k] = v
+	}
+	merged["job"] = job
+
+	return merged
 }
 
 func MonitorApplicationInstrumentation() {
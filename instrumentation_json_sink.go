@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gocraft/health"
+)
+
+// JSONLogSink writes the instrumentation as JSON lines, one per metric, for environments
+// without a metrics backend.
+type JSONLogSink struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	prefix string
+	tags   map[string]string
+}
+
+type jsonLogMetric struct {
+	Time  time.Time         `json:"time"`
+	Kind  string            `json:"kind"`
+	Name  string            `json:"name"`
+	Value float64           `json:"value"`
+	Error string            `json:"error,omitempty"`
+	Tags  map[string]string `json:"tags"`
+}
+
+func NewJSONLogSink(w io.Writer, conf JSONLogSinkConf) *JSONLogSink {
+	return &JSONLogSink{enc: json.NewEncoder(w), prefix: conf.Prefix, tags: conf.Tags}
+}
+
+func (s *JSONLogSink) EmitEvent(job string, event string, kvs map[string]string) {
+	s.write(jsonLogMetric{Kind: "counter", Name: sinkMetricName(s.prefix, event), Value: 1, Tags: sinkTags(s.tags, job, kvs)})
+}
+
+func (s *JSONLogSink) EmitEventErr(job string, event string, err error, kvs map[string]string) {
+	m := jsonLogMetric{Kind: "counter", Name: sinkMetricName(s.prefix, event, "error"), Value: 1, Tags: sinkTags(s.tags, job, kvs)}
+	if err != nil {
+		m.Error = err.Error()
+	}
+	s.write(m)
+}
+
+func (s *JSONLogSink) EmitTiming(job string, event string, nanos int64, kvs map[string]string) {
+	s.write(jsonLogMetric{Kind: "timing_ms", Name: sinkMetricName(s.prefix, event), Value: float64(nanos) / float64(time.Millisecond), Tags: sinkTags(s.tags, job, kvs)})
+}
+
+func (s *JSONLogSink) EmitGauge(job string, event string, value float64, kvs map[string]string) {
+	s.write(jsonLogMetric{Kind: "gauge", Name: sinkMetricName(s.prefix, event), Value: value, Tags: sinkTags(s.tags, job, kvs)})
+}
+
+func (s *JSONLogSink) EmitComplete(job string, status health.CompletionStatus, nanos int64, kvs map[string]string) {
+	tags := sinkTags(s.tags, job, kvs)
+	tags["status"] = status.String()
+	s.write(jsonLogMetric{Kind: "timing_ms", Name: sinkMetricName(s.prefix, "job_complete"), Value: float64(nanos) / float64(time.Millisecond), Tags: tags})
+}
+
+func (s *JSONLogSink) write(m jsonLogMetric) {
+	m.Time = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.enc.Encode(m); err != nil {
+		log.Error("Couldn't write the ", m.Name, " metric: ", err)
+	}
+}
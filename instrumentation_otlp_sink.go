@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/gocraft/health"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const defaultOTLPSinkInterval = 10 * time.Second
+
+// OTLPSink exports the instrumentation as OpenTelemetry metrics: the events as counters,
+// the timings as histograms in milliseconds and the gauges as gauges, with the job and its
+// key values as attributes.
+type OTLPSink struct {
+	provider *sdkmetric.MeterProvider
+	meter    metric.Meter
+	prefix   string
+	tags     map[string]string
+
+	mu         sync.Mutex
+	counters   map[string]metric.Int64Counter
+	histograms map[string]metric.Float64Histogram
+	gauges     map[string]metric.Float64Gauge
+}
+
+func NewOTLPSink(ctx context.Context, conf OTLPSinkConf) (*OTLPSink, error) {
+	exporter, err := newOTLPMetricExporter(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(conf.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultOTLPSinkInterval
+	}
+
+	return newOTLPSink(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval)), conf)
+}
+
+func newOTLPSink(reader sdkmetric.Reader, conf OTLPSinkConf) (*OTLPSink, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "tyk-pump"),
+		attribute.String("service.version", pumps.Version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res))
+
+	return &OTLPSink{
+		provider:   provider,
+		meter:      provider.Meter("github.com/TykTechnologies/tyk-pump"),
+		prefix:     conf.Prefix,
+		tags:       conf.Tags,
+		counters:   map[string]metric.Int64Counter{},
+		histograms: map[string]metric.Float64Histogram{},
+		gauges:     map[string]metric.Float64Gauge{},
+	}, nil
+}
+
+func newOTLPMetricExporter(ctx context.Context, conf OTLPSinkConf) (sdkmetric.Exporter, error) {
+	if conf.Endpoint == "" {
+		return nil, errors.New("the OTLP sink needs an endpoint")
+	}
+
+	switch conf.Exporter {
+	case "", "grpc":
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(conf.Endpoint),
+			otlpmetricgrpc.WithHeaders(conf.Headers),
+		}
+		if conf.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	case "http":
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpointURL(conf.Endpoint),
+			otlpmetrichttp.WithHeaders(conf.Headers),
+		}
+		if conf.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	return nil, fmt.Errorf("unknown OTLP exporter %q, must be grpc or http", conf.Exporter)
+}
+
+// Shutdown exports the metrics not exported yet and stops the export.
+func (s *OTLPSink) Shutdown(ctx context.Context) error {
+	return s.provider.Shutdown(ctx)
+}
+
+func (s *OTLPSink) EmitEvent(job string, event string, kvs map[string]string) {
+	s.count(sinkMetricName(s.prefix, event), job, kvs)
+}
+
+func (s *OTLPSink) EmitEventErr(job string, event string, err error, kvs map[string]string) {
+	s.count(sinkMetricName(s.prefix, event, "error"), job, kvs)
+}
+
+func (s *OTLPSink) EmitTiming(job string, event string, nanos int64, kvs map[string]string) {
+	s.record(sinkMetricName(s.prefix, event), nanos, job, kvs)
+}
+
+func (s *OTLPSink) EmitGauge(job string, event string, value float64, kvs map[string]string) {
+	name := sinkMetricName(s.prefix, event)
+
+	s.mu.Lock()
+	gauge, ok := s.gauges[name]
+	if !ok {
+		var err error
+		if gauge, err = s.meter.Float64Gauge(name); err != nil {
+			s.mu.Unlock()
+			log.Error("Couldn't create the ", name, " OTLP gauge: ", err)
+			return
+		}
+		s.gauges[name] = gauge
+	}
+	s.mu.Unlock()
+
+	gauge.Record(context.Background(), value, s.attributes(job, kvs))
+}
+
+func (s *OTLPSink) EmitComplete(job string, status health.CompletionStatus, nanos int64, kvs map[string]string) {
+	withStatus := map[string]string{"status": status.String()}
+	for k, v := range kvs {
+		withStatus[k] = v
+	}
+	s.record(sinkMetricName(s.prefix, "job_complete"), nanos, job, withStatus)
+}
+
+func (s *OTLPSink) count(name, job string, kvs map[string]string) {
+	s.mu.Lock()
+	counter, ok := s.counters[name]
+	if !ok {
+		var err error
+		if counter, err = s.meter.Int64Counter(name); err != nil {
+			s.mu.Unlock()
+			log.Error("Couldn't create the ", name, " OTLP counter: ", err)
+			return
+		}
+		s.counters[name] = counter
+	}
+	s.mu.Unlock()
+
+	counter.Add(context.Background(), 1, s.attributes(job, kvs))
+}
+
+func (s *OTLPSink) record(name string, nanos int64, job string, kvs map[string]string) {
+	s.mu.Lock()
+	histogram, ok := s.histograms[name]
+	if !ok {
+		var err error
+		if histogram, err = s.meter.Float64Histogram(name, metric.WithUnit("ms")); err != nil {
+			s.mu.Unlock()
+			log.Error("Couldn't create the ", name, " OTLP histogram: ", err)
+			return
+		}
+		s.histograms[name] = histogram
+	}
+	s.mu.Unlock()
+
+	histogram.Record(context.Background(), float64(nanos)/float64(time.Millisecond), s.attributes(job, kvs))
+}
+
+func (s *OTLPSink) attributes(job string, kvs map[string]string) metric.MeasurementOption {
+	tags := sinkTags(s.tags, job, kvs)
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for k, v := range tags {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	return metric.WithAttributes(attrs...)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocraft/health"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestSinkMetricName(t *testing.T) {
+	tcs := []struct {
+		testName string
+		prefix   string
+		parts    []string
+		expected string
+	}{
+		{testName: "no prefix", prefix: "", parts: []string{"record"}, expected: "record"},
+		{testName: "prefix", prefix: "tyk_pump", parts: []string{"record", "error"}, expected: "tyk_pump.record.error"},
+		{testName: "sanitized", prefix: "tyk", parts: []string{"purge_time_Mongo Pump"}, expected: "tyk.purge_time_Mongo_Pump"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, sinkMetricName(tc.prefix, tc.parts...))
+		})
+	}
+}
+
+func TestJSONLogSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := NewJSONLogSink(buf, JSONLogSinkConf{Prefix: "tyk", Tags: map[string]string{"env": "prod", "host": "default"}})
+
+	stream := health.NewStream()
+	stream.AddSink(sink)
+	job := stream.NewJob("PurgeJob")
+	job.EventKv("record", health.Kvs{"host": "pump"})
+	job.EventErr("write", errors.New("timeout"))
+	job.Timing("purge_time", int64(25*time.Millisecond))
+	job.Gauge("backlog", 3)
+	job.Complete(health.Success)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 5)
+
+	metrics := make([]jsonLogMetric, len(lines))
+	for i, line := range lines {
+		assert.NoError(t, json.Unmarshal([]byte(line), &metrics[i]))
+	}
+
+	assert.Equal(t, "counter", metrics[0].Kind)
+	assert.Equal(t, "tyk.record", metrics[0].Name)
+	assert.Equal(t, map[string]string{"env": "prod", "host": "pump", "job": "PurgeJob"}, metrics[0].Tags, "the key values of the job take precedence")
+	assert.Equal(t, "tyk.write.error", metrics[1].Name)
+	assert.Equal(t, "timeout", metrics[1].Error)
+	assert.Equal(t, "timing_ms", metrics[2].Kind)
+	assert.Equal(t, 25.0, metrics[2].Value)
+	assert.Equal(t, "gauge", metrics[3].Kind)
+	assert.Equal(t, 3.0, metrics[3].Value)
+	assert.Equal(t, "tyk.job_complete", metrics[4].Name)
+	assert.Equal(t, "success", metrics[4].Tags["status"])
+}
+
+func TestDogStatsDSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewDogStatsDSink(DogStatsDSinkConf{Address: conn.LocalAddr().String(), Prefix: "tyk", Tags: map[string]string{"env": "prod"}})
+	assert.NoError(t, err)
+
+	sink.EmitEvent("PurgeJob", "record", nil)
+	sink.EmitGauge("GCActivity", "pauses_quantile_max", 2, map[string]string{"host": "pump"})
+	assert.NoError(t, sink.Close())
+
+	// The client may send the metrics in several packets.
+	payload := ""
+	buf := make([]byte, 1024)
+	for !strings.Contains(payload, "|g") || !strings.Contains(payload, "|c") {
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		if !assert.NoError(t, err) {
+			break
+		}
+		payload += string(buf[:n])
+	}
+	assert.Contains(t, payload, "tyk.record:1|c|#env:prod,job:PurgeJob")
+	assert.Contains(t, payload, "tyk.pauses_quantile_max:2|g|#env:prod,host:pump,job:GCActivity")
+}
+
+func TestOTLPSink(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	sink, err := newOTLPSink(reader, OTLPSinkConf{Prefix: "tyk", Tags: map[string]string{"env": "prod"}})
+	assert.NoError(t, err)
+	defer sink.Shutdown(context.Background())
+
+	sink.EmitEvent("PurgeJob", "record", nil)
+	sink.EmitEvent("PurgeJob", "record", nil)
+	sink.EmitTiming("PurgeJob", "purge_time", int64(10*time.Millisecond), nil)
+	sink.EmitGauge("GCActivity", "pauses_quantile_max", 2, nil)
+
+	rm := metricdata.ResourceMetrics{}
+	assert.NoError(t, reader.Collect(context.Background(), &rm))
+	assert.Len(t, rm.ScopeMetrics, 1)
+
+	collected := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		collected[m.Name] = m
+	}
+
+	counter := collected["tyk.record"].Data.(metricdata.Sum[int64])
+	assert.Equal(t, int64(2), counter.DataPoints[0].Value)
+	env, _ := counter.DataPoints[0].Attributes.Value(attribute.Key("env"))
+	assert.Equal(t, "prod", env.AsString())
+	job, _ := counter.DataPoints[0].Attributes.Value(attribute.Key("job"))
+	assert.Equal(t, "PurgeJob", job.AsString())
+
+	histogram := collected["tyk.purge_time"].Data.(metricdata.Histogram[float64])
+	assert.Equal(t, 10.0, histogram.DataPoints[0].Sum)
+	assert.Equal(t, "ms", collected["tyk.purge_time"].Unit)
+
+	gauge := collected["tyk.pauses_quantile_max"].Data.(metricdata.Gauge[float64])
+	assert.Equal(t, 2.0, gauge.DataPoints[0].Value)
+}
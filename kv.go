@@ -30,6 +30,9 @@ func (a kvLogger) Error(msg string, fields map[string]any) { a.l.WithFields(fiel
 type kvStores struct {
 	registry *registry.Registry
 	resolver resolver.Resolver
+	// pumps are the pump configurations as they were before their references were
+	// resolved, for them to be resolved again - see kvRefresher.
+	pumps map[string]PumpConfig
 }
 
 // Resolver returns the resolver to dereference references against, or nil when there
@@ -77,6 +80,13 @@ func resolveKVReferences(ctx context.Context, config *TykPumpConfiguration) (*kv
 		return nil, nil
 	}
 
+	var unresolved struct {
+		Pumps map[string]PumpConfig `json:"pumps"`
+	}
+	if err := json.Unmarshal(marshaledBytes, &unresolved); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
 	reg, err := registry.NewFromConfig(
 		ctx,
 		marshaledBytes,
@@ -87,7 +97,7 @@ func resolveKVReferences(ctx context.Context, config *TykPumpConfiguration) (*kv
 		return nil, fmt.Errorf("initialize KV registry: %w", err)
 	}
 
-	stores := &kvStores{registry: reg, resolver: resolver.NewResolver(reg)}
+	stores := &kvStores{registry: reg, resolver: resolver.NewResolver(reg), pumps: unresolved.Pumps}
 
 	resolvedBytes, err := stores.resolver.ResolveAll(ctx, marshaledBytes)
 	if err != nil {
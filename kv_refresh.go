@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/sirupsen/logrus"
+)
+
+// kvRefresher resolves the KV references of the pump configurations again, periodically,
+// and hands the pumps implementing pumps.CredentialsRefresher their configuration when it
+// changed, for them to pick up rotated credentials.
+type kvRefresher struct {
+	stores   *kvStores
+	interval time.Duration
+	// confs are the configurations the pumps run with, by pump.
+	confs map[pumps.Pump]any
+}
+
+// newKVRefresher returns the refresher of the pumps resolving their references against
+// stores every interval seconds, or nil when there's nothing to refresh: no interval or
+// no stores.
+func newKVRefresher(stores *kvStores, interval int) *kvRefresher {
+	if interval <= 0 || stores == nil {
+		return nil
+	}
+
+	return &kvRefresher{stores: stores, interval: time.Duration(interval) * time.Second}
+}
+
+// run refreshes the credentials of the pumps every interval until ctx is done. The stores
+// must stay open until it returns.
+func (r *kvRefresher) run(ctx context.Context) {
+	pumps.SetKVResolver(r.stores.Resolver())
+	defer pumps.SetKVResolver(nil)
+
+	r.snapshot()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// snapshot records the configurations the pumps were initialised with.
+func (r *kvRefresher) snapshot() {
+	r.confs = map[pumps.Pump]any{}
+	for _, pmp := range Pumps {
+		if _, ok := pmp.(pumps.CredentialsRefresher); !ok {
+			continue
+		}
+
+		name := getPumpState(pmp).name
+		conf, _, err := pumps.EffectiveMeta(pumpType(name), SystemConfig.Pumps[name].Meta)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Couldn't load the configuration of pump ", name, ", its credentials won't be refreshed: ", err)
+			continue
+		}
+		r.confs[pmp] = conf
+	}
+}
+
+// refresh resolves the configurations of the pumps again, and refreshes the credentials
+// of the ones whose configuration changed. A pump failing to refresh them keeps running
+// with its current ones, and is refreshed again next time.
+func (r *kvRefresher) refresh(ctx context.Context) {
+	for pmp, current := range r.confs {
+		state := getPumpState(pmp)
+		pumpLog := log.WithFields(logrus.Fields{
+			"prefix":    mainPrefix,
+			"pump_name": state.name,
+		})
+
+		conf, err := r.resolve(ctx, state.name)
+		if err != nil {
+			pumpLog.Error("Couldn't resolve the KV references of the pump again: ", err)
+			continue
+		}
+		if reflect.DeepEqual(conf, current) {
+			continue
+		}
+
+		if !lockForRefresh(ctx, &state.inUse) {
+			pumpLog.Warning("The pump is still busy writing, its credentials will be refreshed next time")
+			continue
+		}
+		err = pmp.(pumps.CredentialsRefresher).RefreshCredentials(ctx, conf)
+		state.inUse.Unlock()
+		if err != nil {
+			pumpLog.Error("Couldn't refresh the credentials of the pump: ", err)
+			continue
+		}
+
+		r.confs[pmp] = conf
+		pumpLog.Info("Refreshed the configuration of the pump")
+	}
+}
+
+// refreshLockWait is how long a refresh waits for a pump to be done with its writes before
+// leaving it for the next refresh, and refreshLockPoll how often it checks.
+var (
+	refreshLockWait = 5 * time.Second
+	refreshLockPoll = 50 * time.Millisecond
+)
+
+// lockForRefresh locks mu once nothing holds it read locked, and returns true. It tries
+// again every refreshLockPoll rather than waiting on the lock, so the writes and health
+// checks of the pump aren't held behind it, and gives up after refreshLockWait or once
+// ctx is done. A write carrying on past its timeout keeps the pump from being refreshed.
+func lockForRefresh(ctx context.Context, mu *sync.RWMutex) bool {
+	if mu.TryLock() {
+		return true
+	}
+
+	timeout := time.NewTimer(refreshLockWait)
+	defer timeout.Stop()
+	ticker := time.NewTicker(refreshLockPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timeout.C:
+			return false
+		case <-ticker.C:
+			if mu.TryLock() {
+				return true
+			}
+		}
+	}
+}
+
+// resolve returns the configuration of the pump called name, its KV references resolved
+// again.
+func (r *kvRefresher) resolve(ctx context.Context, name string) (any, error) {
+	raw, err := json.Marshal(r.stores.pumps[name].Meta)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := r.stores.resolver.ResolveAll(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta map[string]interface{}
+	if err := json.Unmarshal(resolved, &meta); err != nil {
+		return nil, err
+	}
+
+	conf, _, err := pumps.EffectiveMeta(pumpType(name), meta)
+	return conf, err
+}
+
+// pumpType returns the type of the pump called name.
+func pumpType(name string) string {
+	if pumpType := SystemConfig.Pumps[name].Type; pumpType != "" {
+		return pumpType
+	}
+	return name
+}
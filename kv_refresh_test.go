@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapResolver struct {
+	values map[string]string
+	err    error
+}
+
+func (m *mapResolver) Resolve(_ context.Context, input string) (string, error) {
+	return m.substitute(input), m.err
+}
+
+func (m *mapResolver) ResolveAll(_ context.Context, rawJSON []byte) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []byte(m.substitute(string(rawJSON))), nil
+}
+
+func (m *mapResolver) substitute(in string) string {
+	for ref, value := range m.values {
+		in = strings.ReplaceAll(in, ref, value)
+	}
+	return in
+}
+
+type refreshingPump struct {
+	confs []any
+	err   error
+	pumps.CommonPumpConfig
+}
+
+func (p *refreshingPump) New() pumps.Pump { return &refreshingPump{} }
+
+func (p *refreshingPump) GetName() string { return "Refreshing Pump" }
+
+func (p *refreshingPump) Init(interface{}) error { return nil }
+
+func (p *refreshingPump) WriteData(context.Context, []interface{}) error { return nil }
+
+func (p *refreshingPump) RefreshCredentials(_ context.Context, conf any) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.confs = append(p.confs, conf)
+	return nil
+}
+
+func TestNewKVRefresher(t *testing.T) {
+	assert.Nil(t, newKVRefresher(&kvStores{}, 0), "no interval")
+	assert.Nil(t, newKVRefresher(nil, 60), "no stores")
+	assert.NotNil(t, newKVRefresher(&kvStores{}, 60))
+}
+
+func TestKVRefresher(t *testing.T) {
+	origPumps := Pumps
+	origConfig := SystemConfig
+	t.Cleanup(func() {
+		Pumps = origPumps
+		SystemConfig = origConfig
+	})
+
+	const tokenRef = "kv://vault/splunk#token"
+	resolver := &mapResolver{values: map[string]string{tokenRef: "old-token"}}
+	stores := &kvStores{
+		resolver: resolver,
+		pumps: map[string]PumpConfig{
+			"SPLUNK": {Type: "splunk", Meta: map[string]interface{}{"collector_token": tokenRef, "collector_url": "http://splunk:8088"}},
+		},
+	}
+	SystemConfig = TykPumpConfiguration{
+		Pumps: map[string]PumpConfig{
+			"SPLUNK": {Type: "splunk", Meta: map[string]interface{}{"collector_token": "old-token", "collector_url": "http://splunk:8088"}},
+			"CSV":    {Type: "csv", Meta: map[string]interface{}{"csv_dir": "./"}},
+		},
+	}
+
+	splunk := &refreshingPump{}
+	csv := &recordingPump{rec: &initRecord{}}
+	Pumps = []pumps.Pump{splunk, csv}
+	setPumpState(splunk, "SPLUNK", pumps.CircuitBreakerConf{})
+	setPumpState(csv, "CSV", pumps.CircuitBreakerConf{})
+
+	pumps.SetKVResolver(resolver)
+	t.Cleanup(func() { pumps.SetKVResolver(nil) })
+
+	refresher := newKVRefresher(stores, 60)
+	refresher.snapshot()
+	assert.Len(t, refresher.confs, 1, "only the pumps able to refresh their credentials are tracked")
+
+	refresher.refresh(context.Background())
+	assert.Empty(t, splunk.confs, "the configuration didn't change")
+
+	resolver.values[tokenRef] = "new-token"
+	refresher.refresh(context.Background())
+	require.Len(t, splunk.confs, 1)
+	conf, ok := splunk.confs[0].(*pumps.SplunkPumpConfig)
+	require.True(t, ok)
+	assert.Equal(t, "new-token", conf.CollectorToken)
+	assert.Equal(t, "http://splunk:8088", conf.CollectorURL)
+
+	refresher.refresh(context.Background())
+	assert.Len(t, splunk.confs, 1, "the pump runs with the configuration already")
+
+	resolver.values[tokenRef] = "newer-token"
+	splunk.err = errors.New("splunk unreachable")
+	refresher.refresh(context.Background())
+	assert.Len(t, splunk.confs, 1)
+
+	splunk.err = nil
+	refresher.refresh(context.Background())
+	require.Len(t, splunk.confs, 2, "a failed refresh is tried again")
+	assert.Equal(t, "newer-token", splunk.confs[1].(*pumps.SplunkPumpConfig).CollectorToken)
+
+	resolver.values[tokenRef] = "newest-token"
+	resolver.err = errors.New("vault unreachable")
+	refresher.refresh(context.Background())
+	assert.Len(t, splunk.confs, 2, "the pump keeps its credentials when the references can't be resolved")
+}
+
+func TestLockForRefresh(t *testing.T) {
+	origWait := refreshLockWait
+	t.Cleanup(func() { refreshLockWait = origWait })
+	refreshLockWait = 200 * time.Millisecond
+
+	var mu sync.RWMutex
+	assert.True(t, lockForRefresh(context.Background(), &mu))
+	mu.Unlock()
+
+	// A write still running holds the lock past the wait.
+	mu.RLock()
+	done := make(chan bool)
+	go func() { done <- lockForRefresh(context.Background(), &mu) }()
+
+	read := make(chan struct{})
+	go func() {
+		mu.RLock()
+		mu.RUnlock()
+		close(read)
+	}()
+	select {
+	case <-read:
+	case <-time.After(time.Second):
+		t.Fatal("the writes mustn't wait behind a refresh")
+	}
+	assert.False(t, <-done, "the refresh gives up")
+
+	go func() { done <- lockForRefresh(context.Background(), &mu) }()
+	mu.RUnlock()
+	assert.True(t, <-done, "the refresh goes ahead once the write is done")
+	mu.Unlock()
+}
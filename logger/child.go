@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// NewChildLogger returns a logger with a level of its own, starting at the level of the
+// Pump's logger, writing through the output and the formatter of the Pump's logger. It lets
+// a component log more, or less, than the rest of the Pump.
+func NewChildLogger() *logrus.Logger {
+	child := logrus.New()
+	child.Out = parentWriter{}
+	child.Formatter = parentFormatter{}
+	child.ReportCaller = log.ReportCaller
+	child.ExitFunc = log.ExitFunc
+	child.SetLevel(log.GetLevel())
+
+	for level, hooks := range log.Hooks {
+		child.Hooks[level] = append([]logrus.Hook{}, hooks...)
+	}
+
+	return child
+}
+
+// parentWriter writes to the output of the Pump's logger, even when it's changed after the
+// child logger is created.
+type parentWriter struct{}
+
+func (parentWriter) Write(p []byte) (int, error) {
+	return log.Out.Write(p)
+}
+
+// parentFormatter formats with the formatter of the Pump's logger, even when it's changed
+// after the child logger is created.
+type parentFormatter struct{}
+
+func (parentFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return log.Formatter.Format(entry)
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChildLogger(t *testing.T) {
+	origOut, origLevel := log.Out, log.GetLevel()
+	t.Cleanup(func() {
+		log.Out = origOut
+		log.SetLevel(origLevel)
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	child := NewChildLogger()
+	assert.Equal(t, logrus.InfoLevel, child.GetLevel(), "the child starts at the level of the Pump")
+
+	buf := &bytes.Buffer{}
+	log.Out = buf
+	child.SetLevel(logrus.DebugLevel)
+
+	child.WithField("prefix", "child").Debug("child debug")
+	log.Debug("parent debug")
+
+	assert.Contains(t, buf.String(), "child debug", "the child writes to the output of the Pump, even once changed")
+	assert.Contains(t, buf.String(), "prefix=child", "the child formats with the formatter of the Pump")
+	assert.NotContains(t, buf.String(), "parent debug", "the level of the child doesn't change the one of the Pump")
+	assert.Equal(t, logrus.InfoLevel, log.GetLevel())
+}
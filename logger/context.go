@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"context"
+)
+
+type batchIDKey struct{}
+
+// WithBatchID returns ctx carrying id, the ID of the batch of records being written. The
+// lines the pumps log with ctx carry it in their `batch_id` field.
+func WithBatchID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, batchIDKey{}, id)
+}
+
+// BatchID returns the ID of the batch of records ctx carries, or "" if it carries none.
+func BatchID(ctx context.Context) string {
+	id, _ := ctx.Value(batchIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"errors"
+	"io"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileConfig configures a log file written besides the usual output, rotated by size.
+type FileConfig struct {
+	// Writes the logs to the file too.
+	Enabled bool `json:"enabled"`
+	// The path of the log file.
+	Path string `json:"path"`
+	// The size in megabytes the file is rotated at. Defaults to 100.
+	MaxSize int `json:"max_size"`
+	// The number of rotated files kept. Defaults to 0, keeping them all.
+	MaxBackups int `json:"max_backups"`
+	// The number of days the rotated files are kept. Defaults to 0, keeping them forever.
+	MaxAge int `json:"max_age"`
+	// Compresses the rotated files with gzip.
+	Compress bool `json:"compress"`
+}
+
+// SetupFile writes the logs to the file configured in conf as well as to the current output.
+// It returns the file, to be closed on shutdown, or nil when it isn't enabled.
+func SetupFile(conf FileConfig) (io.Closer, error) {
+	if !conf.Enabled {
+		return nil, nil
+	}
+	if conf.Path == "" {
+		return nil, errors.New("the log file needs a path")
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   conf.Path,
+		MaxSize:    conf.MaxSize,
+		MaxBackups: conf.MaxBackups,
+		MaxAge:     conf.MaxAge,
+		Compress:   conf.Compress,
+	}
+	log.SetOutput(io.MultiWriter(log.Out, file))
+
+	return file, nil
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupFile(t *testing.T) {
+	origOut := log.Out
+	t.Cleanup(func() { log.Out = origOut })
+
+	file, err := SetupFile(FileConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, file, "the file is disabled by default")
+
+	_, err = SetupFile(FileConfig{Enabled: true})
+	assert.Error(t, err, "the file needs a path")
+
+	buf := &bytes.Buffer{}
+	log.Out = buf
+	path := filepath.Join(t.TempDir(), "pump.log")
+	file, err = SetupFile(FileConfig{Enabled: true, Path: path})
+	assert.NoError(t, err)
+
+	log.WithField("prefix", "main").Info("to both")
+	assert.NoError(t, file.Close())
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "to both")
+	assert.Contains(t, buf.String(), "to both", "the logs still go to the previous output")
+}
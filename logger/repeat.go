@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxRepeatKeys bounds the number of distinct messages remembered to spot the repeated ones.
+const maxRepeatKeys = 1000
+
+// LimitRepeats logs the warnings and errors identical to one logged less than interval ago
+// only once: the next one logged after the interval carries the number of the ones dropped
+// in the `repeated` field. It's a no-op when interval isn't positive.
+func LimitRepeats(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	log.SetFormatter(newRepeatLimiter(log.Formatter, interval))
+}
+
+type repeatLimiter struct {
+	logrus.Formatter
+	interval time.Duration
+
+	mu   sync.Mutex
+	seen map[string]*repeat
+}
+
+type repeat struct {
+	last    time.Time
+	dropped int
+}
+
+func newRepeatLimiter(formatter logrus.Formatter, interval time.Duration) *repeatLimiter {
+	if limiter, ok := formatter.(*repeatLimiter); ok {
+		formatter = limiter.Formatter
+	}
+
+	return &repeatLimiter{Formatter: formatter, interval: interval, seen: map[string]*repeat{}}
+}
+
+func (r *repeatLimiter) Format(entry *logrus.Entry) ([]byte, error) {
+	// Fatal and panic lines are never dropped.
+	if entry.Level != logrus.ErrorLevel && entry.Level != logrus.WarnLevel {
+		return r.Formatter.Format(entry)
+	}
+
+	key := fmt.Sprint(entry.Level, "|", entry.Data["prefix"], "|", entry.Data["pump_name"], "|", entry.Message)
+
+	r.mu.Lock()
+	seen, ok := r.seen[key]
+	if ok && entry.Time.Sub(seen.last) < r.interval {
+		seen.dropped++
+		r.mu.Unlock()
+		return nil, nil
+	}
+
+	dropped := 0
+	if ok {
+		dropped = seen.dropped
+	}
+	if !ok && len(r.seen) >= maxRepeatKeys {
+		r.prune(entry.Time)
+	}
+	r.seen[key] = &repeat{last: entry.Time}
+	r.mu.Unlock()
+
+	if dropped > 0 {
+		entry.Data["repeated"] = dropped
+	}
+
+	return r.Formatter.Format(entry)
+}
+
+// prune forgets the messages last logged more than an interval ago, or every message when
+// they're all recent.
+func (r *repeatLimiter) prune(now time.Time) {
+	for key, seen := range r.seen {
+		if now.Sub(seen.last) >= r.interval {
+			delete(r.seen, key)
+		}
+	}
+
+	if len(r.seen) >= maxRepeatKeys {
+		r.seen = map[string]*repeat{}
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeatLimiter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := logrus.New()
+	l.Out = buf
+	l.Formatter = newRepeatLimiter(&logrus.TextFormatter{DisableColors: true, DisableTimestamp: true}, time.Minute)
+
+	start := time.Now()
+	logAt := func(at time.Duration, level logrus.Level, msg string) {
+		l.WithTime(start.Add(at)).WithField("prefix", "kafka-pump").Log(level, msg)
+	}
+
+	logAt(0, logrus.ErrorLevel, "broker down")
+	logAt(time.Second, logrus.ErrorLevel, "broker down")
+	logAt(2*time.Second, logrus.ErrorLevel, "broker down")
+	logAt(3*time.Second, logrus.WarnLevel, "broker down")
+	logAt(4*time.Second, logrus.InfoLevel, "purged")
+	logAt(5*time.Second, logrus.InfoLevel, "purged")
+	logAt(time.Minute+time.Second, logrus.ErrorLevel, "broker down")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, []string{
+		`level=error msg="broker down" prefix=kafka-pump`,
+		`level=warning msg="broker down" prefix=kafka-pump`,
+		`level=info msg=purged prefix=kafka-pump`,
+		`level=info msg=purged prefix=kafka-pump`,
+		`level=error msg="broker down" prefix=kafka-pump repeated=2`,
+	}, lines)
+}
+
+func TestLimitRepeats(t *testing.T) {
+	origFormatter := log.Formatter
+	t.Cleanup(func() { log.Formatter = origFormatter })
+
+	LimitRepeats(0)
+	assert.Same(t, origFormatter, log.Formatter, "a zero interval doesn't limit the repeats")
+
+	LimitRepeats(time.Minute)
+	LimitRepeats(time.Second)
+	limiter, ok := log.Formatter.(*repeatLimiter)
+	assert.True(t, ok)
+	assert.Same(t, origFormatter, limiter.Formatter, "the limiters don't stack")
+	assert.Equal(t, time.Second, limiter.interval)
+}
@@ -3,23 +3,34 @@ package main
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	"github.com/TykTechnologies/tyk-pump/analytics/demo"
 	logger "github.com/TykTechnologies/tyk-pump/logger"
+	"github.com/TykTechnologies/tyk-pump/metrics"
 	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/quarantine"
 	"github.com/TykTechnologies/tyk-pump/serializer"
 	"github.com/TykTechnologies/tyk-pump/server"
 	"github.com/TykTechnologies/tyk-pump/storage"
+	"github.com/TykTechnologies/tyk-pump/tail"
+	"github.com/TykTechnologies/tyk-pump/tracing"
 	"github.com/gocraft/health"
+	"github.com/gofrs/uuid"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -29,7 +40,14 @@ var (
 	UptimeStorage        storage.AnalyticsStorage
 	Pumps                []pumps.Pump
 	UptimePump           pumps.UptimePump
+	UptimePumps          []pumps.UptimeDataPump
 	AnalyticsSerializers []serializer.AnalyticsSerializer
+	Quarantine           quarantine.Store
+	// TailHub streams the records written to the /tail clients. It's nil when the tail is
+	// disabled.
+	TailHub *tail.Hub
+	// logFile is the log file, nil when the logs aren't written to a file.
+	logFile io.Closer
 )
 
 var log = logger.GetLogger()
@@ -52,6 +70,20 @@ var (
 	version = kingpin.Version(pumps.Version)
 )
 
+var (
+	//lint:ignore U1000 Command is run when no other command is passed in command line
+	runCmd             = kingpin.Command("run", "run the pump").Default()
+	quarantineCmd      = kingpin.Command("quarantine", "manage the analytics payloads that couldn't be decoded")
+	quarantineRetryCmd = quarantineCmd.Command("retry", "decode the quarantined payloads again and write them to the pumps that didn't write them yet")
+	validateCmd        = kingpin.Command("validate", "check the configuration file strictly and exit, with a non-zero status if it has problems")
+	schemaCmd          = kingpin.Command("schema", "print the JSON Schema of the configuration file and exit")
+	configCmd          = kingpin.Command("config", "inspect the configuration")
+	configDumpCmd      = configCmd.Command("dump", "print the effective configuration, every pump included, with where each setting comes from, and exit")
+)
+
+// command is the command line command Init parsed.
+var command string
+
 func showDecodeDeprecationWarnings() {
 	if SystemConfig.DecodeRawRequest {
 		log.WithFields(logrus.Fields{
@@ -72,12 +104,22 @@ func showDecodeDeprecationWarnings() {
 func Init() *kvStores {
 	SystemConfig = TykPumpConfiguration{}
 
-	kingpin.Parse()
+	command = kingpin.Parse()
+	if command == validateCmd.FullCommand() {
+		os.Exit(runValidate(*conf, os.Stdout))
+	}
+	if command == schemaCmd.FullCommand() {
+		os.Exit(runSchema(os.Stdout))
+	}
+	if command == configDumpCmd.FullCommand() {
+		os.Exit(runConfigDump(*conf, os.Stdout))
+	}
 	kvStores := LoadConfig(conf, &SystemConfig)
 
 	showDecodeDeprecationWarnings()
 
 	logger.SetupFormatter(SystemConfig.LogFormat, logger.EnvTykLogformat)
+	setupLogOutput()
 
 	envDemo := os.Getenv("TYK_PMP_BUILDDEMODATA")
 	if envDemo != "" {
@@ -86,7 +128,13 @@ func Init() *kvStores {
 	}
 
 	// Serializer init
-	AnalyticsSerializers = []serializer.AnalyticsSerializer{serializer.NewAnalyticsSerializer(serializer.MSGP_SERIALIZER), serializer.NewAnalyticsSerializer(serializer.PROTOBUF_SERIALIZER)}
+	var err error
+	AnalyticsSerializers, err = serializer.NewAnalyticsSerializers(SystemConfig.AnalyticsSerializers)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Fatal("Invalid analytics_serializers: ", err)
+	}
 
 	log.WithFields(logrus.Fields{
 		"prefix": mainPrefix,
@@ -119,6 +167,18 @@ func Init() *kvStores {
 	return kvStores
 }
 
+// setupLogOutput sets up the log file and the limit on repeated log lines.
+func setupLogOutput() {
+	var err error
+	if logFile, err = logger.SetupFile(SystemConfig.LogFile); err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Fatal("Error setting up the log file: ", err)
+	}
+
+	logger.LimitRepeats(time.Duration(SystemConfig.LogRepeatInterval) * time.Second)
+}
+
 func setupAnalyticsStore() {
 	switch SystemConfig.AnalyticsStorageType {
 	case "redis", "":
@@ -192,12 +252,14 @@ func storeVersion() {
 // Each pump applies its own env var overrides while it initialises, which can introduce
 // KV references the config-wide resolution never saw. kvStores is what those resolve
 // against, and it is only reachable to the pumps for the duration of this call - after
-// it, nothing dereferences a reference again.
+// it, only the kvRefresher dereferences a reference again, when the references are
+// refreshed.
 func initialisePumps(kvStores *kvStores) {
 	pumps.SetKVResolver(kvStores.Resolver())
 	defer pumps.SetKVResolver(nil)
 
 	Pumps = []pumps.Pump{}
+	UptimePumps = []pumps.UptimeDataPump{}
 
 	for key, pmp := range SystemConfig.Pumps {
 		pumpTypeName := pmp.Type
@@ -219,6 +281,19 @@ func initialisePumps(kvStores *kvStores) {
 			thisPmp.SetIgnoreFields(pmp.IgnoreFields)
 			thisPmp.SetDecodingRequest(pmp.DecodeRawRequest)
 			thisPmp.SetDecodingResponse(pmp.DecodeRawResponse)
+			if logPmp, ok := thisPmp.(pumps.LogFieldsSetter); ok {
+				logPmp.SetLogFields(logrus.Fields{"pump_name": key, "pump_type": thisPmp.GetName()})
+			}
+			if pmp.LogLevel != "" {
+				level, err := logrus.ParseLevel(pmp.LogLevel)
+				if err != nil {
+					log.WithFields(logrus.Fields{
+						"prefix": mainPrefix,
+					}).Error("Invalid log level of pump ", key, ", keeping the level of the Pump: ", err)
+				} else {
+					thisPmp.SetLogLevel(level)
+				}
+			}
 			initErr := thisPmp.Init(pmp.Meta)
 			if initErr != nil {
 				log.WithField("pump", thisPmp.GetName()).Error("Pump init error (skipping): ", initErr)
@@ -227,6 +302,18 @@ func initialisePumps(kvStores *kvStores) {
 					"prefix": mainPrefix,
 				}).Info("Init Pump: ", key)
 				Pumps = append(Pumps, thisPmp)
+				setPumpState(thisPmp, key, pmp.CircuitBreaker)
+
+				if pmp.Uptime {
+					if uptimePmp, ok := thisPmp.(pumps.UptimeDataPump); ok {
+						uptimePmp.SetUptimeFilters(pmp.UptimeFilters)
+						UptimePumps = append(UptimePumps, uptimePmp)
+					} else {
+						log.WithFields(logrus.Fields{
+							"prefix": mainPrefix,
+						}).Warning("Pump ", key, " doesn't support uptime data, not writing it there")
+					}
+				}
 			}
 		}
 	}
@@ -248,6 +335,13 @@ func initialiseUptimePump() {
 	}).Info("'dont_purge_uptime_data' set to false, attempting to start Uptime pump! ")
 
 	switch SystemConfig.UptimePumpConfig.UptimeType {
+	case "none":
+		UptimePump = nil
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Info("Uptime data is only written to the pumps with uptime enabled")
+		return
+
 	case "sql":
 		UptimePump = &pumps.SQLPump{IsUptime: true}
 		UptimePump.Init(SystemConfig.UptimePumpConfig.SQLConf)
@@ -263,37 +357,69 @@ func initialiseUptimePump() {
 	}).Info("Init Uptime Pump: ", UptimePump.GetName())
 }
 
+// analyticsKeyNames returns the keys the Gateway may write analytics records to, before
+// the suffix of their serializer.
+func analyticsKeyNames() []string {
+	// tyk-system-analytics comes first to maintain backwards compatibility, and for when
+	// analytics_config.enable_multiple_analytics_keys is disabled in the gateway
+	keyNames := []string{storage.ANALYTICS_KEYNAME}
+	for i := 0; i < 10; i++ {
+		keyNames = append(keyNames, fmt.Sprintf("%v_%v", storage.ANALYTICS_KEYNAME, i))
+	}
+
+	return keyNames
+}
+
+// purgeRequests triggers a purge without waiting for the next tick of the purge loop.
+var purgeRequests = make(chan struct{}, 1)
+
+// serverShutdownTimeout bounds how long the health check server may take to complete the
+// requests in flight on shutdown.
+const serverShutdownTimeout = 5 * time.Second
+
 func StartPurgeLoop(wg *sync.WaitGroup, ctx context.Context, secInterval int, chunkSize int64, expire time.Duration, omitDetails bool) {
-	for range time.Tick(time.Duration(secInterval) * time.Second) {
+	ticker := time.NewTicker(time.Duration(secInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-purgeRequests:
+		}
 
 		job := instrument.NewJob("PumpRecordsPurge")
 		startTime := time.Now()
+		purgeFailed := false
+		cycleCtx, cycleSpan := tracing.Tracer().Start(context.Background(), "purge")
 
-		for i := -1; i < 10; i++ {
-			var analyticsKeyName string
-			if i == -1 {
-				// if it's the first iteration, we look for tyk-system-analytics to maintain backwards compatibility or if analytics_config.enable_multiple_analytics_keys is disabled in the gateway
-				analyticsKeyName = storage.ANALYTICS_KEYNAME
-			} else {
-				analyticsKeyName = fmt.Sprintf("%v_%v", storage.ANALYTICS_KEYNAME, i)
-			}
-
+		for _, analyticsKeyName := range analyticsKeyNames() {
 			for _, serializerMethod := range AnalyticsSerializers {
-				analyticsKeyName += serializerMethod.GetSuffix()
-				AnalyticsValues, err := AnalyticsStore.GetAndDeleteSet(analyticsKeyName, chunkSize, expire)
+				serializerKeyName := analyticsKeyName + serializerMethod.GetSuffix()
+				keyCtx, keySpan := tracing.Tracer().Start(cycleCtx, "purge_key", trace.WithAttributes(attribute.String("tyk.analytics_key", serializerKeyName)))
+
+				_, popSpan := tracing.Tracer().Start(keyCtx, "pop")
+				AnalyticsValues, err := AnalyticsStore.GetAndDeleteSet(serializerKeyName, chunkSize, expire)
+				popSpan.SetAttributes(attribute.Int("tyk.records", len(AnalyticsValues)))
+				endSpan(popSpan, err)
 				if err != nil {
+					purgeFailed = true
 					log.WithFields(logrus.Fields{
 						"prefix": mainPrefix,
 					}).Error("Error on Purge Loop. Is Temporal Storage down?: " + err.Error())
 				}
 				if len(AnalyticsValues) > 0 {
-					PreprocessAnalyticsValues(AnalyticsValues, serializerMethod, analyticsKeyName, omitDetails, job, startTime, secInterval)
+					metrics.RecordsPopped.WithLabelValues(serializerKeyName).Add(float64(len(AnalyticsValues)))
+					PreprocessAnalyticsValues(keyCtx, AnalyticsValues, serializerMethod, serializerKeyName, omitDetails, job, startTime, secInterval)
 				}
+				keySpan.End()
 			}
-
 		}
 
 		job.Timing("purge_time_all", time.Since(startTime).Nanoseconds())
+		metrics.PurgeDuration.Observe(time.Since(startTime).Seconds())
+		if !purgeFailed {
+			recordPurge(time.Now())
+		}
 
 		if !SystemConfig.DontPurgeUptimeData {
 			UptimeValues, err := UptimeStorage.GetAndDeleteSet(storage.UptimeAnalytics_KEYNAME, chunkSize, expire)
@@ -302,8 +428,12 @@ func StartPurgeLoop(wg *sync.WaitGroup, ctx context.Context, secInterval int, ch
 					"prefix": mainPrefix,
 				}).Error("Error on Purge Loop. Is Temporal Storage down?: " + err.Error())
 			}
-			UptimePump.WriteUptimeData(UptimeValues)
+			if UptimePump != nil {
+				UptimePump.WriteUptimeData(filterUptimeValues(SystemConfig.UptimePumpConfig.UptimeFilters, UptimeValues))
+			}
+			writeUptimeToPumps(UptimeValues, job)
 		}
+		cycleSpan.End()
 
 		if checkShutdown(ctx, wg) {
 			return
@@ -311,28 +441,64 @@ func StartPurgeLoop(wg *sync.WaitGroup, ctx context.Context, secInterval int, ch
 	}
 }
 
-func PreprocessAnalyticsValues(AnalyticsValues []interface{}, serializerMethod serializer.AnalyticsSerializer, analyticsKeyName string, omitDetails bool, job *health.Job, startTime time.Time, secInterval int) {
-	keys := make([]interface{}, len(AnalyticsValues))
+func PreprocessAnalyticsValues(ctx context.Context, AnalyticsValues []interface{}, serializerMethod serializer.AnalyticsSerializer, analyticsKeyName string, omitDetails bool, job *health.Job, startTime time.Time, secInterval int) {
+	_, decodeSpan := tracing.Tracer().Start(ctx, "decode")
+	keys := make([]interface{}, 0, len(AnalyticsValues))
+	quarantined := []quarantine.Entry{}
+	serializerName := serializer.Name(serializerMethod)
 
-	for i, v := range AnalyticsValues {
-		decoded := analytics.AnalyticsRecord{}
-		err := serializerMethod.Decode([]byte(v.(string)), &decoded)
-
-		log.WithFields(logrus.Fields{
-			"prefix": mainPrefix,
-		}).Debug("Decoded Record: ", decoded)
+	for _, v := range AnalyticsValues {
+		data := []byte(v.(string))
+		decoded, err := decodeAnalyticsValue(data, serializerMethod)
 		if err != nil {
-			log.WithFields(logrus.Fields{
-				"prefix":       mainPrefix,
-				"analytic_key": analyticsKeyName,
-			}).Error("Couldn't unmarshal analytics data:", err)
+			if errors.Is(err, analytics.ErrUnknownSchemaVersion) {
+				log.WithFields(logrus.Fields{
+					"prefix":       mainPrefix,
+					"analytic_key": analyticsKeyName,
+				}).Error("Skipping analytics record: ", err)
+				job.Event("record_unknown_schema")
+			} else {
+				log.WithFields(logrus.Fields{
+					"prefix":       mainPrefix,
+					"analytic_key": analyticsKeyName,
+				}).Error("Couldn't unmarshal analytics data:", err)
+			}
+			metrics.RecordsDecodeFailed.WithLabelValues(serializerName).Inc()
+			quarantined = append(quarantined, newQuarantineEntry(data, serializerMethod, analyticsKeyName, err))
 			continue
 		}
-		keys[i] = interface{}(decoded)
+		keys = append(keys, interface{}(decoded))
 		job.Event("record")
+		metrics.RecordsDecoded.WithLabelValues(serializerName).Inc()
 	}
+	quarantinePayloads(quarantined, job)
+	decodeSpan.SetAttributes(
+		attribute.String("tyk.serializer", serializerName),
+		attribute.Int("tyk.records", len(keys)),
+		attribute.Int("tyk.records_failed", len(quarantined)),
+	)
+	decodeSpan.End()
 	// Send to pumps
-	writeToPumps(keys, job, startTime, int(secInterval))
+	dispatchToPumps(ctx, keys, job, startTime, int(secInterval))
+}
+
+// decodeAnalyticsValue decodes a payload read from a key of serializerMethod, and
+// normalises it to the current record schema.
+func decodeAnalyticsValue(data []byte, serializerMethod serializer.AnalyticsSerializer) (analytics.AnalyticsRecord, error) {
+	decoded := analytics.AnalyticsRecord{}
+	// A key may be shared by producers using different formats, so each payload
+	// is decoded with whatever its header says, the key's serializer otherwise.
+	err := serializer.DetectSerializer(data, serializerMethod).Decode(data, &decoded)
+
+	log.WithFields(logrus.Fields{
+		"prefix": mainPrefix,
+	}).Debug("Decoded Record: ", decoded)
+	if err != nil {
+		return decoded, err
+	}
+	// Normalise records from older producers, and hold back the ones from producers
+	// newer than this Pump rather than writing them half-decoded.
+	return decoded, decoded.Upcast()
 }
 
 func checkShutdown(ctx context.Context, wg *sync.WaitGroup) bool {
@@ -361,19 +527,51 @@ func checkShutdown(ctx context.Context, wg *sync.WaitGroup) bool {
 }
 
 func writeToPumps(keys []interface{}, job *health.Job, startTime time.Time, purgeDelay int) {
+	dispatchToPumps(context.Background(), keys, job, startTime, purgeDelay)
+}
+
+// dispatchToPumps writes keys to every pump, the span of each write being a child of the
+// one carried by ctx. It returns the errors of the pumps that didn't write them.
+func dispatchToPumps(ctx context.Context, keys []interface{}, job *health.Job, startTime time.Time, purgeDelay int) error {
 	// Send to pumps
-	if Pumps != nil {
-		var wg sync.WaitGroup
-		wg.Add(len(Pumps))
-		for _, pmp := range Pumps {
-			go execPumpWriting(&wg, pmp, &keys, purgeDelay, startTime, job)
-		}
-		wg.Wait()
-	} else {
+	if Pumps == nil {
 		log.WithFields(logrus.Fields{
 			"prefix": mainPrefix,
 		}).Warning("No pumps defined!")
+		return errors.New("no pumps defined")
+	}
+
+	errs := dispatchEach(ctx, Pumps, func(int) []interface{} { return keys }, job, startTime, purgeDelay)
+	return errors.Join(errs...)
+}
+
+// dispatchEach writes keysOf(i) to pmps[i], to every pump at once, and returns the error
+// of each pump.
+func dispatchEach(ctx context.Context, pmps []pumps.Pump, keysOf func(i int) []interface{}, job *health.Job, startTime time.Time, purgeDelay int) []error {
+	ctx = withNewBatchID(ctx)
+	errs := make([]error, len(pmps))
+	var wg sync.WaitGroup
+	wg.Add(len(pmps))
+	for i, pmp := range pmps {
+		go func(i int, pmp pumps.Pump) {
+			defer wg.Done()
+			keys := keysOf(i)
+			errs[i] = execPumpWriting(ctx, pmp, &keys, purgeDelay, startTime, job)
+		}(i, pmp)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// withNewBatchID returns ctx with a new ID for the batch of records purged, logged with the
+// writes of the batch to correlate their lines.
+func withNewBatchID(ctx context.Context) context.Context {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return ctx
 	}
+	return logger.WithBatchID(ctx, id.String())
 }
 
 func filterData(pump pumps.Pump, keys []interface{}) []interface{} {
@@ -432,24 +630,46 @@ func filterData(pump pumps.Pump, keys []interface{}) []interface{} {
 	return filteredKeys
 }
 
-func execPumpWriting(wg *sync.WaitGroup, pmp pumps.Pump, keys *[]interface{}, purgeDelay int, startTime time.Time, job *health.Job) {
+// execPumpWriting writes keys to pmp, returning why they weren't written, skipped pumps
+// included.
+func execPumpWriting(parentCtx context.Context, pmp pumps.Pump, keys *[]interface{}, purgeDelay int, startTime time.Time, job *health.Job) error {
+	state := getPumpState(pmp)
+	batchID := logger.BatchID(parentCtx)
+	pumpLog := log.WithFields(logrus.Fields{
+		"prefix":    mainPrefix,
+		"pump_name": state.name,
+		"pump_type": pmp.GetName(),
+		"batch_id":  batchID,
+	})
+
 	timer := time.AfterFunc(time.Duration(purgeDelay)*time.Second, func() {
 		if pmp.GetTimeout() == 0 {
-			log.WithFields(logrus.Fields{
-				"prefix": mainPrefix,
-			}).Warning("Pump  ", pmp.GetName(), " is taking more time than the value configured of purge_delay. You should try to set a timeout for this pump.")
+			pumpLog.Warning("Pump  ", pmp.GetName(), " is taking more time than the value configured of purge_delay. You should try to set a timeout for this pump.")
 		} else if pmp.GetTimeout() > purgeDelay {
-			log.WithFields(logrus.Fields{
-				"prefix": mainPrefix,
-			}).Warning("Pump  ", pmp.GetName(), " is taking more time than the value configured of purge_delay. You should try lowering the timeout configured for this pump.")
+			pumpLog.Warning("Pump  ", pmp.GetName(), " is taking more time than the value configured of purge_delay. You should try lowering the timeout configured for this pump.")
 		}
 	})
 	defer timer.Stop()
-	defer wg.Done()
 
-	log.WithFields(logrus.Fields{
-		"prefix": mainPrefix,
-	}).Debug("Writing to: ", pmp.GetName())
+	if state.isPaused() {
+		pumpLog.Debug("Skipping ", pmp.GetName(), ": it's paused")
+		return fmt.Errorf("%s is paused", state.name)
+	}
+	if !state.stats.Allow() {
+		pumpLog.Warning("Skipping ", pmp.GetName(), ": its circuit breaker is open")
+		return fmt.Errorf("%s circuit breaker is open", state.name)
+	}
+
+	pumpLog.Debug("Writing to: ", pmp.GetName())
+
+	// The pump gets the span of its write in its context, for it to propagate to its backend.
+	spanCtx, span := tracing.Tracer().Start(parentCtx, "write", trace.WithAttributes(
+		attribute.String("tyk.pump", state.name),
+		attribute.String("tyk.pump_type", pmp.GetName()),
+		attribute.String("tyk.batch_id", batchID),
+	))
+	var writeErr error
+	defer func() { endSpan(span, writeErr) }()
 
 	ch := make(chan error, 1)
 	// Load pump timeout
@@ -458,46 +678,107 @@ func execPumpWriting(wg *sync.WaitGroup, pmp pumps.Pump, keys *[]interface{}, pu
 	var cancel context.CancelFunc
 	// Initialize context depending if the pump has a configured timeout
 	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+		ctx, cancel = context.WithTimeout(spanCtx, time.Duration(timeout)*time.Second)
 	} else {
-		ctx, cancel = context.WithCancel(context.Background())
+		ctx, cancel = context.WithCancel(spanCtx)
 	}
 
 	defer cancel()
 
+	// The number of records handed to the pump, once filtered, filtered telling they were.
+	var sent atomic.Int64
+	var filtered atomic.Bool
+	// The records handed to the pump, only read once it's answered on ch.
+	var filteredKeys []interface{}
+	writeStart := time.Now()
 	go func(ch chan error, ctx context.Context, pmp pumps.Pump, keys *[]interface{}) {
-		filteredKeys := filterData(pmp, *keys)
+		_, filterSpan := tracing.Tracer().Start(ctx, "filter")
+		filteredKeys = filterData(pmp, *keys)
+		filterSpan.SetAttributes(attribute.Int("tyk.records_filtered", len(*keys)-len(filteredKeys)))
+		filterSpan.End()
+
+		metrics.PumpRecordsFiltered.WithLabelValues(state.name).Add(float64(len(*keys) - len(filteredKeys)))
+		sent.Store(int64(len(filteredKeys)))
+		filtered.Store(true)
+		state.inUse.RLock()
+		defer state.inUse.RUnlock()
 		ch <- pmp.WriteData(ctx, filteredKeys)
 	}(ch, ctx, pmp, keys)
 
 	select {
 	case err := <-ch:
+		writeErr = err
+		state.stats.Record(err)
+		metrics.PumpWriteDuration.WithLabelValues(state.name).Observe(time.Since(writeStart).Seconds())
+		if err == nil {
+			metrics.PumpRecordsSent.WithLabelValues(state.name).Add(float64(sent.Load()))
+			// Only the records the pump has written are streamed.
+			TailHub.Publish(state.name, filteredKeys)
+		} else {
+			metrics.PumpRecordsFailed.WithLabelValues(state.name).Add(float64(sent.Load()))
+		}
 		if err != nil {
-			log.WithFields(logrus.Fields{
-				"prefix": mainPrefix,
-			}).Warning("Error Writing to: ", pmp.GetName(), " - Error:", err)
+			pumpLog.Warning("Error Writing to: ", pmp.GetName(), " - Error:", err)
 		}
 	case <-ctx.Done():
+		writeErr = ctx.Err()
+		state.stats.Record(ctx.Err())
+		metrics.PumpWriteDuration.WithLabelValues(state.name).Observe(time.Since(writeStart).Seconds())
+		failed := sent.Load()
+		if !filtered.Load() {
+			// The records weren't even filtered in time, so none of them was written.
+			failed = int64(len(*keys))
+		}
+		metrics.PumpRecordsFailed.WithLabelValues(state.name).Add(float64(failed))
 		switch ctx.Err() {
 		case context.Canceled:
-			log.WithFields(logrus.Fields{
-				"prefix": mainPrefix,
-			}).Warning("The writing to ", pmp.GetName(), " have got canceled.")
+			pumpLog.Warning("The writing to ", pmp.GetName(), " have got canceled.")
 		case context.DeadlineExceeded:
-			log.WithFields(logrus.Fields{
-				"prefix": mainPrefix,
-			}).Warning("Timeout Writing to: ", pmp.GetName())
+			pumpLog.Warning("Timeout Writing to: ", pmp.GetName())
 		}
 	}
+	span.SetAttributes(attribute.Int64("tyk.records", sent.Load()))
 	if job != nil {
 		job.Timing("purge_time_"+pmp.GetName(), time.Since(startTime).Nanoseconds())
 	}
+	if writeErr != nil {
+		return fmt.Errorf("%s: %w", state.name, writeErr)
+	}
+	return nil
+}
+
+// endSpan ends span, marking it as failed with err if it isn't nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
 
 func main() {
 	kvStores := Init()
 	SetupInstrumentation()
-	go server.ServeHealthCheck(SystemConfig.HealthCheckEndpointName, SystemConfig.HealthCheckEndpointPort, SystemConfig.HTTPProfile)
+	server.SetReporter(readinessReporter{conf: SystemConfig.Readiness, purgeDelay: SystemConfig.PurgeDelay})
+	if SystemConfig.AdminAPI.Enabled {
+		server.SetAdmin(pumpAdmin{}, SystemConfig.AdminAPI.Secret)
+	}
+	if SystemConfig.EnableMetrics {
+		server.SetMetrics(SystemConfig.MetricsEndpointName, metrics.Handler())
+	}
+	if SystemConfig.Tail.Enabled {
+		TailHub = tail.NewHub(SystemConfig.Tail)
+		server.SetTail(TailHub, SystemConfig.Tail.Secret)
+	}
+	go server.ServeHealthCheck(SystemConfig.HealthCheckEndpointName, SystemConfig.ReadinessEndpointName, SystemConfig.HealthCheckEndpointPort, SystemConfig.HTTPProfile, SystemConfig.HealthCheckServer)
+
+	shutdownTracing, err := tracing.Init(context.Background(), SystemConfig.Tracing, pumps.Version)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Fatal("Error setting up tracing: ", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// Store version which will be read by dashboard and sent to
 	// vclu(version check and licecnse utilisation) service
@@ -506,11 +787,29 @@ func main() {
 	// Create the store
 	setupAnalyticsStore()
 
+	setupQuarantine()
+
+	metrics.SetBacklogFunc(queueDepths)
+	if Quarantine != nil {
+		metrics.SetQuarantineFunc(Quarantine.Len)
+	}
+
 	// prime the pumps
 	initialisePumps(kvStores)
 
-	// Pump init is the last thing that dereferences a KV reference.
-	kvStores.Close(context.Background())
+	// Pump init is the last thing that dereferences a KV reference, unless the references
+	// are refreshed.
+	refresher := newKVRefresher(kvStores, SystemConfig.KVRefreshInterval)
+	if refresher == nil {
+		kvStores.Close(context.Background())
+	} else {
+		defer kvStores.Close(context.Background())
+	}
+
+	if command == quarantineRetryCmd.FullCommand() {
+		retryQuarantine()
+		return
+	}
 
 	if *demoMode != "" {
 		log.Info("BUILDING DEMO DATA AND EXITING...")
@@ -537,13 +836,37 @@ func main() {
 	wg.Add(1)
 	ctx, cancel := context.WithCancel(context.Background())
 	go StartPurgeLoop(&wg, ctx, SystemConfig.PurgeDelay, SystemConfig.PurgeChunk, time.Duration(SystemConfig.StorageExpirationTime)*time.Second, SystemConfig.OmitDetailedRecording)
+	if refresher != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			refresher.run(ctx)
+		}()
+	}
 
 	termChan := make(chan os.Signal, 1)
 	signal.Notify(termChan, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	<-termChan // Blocks here until either SIGINT or SIGTERM is received.
 	cancel()   // cancel the context
 	wg.Wait()  // wait till all the pumps finish
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Warning("Error shutting down the health check server: ", err)
+	}
+	if err := StopInstrumentation(shutdownCtx); err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Warning("Error stopping the instrumentation: ", err)
+	}
 	log.WithFields(logrus.Fields{
 		"prefix": mainPrefix,
 	}).Info("Tyk-pump stopped.")
+
+	if logFile != nil {
+		logFile.Close()
+	}
 }
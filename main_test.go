@@ -1,10 +1,17 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
@@ -12,9 +19,20 @@ import (
 
 	"github.com/TykTechnologies/storage/kv/resolver"
 	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/metrics"
 	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/quarantine"
+	"github.com/TykTechnologies/tyk-pump/serializer"
+	"github.com/TykTechnologies/tyk-pump/tail"
+	"github.com/TykTechnologies/tyk-pump/tracing"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	msgpack "gopkg.in/vmihailenco/msgpack.v2"
 )
 
 type MockedPump struct {
@@ -557,6 +575,23 @@ func TestInitialisePumps_SkipsUnknownPumpType(t *testing.T) {
 	assert.Len(t, Pumps, 1, "an unknown pump type must be skipped, not fatal")
 }
 
+func TestInitialisePumps_LogLevel(t *testing.T) {
+	registerRecordingPump(t, "recording")
+
+	SystemConfig = TykPumpConfiguration{
+		DontPurgeUptimeData: true,
+		Pumps: map[string]PumpConfig{
+			"debug":   {Type: "recording", LogLevel: "debug"},
+			"default": {Type: "recording"},
+			"invalid": {Type: "recording", LogLevel: "verbose"},
+		},
+	}
+
+	initialisePumps(nil)
+
+	assert.Len(t, Pumps, 3, "a pump with an invalid log level must keep the level of the Pump")
+}
+
 func TestInitialisePumps_InstallsResolverDuringInitAndClearsAfter(t *testing.T) {
 	rec := registerRecordingPump(t, "recording")
 
@@ -593,3 +628,357 @@ func TestInitialisePumps_NilKVStoresInstallsNoResolver(t *testing.T) {
 	assert.Nil(t, rec.resolverAtInit, "a nil kvStores installs no resolver")
 	assert.Len(t, Pumps, 1)
 }
+
+func TestPreprocessAnalyticsValues_MixedFormats(t *testing.T) {
+	mockedPump := &MockedPump{}
+	origPumps := Pumps
+	Pumps = []pumps.Pump{mockedPump}
+	t.Cleanup(func() { Pumps = origPumps })
+
+	record := analytics.AnalyticsRecord{APIID: "api123", OrgID: "123"}
+
+	values := make([]interface{}, 0, 3)
+	for _, serializerType := range []string{serializer.MSGP_SERIALIZER, serializer.JSON_SERIALIZER, "protobuf_zstd"} {
+		encoded, err := serializer.NewAnalyticsSerializer(serializerType).Encode(&record)
+		assert.NoError(t, err)
+		values = append(values, string(encoded))
+	}
+
+	job := instrument.NewJob("TestJob")
+	PreprocessAnalyticsValues(context.Background(), values, serializer.NewAnalyticsSerializer(serializer.MSGP_SERIALIZER), "tyk-system-analytics", false, job, time.Now(), 2)
+
+	assert.Equal(t, 3, mockedPump.CounterRequest, "every payload must be decoded whatever its format")
+}
+
+func TestPreprocessAnalyticsValues_Quarantine(t *testing.T) {
+	mockedPump := &MockedPump{}
+	origPumps, origQuarantine := Pumps, Quarantine
+	t.Cleanup(func() { Pumps, Quarantine = origPumps, origQuarantine })
+	Pumps = []pumps.Pump{mockedPump}
+
+	store, err := quarantine.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	Quarantine = store
+
+	encoded, err := serializer.NewAnalyticsSerializer(serializer.PROTOBUF_SERIALIZER).Encode(&analytics.AnalyticsRecord{APIID: "api123"})
+	assert.NoError(t, err)
+	values := []interface{}{string(encoded), "\x93not protobuf", string(encoded)}
+
+	job := instrument.NewJob("TestJob")
+	PreprocessAnalyticsValues(context.Background(), values, serializer.NewAnalyticsSerializer(serializer.PROTOBUF_SERIALIZER), "tyk-system-analytics_protobuf", false, job, time.Now(), 2)
+
+	assert.Equal(t, 2, mockedPump.CounterRequest, "only the decoded records must reach the pumps")
+
+	entries, err := store.Peek(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, []byte("\x93not protobuf"), entries[0].Payload)
+		assert.Equal(t, serializer.PROTOBUF_SERIALIZER, entries[0].Serializer)
+		assert.Equal(t, "tyk-system-analytics_protobuf", entries[0].KeyName)
+		assert.NotEmpty(t, entries[0].Error)
+	}
+}
+
+type failingPump struct {
+	MockedPump
+}
+
+func (p *failingPump) WriteData(context.Context, []interface{}) error {
+	return errors.New("connection refused")
+}
+
+func TestRetryQuarantine(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		pumps       []pumps.Pump
+		expectedLen int
+	}{
+		{testName: "every pump writes", pumps: []pumps.Pump{&MockedPump{}, &MockedPump{}}, expectedLen: 1},
+		{testName: "a pump fails", pumps: []pumps.Pump{&MockedPump{}, &failingPump{}}, expectedLen: 2},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			origPumps, origQuarantine := Pumps, Quarantine
+			t.Cleanup(func() { Pumps, Quarantine = origPumps, origQuarantine })
+			Pumps = tc.pumps
+			for i, pmp := range tc.pumps {
+				setPumpState(pmp, fmt.Sprintf("pump%d", i), pumps.CircuitBreakerConf{})
+			}
+
+			store, err := quarantine.NewFileStore(t.TempDir())
+			assert.NoError(t, err)
+			Quarantine = store
+
+			encoded, err := serializer.NewAnalyticsSerializer(serializer.PROTOBUF_SERIALIZER).Encode(&analytics.AnalyticsRecord{APIID: "api123"})
+			assert.NoError(t, err)
+			assert.NoError(t, store.Put(
+				quarantine.Entry{Payload: encoded, Serializer: serializer.PROTOBUF_SERIALIZER, Error: "was broken"},
+				quarantine.Entry{Payload: []byte("\x93not protobuf"), Serializer: serializer.PROTOBUF_SERIALIZER, Error: "old error"},
+			))
+
+			retryQuarantine()
+
+			assert.Equal(t, 1, tc.pumps[0].(*MockedPump).CounterRequest)
+
+			entries, err := store.Peek(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, entries, tc.expectedLen, "the payloads must stay in the quarantine until every pump writes them")
+			for _, entry := range entries {
+				if bytes.Equal(entry.Payload, encoded) {
+					assert.Equal(t, "was broken", entry.Error)
+					assert.Equal(t, []string{"pump0"}, entry.WrittenBy, "the pumps that wrote the payloads are recorded")
+				} else {
+					assert.NotEqual(t, "old error", entry.Error, "the undecodable payloads are quarantined back with why")
+				}
+			}
+		})
+	}
+}
+
+func TestRetryQuarantine_WrittenBy(t *testing.T) {
+	origPumps, origQuarantine := Pumps, Quarantine
+	t.Cleanup(func() { Pumps, Quarantine = origPumps, origQuarantine })
+	written, failing := &MockedPump{}, &failingPump{}
+	setPumpState(written, "written", pumps.CircuitBreakerConf{})
+	setPumpState(failing, "failing", pumps.CircuitBreakerConf{})
+	Pumps = []pumps.Pump{written, failing}
+
+	store, err := quarantine.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	Quarantine = store
+	encoded, err := serializer.NewAnalyticsSerializer(serializer.PROTOBUF_SERIALIZER).Encode(&analytics.AnalyticsRecord{APIID: "api123"})
+	assert.NoError(t, err)
+	assert.NoError(t, store.Put(quarantine.Entry{Payload: encoded, Serializer: serializer.PROTOBUF_SERIALIZER, Error: "was broken"}))
+
+	retryQuarantine()
+	length, err := store.Len(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), length)
+
+	// The pump fixed, the next retry only writes the payload to it.
+	fixed := &MockedPump{}
+	setPumpState(fixed, "failing", pumps.CircuitBreakerConf{})
+	Pumps = []pumps.Pump{written, fixed}
+	retryQuarantine()
+
+	assert.Equal(t, 1, written.CounterRequest, "the pumps that wrote the payloads don't write them again")
+	assert.Equal(t, 1, fixed.CounterRequest)
+	length, err = store.Len(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), length)
+}
+
+type MockedUptimePump struct {
+	MockedPump
+	records []analytics.UptimeReportData
+}
+
+func (p *MockedUptimePump) WriteUptimeRecords(ctx context.Context, records []analytics.UptimeReportData) error {
+	p.records = append(p.records, records...)
+	return nil
+}
+
+func TestWriteUptimeToPumps(t *testing.T) {
+	origUptimePumps := UptimePumps
+	t.Cleanup(func() { UptimePumps = origUptimePumps })
+
+	allPump := &MockedUptimePump{}
+	filteredPump := &MockedUptimePump{}
+	filteredPump.SetUptimeFilters(analytics.UptimeFilters{OrgsIDs: []string{"org1"}, SkippedAPIIDs: []string{"api2"}})
+	UptimePumps = []pumps.UptimeDataPump{allPump, filteredPump}
+
+	values := []interface{}{"not msgpack"}
+	for _, record := range []analytics.UptimeReportData{
+		{URL: "http://a", APIID: "api1", OrgID: "org1"},
+		{URL: "http://b", APIID: "api2", OrgID: "org1"},
+		{URL: "http://c", APIID: "api3", OrgID: "org2"},
+	} {
+		encoded, err := msgpack.Marshal(record)
+		assert.NoError(t, err)
+		values = append(values, string(encoded))
+	}
+
+	writeUptimeToPumps(values, instrument.NewJob("TestJob"))
+
+	assert.Len(t, allPump.records, 3, "undecodable uptime data must be skipped")
+	if assert.Len(t, filteredPump.records, 1) {
+		assert.Equal(t, "http://a", filteredPump.records[0].URL)
+	}
+}
+
+func TestFilterUptimeValues(t *testing.T) {
+	values := []interface{}{"not msgpack"}
+	for _, record := range []analytics.UptimeReportData{
+		{URL: "http://a", APIID: "api1", OrgID: "org1"},
+		{URL: "http://b", APIID: "api2", OrgID: "org1"},
+	} {
+		encoded, err := msgpack.Marshal(record)
+		assert.NoError(t, err)
+		values = append(values, string(encoded))
+	}
+
+	tcs := []struct {
+		testName string
+		filters  analytics.UptimeFilters
+		expected []interface{}
+	}{
+		{testName: "no filters", expected: values},
+		{testName: "skipped api", filters: analytics.UptimeFilters{SkippedAPIIDs: []string{"api2"}}, expected: values[:2]},
+		{testName: "other org", filters: analytics.UptimeFilters{OrgsIDs: []string{"org2"}}, expected: values[:1]},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, filterUptimeValues(tc.filters, values))
+		})
+	}
+}
+
+func TestExecPumpWriting_Metrics(t *testing.T) {
+	mockedPump := &MockedPump{}
+	mockedPump.SetFilters(analytics.AnalyticsFilters{APIIDs: []string{"api1"}})
+	setPumpState(mockedPump, "metrics-mocked", pumps.CircuitBreakerConf{})
+
+	origPumps := Pumps
+	t.Cleanup(func() { Pumps = origPumps })
+	Pumps = []pumps.Pump{mockedPump}
+
+	keys := []interface{}{analytics.AnalyticsRecord{APIID: "api1"}, analytics.AnalyticsRecord{APIID: "api2"}}
+	writeToPumps(keys, nil, time.Now(), 2)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.PumpRecordsFiltered.WithLabelValues("metrics-mocked")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.PumpRecordsSent.WithLabelValues("metrics-mocked")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.PumpRecordsFailed.WithLabelValues("metrics-mocked")))
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `tyk_pump_pump_write_duration_seconds_count{pump="metrics-mocked"} 1`)
+}
+
+// stuckFilterPump holds the filtering of the records until release is closed, and closes
+// written once it's handed them.
+type stuckFilterPump struct {
+	MockedPump
+	release chan struct{}
+	written chan struct{}
+}
+
+func (p *stuckFilterPump) GetFilters() analytics.AnalyticsFilters {
+	<-p.release
+	return p.MockedPump.GetFilters()
+}
+
+func (p *stuckFilterPump) WriteData(context.Context, []interface{}) error {
+	close(p.written)
+	return nil
+}
+
+func TestExecPumpWriting_FailedBeforeFiltering(t *testing.T) {
+	pmp := &stuckFilterPump{release: make(chan struct{}), written: make(chan struct{})}
+	setPumpState(pmp, "stuck-mocked", pumps.CircuitBreakerConf{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	keys := []interface{}{analytics.AnalyticsRecord{APIID: "api1"}, analytics.AnalyticsRecord{APIID: "api2"}}
+	err := execPumpWriting(ctx, pmp, &keys, 2, time.Now(), nil)
+	close(pmp.release)
+	<-pmp.written
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.PumpRecordsFailed.WithLabelValues("stuck-mocked")), "the records not handed to the pump in time failed")
+}
+
+// stuckWritePump filters every record out, then cancels the write and waits for release.
+type stuckWritePump struct {
+	MockedPump
+	cancel  context.CancelFunc
+	release chan struct{}
+}
+
+func (p *stuckWritePump) WriteData(context.Context, []interface{}) error {
+	p.cancel()
+	<-p.release
+	return nil
+}
+
+func TestExecPumpWriting_FailedAllFiltered(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pmp := &stuckWritePump{cancel: cancel, release: make(chan struct{})}
+	pmp.SetFilters(analytics.AnalyticsFilters{SkippedAPIIDs: []string{"api1", "api2"}})
+	setPumpState(pmp, "filtered-mocked", pumps.CircuitBreakerConf{})
+	t.Cleanup(func() { close(pmp.release) })
+
+	keys := []interface{}{analytics.AnalyticsRecord{APIID: "api1"}, analytics.AnalyticsRecord{APIID: "api2"}}
+	err := execPumpWriting(ctx, pmp, &keys, 2, time.Now(), nil)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.PumpRecordsFailed.WithLabelValues("filtered-mocked")), "the records filtered out didn't fail")
+}
+
+func TestPreprocessAnalyticsValues_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	origProvider := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(origProvider) })
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mockedPump := &MockedPump{}
+	setPumpState(mockedPump, "traced", pumps.CircuitBreakerConf{})
+	origPumps := Pumps
+	t.Cleanup(func() { Pumps = origPumps })
+	Pumps = []pumps.Pump{mockedPump}
+
+	msgpackSerializer := serializer.NewAnalyticsSerializer(serializer.MSGP_SERIALIZER)
+	encoded, err := msgpackSerializer.Encode(&analytics.AnalyticsRecord{APIID: "api1"})
+	assert.NoError(t, err)
+
+	ctx, keySpan := tracing.Tracer().Start(context.Background(), "purge_key")
+	PreprocessAnalyticsValues(ctx, []interface{}{string(encoded)}, msgpackSerializer, "tyk-system-analytics", false, instrument.NewJob("TestJob"), time.Now(), 2)
+	keySpan.End()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	if assert.Len(t, spans, 4) {
+		keySpanID := spans["purge_key"].SpanContext().SpanID()
+		assert.Equal(t, keySpanID, spans["decode"].Parent().SpanID())
+		assert.Equal(t, keySpanID, spans["write"].Parent().SpanID())
+		assert.Equal(t, spans["write"].SpanContext().SpanID(), spans["filter"].Parent().SpanID())
+		assert.Contains(t, spans["write"].Attributes(), attribute.String("tyk.pump", "traced"))
+		assert.Contains(t, spans["write"].Attributes(), attribute.Int64("tyk.records", 1))
+	}
+}
+
+func TestExecPumpWriting_Tail(t *testing.T) {
+	mockedPump := &MockedPump{}
+	mockedPump.SetFilters(analytics.AnalyticsFilters{APIIDs: []string{"api1"}})
+	setPumpState(mockedPump, "tailed", pumps.CircuitBreakerConf{})
+
+	origPumps, origHub := Pumps, TailHub
+	t.Cleanup(func() { Pumps, TailHub = origPumps, origHub })
+	Pumps = []pumps.Pump{mockedPump}
+	TailHub = tail.NewHub(tail.Config{})
+
+	srv := httptest.NewServer(TailHub)
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/tail?pump=tailed")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	keys := []interface{}{analytics.AnalyticsRecord{APIID: "api1"}, analytics.AnalyticsRecord{APIID: "api2"}}
+	failing := &failingPump{}
+	setPumpState(failing, "tailed", pumps.CircuitBreakerConf{})
+	Pumps = []pumps.Pump{failing}
+	writeToPumps([]interface{}{analytics.AnalyticsRecord{APIID: "api3"}}, nil, time.Now(), 2)
+	Pumps = []pumps.Pump{mockedPump}
+	writeToPumps(keys, nil, time.Now(), 2)
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+
+	ev := tail.Event{}
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev))
+	assert.Equal(t, "tailed", ev.Pump)
+	assert.Equal(t, "api1", ev.Record.APIID, "only the records the pump wrote, once filtered, are streamed")
+}
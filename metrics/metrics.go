@@ -0,0 +1,183 @@
+// Package metrics holds the metrics the Pump exposes about itself. They are kept in their
+// own registry, apart from the default one the Prometheus pump serves the analytics
+// metrics from, so the two never collide.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	namespace = "tyk_pump"
+	logPrefix = "metrics"
+)
+
+// collectTimeout bounds how long the metrics read on scrape, such as the backlog, may
+// take to collect.
+const collectTimeout = 5 * time.Second
+
+var log = logger.GetLogger()
+
+// Registry is the registry every metric of the Pump is registered in.
+var Registry = prometheus.NewRegistry()
+
+var (
+	RecordsPopped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "records_popped_total",
+		Help:      "Number of records popped from the temporal storage, by key.",
+	}, []string{"key"})
+	RecordsDecoded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "records_decoded_total",
+		Help:      "Number of records decoded, by serializer.",
+	}, []string{"serializer"})
+	RecordsDecodeFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "records_decode_failed_total",
+		Help:      "Number of records that couldn't be decoded, by serializer.",
+	}, []string{"serializer"})
+
+	PumpRecordsFiltered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pump_records_filtered_total",
+		Help:      "Number of records filtered out before being written, by pump.",
+	}, []string{"pump"})
+	PumpRecordsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pump_records_sent_total",
+		Help:      "Number of records written, by pump.",
+	}, []string{"pump"})
+	PumpRecordsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pump_records_failed_total",
+		Help:      "Number of records whose write failed or timed out, by pump.",
+	}, []string{"pump"})
+	PumpWriteDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "pump_write_duration_seconds",
+		Help:      "Duration of the writes, by pump.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"pump"})
+
+	PurgeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "purge_duration_seconds",
+		Help:      "Duration of the purge cycles.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	})
+)
+
+var (
+	backlogDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "backlog_records"),
+		"Number of records waiting in the temporal storage, by key.",
+		[]string{"key"}, nil,
+	)
+	quarantineDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "quarantine_entries"),
+		"Number of payloads waiting in the quarantine.",
+		nil, nil,
+	)
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RecordsPopped,
+		RecordsDecoded,
+		RecordsDecodeFailed,
+		PumpRecordsFiltered,
+		PumpRecordsSent,
+		PumpRecordsFailed,
+		PumpWriteDuration,
+		PurgeDuration,
+		sizes,
+	)
+}
+
+// Handler serves the metrics of Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// BacklogFunc returns the number of records waiting in each key of the temporal storage.
+type BacklogFunc func(ctx context.Context) (map[string]int64, error)
+
+// QuarantineFunc returns the number of entries in the quarantine.
+type QuarantineFunc func(ctx context.Context) (int64, error)
+
+// sizesCollector reads the backlog and the quarantine size on scrape, so they are never
+// stale and cost nothing when nobody scrapes them.
+type sizesCollector struct {
+	mu         sync.RWMutex
+	backlog    BacklogFunc
+	quarantine QuarantineFunc
+}
+
+var sizes = &sizesCollector{}
+
+// SetBacklogFunc sets where the backlog gauges are read from. They aren't reported until
+// it's set.
+func SetBacklogFunc(fn BacklogFunc) {
+	sizes.mu.Lock()
+	defer sizes.mu.Unlock()
+
+	sizes.backlog = fn
+}
+
+// SetQuarantineFunc sets where the quarantine gauge is read from. It isn't reported until
+// it's set.
+func SetQuarantineFunc(fn QuarantineFunc) {
+	sizes.mu.Lock()
+	defer sizes.mu.Unlock()
+
+	sizes.quarantine = fn
+}
+
+func (c *sizesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- backlogDesc
+	ch <- quarantineDesc
+}
+
+func (c *sizesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	backlog, quarantine := c.backlog, c.quarantine
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	if backlog != nil {
+		depths, err := backlog(ctx)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": logPrefix,
+			}).Warning("Couldn't collect the backlog: ", err)
+		}
+		for key, depth := range depths {
+			ch <- prometheus.MustNewConstMetric(backlogDesc, prometheus.GaugeValue, float64(depth), key)
+		}
+	}
+
+	if quarantine != nil {
+		length, err := quarantine(ctx)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": logPrefix,
+			}).Warning("Couldn't collect the quarantine size: ", err)
+			return
+		}
+		ch <- prometheus.MustNewConstMetric(quarantineDesc, prometheus.GaugeValue, float64(length))
+	}
+}
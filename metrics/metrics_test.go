@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizesCollector(t *testing.T) {
+	t.Cleanup(func() {
+		SetBacklogFunc(nil)
+		SetQuarantineFunc(nil)
+	})
+
+	assert.Equal(t, 0, testutil.CollectAndCount(sizes), "nothing is reported until the funcs are set")
+
+	SetBacklogFunc(func(context.Context) (map[string]int64, error) {
+		return map[string]int64{"tyk-system-analytics": 3, "tyk-system-analytics_protobuf": 0}, nil
+	})
+	SetQuarantineFunc(func(context.Context) (int64, error) {
+		return 2, nil
+	})
+
+	expected := `
+# HELP tyk_pump_backlog_records Number of records waiting in the temporal storage, by key.
+# TYPE tyk_pump_backlog_records gauge
+tyk_pump_backlog_records{key="tyk-system-analytics"} 3
+tyk_pump_backlog_records{key="tyk-system-analytics_protobuf"} 0
+# HELP tyk_pump_quarantine_entries Number of payloads waiting in the quarantine.
+# TYPE tyk_pump_quarantine_entries gauge
+tyk_pump_quarantine_entries 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(sizes, strings.NewReader(expected)))
+
+	SetQuarantineFunc(func(context.Context) (int64, error) {
+		return 0, errors.New("not connected")
+	})
+	assert.Equal(t, 2, testutil.CollectAndCount(sizes), "a failing func must only skip its own metric")
+}
+
+func TestHandler(t *testing.T) {
+	PumpRecordsSent.WithLabelValues("mongo").Add(5)
+	t.Cleanup(PumpRecordsSent.Reset)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `tyk_pump_pump_records_sent_total{pump="mongo"} 5`)
+	assert.Contains(t, rec.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,424 @@
+package pumps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/mitchellh/mapstructure"
+)
+
+// ClickHousePump writes the analytics records to a ClickHouse MergeTree table over the
+// native protocol, a column at a time.
+type ClickHousePump struct {
+	conf *ClickHouseConf
+	conn clickHouseConn
+	CommonPumpConfig
+}
+
+// clickHouseConn is the part of driver.Conn the pump uses.
+type clickHouseConn interface {
+	Exec(ctx context.Context, query string, args ...any) error
+	PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+var (
+	clickHousePrefix     = "clickhouse-pump"
+	clickHouseDefaultENV = PUMPS_ENV_PREFIX + "_CLICKHOUSE" + PUMPS_ENV_META_PREFIX
+
+	// clickHouseQuantiles are the latency quantiles of the rollups.
+	clickHouseQuantiles = "0.5, 0.9, 0.95, 0.99"
+
+	// openClickHouse opens the connection to the server, replaced in the tests.
+	openClickHouse = func(options *clickhouse.Options) (clickHouseConn, error) {
+		return clickhouse.Open(options)
+	}
+)
+
+const (
+	clickHouseDefaultAddr      = "localhost:9000"
+	clickHouseDefaultDatabase  = "default"
+	clickHouseDefaultBatchSize = 10000
+)
+
+// @PumpConf ClickHouse
+type ClickHouseConf struct {
+	// The prefix for the environment variables that will be used to override the configuration.
+	// Defaults to `TYK_PMP_PUMPS_CLICKHOUSE_META`
+	EnvPrefix string `mapstructure:"meta_env_prefix"`
+	// The `host:port` addresses of the native protocol of the servers, tried in turn. Defaults
+	// to `localhost:9000`.
+	Addrs []string `json:"addrs" mapstructure:"addrs"`
+	// The database of the table. Defaults to `default`.
+	Database string `json:"database" mapstructure:"database"`
+	// The user the pump connects as.
+	Username string `json:"username" mapstructure:"username"`
+	// The password of the user.
+	Password string `json:"password" mapstructure:"password"`
+	// The table the records are written to. Defaults to `tyk_analytics`.
+	Table string `json:"table" mapstructure:"table"`
+	// The compression of the blocks sent to the server, `lz4`, `zstd` or `none`. Defaults to
+	// `lz4`.
+	Compression string `json:"compression" mapstructure:"compression"`
+	// The timeout, in seconds, of the connection to the server. Defaults to 30.
+	DialTimeout int `json:"dial_timeout" mapstructure:"dial_timeout"`
+	// The maximum number of records of an insert. Defaults to 10000, ClickHouse being at its
+	// best with large inserts.
+	BatchSize int `json:"batch_size" mapstructure:"batch_size"`
+	// Stops the table from deleting the records once past their `expireAt`.
+	DisableTTL bool `json:"disable_ttl" mapstructure:"disable_ttl"`
+	// Creates the `<table>_1m` table, and the materialized view filling it, with the number of
+	// requests and the latency quantiles of every minute, API, key and response code.
+	EnableRollups bool `json:"enable_rollups" mapstructure:"enable_rollups"`
+	// Connects over TLS.
+	UseSSL bool `json:"use_ssl" mapstructure:"use_ssl"`
+	// Controls whether the pump client verifies the server's certificate chain and host name.
+	SSLInsecureSkipVerify bool `json:"ssl_insecure_skip_verify" mapstructure:"ssl_insecure_skip_verify"`
+	// Path to the PEM file with trusted CA certificates that will be used to verify the
+	// server's certificate.
+	SSLCAFile string `json:"ssl_ca_file" mapstructure:"ssl_ca_file"`
+	// SSL cert file location, for mTLS.
+	SSLCertFile string `json:"ssl_cert_file" mapstructure:"ssl_cert_file"`
+	// SSL cert key location, for mTLS.
+	SSLKeyFile string `json:"ssl_key_file" mapstructure:"ssl_key_file"`
+	// SSL Server name used in the TLS connection.
+	SSLServerName string `json:"ssl_server_name" mapstructure:"ssl_server_name"`
+}
+
+func (c *ClickHousePump) New() Pump {
+	return &ClickHousePump{}
+}
+
+func (c *ClickHousePump) GetName() string {
+	return "ClickHouse Pump"
+}
+
+func (c *ClickHousePump) GetEnvPrefix() string {
+	return c.conf.EnvPrefix
+}
+
+func (c *ClickHousePump) Init(config interface{}) error {
+	c.conf = &ClickHouseConf{}
+	c.log = c.newLog(clickHousePrefix)
+
+	if err := mapstructure.Decode(config, c.conf); err != nil {
+		c.log.Error("Failed to decode configuration: ", err)
+		return err
+	}
+
+	processPumpEnvVars(c, c.log, c.conf, clickHouseDefaultENV)
+
+	if len(c.conf.Addrs) == 0 {
+		c.conf.Addrs = []string{clickHouseDefaultAddr}
+	}
+	if c.conf.Database == "" {
+		c.conf.Database = clickHouseDefaultDatabase
+	}
+	if c.conf.Table == "" {
+		c.conf.Table = analytics.SQLTable
+	}
+	if c.conf.BatchSize <= 0 {
+		c.conf.BatchSize = clickHouseDefaultBatchSize
+	}
+
+	options, err := c.options()
+	if err != nil {
+		return err
+	}
+	conn, err := openClickHouse(options)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.DialTimeout)
+	defer cancel()
+	if err := conn.Ping(ctx); err != nil {
+		conn.Close()
+		return fmt.Errorf("couldn't connect to ClickHouse: %w", err)
+	}
+	if err := c.createSchema(ctx, conn); err != nil {
+		conn.Close()
+		return fmt.Errorf("couldn't create the schema: %w", err)
+	}
+	c.conn = conn
+
+	c.log.Info(c.GetName() + " Initialized")
+	return nil
+}
+
+// options returns the options of the connection to the servers.
+func (c *ClickHousePump) options() (*clickhouse.Options, error) {
+	options := &clickhouse.Options{
+		Addr: c.conf.Addrs,
+		Auth: clickhouse.Auth{
+			Database: c.conf.Database,
+			Username: c.conf.Username,
+			Password: c.conf.Password,
+		},
+		DialTimeout: 30 * time.Second,
+	}
+	if c.conf.DialTimeout > 0 {
+		options.DialTimeout = time.Duration(c.conf.DialTimeout) * time.Second
+	}
+
+	switch c.conf.Compression {
+	case "", "lz4":
+		options.Compression = &clickhouse.Compression{Method: clickhouse.CompressionLZ4}
+	case "zstd":
+		options.Compression = &clickhouse.Compression{Method: clickhouse.CompressionZSTD}
+	case "none":
+	default:
+		return nil, fmt.Errorf("unsupported compression %q, must be lz4, zstd or none", c.conf.Compression)
+	}
+
+	if c.conf.UseSSL {
+		tlsConfig, err := NewTLSConfig(TLSConfig{
+			CertFile:           c.conf.SSLCertFile,
+			KeyFile:            c.conf.SSLKeyFile,
+			CAFile:             c.conf.SSLCAFile,
+			ServerName:         c.conf.SSLServerName,
+			InsecureSkipVerify: c.conf.SSLInsecureSkipVerify,
+		}, c.log)
+		if err != nil {
+			return nil, err
+		}
+		options.TLS = tlsConfig
+	}
+
+	return options, nil
+}
+
+// createSchema creates the table of the records and, when enabled, the rollups. The
+// columns added since the table was created are added to it.
+func (c *ClickHousePump) createSchema(ctx context.Context, conn clickHouseConn) error {
+	for _, query := range c.schema() {
+		c.log.Debug("Running: ", query)
+		if err := conn.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schema returns the statements creating the schema.
+func (c *ClickHousePump) schema() []string {
+	table := c.tableName(c.conf.Table)
+
+	definitions := make([]string, len(clickHouseColumns))
+	additions := make([]string, len(clickHouseColumns))
+	for i, column := range clickHouseColumns {
+		definitions[i] = column.name + " " + column.typ
+		additions[i] = "ADD COLUMN IF NOT EXISTS " + definitions[i]
+	}
+
+	create := "CREATE TABLE IF NOT EXISTS " + table + " (\n\t" + strings.Join(definitions, ",\n\t") + "\n)" +
+		" ENGINE = MergeTree" +
+		" PARTITION BY toDate(timestamp)" +
+		" ORDER BY (org_id, api_id, timestamp)"
+	if !c.conf.DisableTTL {
+		// The records without an expiry are kept.
+		create += " TTL expire_at DELETE WHERE expire_at > toDateTime(0)"
+	}
+	queries := []string{create, "ALTER TABLE " + table + " " + strings.Join(additions, ", ")}
+
+	if c.conf.EnableRollups {
+		rollup := c.tableName(c.conf.Table + "_1m")
+		queries = append(queries,
+			"CREATE TABLE IF NOT EXISTS "+rollup+" (\n"+
+				"\tminute DateTime,\n"+
+				"\torg_id LowCardinality(String),\n"+
+				"\tapi_id LowCardinality(String),\n"+
+				"\tapi_key String,\n"+
+				"\tresponse_code Int32,\n"+
+				"\trequests SimpleAggregateFunction(sum, UInt64),\n"+
+				"\tlatency_total_quantiles AggregateFunction(quantiles("+clickHouseQuantiles+"), Int64),\n"+
+				"\tlatency_upstream_quantiles AggregateFunction(quantiles("+clickHouseQuantiles+"), Int64)\n"+
+				") ENGINE = AggregatingMergeTree"+
+				" PARTITION BY toDate(minute)"+
+				" ORDER BY (org_id, api_id, api_key, response_code, minute)",
+			"CREATE MATERIALIZED VIEW IF NOT EXISTS "+c.tableName(c.conf.Table+"_1m_mv")+" TO "+rollup+" AS SELECT"+
+				" toStartOfMinute(timestamp) AS minute, org_id, api_id, api_key, response_code,"+
+				" count() AS requests,"+
+				" quantilesState("+clickHouseQuantiles+")(latency_total) AS latency_total_quantiles,"+
+				" quantilesState("+clickHouseQuantiles+")(latency_upstream) AS latency_upstream_quantiles"+
+				" FROM "+table+
+				" GROUP BY minute, org_id, api_id, api_key, response_code",
+		)
+	}
+
+	return queries
+}
+
+// tableName returns the quoted name of table in the database of the pump.
+func (c *ClickHousePump) tableName(table string) string {
+	return clickHouseIdentifier(c.conf.Database) + "." + clickHouseIdentifier(table)
+}
+
+func clickHouseIdentifier(name string) string {
+	return "`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(name) + "`"
+}
+
+func (c *ClickHousePump) WriteData(ctx context.Context, data []interface{}) error {
+	c.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
+
+	records := make([]analytics.AnalyticsRecord, 0, len(data))
+	for _, v := range data {
+		if record, ok := v.(analytics.AnalyticsRecord); ok {
+			records = append(records, record)
+		}
+	}
+
+	for start := 0; start < len(records); start += c.conf.BatchSize {
+		end := start + c.conf.BatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		if err := c.insert(ctx, records[start:end]); err != nil {
+			c.log.WithContext(ctx).Error("Failed to insert the records: ", err)
+			return err
+		}
+	}
+
+	c.log.WithContext(ctx).Info("Purged ", len(records), " records...")
+	return nil
+}
+
+// insert writes records in a single batch, a column at a time.
+func (c *ClickHousePump) insert(ctx context.Context, records []analytics.AnalyticsRecord) error {
+	names := make([]string, len(clickHouseColumns))
+	for i, column := range clickHouseColumns {
+		names[i] = column.name
+	}
+
+	batch, err := c.conn.PrepareBatch(ctx, "INSERT INTO "+c.tableName(c.conf.Table)+" ("+strings.Join(names, ", ")+")")
+	if err != nil {
+		return err
+	}
+	defer batch.Close()
+
+	for i, column := range clickHouseColumns {
+		if err := batch.Column(i).Append(column.values(records)); err != nil {
+			return fmt.Errorf("column %s: %w", column.name, err)
+		}
+	}
+
+	return batch.Send()
+}
+
+func (c *ClickHousePump) Shutdown() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// clickHouseColumn is a column of the table of the records.
+type clickHouseColumn struct {
+	name string
+	typ  string
+	// values returns the values of the column for records.
+	values func(records []analytics.AnalyticsRecord) any
+}
+
+// newClickHouseColumn returns the column called name, of type typ, its values taken from
+// the records by value.
+func newClickHouseColumn[T any](name, typ string, value func(*analytics.AnalyticsRecord) T) clickHouseColumn {
+	return clickHouseColumn{
+		name: name,
+		typ:  typ,
+		values: func(records []analytics.AnalyticsRecord) any {
+			values := make([]T, len(records))
+			for i := range records {
+				values[i] = value(&records[i])
+			}
+			return values
+		},
+	}
+}
+
+// clickHouseColumns are the columns of the table of the records. New columns are added to
+// the existing tables at startup, so they must only ever be appended.
+var clickHouseColumns = []clickHouseColumn{
+	newClickHouseColumn("timestamp", "DateTime64(3)", func(r *analytics.AnalyticsRecord) time.Time { return r.TimeStamp }),
+	newClickHouseColumn("org_id", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.OrgID }),
+	newClickHouseColumn("api_id", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.APIID }),
+	newClickHouseColumn("api_name", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.APIName }),
+	newClickHouseColumn("api_version", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.APIVersion }),
+	newClickHouseColumn("api_key", "String", func(r *analytics.AnalyticsRecord) string { return r.APIKey }),
+	newClickHouseColumn("oauth_id", "String", func(r *analytics.AnalyticsRecord) string { return r.OauthID }),
+	newClickHouseColumn("alias", "String", func(r *analytics.AnalyticsRecord) string { return r.Alias }),
+	newClickHouseColumn("method", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.Method }),
+	newClickHouseColumn("host", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.Host }),
+	newClickHouseColumn("path", "String", func(r *analytics.AnalyticsRecord) string { return r.Path }),
+	newClickHouseColumn("raw_path", "String", func(r *analytics.AnalyticsRecord) string { return r.RawPath }),
+	newClickHouseColumn("original_path", "String", func(r *analytics.AnalyticsRecord) string { return r.OriginalPath }),
+	newClickHouseColumn("listen_path", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.ListenPath }),
+	newClickHouseColumn("response_code", "Int32", func(r *analytics.AnalyticsRecord) int32 { return int32(r.ResponseCode) }),
+	newClickHouseColumn("content_length", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.ContentLength }),
+	newClickHouseColumn("user_agent", "String", func(r *analytics.AnalyticsRecord) string { return r.UserAgent }),
+	newClickHouseColumn("ip_address", "String", func(r *analytics.AnalyticsRecord) string { return r.IPAddress }),
+	newClickHouseColumn("geo_country", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.Geo.Country.ISOCode }),
+	newClickHouseColumn("geo_city_geoname_id", "UInt64", func(r *analytics.AnalyticsRecord) uint64 { return uint64(r.Geo.City.GeoNameID) }),
+	newClickHouseColumn("geo_latitude", "Float64", func(r *analytics.AnalyticsRecord) float64 { return r.Geo.Location.Latitude }),
+	newClickHouseColumn("geo_longitude", "Float64", func(r *analytics.AnalyticsRecord) float64 { return r.Geo.Location.Longitude }),
+	newClickHouseColumn("geo_timezone", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.Geo.Location.TimeZone }),
+	newClickHouseColumn("request_time", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.RequestTime }),
+	newClickHouseColumn("latency_total", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.Latency.Total }),
+	newClickHouseColumn("latency_upstream", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.Latency.Upstream }),
+	newClickHouseColumn("latency_gateway", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.Latency.Gateway }),
+	newClickHouseColumn("network_open_connections", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.Network.OpenConnections }),
+	newClickHouseColumn("network_closed_connections", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.Network.ClosedConnection }),
+	newClickHouseColumn("network_bytes_in", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.Network.BytesIn }),
+	newClickHouseColumn("network_bytes_out", "Int64", func(r *analytics.AnalyticsRecord) int64 { return r.Network.BytesOut }),
+	newClickHouseColumn("tags", "Array(LowCardinality(String))", func(r *analytics.AnalyticsRecord) []string { return clickHouseStrings(r.Tags) }),
+	newClickHouseColumn("track_path", "Bool", func(r *analytics.AnalyticsRecord) bool { return r.TrackPath }),
+	newClickHouseColumn("raw_request", "String CODEC(ZSTD)", func(r *analytics.AnalyticsRecord) string { return r.RawRequest }),
+	newClickHouseColumn("raw_response", "String CODEC(ZSTD)", func(r *analytics.AnalyticsRecord) string { return r.RawResponse }),
+	// The records without an expiry are stored with the epoch, the zero of the column.
+	newClickHouseColumn("expire_at", "DateTime", func(r *analytics.AnalyticsRecord) time.Time {
+		if r.ExpireAt.IsZero() {
+			return time.Unix(0, 0)
+		}
+		return r.ExpireAt
+	}),
+	newClickHouseColumn("is_graphql", "Bool", func(r *analytics.AnalyticsRecord) bool { return r.IsGraphRecord() }),
+	newClickHouseColumn("graphql_operation_type", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string {
+		return r.ToGraphRecord().OperationType
+	}),
+	newClickHouseColumn("graphql_root_fields", "Array(String)", func(r *analytics.AnalyticsRecord) []string {
+		return clickHouseStrings(r.GraphQLStats.RootFields)
+	}),
+	newClickHouseColumn("graphql_types", "Map(String, Array(String))", func(r *analytics.AnalyticsRecord) map[string][]string {
+		if r.GraphQLStats.Types == nil {
+			return map[string][]string{}
+		}
+		return r.GraphQLStats.Types
+	}),
+	newClickHouseColumn("graphql_variables", "String", func(r *analytics.AnalyticsRecord) string { return r.GraphQLStats.Variables }),
+	newClickHouseColumn("graphql_errors", "Array(String)", func(r *analytics.AnalyticsRecord) []string {
+		messages := make([]string, len(r.GraphQLStats.Errors))
+		for i, graphErr := range r.GraphQLStats.Errors {
+			messages[i] = graphErr.Message
+		}
+		return messages
+	}),
+	newClickHouseColumn("graphql_has_errors", "Bool", func(r *analytics.AnalyticsRecord) bool { return r.ToGraphRecord().HasErrors }),
+	newClickHouseColumn("is_mcp", "Bool", func(r *analytics.AnalyticsRecord) bool { return r.IsMCPRecord() }),
+	newClickHouseColumn("mcp_jsonrpc_method", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.MCPStats.JSONRPCMethod }),
+	newClickHouseColumn("mcp_primitive_type", "LowCardinality(String)", func(r *analytics.AnalyticsRecord) string { return r.MCPStats.PrimitiveType }),
+	newClickHouseColumn("mcp_primitive_name", "String", func(r *analytics.AnalyticsRecord) string { return r.MCPStats.PrimitiveName }),
+}
+
+// clickHouseStrings returns values, empty rather than nil.
+func clickHouseStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
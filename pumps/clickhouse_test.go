@@ -0,0 +1,275 @@
+package pumps
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clickHouseRecorder records the statements and the batches sent to the server.
+type clickHouseRecorder struct {
+	options *clickhouse.Options
+	execs   []string
+	batches []*clickHouseTestBatch
+	pingErr error
+	sendErr error
+	closed  bool
+}
+
+func (r *clickHouseRecorder) Exec(_ context.Context, query string, _ ...any) error {
+	r.execs = append(r.execs, query)
+	return nil
+}
+
+func (r *clickHouseRecorder) PrepareBatch(_ context.Context, query string, _ ...driver.PrepareBatchOption) (driver.Batch, error) {
+	batch := &clickHouseTestBatch{query: query, columns: map[int]any{}, sendErr: r.sendErr}
+	r.batches = append(r.batches, batch)
+	return batch, nil
+}
+
+func (r *clickHouseRecorder) Ping(context.Context) error { return r.pingErr }
+
+func (r *clickHouseRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+type clickHouseTestBatch struct {
+	query   string
+	columns map[int]any
+	sent    bool
+	sendErr error
+}
+
+type clickHouseTestColumn struct {
+	batch *clickHouseTestBatch
+	index int
+}
+
+func (c clickHouseTestColumn) Append(v any) error {
+	c.batch.columns[c.index] = v
+	return nil
+}
+
+func (c clickHouseTestColumn) AppendRow(any) error { return errors.New("not supported") }
+
+func (b *clickHouseTestBatch) Abort() error           { return nil }
+func (b *clickHouseTestBatch) Append(...any) error    { return errors.New("not supported") }
+func (b *clickHouseTestBatch) AppendStruct(any) error { return errors.New("not supported") }
+func (b *clickHouseTestBatch) Column(i int) driver.BatchColumn {
+	return clickHouseTestColumn{batch: b, index: i}
+}
+func (b *clickHouseTestBatch) Flush() error                { return nil }
+func (b *clickHouseTestBatch) IsSent() bool                { return b.sent }
+func (b *clickHouseTestBatch) Rows() int                   { return 0 }
+func (b *clickHouseTestBatch) Columns() []column.Interface { return nil }
+func (b *clickHouseTestBatch) Close() error                { return nil }
+func (b *clickHouseTestBatch) Send() error {
+	b.sent = b.sendErr == nil
+	return b.sendErr
+}
+
+// column returns the values of the column called name.
+func (b *clickHouseTestBatch) column(name string) any {
+	for i, column := range clickHouseColumns {
+		if column.name == name {
+			return b.columns[i]
+		}
+	}
+	return nil
+}
+
+func newClickHouseRecorder(t *testing.T) *clickHouseRecorder {
+	t.Helper()
+
+	recorder := &clickHouseRecorder{}
+	origOpen := openClickHouse
+	openClickHouse = func(options *clickhouse.Options) (clickHouseConn, error) {
+		recorder.options = options
+		return recorder, nil
+	}
+	t.Cleanup(func() { openClickHouse = origOpen })
+
+	return recorder
+}
+
+func TestClickHousePump_Init(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		config      map[string]interface{}
+		pingErr     error
+		expectedErr string
+		check       func(t *testing.T, recorder *clickHouseRecorder)
+	}{
+		{
+			testName: "defaults",
+			config:   map[string]interface{}{},
+			check: func(t *testing.T, recorder *clickHouseRecorder) {
+				assert.Equal(t, []string{"localhost:9000"}, recorder.options.Addr)
+				assert.Equal(t, "default", recorder.options.Auth.Database)
+				assert.Equal(t, clickhouse.CompressionLZ4, recorder.options.Compression.Method)
+				assert.Equal(t, 30*time.Second, recorder.options.DialTimeout)
+				assert.Nil(t, recorder.options.TLS)
+
+				require.Len(t, recorder.execs, 2)
+				create := recorder.execs[0]
+				assert.True(t, strings.HasPrefix(create, "CREATE TABLE IF NOT EXISTS `default`.`tyk_analytics` ("))
+				assert.Contains(t, create, "\ttimestamp DateTime64(3),\n")
+				assert.Contains(t, create, "\tgraphql_types Map(String, Array(String)),\n")
+				assert.Contains(t, create, "\tmcp_primitive_name String\n)")
+				assert.Contains(t, create, " ENGINE = MergeTree PARTITION BY toDate(timestamp) ORDER BY (org_id, api_id, timestamp)")
+				assert.Contains(t, create, " TTL expire_at DELETE WHERE expire_at > toDateTime(0)")
+				assert.True(t, strings.HasPrefix(recorder.execs[1], "ALTER TABLE `default`.`tyk_analytics` ADD COLUMN IF NOT EXISTS timestamp DateTime64(3), "))
+			},
+		},
+		{
+			testName: "custom",
+			config: map[string]interface{}{
+				"addrs":          []string{"ch1:9440", "ch2:9440"},
+				"database":       "analytics",
+				"table":          "requests",
+				"username":       "tyk",
+				"password":       "secret",
+				"compression":    "zstd",
+				"dial_timeout":   5,
+				"use_ssl":        true,
+				"disable_ttl":    true,
+				"enable_rollups": true,
+			},
+			check: func(t *testing.T, recorder *clickHouseRecorder) {
+				assert.Equal(t, []string{"ch1:9440", "ch2:9440"}, recorder.options.Addr)
+				assert.Equal(t, clickhouse.Auth{Database: "analytics", Username: "tyk", Password: "secret"}, recorder.options.Auth)
+				assert.Equal(t, clickhouse.CompressionZSTD, recorder.options.Compression.Method)
+				assert.Equal(t, 5*time.Second, recorder.options.DialTimeout)
+				assert.NotNil(t, recorder.options.TLS)
+
+				require.Len(t, recorder.execs, 4)
+				assert.NotContains(t, recorder.execs[0], "TTL")
+				assert.True(t, strings.HasPrefix(recorder.execs[2], "CREATE TABLE IF NOT EXISTS `analytics`.`requests_1m` ("))
+				assert.Contains(t, recorder.execs[2], "latency_total_quantiles AggregateFunction(quantiles(0.5, 0.9, 0.95, 0.99), Int64)")
+				assert.Contains(t, recorder.execs[2], "ENGINE = AggregatingMergeTree")
+				assert.True(t, strings.HasPrefix(recorder.execs[3], "CREATE MATERIALIZED VIEW IF NOT EXISTS `analytics`.`requests_1m_mv` TO `analytics`.`requests_1m` AS SELECT"))
+				assert.Contains(t, recorder.execs[3], "FROM `analytics`.`requests` GROUP BY minute, org_id, api_id, api_key, response_code")
+			},
+		},
+		{
+			testName: "no compression",
+			config:   map[string]interface{}{"compression": "none"},
+			check: func(t *testing.T, recorder *clickHouseRecorder) {
+				assert.Nil(t, recorder.options.Compression)
+			},
+		},
+		{
+			testName:    "unknown compression",
+			config:      map[string]interface{}{"compression": "gzip"},
+			expectedErr: `unsupported compression "gzip"`,
+		},
+		{
+			testName:    "unreachable",
+			config:      map[string]interface{}{},
+			pingErr:     errors.New("connection refused"),
+			expectedErr: "couldn't connect to ClickHouse: connection refused",
+			check: func(t *testing.T, recorder *clickHouseRecorder) {
+				assert.True(t, recorder.closed)
+				assert.Empty(t, recorder.execs)
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			recorder := newClickHouseRecorder(t)
+			recorder.pingErr = tc.pingErr
+
+			pmp := &ClickHousePump{}
+			err := pmp.Init(tc.config)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.check != nil {
+				tc.check(t, recorder)
+			}
+		})
+	}
+}
+
+func TestClickHousePump_WriteData(t *testing.T) {
+	recorder := newClickHouseRecorder(t)
+	pmp := &ClickHousePump{}
+	require.NoError(t, pmp.Init(map[string]interface{}{"batch_size": 2}))
+
+	timestamp := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expireAt := timestamp.AddDate(0, 0, 7)
+	data := []interface{}{
+		analytics.AnalyticsRecord{
+			APIID: "api1", OrgID: "org1", Method: "GET", Path: "/users", ResponseCode: 200,
+			TimeStamp: timestamp, ExpireAt: expireAt, Tags: []string{"tag1"},
+			Latency: analytics.Latency{Total: 30, Upstream: 20, Gateway: 10},
+		},
+		analytics.AnalyticsRecord{
+			APIID: "api2", OrgID: "org1", Method: "POST", Path: "/graphql", ResponseCode: 200, TimeStamp: timestamp,
+			GraphQLStats: analytics.GraphQLStats{
+				IsGraphQL:     true,
+				OperationType: analytics.OperationMutation,
+				RootFields:    []string{"createUser"},
+				Types:         map[string][]string{"User": {"id"}},
+				Errors:        []analytics.GraphError{{Message: "denied"}},
+				HasErrors:     true,
+			},
+		},
+		analytics.AnalyticsRecord{
+			APIID: "api3", OrgID: "org1", Method: "POST", Path: "/mcp", ResponseCode: 500, TimeStamp: timestamp,
+			MCPStats: analytics.MCPStats{IsMCP: true, JSONRPCMethod: "tools/call", PrimitiveType: "tool", PrimitiveName: "search"},
+		},
+		"not a record",
+	}
+	require.NoError(t, pmp.WriteData(context.Background(), data))
+
+	require.Len(t, recorder.batches, 2, "the records are inserted batch_size at a time")
+	first, second := recorder.batches[0], recorder.batches[1]
+	assert.True(t, first.sent)
+	assert.True(t, second.sent)
+	assert.True(t, strings.HasPrefix(first.query, "INSERT INTO `default`.`tyk_analytics` (timestamp, org_id, api_id, "))
+	assert.Len(t, first.columns, len(clickHouseColumns), "every column is appended")
+
+	assert.Equal(t, []time.Time{timestamp, timestamp}, first.column("timestamp"))
+	assert.Equal(t, []string{"api1", "api2"}, first.column("api_id"))
+	assert.Equal(t, []int32{200, 200}, first.column("response_code"))
+	assert.Equal(t, []int64{30, 0}, first.column("latency_total"))
+	assert.Equal(t, [][]string{{"tag1"}, {}}, first.column("tags"))
+	assert.Equal(t, []time.Time{expireAt, time.Unix(0, 0)}, first.column("expire_at"), "the records without an expiry are kept")
+
+	assert.Equal(t, []bool{false, true}, first.column("is_graphql"))
+	assert.Equal(t, []string{"", "Mutation"}, first.column("graphql_operation_type"))
+	assert.Equal(t, [][]string{{}, {"createUser"}}, first.column("graphql_root_fields"))
+	assert.Equal(t, []map[string][]string{{}, {"User": {"id"}}}, first.column("graphql_types"))
+	assert.Equal(t, [][]string{{}, {"denied"}}, first.column("graphql_errors"))
+	assert.Equal(t, []bool{false, true}, first.column("graphql_has_errors"))
+
+	assert.Equal(t, []bool{true}, second.column("is_mcp"))
+	assert.Equal(t, []string{"tools/call"}, second.column("mcp_jsonrpc_method"))
+	assert.Equal(t, []string{"tool"}, second.column("mcp_primitive_type"))
+	assert.Equal(t, []string{"search"}, second.column("mcp_primitive_name"))
+
+	recorder.sendErr = errors.New("too many parts")
+	assert.EqualError(t, pmp.WriteData(context.Background(), data[:1]), "too many parts")
+
+	require.NoError(t, pmp.Shutdown())
+	assert.True(t, recorder.closed)
+}
+
+func TestClickHouseIdentifier(t *testing.T) {
+	assert.Equal(t, "`tyk_analytics`", clickHouseIdentifier("tyk_analytics"))
+	assert.Equal(t, "`odd\\`name\\\\`", clickHouseIdentifier("odd`name\\"))
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/logger"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	gorm_logger "gorm.io/gorm/logger"
@@ -17,10 +18,12 @@ import (
 
 type CommonPumpConfig struct {
 	filters               analytics.AnalyticsFilters
+	uptimeFilters         analytics.UptimeFilters
 	timeout               int
 	maxRecordSize         int
 	OmitDetailedRecording bool
 	log                   *logrus.Entry
+	pumpLog               *pumpLog
 	ignoreFields          []string
 	decodeResponseBase64  bool
 	decodeRequestBase64   bool
@@ -32,6 +35,12 @@ func (p *CommonPumpConfig) SetFilters(filters analytics.AnalyticsFilters) {
 func (p *CommonPumpConfig) GetFilters() analytics.AnalyticsFilters {
 	return p.filters
 }
+func (p *CommonPumpConfig) SetUptimeFilters(filters analytics.UptimeFilters) {
+	p.uptimeFilters = filters
+}
+func (p *CommonPumpConfig) GetUptimeFilters() analytics.UptimeFilters {
+	return p.uptimeFilters
+}
 func (p *CommonPumpConfig) SetTimeout(timeout int) {
 	p.timeout = timeout
 }
@@ -63,8 +72,57 @@ func (p *CommonPumpConfig) GetMaxRecordSize() int {
 	return p.maxRecordSize
 }
 
+// SetLogLevel sets the level of the pump's logger, apart from the rest of the Pump. It can be
+// called while the pump is running.
 func (p *CommonPumpConfig) SetLogLevel(level logrus.Level) {
-	p.log.Level = level
+	p.getPumpLog().logger.SetLevel(level)
+}
+
+// SetLogFields sets the fields every log line of the pump carries. It must be called before
+// Init.
+func (p *CommonPumpConfig) SetLogFields(fields logrus.Fields) {
+	p.getPumpLog().fields = fields
+}
+
+// newLog returns the entry the pump logs with, with the given prefix.
+func (p *CommonPumpConfig) newLog(prefix string) *logrus.Entry {
+	l := p.getPumpLog()
+	return l.logger.WithFields(l.fields).WithField("prefix", prefix)
+}
+
+func (p *CommonPumpConfig) getPumpLog() *pumpLog {
+	if p.pumpLog == nil {
+		p.pumpLog = newPumpLog()
+	}
+	return p.pumpLog
+}
+
+// pumpLog is the logger of a pump, with a level of its own, and the context of its lines.
+type pumpLog struct {
+	logger *logrus.Logger
+	fields logrus.Fields
+}
+
+func newPumpLog() *pumpLog {
+	l := &pumpLog{logger: logger.NewChildLogger()}
+	l.logger.AddHook(l)
+	return l
+}
+
+func (l *pumpLog) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the ID of the batch being written to the lines logged with the context of the
+// write.
+func (l *pumpLog) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if id := logger.BatchID(entry.Context); id != "" {
+		entry.Data["batch_id"] = id
+	}
+	return nil
 }
 
 func (p *CommonPumpConfig) SetIgnoreFields(fields []string) {
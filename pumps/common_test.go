@@ -1,6 +1,8 @@
 package pumps
 
 import (
+	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -11,9 +13,11 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/TykTechnologies/tyk-pump/logger"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/mitchellh/mapstructure"
 	"github.com/sirupsen/logrus"
@@ -37,6 +41,36 @@ func TestSetDecodingResponse(t *testing.T) {
 	assert.True(t, actualValue)
 }
 
+func TestCommonPumpConfig_Log(t *testing.T) {
+	buf := &bytes.Buffer{}
+	origOut := log.Out
+	log.Out = buf
+	t.Cleanup(func() { log.Out = origOut })
+
+	pump := &CommonPumpConfig{}
+	pump.SetLogFields(logrus.Fields{"pump_name": "events", "pump_type": "kafka"})
+	pump.SetLogLevel(logrus.DebugLevel)
+	pump.log = pump.newLog(kafkaPrefix)
+
+	pump.log.WithContext(logger.WithBatchID(context.Background(), "batch-1")).Debug("writing")
+	log.Debug("not logged")
+
+	assert.Contains(t, buf.String(), "writing", "the level of the pump is its own")
+	assert.NotContains(t, buf.String(), "not logged", "the level of the Pump doesn't change")
+	for _, field := range []string{"pump_name=events", "pump_type=kafka", "prefix=kafka-pump", "batch_id=batch-1"} {
+		assert.Contains(t, buf.String(), field)
+	}
+
+	buf.Reset()
+	pump.SetLogLevel(logrus.InfoLevel)
+	pump.log.Debug("writing")
+	pump.log.WithContext(logger.WithBatchID(context.Background(), "batch-2")).Info("written")
+	pump.log.Info("shut down")
+	assert.NotContains(t, buf.String(), "writing", "the level can change while the pump runs")
+	assert.Contains(t, buf.String(), "batch_id=batch-2")
+	assert.Equal(t, 1, strings.Count(buf.String(), "batch_id"), "only the lines logged with the batch carry its ID")
+}
+
 // TestPumpEnvVarOverride tests the generic behavior of environment variable overrides
 // for pump configurations. This test validates that the processPumpEnvVars mechanism
 // (which uses mapstructure.Decode + envconfig.Process) correctly overrides configuration
@@ -0,0 +1,52 @@
+package pumps
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// CredentialsRefresher is implemented by pumps that can pick up new credentials while they
+// run, such as a database password rotated in a KV store. conf is the configuration the
+// pump decodes its meta into, e.g. *SQLConf, with the env var overrides and KV references
+// applied. The pump rebuilds its clients with the credentials and connection settings of
+// conf, keeping its other settings, and keeps its current clients when it fails to.
+//
+// The caller makes sure the pump isn't writing while its credentials are refreshed.
+type CredentialsRefresher interface {
+	RefreshCredentials(ctx context.Context, conf any) error
+}
+
+func unexpectedConfError(conf any) error {
+	return fmt.Errorf("unexpected configuration of type %T", conf)
+}
+
+// closeReplaced closes client, replaced by a client with new credentials, when it can be
+// closed.
+func closeReplaced(client any, log *logrus.Entry) {
+	closer, ok := client.(io.Closer)
+	if !ok {
+		return
+	}
+
+	if err := closer.Close(); err != nil {
+		log.WithError(err).Warn("Couldn't close the client replaced by the one with the refreshed credentials")
+	}
+}
+
+// closeReplacedDB closes the connection pool of db, replaced by one with new credentials.
+func closeReplacedDB(db *gorm.DB, log *logrus.Entry) {
+	if db == nil {
+		return
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.WithError(err).Warn("Couldn't close the connection pool replaced by the one with the refreshed credentials")
+		return
+	}
+	closeReplaced(sqlDB, log)
+}
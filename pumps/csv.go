@@ -46,7 +46,7 @@ func (c *CSVPump) GetEnvPrefix() string {
 
 func (c *CSVPump) Init(conf interface{}) error {
 	c.csvConf = &CSVConf{}
-	c.log = log.WithField("prefix", csvPrefix)
+	c.log = c.newLog(csvPrefix)
 
 	err := mapstructure.Decode(conf, &c.csvConf)
 	if err != nil {
@@ -65,7 +65,7 @@ func (c *CSVPump) Init(conf interface{}) error {
 }
 
 func (c *CSVPump) WriteData(ctx context.Context, data []interface{}) error {
-	c.log.Debug("Attempting to write ", len(data), " records...")
+	c.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	curtime := time.Now()
 	fname := fmt.Sprintf("%d-%s-%d-%d.csv", curtime.Year(), curtime.Month().String(), curtime.Day(), curtime.Hour())
@@ -78,14 +78,14 @@ func (c *CSVPump) WriteData(ctx context.Context, data []interface{}) error {
 		var createErr error
 		outfile, createErr = os.Create(fname)
 		if createErr != nil {
-			c.log.Error("Failed to create new CSV file: ", createErr)
+			c.log.WithContext(ctx).Error("Failed to create new CSV file: ", createErr)
 		}
 		appendHeader = true
 	} else {
 		var appendErr error
 		outfile, appendErr = os.OpenFile(fname, os.O_APPEND|os.O_WRONLY, 0600)
 		if appendErr != nil {
-			c.log.Error("Failed to open CSV file: ", appendErr)
+			c.log.WithContext(ctx).Error("Failed to open CSV file: ", appendErr)
 		}
 	}
 
@@ -98,7 +98,7 @@ func (c *CSVPump) WriteData(ctx context.Context, data []interface{}) error {
 
 		err := writer.Write(headers)
 		if err != nil {
-			c.log.Error("Failed to write file headers: ", err)
+			c.log.WithContext(ctx).Error("Failed to write file headers: ", err)
 			return err
 
 		}
@@ -125,12 +125,12 @@ func (c *CSVPump) WriteData(ctx context.Context, data []interface{}) error {
 		// 	decoded.APIVersion}
 		err := writer.Write(toWrite)
 		if err != nil {
-			c.log.Error("File write failed:", err)
+			c.log.WithContext(ctx).Error("File write failed:", err)
 			return err
 		}
 
 	}
 	writer.Flush()
-	c.log.Info("Purged ", len(data), " records...")
+	c.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 	return nil
 }
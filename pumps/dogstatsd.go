@@ -116,7 +116,7 @@ func (s *DogStatsdPump) GetEnvPrefix() string {
 
 func (s *DogStatsdPump) Init(conf interface{}) error {
 
-	s.log = log.WithField("prefix", dogstatPrefix)
+	s.log = s.newLog(dogstatPrefix)
 
 	if err := mapstructure.Decode(conf, &s.conf); err != nil {
 		return errors.Wrap(err, "unable to decode dogstatsd configuration")
@@ -185,7 +185,7 @@ func (s *DogStatsdPump) WriteData(ctx context.Context, data []interface{}) error
 		return nil
 	}
 
-	s.log.Debug("Attempting to write ", len(data), " records...")
+	s.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 	for _, v := range data {
 		// Convert to AnalyticsRecord
 		decoded := v.(analytics.AnalyticsRecord)
@@ -247,10 +247,10 @@ func (s *DogStatsdPump) WriteData(ctx context.Context, data []interface{}) error
 		}
 
 		if err := s.client.Histogram("request_time", float64(decoded.RequestTime), tags, s.conf.SampleRate); err != nil {
-			s.log.WithError(err).Error("unable to record Histogram, dropping analytics record")
+			s.log.WithContext(ctx).WithError(err).Error("unable to record Histogram, dropping analytics record")
 		}
 	}
-	s.log.Info("Purged ", len(data), " records...")
+	s.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
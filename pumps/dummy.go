@@ -21,13 +21,13 @@ func (p *DummyPump) GetName() string {
 }
 
 func (p *DummyPump) Init(conf interface{}) error {
-	p.log = log.WithField("prefix", dummyPrefix)
+	p.log = p.newLog(dummyPrefix)
 
 	p.log.Info("Dummy Initialized")
 	return nil
 }
 
 func (p *DummyPump) WriteData(ctx context.Context, data []interface{}) error {
-	p.log.Info("Writing ", len(data), " records")
+	p.log.WithContext(ctx).Info("Writing ", len(data), " records")
 	return nil
 }
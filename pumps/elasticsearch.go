@@ -94,6 +94,10 @@ type ElasticsearchConf struct {
 	SSLCAFile string `json:"ssl_ca_file" mapstructure:"ssl_ca_file"`
 	// When set, Model Context Protocol (MCP) records are written to this index instead of the default `IndexName`. Supports the same rolling-index date suffix as `IndexName` when `RollingIndex` is enabled. Defaults to `""` (empty string), meaning all records go to `IndexName`.
 	MCPIndexName string `json:"mcp_index_name" mapstructure:"mcp_index_name"`
+	// The name of the index uptime records are placed in, when the pump has `uptime` enabled.
+	// Supports the same rolling-index date suffix as `IndexName`. Defaults to
+	// "tyk_uptime_analytics".
+	UptimeIndexName string `json:"uptime_index_name" mapstructure:"uptime_index_name"`
 }
 
 type ElasticsearchBulkConfig struct {
@@ -111,7 +115,11 @@ type ElasticsearchBulkConfig struct {
 
 type ElasticsearchOperator interface {
 	processData(ctx context.Context, data []interface{}, esConf *ElasticsearchConf) error
+	processUptimeData(ctx context.Context, data []analytics.UptimeReportData, esConf *ElasticsearchConf) error
+	clusterStatus(ctx context.Context) (string, error)
 	flushRecords() error
+	// close flushes the bulked records and stops the bulk processor.
+	close() error
 }
 
 type Elasticsearch3Operator struct {
@@ -154,7 +162,10 @@ func (t *ApiKeyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 }
 
 func (e *ElasticsearchPump) getOperator() (ElasticsearchOperator, error) {
-	conf := *e.esConf
+	return e.newOperator(*e.esConf)
+}
+
+func (e *ElasticsearchPump) newOperator(conf ElasticsearchConf) (ElasticsearchOperator, error) {
 	var err error
 
 	urls := strings.Split(conf.ElasticsearchURL, ",")
@@ -178,6 +189,7 @@ func (e *ElasticsearchPump) getOperator() (ElasticsearchOperator, error) {
 		}
 		httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConf}}
 	}
+	httpClient = &http.Client{Transport: &tracingTransport{base: httpClient.Transport}}
 
 	switch conf.Version {
 	case "3":
@@ -351,7 +363,7 @@ func (e *ElasticsearchPump) GetEnvPrefix() string {
 
 func (e *ElasticsearchPump) Init(config interface{}) error {
 	e.esConf = &ElasticsearchConf{}
-	e.log = log.WithField("prefix", elasticsearchPrefix)
+	e.log = e.newLog(elasticsearchPrefix)
 
 	loadConfigErr := mapstructure.Decode(config, &e.esConf)
 	if loadConfigErr != nil {
@@ -364,6 +376,10 @@ func (e *ElasticsearchPump) Init(config interface{}) error {
 		e.esConf.IndexName = "tyk_analytics"
 	}
 
+	if e.esConf.UptimeIndexName == "" {
+		e.esConf.UptimeIndexName = "tyk_uptime_analytics"
+	}
+
 	if "" == e.esConf.ElasticsearchURL {
 		e.esConf.ElasticsearchURL = "http://localhost:9200"
 	}
@@ -409,10 +425,10 @@ func (e *ElasticsearchPump) connect() {
 }
 
 func (e *ElasticsearchPump) WriteData(ctx context.Context, data []interface{}) error {
-	e.log.Debug("Attempting to write ", len(data), " records...")
+	e.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	if e.operator == nil {
-		e.log.Debug("Connecting to analytics store")
+		e.log.WithContext(ctx).Debug("Connecting to analytics store")
 		e.connect()
 		e.WriteData(ctx, data)
 	} else {
@@ -423,6 +439,21 @@ func (e *ElasticsearchPump) WriteData(ctx context.Context, data []interface{}) e
 	return nil
 }
 
+// WriteUptimeRecords writes every uptime record to `uptime_index_name`.
+func (e *ElasticsearchPump) WriteUptimeRecords(ctx context.Context, data []analytics.UptimeReportData) error {
+	e.log.WithContext(ctx).Debug("Attempting to write ", len(data), " uptime records...")
+
+	if e.operator == nil {
+		e.log.WithContext(ctx).Debug("Connecting to analytics store")
+		e.connect()
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	return e.operator.processUptimeData(ctx, data, e.esConf)
+}
+
 func getIndexName(esConf *ElasticsearchConf) string {
 	indexName := esConf.IndexName
 
@@ -449,6 +480,36 @@ func getIndexNameForRecord(esConf *ElasticsearchConf, record analytics.Analytics
 	return getIndexName(esConf)
 }
 
+func getUptimeIndexName(esConf *ElasticsearchConf) string {
+	indexName := esConf.UptimeIndexName
+	if esConf.RollingIndex {
+		indexName += "-" + time.Now().Format("2006.01.02")
+	}
+	return indexName
+}
+
+func getUptimeMapping(record analytics.UptimeReportData, generateID bool) (map[string]interface{}, string) {
+	mapping := map[string]interface{}{
+		"@timestamp":      record.TimeStamp,
+		"url":             record.URL,
+		"request_time_ms": record.RequestTime,
+		"response_code":   record.ResponseCode,
+		"tcp_error":       record.TCPError,
+		"server_error":    record.ServerError,
+		"api_id":          record.APIID,
+		"org_id":          record.OrgID,
+	}
+
+	if generateID {
+		hasher := murmur3.New64()
+		hasher.Write([]byte(fmt.Sprintf("%d%s%s%d", record.TimeStamp.UnixNano(), record.URL, record.APIID, record.RequestTime)))
+
+		return mapping, string(hasher.Sum(nil))
+	}
+
+	return mapping, ""
+}
+
 func getMapping(datum analytics.AnalyticsRecord, extendedStatistics bool, generateID bool, decodeBase64 bool) (map[string]interface{}, string) {
 	record := datum
 
@@ -533,10 +594,49 @@ func (e Elasticsearch3Operator) processData(ctx context.Context, data []interfac
 	return nil
 }
 
+func (e Elasticsearch3Operator) processUptimeData(ctx context.Context, data []analytics.UptimeReportData, esConf *ElasticsearchConf) error {
+	indexName := getUptimeIndexName(esConf)
+	index := e.esClient.Index().Index(indexName)
+
+	for _, d := range data {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			continue
+		}
+
+		mapping, id := getUptimeMapping(d, esConf.GenerateID)
+
+		if !esConf.DisableBulk {
+			r := elasticv3.NewBulkIndexRequest().Index(indexName).Type(esConf.DocumentType).Id(id).Doc(mapping)
+			e.bulkProcessor.Add(r)
+		} else {
+			_, err := index.BodyJson(mapping).Type(esConf.DocumentType).Id(id).DoC(ctx)
+			if err != nil {
+				e.log.Error("Error while writing uptime record ", d, err)
+			}
+		}
+	}
+	if esConf.DisableBulk {
+		e.log.Info("Purged ", len(data), " uptime records...")
+	}
+	return nil
+}
+
+func (e Elasticsearch3Operator) clusterStatus(ctx context.Context) (string, error) {
+	health, err := e.esClient.ClusterHealth().DoC(ctx)
+	if err != nil {
+		return "", err
+	}
+	return health.Status, nil
+}
+
 func (e Elasticsearch3Operator) flushRecords() error {
 	return e.bulkProcessor.Flush()
 }
 
+func (e Elasticsearch3Operator) close() error {
+	return e.bulkProcessor.Close()
+}
+
 func (e Elasticsearch5Operator) processData(ctx context.Context, data []interface{}, esConf *ElasticsearchConf) error {
 	index := e.esClient.Index().Index(getIndexName(esConf))
 
@@ -570,10 +670,49 @@ func (e Elasticsearch5Operator) processData(ctx context.Context, data []interfac
 	return nil
 }
 
+func (e Elasticsearch5Operator) processUptimeData(ctx context.Context, data []analytics.UptimeReportData, esConf *ElasticsearchConf) error {
+	indexName := getUptimeIndexName(esConf)
+	index := e.esClient.Index().Index(indexName)
+
+	for _, d := range data {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			continue
+		}
+
+		mapping, id := getUptimeMapping(d, esConf.GenerateID)
+
+		if !esConf.DisableBulk {
+			r := elasticv5.NewBulkIndexRequest().Index(indexName).Type(esConf.DocumentType).Id(id).Doc(mapping)
+			e.bulkProcessor.Add(r)
+		} else {
+			_, err := index.BodyJson(mapping).Type(esConf.DocumentType).Id(id).Do(ctx)
+			if err != nil {
+				e.log.Error("Error while writing uptime record ", d, err)
+			}
+		}
+	}
+	if esConf.DisableBulk {
+		e.log.Info("Purged ", len(data), " uptime records...")
+	}
+	return nil
+}
+
+func (e Elasticsearch5Operator) clusterStatus(ctx context.Context) (string, error) {
+	health, err := e.esClient.ClusterHealth().Do(ctx)
+	if err != nil {
+		return "", err
+	}
+	return health.Status, nil
+}
+
 func (e Elasticsearch5Operator) flushRecords() error {
 	return e.bulkProcessor.Flush()
 }
 
+func (e Elasticsearch5Operator) close() error {
+	return e.bulkProcessor.Close()
+}
+
 func (e Elasticsearch6Operator) processData(ctx context.Context, data []interface{}, esConf *ElasticsearchConf) error {
 	index := e.esClient.Index().Index(getIndexName(esConf))
 
@@ -611,10 +750,49 @@ func (e Elasticsearch6Operator) processData(ctx context.Context, data []interfac
 	return nil
 }
 
+func (e Elasticsearch6Operator) processUptimeData(ctx context.Context, data []analytics.UptimeReportData, esConf *ElasticsearchConf) error {
+	indexName := getUptimeIndexName(esConf)
+	index := e.esClient.Index().Index(indexName)
+
+	for _, d := range data {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			continue
+		}
+
+		mapping, id := getUptimeMapping(d, esConf.GenerateID)
+
+		if !esConf.DisableBulk {
+			r := elasticv6.NewBulkIndexRequest().Index(indexName).Type(esConf.DocumentType).Id(id).Doc(mapping)
+			e.bulkProcessor.Add(r)
+		} else {
+			_, err := index.BodyJson(mapping).Type(esConf.DocumentType).Id(id).Do(ctx)
+			if err != nil {
+				e.log.Error("Error while writing uptime record ", d, err)
+			}
+		}
+	}
+	if esConf.DisableBulk {
+		e.log.Info("Purged ", len(data), " uptime records...")
+	}
+	return nil
+}
+
+func (e Elasticsearch6Operator) clusterStatus(ctx context.Context) (string, error) {
+	health, err := e.esClient.ClusterHealth().Do(ctx)
+	if err != nil {
+		return "", err
+	}
+	return health.Status, nil
+}
+
 func (e Elasticsearch6Operator) flushRecords() error {
 	return e.bulkProcessor.Flush()
 }
 
+func (e Elasticsearch6Operator) close() error {
+	return e.bulkProcessor.Close()
+}
+
 func (e Elasticsearch7Operator) processData(ctx context.Context, data []interface{}, esConf *ElasticsearchConf) error {
 	index := e.esClient.Index().Index(getIndexName(esConf))
 
@@ -649,10 +827,49 @@ func (e Elasticsearch7Operator) processData(ctx context.Context, data []interfac
 	return nil
 }
 
+func (e Elasticsearch7Operator) processUptimeData(ctx context.Context, data []analytics.UptimeReportData, esConf *ElasticsearchConf) error {
+	indexName := getUptimeIndexName(esConf)
+	index := e.esClient.Index().Index(indexName)
+
+	for _, d := range data {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			continue
+		}
+
+		mapping, id := getUptimeMapping(d, esConf.GenerateID)
+
+		if !esConf.DisableBulk {
+			r := elasticv7.NewBulkIndexRequest().Index(indexName).Id(id).Doc(mapping)
+			e.bulkProcessor.Add(r)
+		} else {
+			_, err := index.BodyJson(mapping).Id(id).Do(ctx)
+			if err != nil {
+				e.log.Error("Error while writing uptime record ", d, err)
+			}
+		}
+	}
+	if esConf.DisableBulk {
+		e.log.Info("Purged ", len(data), " uptime records...")
+	}
+	return nil
+}
+
+func (e Elasticsearch7Operator) clusterStatus(ctx context.Context) (string, error) {
+	health, err := e.esClient.ClusterHealth().Do(ctx)
+	if err != nil {
+		return "", err
+	}
+	return health.Status, nil
+}
+
 func (e Elasticsearch7Operator) flushRecords() error {
 	return e.bulkProcessor.Flush()
 }
 
+func (e Elasticsearch7Operator) close() error {
+	return e.bulkProcessor.Close()
+}
+
 // printPurgedBulkRecords print the purged records = bulk size when bulk is enabled
 func printPurgedBulkRecords(bulkSize int, err error, logger *logrus.Entry) {
 	if err != nil {
@@ -662,6 +879,68 @@ func printPurgedBulkRecords(bulkSize int, err error, logger *logrus.Entry) {
 	logger.Infof("Purged %+v records", bulkSize)
 }
 
+// CheckHealth fails when the cluster can't be reached or its health is red.
+func (e *ElasticsearchPump) CheckHealth(ctx context.Context) error {
+	if e.operator == nil {
+		return errNotConnected
+	}
+
+	status, err := e.operator.clusterStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if status == "red" {
+		return errors.New("cluster health is red")
+	}
+
+	return nil
+}
+
+// RefreshCredentials reconnects with the URL, API key, basic auth credentials and
+// certificates of conf, an *ElasticsearchConf, when they changed. The records bulked by the
+// replaced client are flushed before it's stopped.
+func (e *ElasticsearchPump) RefreshCredentials(ctx context.Context, conf any) error {
+	newConf, ok := conf.(*ElasticsearchConf)
+	if !ok {
+		return unexpectedConfError(conf)
+	}
+
+	esConf := *e.esConf
+	if newConf.ElasticsearchURL != "" {
+		esConf.ElasticsearchURL = newConf.ElasticsearchURL
+	}
+	esConf.AuthAPIKeyID = newConf.AuthAPIKeyID
+	esConf.AuthAPIKey = newConf.AuthAPIKey
+	esConf.Username = newConf.Username
+	esConf.Password = newConf.Password
+	esConf.SSLCertFile = newConf.SSLCertFile
+	esConf.SSLKeyFile = newConf.SSLKeyFile
+	esConf.SSLCAFile = newConf.SSLCAFile
+	if esConf == *e.esConf {
+		return nil
+	}
+
+	operator, err := e.newOperator(esConf)
+	if err != nil {
+		return err
+	}
+	if _, err := operator.clusterStatus(ctx); err != nil {
+		operator.close()
+		return err
+	}
+
+	oldOperator := e.operator
+	e.operator, e.esConf = operator, &esConf
+	if oldOperator != nil {
+		if err := oldOperator.close(); err != nil {
+			e.log.WithError(err).Warn("Couldn't flush the records bulked with the replaced credentials")
+		}
+	}
+
+	e.log.Info("Reconnected with the refreshed credentials")
+	return nil
+}
+
 func (e *ElasticsearchPump) Shutdown() error {
 	if !e.esConf.DisableBulk {
 		e.log.Info("Flushing bulked records...")
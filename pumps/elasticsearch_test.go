@@ -278,3 +278,34 @@ func TestGetMapping_ExtendedStatistics(t *testing.T) {
 		assert.NotEmpty(t, id)
 	})
 }
+
+func TestGetUptimeMapping(t *testing.T) {
+	record := analytics.UptimeReportData{
+		URL:          "http://upstream",
+		RequestTime:  25,
+		ResponseCode: 503,
+		ServerError:  true,
+		APIID:        "api1",
+		OrgID:        "org1",
+		TimeStamp:    time.Now(),
+	}
+
+	mapping, id := getUptimeMapping(record, false)
+	assert.Equal(t, "", id)
+	assert.Equal(t, "http://upstream", mapping["url"])
+	assert.Equal(t, int64(25), mapping["request_time_ms"])
+	assert.Equal(t, 503, mapping["response_code"])
+	assert.Equal(t, true, mapping["server_error"])
+	assert.Equal(t, "api1", mapping["api_id"])
+	assert.Equal(t, "org1", mapping["org_id"])
+
+	_, id = getUptimeMapping(record, true)
+	assert.NotEmpty(t, id)
+	_, sameID := getUptimeMapping(record, true)
+	assert.Equal(t, id, sameID, "generated ids must be stable")
+}
+
+func TestGetUptimeIndexName(t *testing.T) {
+	assert.Equal(t, "tyk_uptime_analytics", getUptimeIndexName(&ElasticsearchConf{UptimeIndexName: "tyk_uptime_analytics"}))
+	assert.Equal(t, "tyk_uptime_analytics-"+time.Now().Format("2006.01.02"), getUptimeIndexName(&ElasticsearchConf{UptimeIndexName: "tyk_uptime_analytics", RollingIndex: true}))
+}
@@ -46,7 +46,7 @@ func (g *GraphMongoPump) SetDecodingResponse(decoding bool) {
 
 func (g *GraphMongoPump) Init(config interface{}) error {
 	g.dbConf = &MongoConf{}
-	g.log = log.WithField("prefix", mongoGraphPrefix)
+	g.log = g.newLog(mongoGraphPrefix)
 	g.MongoPump.CommonPumpConfig = g.CommonPumpConfig
 
 	err := mapstructure.Decode(config, &g.dbConf)
@@ -94,11 +94,11 @@ func (g *GraphMongoPump) Init(config interface{}) error {
 func (g *GraphMongoPump) WriteData(ctx context.Context, data []interface{}) error {
 	collectionName := g.dbConf.CollectionName
 	if collectionName == "" {
-		g.log.Warn("no collection name")
+		g.log.WithContext(ctx).Warn("no collection name")
 		return fmt.Errorf("no collection name")
 	}
 
-	g.log.Debug("Attempting to write ", len(data), " records...")
+	g.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	accumulateSet := g.AccumulateSet(data, true)
 
@@ -120,13 +120,13 @@ func (g *GraphMongoPump) WriteData(ctx context.Context, data []interface{}) erro
 					err error
 				)
 				if !r.GraphQLStats.IsGraphQL {
-					g.log.Warn("skipping record parsing")
+					g.log.WithContext(ctx).Warn("skipping record parsing")
 					gr = analytics.GraphRecord{AnalyticsRecord: *r}
 				} else {
 					gr = r.ToGraphRecord()
 					if err != nil {
 						errCh <- err
-						g.log.WithError(err).Warn("error converting 1 record to graph record")
+						g.log.WithContext(ctx).WithError(err).Warn("error converting 1 record to graph record")
 						continue
 					}
 				}
@@ -134,22 +134,22 @@ func (g *GraphMongoPump) WriteData(ctx context.Context, data []interface{}) erro
 				finalSet = append(finalSet, &gr)
 			}
 
-			g.log.WithFields(logrus.Fields{
+			g.log.WithContext(ctx).WithFields(logrus.Fields{
 				"collection":        collectionName,
 				"number of records": len(finalSet),
 			}).Debug("Attempt to purge records")
 			err := g.store.Insert(context.Background(), finalSet...)
 			if err != nil {
-				g.log.WithFields(logrus.Fields{"collection": collectionName, "number of records": len(finalSet)}).Error("Problem inserting to mongo collection: ", err)
+				g.log.WithContext(ctx).WithFields(logrus.Fields{"collection": collectionName, "number of records": len(finalSet)}).Error("Problem inserting to mongo collection: ", err)
 
 				if strings.Contains(strings.ToLower(err.Error()), "closed explicitly") {
-					g.log.Warning("--> Detected connection failure!")
+					g.log.WithContext(ctx).Warning("--> Detected connection failure!")
 				}
 				errCh <- err
 				return
 			}
 			errCh <- nil
-			g.log.WithFields(logrus.Fields{
+			g.log.WithContext(ctx).WithFields(logrus.Fields{
 				"collection":        collectionName,
 				"number of records": len(finalSet),
 			}).Info("Completed purging the records")
@@ -162,7 +162,7 @@ func (g *GraphMongoPump) WriteData(ctx context.Context, data []interface{}) erro
 			return err
 		}
 	}
-	g.log.Info("Purged ", len(data), " records...")
+	g.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
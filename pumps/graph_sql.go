@@ -6,7 +6,6 @@ import (
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	"github.com/mitchellh/mapstructure"
-	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -41,7 +40,7 @@ func (g *GraphSQLPump) New() Pump {
 }
 
 func (g *GraphSQLPump) Init(conf interface{}) error {
-	g.log = log.WithField("prefix", GraphSQLPrefix)
+	g.log = g.newLog(GraphSQLPrefix)
 
 	if err := mapstructure.Decode(conf, &g.Conf); err != nil {
 		g.log.WithError(err).Error("error decoding conf")
@@ -109,7 +108,7 @@ func (g *GraphSQLPump) GetEnvPrefix() string {
 }
 
 func (g *GraphSQLPump) WriteData(ctx context.Context, data []interface{}) error {
-	g.log.Debug("Attempting to write ", len(data), " records...")
+	g.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	graphRecords := g.getGraphRecords(data)
 	dataLen := len(graphRecords)
@@ -118,7 +117,7 @@ func (g *GraphSQLPump) WriteData(ctx context.Context, data []interface{}) error
 	endIndex := dataLen
 	// We iterate dataLen +1 times since we're writing the data after the date change on sharding_table:true
 	if dataLen == 0 {
-		g.log.Debug("no graphql records")
+		g.log.WithContext(ctx).Debug("no graphql records")
 		return nil
 	}
 	for i := 0; i <= dataLen; i++ {
@@ -144,8 +143,8 @@ func (g *GraphSQLPump) WriteData(ctx context.Context, data []interface{}) error
 			g.db = g.db.Table(table)
 			if !g.db.Migrator().HasTable(table) {
 				if err := g.db.AutoMigrate(&analytics.GraphRecord{}); err != nil {
-					g.log.Error("error creating table for record")
-					g.log.WithError(err).Debug("error creating table for record")
+					g.log.WithContext(ctx).Error("error creating table for record")
+					g.log.WithContext(ctx).WithError(err).Debug("error creating table for record")
 				}
 			}
 		} else {
@@ -161,18 +160,19 @@ func (g *GraphSQLPump) WriteData(ctx context.Context, data []interface{}) error
 			}
 			tx := g.db.WithContext(ctx).Create(recs[ri:ends])
 			if tx.Error != nil {
-				g.log.Error(tx.Error)
+				g.log.WithContext(ctx).Error(tx.Error)
 			}
 		}
 
 		startIndex = i // next day start index, necessary for sharded case
 	}
 
-	g.log.Info("Purged ", dataLen, " records...")
+	g.log.WithContext(ctx).Info("Purged ", dataLen, " records...")
 
 	return nil
 }
 
-func (g *GraphSQLPump) SetLogLevel(level logrus.Level) {
-	g.log.Level = level
+// CheckHealth pings the database.
+func (g *GraphSQLPump) CheckHealth(ctx context.Context) error {
+	return pingSQL(ctx, g.db)
 }
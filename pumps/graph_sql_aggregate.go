@@ -35,7 +35,7 @@ func (s *GraphSQLAggregatePump) New() Pump {
 
 func (s *GraphSQLAggregatePump) Init(conf interface{}) error {
 	s.SQLConf = &SQLAggregatePumpConf{}
-	s.log = log.WithField("prefix", SQLAggregatePumpPrefix)
+	s.log = s.newLog(SQLAggregatePumpPrefix)
 
 	err := mapstructure.Decode(conf, &s.SQLConf)
 	if err != nil {
@@ -87,7 +87,7 @@ func (s *GraphSQLAggregatePump) Init(conf interface{}) error {
 
 func (s *GraphSQLAggregatePump) WriteData(ctx context.Context, data []interface{}) error {
 	dataLen := len(data)
-	s.log.Debug("Attempting to write ", dataLen, " records...")
+	s.log.WithContext(ctx).Debug("Attempting to write ", dataLen, " records...")
 
 	if dataLen == 0 {
 		return nil
@@ -120,7 +120,7 @@ func (s *GraphSQLAggregatePump) WriteData(ctx context.Context, data []interface{
 			s.db = s.db.Table(table)
 			if !s.db.Migrator().HasTable(table) {
 				if err := s.db.AutoMigrate(&analytics.GraphSQLAnalyticsRecordAggregate{}); err != nil {
-					s.log.WithError(err).Warn("error running auto migration")
+					s.log.WithContext(ctx).WithError(err).Warn("error running auto migration")
 				}
 			}
 		} else {
@@ -142,14 +142,14 @@ func (s *GraphSQLAggregatePump) WriteData(ctx context.Context, data []interface{
 			ag := analyticsPerAPI[apiID]
 			err := s.DoAggregatedWriting(ctx, table, ag.OrgID, apiID, &ag)
 			if err != nil {
-				s.log.WithError(err).Error("error writing record")
+				s.log.WithContext(ctx).WithError(err).Error("error writing record")
 				return err
 			}
 		}
 
 		startIndex = i // next day start index, necessary for sharded case
 	}
-	s.log.Info("Purged ", dataLen, " records...")
+	s.log.WithContext(ctx).Info("Purged ", dataLen, " records...")
 
 	return nil
 }
@@ -193,3 +193,8 @@ func (s *GraphSQLAggregatePump) DoAggregatedWriting(ctx context.Context, table,
 
 	return nil
 }
+
+// CheckHealth pings the database.
+func (s *GraphSQLAggregatePump) CheckHealth(ctx context.Context) error {
+	return pingSQL(ctx, s.db)
+}
@@ -65,7 +65,7 @@ func (p *GraylogPump) GetEnvPrefix() string {
 func (p *GraylogPump) Init(conf interface{}) error {
 	p.conf = &GraylogConf{}
 
-	p.log = log.WithField("prefix", graylogPrefix)
+	p.log = p.newLog(graylogPrefix)
 
 	err := mapstructure.Decode(conf, &p.conf)
 	if err != nil {
@@ -99,7 +99,7 @@ func (p *GraylogPump) connect() {
 }
 
 func (p *GraylogPump) WriteData(ctx context.Context, data []interface{}) error {
-	p.log.Debug("Attempting to write ", len(data), " records...")
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	if p.client == nil {
 		p.connect()
@@ -111,13 +111,13 @@ func (p *GraylogPump) WriteData(ctx context.Context, data []interface{}) error {
 
 		rReq, err := base64.StdEncoding.DecodeString(record.RawRequest)
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
 		rResp, err := base64.StdEncoding.DecodeString(record.RawResponse)
 
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
 		mapping := map[string]interface{}{
@@ -146,7 +146,7 @@ func (p *GraylogPump) WriteData(ctx context.Context, data []interface{}) error {
 
 		message, err := json.Marshal(messageMap)
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
 		gelfData := map[string]interface{}{
@@ -159,14 +159,14 @@ func (p *GraylogPump) WriteData(ctx context.Context, data []interface{}) error {
 		gelfString, err := json.Marshal(gelfData)
 
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
-		p.log.Debug("Writing ", string(message))
+		p.log.WithContext(ctx).Debug("Writing ", string(message))
 
 		p.client.Log(string(gelfString))
 	}
-	p.log.Info("Purged ", len(data), " records...")
+	p.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -0,0 +1,181 @@
+package pumps
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/storage/persistent"
+	"gorm.io/gorm"
+)
+
+// HealthChecker is implemented by pumps that can check their backend is reachable, such
+// as with a database ping. The readiness endpoint reports a pump that fails its check as
+// unhealthy.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// errorRateWindow is the number of most recent writes a pump's error rate is computed
+// over.
+const errorRateWindow = 20
+
+type CircuitBreakerConf struct {
+	// The number of consecutive failed writes after which the pump is skipped, until
+	// `cooldown` has passed. Defaults to 0, which never skips the pump.
+	FailureThreshold int `json:"failure_threshold"`
+	// The number of seconds a tripped pump is skipped for, before a single write is let
+	// through to check whether it has recovered. Defaults to 30.
+	Cooldown int `json:"cooldown"`
+}
+
+// WriteStats tracks the outcome of the recent writes of a pump, and trips its circuit
+// breaker after too many consecutive failed writes. It's safe for concurrent use.
+type WriteStats struct {
+	mu sync.Mutex
+
+	breaker CircuitBreakerConf
+
+	lastWrite           time.Time
+	lastSuccess         time.Time
+	lastError           string
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+
+	// results holds whether each of the last errorRateWindow writes failed, as a ring.
+	results []bool
+	next    int
+}
+
+// WriteStatsSnapshot is the state of a WriteStats at a point in time.
+type WriteStatsSnapshot struct {
+	LastWrite   time.Time
+	LastSuccess time.Time
+	LastError   string
+	// ErrorRate is the ratio of failed writes among the most recent ones, from 0 to 1.
+	ErrorRate float64
+	Circuit   string
+}
+
+func NewWriteStats(breaker CircuitBreakerConf) *WriteStats {
+	if breaker.Cooldown <= 0 {
+		breaker.Cooldown = 30
+	}
+
+	return &WriteStats{breaker: breaker, results: make([]bool, 0, errorRateWindow)}
+}
+
+// Allow reports whether the pump should be written to. While the circuit is open it
+// returns false, until the cooldown has passed and a single write is let through.
+func (s *WriteStats) Allow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.circuit(time.Now()) {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if s.probing {
+			return false
+		}
+		s.probing = true
+	}
+
+	return true
+}
+
+// Record records the outcome of a write, err being nil if it succeeded.
+func (s *WriteStats) Record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.lastWrite = now
+	s.probing = false
+
+	if err != nil {
+		s.lastError = err.Error()
+		s.consecutiveFailures++
+		if s.breaker.FailureThreshold > 0 && s.consecutiveFailures >= s.breaker.FailureThreshold {
+			s.openedAt = now
+		}
+	} else {
+		s.lastSuccess = now
+		s.lastError = ""
+		s.consecutiveFailures = 0
+		s.openedAt = time.Time{}
+	}
+
+	if len(s.results) < errorRateWindow {
+		s.results = append(s.results, err != nil)
+	} else {
+		s.results[s.next] = err != nil
+	}
+	s.next = (s.next + 1) % errorRateWindow
+}
+
+func (s *WriteStats) Snapshot() WriteStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := WriteStatsSnapshot{
+		LastWrite:   s.lastWrite,
+		LastSuccess: s.lastSuccess,
+		LastError:   s.lastError,
+		Circuit:     s.circuit(time.Now()),
+	}
+
+	if len(s.results) > 0 {
+		failures := 0
+		for _, failed := range s.results {
+			if failed {
+				failures++
+			}
+		}
+		snapshot.ErrorRate = float64(failures) / float64(len(s.results))
+	}
+
+	return snapshot
+}
+
+func (s *WriteStats) circuit(now time.Time) string {
+	if s.openedAt.IsZero() {
+		return CircuitClosed
+	}
+	if now.Sub(s.openedAt) < time.Duration(s.breaker.Cooldown)*time.Second {
+		return CircuitOpen
+	}
+
+	return CircuitHalfOpen
+}
+
+var errNotConnected = errors.New("not connected")
+
+func pingStore(ctx context.Context, store persistent.PersistentStorage) error {
+	if store == nil {
+		return errNotConnected
+	}
+
+	return store.Ping(ctx)
+}
+
+func pingSQL(ctx context.Context, db *gorm.DB) error {
+	if db == nil {
+		return errNotConnected
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
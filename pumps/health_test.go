@@ -0,0 +1,57 @@
+package pumps
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteStats_ErrorRate(t *testing.T) {
+	stats := NewWriteStats(CircuitBreakerConf{})
+	assert.Equal(t, 0.0, stats.Snapshot().ErrorRate)
+
+	stats.Record(nil)
+	stats.Record(errors.New("boom"))
+	snapshot := stats.Snapshot()
+	assert.Equal(t, 0.5, snapshot.ErrorRate)
+	assert.Equal(t, "boom", snapshot.LastError)
+	assert.False(t, snapshot.LastSuccess.IsZero())
+
+	// only the most recent writes count
+	for i := 0; i < errorRateWindow; i++ {
+		stats.Record(nil)
+	}
+	snapshot = stats.Snapshot()
+	assert.Equal(t, 0.0, snapshot.ErrorRate)
+	assert.Empty(t, snapshot.LastError)
+	assert.Equal(t, CircuitClosed, snapshot.Circuit, "a disabled circuit breaker never opens")
+}
+
+func TestWriteStats_CircuitBreaker(t *testing.T) {
+	stats := NewWriteStats(CircuitBreakerConf{FailureThreshold: 2, Cooldown: 1})
+
+	assert.True(t, stats.Allow())
+	stats.Record(errors.New("boom"))
+	assert.True(t, stats.Allow(), "a single failure must not trip the circuit")
+	stats.Record(errors.New("boom"))
+
+	assert.Equal(t, CircuitOpen, stats.Snapshot().Circuit)
+	assert.False(t, stats.Allow())
+
+	// once the cooldown has passed a single write is let through
+	stats.openedAt = time.Now().Add(-2 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, stats.Snapshot().Circuit)
+	assert.True(t, stats.Allow())
+	assert.False(t, stats.Allow())
+
+	stats.Record(errors.New("boom"))
+	assert.Equal(t, CircuitOpen, stats.Snapshot().Circuit, "a failed probe must trip the circuit again")
+
+	stats.openedAt = time.Now().Add(-2 * time.Second)
+	assert.True(t, stats.Allow())
+	stats.Record(nil)
+	assert.Equal(t, CircuitClosed, stats.Snapshot().Circuit)
+	assert.True(t, stats.Allow())
+}
@@ -128,7 +128,7 @@ func (p *HybridPump) New() Pump {
 }
 
 func (p *HybridPump) Init(config interface{}) error {
-	p.log = log.WithField("prefix", hybridPrefix)
+	p.log = p.newLog(hybridPrefix)
 
 	// Read configuration file
 	p.hybridConfig = &HybridPumpConf{}
@@ -261,19 +261,19 @@ func (p *HybridPump) WriteData(ctx context.Context, data []interface{}) error {
 	if len(data) == 0 {
 		return nil
 	}
-	p.log.Debug("Attempting to write ", len(data), " records...")
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	err := p.RPCLogin()
 	if err != nil {
 		if errors.Is(err, ErrRPCLogin) {
-			p.log.Error("Failed to login to Tyk MDCB: ", err)
+			p.log.WithContext(ctx).Error("Failed to login to Tyk MDCB: ", err)
 			return err
 		}
-		p.log.Error("Failed to connect to Tyk MDCB, retrying")
+		p.log.WithContext(ctx).Error("Failed to connect to Tyk MDCB, retrying")
 
 		// try to login again
 		if err = p.connectAndLogin(false); err != nil {
-			p.log.Error(err)
+			p.log.WithContext(ctx).Error(err)
 			return err
 		}
 	}
@@ -284,14 +284,14 @@ func (p *HybridPump) WriteData(ctx context.Context, data []interface{}) error {
 		// turn array with analytics records into JSON payload
 		jsonData, err := json.Marshal(data)
 		if err != nil {
-			p.log.WithError(err).Error("Failed to marshal analytics data")
+			p.log.WithContext(ctx).WithError(err).Error("Failed to marshal analytics data")
 			return err
 		}
 
-		p.log.Debug("Sending analytics data to Tyk MDCB")
+		p.log.WithContext(ctx).Debug("Sending analytics data to Tyk MDCB")
 
 		if _, err := p.callRPCFn("PurgeAnalyticsData", string(jsonData)); err != nil {
-			p.log.WithError(err).Error("Failed to call PurgeAnalyticsData")
+			p.log.WithContext(ctx).WithError(err).Error("Failed to call PurgeAnalyticsData")
 			return err
 		}
 	} else {
@@ -301,15 +301,15 @@ func (p *HybridPump) WriteData(ctx context.Context, data []interface{}) error {
 		// turn map with analytics aggregates into JSON payload
 		jsonData, err := json.Marshal(aggregates)
 		if err != nil {
-			p.log.WithError(err).Error("Failed to marshal analytics aggregates data")
+			p.log.WithContext(ctx).WithError(err).Error("Failed to marshal analytics aggregates data")
 			return err
 		}
 
-		p.log.Debug("Sending aggregated analytics data to Tyk MDCB")
+		p.log.WithContext(ctx).Debug("Sending aggregated analytics data to Tyk MDCB")
 
 		// send aggregated data
 		if _, err := p.callRPCFn("PurgeAnalyticsDataAggregated", string(jsonData)); err != nil {
-			p.log.WithError(err).Error("Failed to call PurgeAnalyticsDataAggregated")
+			p.log.WithContext(ctx).WithError(err).Error("Failed to call PurgeAnalyticsDataAggregated")
 			return err
 		}
 
@@ -320,7 +320,7 @@ func (p *HybridPump) WriteData(ctx context.Context, data []interface{}) error {
 			}
 		}
 	}
-	p.log.Info("Purged ", len(data), " records...")
+	p.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
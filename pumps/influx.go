@@ -60,7 +60,7 @@ func (i *InfluxPump) GetEnvPrefix() string {
 
 func (i *InfluxPump) Init(config interface{}) error {
 	i.dbConf = &InfluxConf{}
-	i.log = log.WithField("prefix", influxPrefix)
+	i.log = i.newLog(influxPrefix)
 
 	err := mapstructure.Decode(config, &i.dbConf)
 	if err != nil {
@@ -96,7 +96,7 @@ func (i *InfluxPump) connect() client.Client {
 func (i *InfluxPump) WriteData(ctx context.Context, data []interface{}) error {
 	c := i.connect()
 	defer c.Close()
-	i.log.Debug("Attempting to write ", len(data), " records...")
+	i.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	bp, _ := client.NewBatchPoints(client.BatchPointsConfig{
 		Database:  i.dbConf.DatabaseName,
@@ -151,7 +151,7 @@ func (i *InfluxPump) WriteData(ctx context.Context, data []interface{}) error {
 
 		// New record
 		if pt, err = client.NewPoint(table, tags, fields, time.Now()); err != nil {
-			i.log.Error(err)
+			i.log.WithContext(ctx).Error(err)
 			continue
 		}
 
@@ -161,7 +161,7 @@ func (i *InfluxPump) WriteData(ctx context.Context, data []interface{}) error {
 
 	// Now that all points are added, write the batch
 	c.Write(bp)
-	i.log.Info("Purged ", len(data), " records...")
+	i.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -86,7 +86,7 @@ func (i *Influx2Pump) GetEnvPrefix() string {
 
 func (i *Influx2Pump) Init(config interface{}) error {
 	i.dbConf = &Influx2Conf{}
-	i.log = log.WithField("prefix", influx2Prefix)
+	i.log = i.newLog(influx2Prefix)
 
 	err := mapstructure.Decode(config, &i.dbConf)
 	if err != nil {
@@ -184,7 +184,7 @@ func (i *Influx2Pump) createBucket(ctx context.Context, orgID *string) (*domain.
 }
 
 func (i *Influx2Pump) WriteData(ctx context.Context, data []interface{}) error {
-	i.log.Debug("Attempting to write ", len(data), " records...")
+	i.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	writeApi := i.client.WriteAPI(i.dbConf.OrgName, i.dbConf.BucketName)
 
@@ -239,7 +239,7 @@ func (i *Influx2Pump) WriteData(ctx context.Context, data []interface{}) error {
 	if i.dbConf.Flush {
 		writeApi.Flush()
 	}
-	i.log.Info("Purged ", len(data), " records...")
+	i.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -42,4 +42,11 @@ func init() {
 	AvailablePumps["resurfaceio"] = &ResurfacePump{}
 	AvailablePumps["sqs"] = &SQSPump{}
 	AvailablePumps["kinesis"] = &KinesisPump{}
+	AvailablePumps["otlp"] = &OTLPPump{}
+	AvailablePumps["clickhouse"] = &ClickHousePump{}
+	AvailablePumps["loki"] = &LokiPump{}
+	AvailablePumps["parquet"] = &ParquetPump{}
+	AvailablePumps["s3"] = &S3Pump{}
+	AvailablePumps["webhook"] = &WebhookPump{}
+	AvailablePumps["nats"] = &NATSPump{}
 }
@@ -44,6 +44,9 @@ type KafkaConf struct {
 	ClientId string `json:"client_id" mapstructure:"client_id"`
 	// The topic that the writer will produce messages to.
 	Topic string `json:"topic" mapstructure:"topic"`
+	// The topic uptime records are produced to, when the pump has `uptime` enabled. Defaults
+	// to `topic`.
+	UptimeTopic string `json:"uptime_topic" mapstructure:"uptime_topic"`
 	// Timeout is the maximum amount of seconds to wait for a connect or write to complete.
 	Timeout interface{} `json:"timeout" mapstructure:"timeout"`
 	// Enable "github.com/golang/snappy" codec to be used to compress Kafka messages. By default
@@ -90,7 +93,7 @@ func (k *KafkaPump) GetEnvPrefix() string {
 }
 
 func (k *KafkaPump) Init(config interface{}) error {
-	k.log = log.WithField("prefix", kafkaPrefix)
+	k.log = k.newLog(kafkaPrefix)
 
 	//Read configuration file
 	k.kafkaConf = &KafkaConf{}
@@ -105,40 +108,17 @@ func (k *KafkaPump) Init(config interface{}) error {
 		k.kafkaConf.Timeout = os.Getenv("TYK_PMP_PUMPS_KAFKA_META_TIMEOUT")
 	}
 
-	var tlsConfig *tls.Config
-	if k.kafkaConf.UseSSL {
-		tlsConfig, err = NewTLSConfig(TLSConfig{
-			CertFile:           k.kafkaConf.SSLCertFile,
-			KeyFile:            k.kafkaConf.SSLKeyFile,
-			CAFile:             k.kafkaConf.SSLCAFile,
-			InsecureSkipVerify: k.kafkaConf.SSLInsecureSkipVerify,
-		}, k.log)
-		if err != nil {
-			return fmt.Errorf("failed to initialize Kafka pump SSL configuration: %w", err)
-		}
-	} else if k.kafkaConf.SASLMechanism != "" {
+	tlsConfig, err := k.tlsConfig(k.kafkaConf)
+	if err != nil {
+		return err
+	}
+	if !k.kafkaConf.UseSSL && k.kafkaConf.SASLMechanism != "" {
 		k.log.WithField("SASL-Mechanism", k.kafkaConf.SASLMechanism).Warn("SASL-Mechanism is setted but use_ssl is false.")
 	}
 
-	var mechanism sasl.Mechanism
-
-	switch k.kafkaConf.SASLMechanism {
-	case "":
-		break
-	case "PLAIN", "plain":
-		mechanism = plain.Mechanism{Username: k.kafkaConf.Username, Password: k.kafkaConf.Password}
-	case "SCRAM", "scram":
-		algorithm := scram.SHA256
-		if k.kafkaConf.Algorithm == "sha-512" || k.kafkaConf.Algorithm == "SHA-512" {
-			algorithm = scram.SHA512
-		}
-		var mechErr error
-		mechanism, mechErr = scram.Mechanism(algorithm, k.kafkaConf.Username, k.kafkaConf.Password)
-		if mechErr != nil {
-			k.log.Fatal("Failed initialize kafka mechanism  : ", mechErr)
-		}
-	default:
-		k.log.WithField("SASL-Mechanism", k.kafkaConf.SASLMechanism).Warn("Tyk pump doesn't support this SASL mechanism.")
+	mechanism, mechErr := k.saslMechanism(k.kafkaConf)
+	if mechErr != nil {
+		k.log.Fatal("Failed initialize kafka mechanism  : ", mechErr)
 	}
 
 	// Timeout is an interface type to allow both time.Duration and float values
@@ -187,9 +167,86 @@ func (k *KafkaPump) Init(config interface{}) error {
 	return nil
 }
 
+// tlsConfig returns the TLS configuration of conf, nil when it doesn't use SSL.
+func (k *KafkaPump) tlsConfig(conf *KafkaConf) (*tls.Config, error) {
+	if !conf.UseSSL {
+		return nil, nil
+	}
+
+	tlsConfig, err := NewTLSConfig(TLSConfig{
+		CertFile:           conf.SSLCertFile,
+		KeyFile:            conf.SSLKeyFile,
+		CAFile:             conf.SSLCAFile,
+		InsecureSkipVerify: conf.SSLInsecureSkipVerify,
+	}, k.log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Kafka pump SSL configuration: %w", err)
+	}
+	return tlsConfig, nil
+}
+
+// saslMechanism returns the SASL mechanism of conf, authenticating with its credentials,
+// nil when it sets none.
+func (k *KafkaPump) saslMechanism(conf *KafkaConf) (sasl.Mechanism, error) {
+	switch conf.SASLMechanism {
+	case "":
+		return nil, nil
+	case "PLAIN", "plain":
+		return plain.Mechanism{Username: conf.Username, Password: conf.Password}, nil
+	case "SCRAM", "scram":
+		algorithm := scram.SHA256
+		if conf.Algorithm == "sha-512" || conf.Algorithm == "SHA-512" {
+			algorithm = scram.SHA512
+		}
+		return scram.Mechanism(algorithm, conf.Username, conf.Password)
+	default:
+		k.log.WithField("SASL-Mechanism", conf.SASLMechanism).Warn("Tyk pump doesn't support this SASL mechanism.")
+		return nil, nil
+	}
+}
+
+// RefreshCredentials authenticates the next writes with the SASL credentials and
+// certificates of conf, a *KafkaConf, when they changed.
+func (k *KafkaPump) RefreshCredentials(_ context.Context, conf any) error {
+	newConf, ok := conf.(*KafkaConf)
+	if !ok {
+		return unexpectedConfError(conf)
+	}
+
+	kafkaConf := *k.kafkaConf
+	kafkaConf.Username = newConf.Username
+	kafkaConf.Password = newConf.Password
+	kafkaConf.SSLCertFile = newConf.SSLCertFile
+	kafkaConf.SSLKeyFile = newConf.SSLKeyFile
+	kafkaConf.SSLCAFile = newConf.SSLCAFile
+	if kafkaConf.Username == k.kafkaConf.Username && kafkaConf.Password == k.kafkaConf.Password &&
+		kafkaConf.SSLCertFile == k.kafkaConf.SSLCertFile && kafkaConf.SSLKeyFile == k.kafkaConf.SSLKeyFile &&
+		kafkaConf.SSLCAFile == k.kafkaConf.SSLCAFile {
+		return nil
+	}
+
+	tlsConfig, err := k.tlsConfig(&kafkaConf)
+	if err != nil {
+		return err
+	}
+	mechanism, err := k.saslMechanism(&kafkaConf)
+	if err != nil {
+		return err
+	}
+
+	// A writer is created for every write, with the dialer of the writer config.
+	dialer := *k.writerConfig.Dialer
+	dialer.TLS, dialer.SASLMechanism = tlsConfig, mechanism
+	k.writerConfig.Dialer = &dialer
+	k.kafkaConf = &kafkaConf
+
+	k.log.Info("Authenticating with the refreshed credentials")
+	return nil
+}
+
 func (k *KafkaPump) WriteData(ctx context.Context, data []interface{}) error {
 	startTime := time.Now()
-	k.log.Debug("Attempting to write ", len(data), " records...")
+	k.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 	kafkaMessages := make([]kafka.Message, len(data))
 	for i, v := range data {
 		//Build message format
@@ -224,7 +281,7 @@ func (k *KafkaPump) WriteData(ctx context.Context, data []interface{}) error {
 		//Transform object to json string
 		json, jsonError := json.Marshal(message)
 		if jsonError != nil {
-			k.log.WithError(jsonError).Error("unable to marshal message")
+			k.log.WithContext(ctx).WithError(jsonError).Error("unable to marshal message")
 		}
 
 		//Kafka message structure
@@ -236,10 +293,58 @@ func (k *KafkaPump) WriteData(ctx context.Context, data []interface{}) error {
 	//Send kafka message
 	kafkaError := k.write(ctx, kafkaMessages)
 	if kafkaError != nil {
-		k.log.WithError(kafkaError).Error("unable to write message")
+		k.log.WithContext(ctx).WithError(kafkaError).Error("unable to write message")
+	}
+	k.log.WithContext(ctx).Debug("ElapsedTime in seconds for ", len(data), " records:", time.Now().Sub(startTime))
+	k.log.WithContext(ctx).Info("Purged ", len(data), " records...")
+	return nil
+}
+
+// WriteUptimeRecords produces every uptime record to `uptime_topic`, with the same
+// static metadata as analytics records.
+func (k *KafkaPump) WriteUptimeRecords(ctx context.Context, data []analytics.UptimeReportData) error {
+	startTime := time.Now()
+	k.log.WithContext(ctx).Debug("Attempting to write ", len(data), " uptime records...")
+	kafkaMessages := make([]kafka.Message, len(data))
+	for i, decoded := range data {
+		message := Json{
+			"timestamp":       decoded.TimeStamp,
+			"url":             decoded.URL,
+			"request_time_ms": decoded.RequestTime,
+			"response_code":   decoded.ResponseCode,
+			"tcp_error":       decoded.TCPError,
+			"server_error":    decoded.ServerError,
+			"api_id":          decoded.APIID,
+			"org_id":          decoded.OrgID,
+		}
+		for key, value := range k.kafkaConf.MetaData {
+			message[key] = value
+		}
+
+		json, jsonError := json.Marshal(message)
+		if jsonError != nil {
+			k.log.WithContext(ctx).WithError(jsonError).Error("unable to marshal uptime message")
+		}
+
+		kafkaMessages[i] = kafka.Message{
+			Time:  time.Now(),
+			Value: json,
+		}
+	}
+
+	writerConfig := k.writerConfig
+	if k.kafkaConf.UptimeTopic != "" {
+		writerConfig.Topic = k.kafkaConf.UptimeTopic
+	}
+	kafkaWriter := kafka.NewWriter(writerConfig)
+	defer kafkaWriter.Close()
+
+	if err := kafkaWriter.WriteMessages(ctx, kafkaMessages...); err != nil {
+		k.log.WithContext(ctx).WithError(err).Error("unable to write uptime message")
+		return err
 	}
-	k.log.Debug("ElapsedTime in seconds for ", len(data), " records:", time.Now().Sub(startTime))
-	k.log.Info("Purged ", len(data), " records...")
+	k.log.WithContext(ctx).Debug("ElapsedTime in seconds for ", len(data), " uptime records:", time.Since(startTime))
+	k.log.WithContext(ctx).Info("Purged ", len(data), " uptime records...")
 	return nil
 }
 
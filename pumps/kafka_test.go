@@ -1,12 +1,14 @@
 package pumps
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -358,3 +360,29 @@ func TestKafkaPump_InitTLSConfig(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to initialize Kafka pump SSL configuration")
 	})
 }
+
+func TestKafkaPump_RefreshCredentials(t *testing.T) {
+	pump := &KafkaPump{}
+	err := pump.Init(map[string]any{
+		"broker":         []string{"localhost:9092"},
+		"topic":          "test-topic",
+		"client_id":      "test-client",
+		"sasl_mechanism": "plain",
+		"sasl_username":  "pump",
+		"sasl_password":  "old-password",
+	})
+	assert.NoError(t, err)
+	dialer := pump.writerConfig.Dialer
+
+	assert.NoError(t, pump.RefreshCredentials(context.Background(), &KafkaConf{Username: "pump", Password: "old-password"}))
+	assert.Same(t, dialer, pump.writerConfig.Dialer, "the credentials didn't change")
+
+	assert.NoError(t, pump.RefreshCredentials(context.Background(), &KafkaConf{Username: "pump", Password: "new-password", Topic: "ignored"}))
+	assert.Equal(t, plain.Mechanism{Username: "pump", Password: "new-password"}, pump.writerConfig.Dialer.SASLMechanism)
+	assert.Equal(t, "test-client", pump.writerConfig.Dialer.ClientID, "the other dialer settings are kept")
+	assert.Equal(t, "test-topic", pump.writerConfig.Topic, "the other settings are kept")
+	assert.Equal(t, "new-password", pump.kafkaConf.Password)
+
+	err = pump.RefreshCredentials(context.Background(), &SplunkPumpConfig{})
+	assert.EqualError(t, err, "unexpected configuration of type *pumps.SplunkPumpConfig")
+}
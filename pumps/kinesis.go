@@ -67,7 +67,7 @@ func (p *KinesisPump) New() Pump {
 
 // Init initializes the pump with configuration settings.
 func (p *KinesisPump) Init(config interface{}) error {
-	p.log = log.WithField("prefix", kinesisPrefix)
+	p.log = p.newLog(kinesisPrefix)
 
 	// Read configuration file
 	p.kinesisConf = &KinesisConf{}
@@ -153,7 +153,7 @@ func (p *KinesisPump) WriteData(ctx context.Context, records []interface{}) erro
 			// Build message format
 			decoded, ok := record.(analytics.AnalyticsRecord)
 			if !ok {
-				p.log.WithField("record", record).Error("unable to decode record")
+				p.log.WithContext(ctx).WithField("record", record).Error("unable to decode record")
 				continue
 			}
 			//nolint:dupl
@@ -183,12 +183,12 @@ func (p *KinesisPump) WriteData(ctx context.Context, records []interface{}) erro
 			// Transform object to json string
 			json, jsonError := json.Marshal(analyticsRecord)
 			if jsonError != nil {
-				p.log.WithError(jsonError).Error("unable to marshal message")
+				p.log.WithContext(ctx).WithError(jsonError).Error("unable to marshal message")
 			}
 
 			n, err := rand.Int(rand.Reader, big.NewInt(1000000000))
 			if err != nil {
-				p.log.Error("failed to generate int for Partition key: ", err)
+				p.log.WithContext(ctx).Error("failed to generate int for Partition key: ", err)
 			}
 
 			// Partition key uses a string representation of Int
@@ -211,18 +211,18 @@ func (p *KinesisPump) WriteData(ctx context.Context, records []interface{}) erro
 
 		output, err := p.client.PutRecords(ctx, input)
 		if err != nil {
-			p.log.Error("failed to put records to Kinesis: ", err)
+			p.log.WithContext(ctx).Error("failed to put records to Kinesis: ", err)
 		}
 
 		// Check for failed records
 		if output != nil {
 			for _, record := range output.Records {
 				if record.ErrorCode != nil {
-					p.log.Debugf("Failed to put record: %s - %s", aws.ToString(record.ErrorCode), aws.ToString(record.ErrorMessage))
+					p.log.WithContext(ctx).Debugf("Failed to put record: %s - %s", aws.ToString(record.ErrorCode), aws.ToString(record.ErrorMessage))
 				}
-				p.log.Debug(record)
+				p.log.WithContext(ctx).Debug(record)
 			}
-			p.log.Info("Purged ", len(output.Records), " records...")
+			p.log.WithContext(ctx).Info("Purged ", len(output.Records), " records...")
 		}
 	}
 	return nil
@@ -111,7 +111,7 @@ func (p *LogzioPump) GetEnvPrefix() string {
 
 func (p *LogzioPump) Init(config interface{}) error {
 	p.config = NewLogzioPumpConfig()
-	p.log = log.WithField("prefix", LogzioPumpPrefix)
+	p.log = p.newLog(LogzioPumpPrefix)
 
 	err := mapstructure.Decode(config, p.config)
 	if err != nil {
@@ -132,7 +132,7 @@ func (p *LogzioPump) Init(config interface{}) error {
 }
 
 func (p *LogzioPump) WriteData(ctx context.Context, data []interface{}) error {
-	p.log.Debug("Attempting to write ", len(data), " records...")
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	for _, v := range data {
 		decoded := v.(analytics.AnalyticsRecord)
@@ -160,7 +160,7 @@ func (p *LogzioPump) WriteData(ctx context.Context, data []interface{}) error {
 
 		p.sender.Send(event)
 	}
-	p.log.Info("Purged ", len(data), " records...")
+	p.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
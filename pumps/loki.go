@@ -0,0 +1,455 @@
+package pumps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/retry"
+	"github.com/golang/snappy"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// LokiPump pushes the analytics records to Grafana Loki, as JSON log lines of streams
+// labelled with the low-cardinality fields of the records.
+type LokiPump struct {
+	conf       *LokiConf
+	pushURL    string
+	httpClient *http.Client
+	retry      *retry.BackoffHTTPRetry
+	CommonPumpConfig
+}
+
+var (
+	lokiPrefix     = "loki-pump"
+	lokiDefaultENV = PUMPS_ENV_PREFIX + "_LOKI" + PUMPS_ENV_META_PREFIX
+
+	lokiDefaultLabels = []string{"api_name", "org_id", "method", "status_class"}
+)
+
+const (
+	lokiPushPath         = "/loki/api/v1/push"
+	lokiEncodingProtobuf = "protobuf"
+	lokiEncodingJSON     = "json"
+	lokiDefaultBatchSize = 1000
+	lokiDefaultTimeout   = 10
+)
+
+// lokiField returns the value of a field of record.
+type lokiField func(record *analytics.AnalyticsRecord) string
+
+// lokiLabelFields are the fields the streams can be labelled with, the ones with few
+// enough values for Loki to index them.
+var lokiLabelFields = map[string]lokiField{
+	"api_id":        func(r *analytics.AnalyticsRecord) string { return r.APIID },
+	"api_name":      func(r *analytics.AnalyticsRecord) string { return r.APIName },
+	"api_version":   func(r *analytics.AnalyticsRecord) string { return r.APIVersion },
+	"org_id":        func(r *analytics.AnalyticsRecord) string { return r.OrgID },
+	"host":          func(r *analytics.AnalyticsRecord) string { return r.Host },
+	"listen_path":   func(r *analytics.AnalyticsRecord) string { return r.ListenPath },
+	"method":        func(r *analytics.AnalyticsRecord) string { return r.Method },
+	"response_code": func(r *analytics.AnalyticsRecord) string { return strconv.Itoa(r.ResponseCode) },
+	"status_class": func(r *analytics.AnalyticsRecord) string {
+		if r.ResponseCode <= 0 {
+			return ""
+		}
+		return strconv.Itoa(r.ResponseCode/100) + "xx"
+	},
+}
+
+// lokiMetadataFields are the fields the entries can carry as structured metadata: the
+// label ones and the high-cardinality ones.
+var lokiMetadataFields = func() map[string]lokiField {
+	fields := map[string]lokiField{
+		"path":             func(r *analytics.AnalyticsRecord) string { return r.Path },
+		"ip_address":       func(r *analytics.AnalyticsRecord) string { return r.IPAddress },
+		"api_key":          func(r *analytics.AnalyticsRecord) string { return r.APIKey },
+		"oauth_id":         func(r *analytics.AnalyticsRecord) string { return r.OauthID },
+		"alias":            func(r *analytics.AnalyticsRecord) string { return r.Alias },
+		"user_agent":       func(r *analytics.AnalyticsRecord) string { return r.UserAgent },
+		"request_time":     func(r *analytics.AnalyticsRecord) string { return strconv.FormatInt(r.RequestTime, 10) },
+		"latency_total":    func(r *analytics.AnalyticsRecord) string { return strconv.FormatInt(r.Latency.Total, 10) },
+		"latency_upstream": func(r *analytics.AnalyticsRecord) string { return strconv.FormatInt(r.Latency.Upstream, 10) },
+	}
+	for name, field := range lokiLabelFields {
+		fields[name] = field
+	}
+	return fields
+}()
+
+// @PumpConf Loki
+type LokiConf struct {
+	// The prefix for the environment variables that will be used to override the configuration.
+	// Defaults to `TYK_PMP_PUMPS_LOKI_META`
+	EnvPrefix string `mapstructure:"meta_env_prefix"`
+	// The URL of Loki, such as `http://loki:3100`. The records are pushed to its
+	// `/loki/api/v1/push` endpoint.
+	URL string `json:"url" mapstructure:"url"`
+	// The encoding of the pushes, `protobuf`, snappy compressed, or `json`. Defaults to
+	// `protobuf`.
+	Encoding string `json:"encoding" mapstructure:"encoding"`
+	// The tenant the records are pushed to, sent as the `X-Scope-OrgID` header to
+	// multi-tenant Loki.
+	TenantID string `json:"tenant_id" mapstructure:"tenant_id"`
+	// The user of the basic authentication.
+	Username string `json:"username" mapstructure:"username"`
+	// The password of the basic authentication.
+	Password string `json:"password" mapstructure:"password"`
+	// The token of the bearer authentication, used instead of the basic one.
+	BearerToken string `json:"bearer_token" mapstructure:"bearer_token"`
+	// The fields of the records the streams are labelled with: `api_id`, `api_name`,
+	// `api_version`, `org_id`, `host`, `listen_path`, `method`, `response_code` or
+	// `status_class`, such as `2xx`. Defaults to `api_name`, `org_id`, `method` and
+	// `status_class`.
+	Labels []string `json:"labels" mapstructure:"labels"`
+	// Labels with the same value for every stream. Defaults to `job` set to `tyk-pump`.
+	StaticLabels map[string]string `json:"static_labels" mapstructure:"static_labels"`
+	// The fields of the records the entries carry as structured metadata, a label field or
+	// one of `path`, `ip_address`, `api_key`, `oauth_id`, `alias`, `user_agent`,
+	// `request_time`, `latency_total` and `latency_upstream`. They're in the log line
+	// anyway, structured metadata requiring Loki 3.
+	StructuredMetadata []string `json:"structured_metadata" mapstructure:"structured_metadata"`
+	// The maximum number of records of a push. Defaults to 1000.
+	BatchSize int `json:"batch_size" mapstructure:"batch_size"`
+	// The timeout, in seconds, of a push. Defaults to 10.
+	Timeout int `json:"timeout" mapstructure:"timeout"`
+	// The number of times a push failing with a 429, a 5xx or a network error is retried,
+	// with an exponential backoff. Defaults to 0.
+	MaxRetries uint64 `json:"max_retries" mapstructure:"max_retries"`
+	// Controls whether the pump client verifies Loki's certificate chain and host name.
+	SSLInsecureSkipVerify bool `json:"ssl_insecure_skip_verify" mapstructure:"ssl_insecure_skip_verify"`
+	// Path to the PEM file with trusted CA certificates that will be used to verify Loki's
+	// certificate.
+	SSLCAFile string `json:"ssl_ca_file" mapstructure:"ssl_ca_file"`
+	// SSL cert file location, for mTLS.
+	SSLCertFile string `json:"ssl_cert_file" mapstructure:"ssl_cert_file"`
+	// SSL cert key location, for mTLS.
+	SSLKeyFile string `json:"ssl_key_file" mapstructure:"ssl_key_file"`
+	// SSL Server name used in the TLS connection.
+	SSLServerName string `json:"ssl_server_name" mapstructure:"ssl_server_name"`
+}
+
+func (p *LokiPump) New() Pump {
+	return &LokiPump{}
+}
+
+func (p *LokiPump) GetName() string {
+	return "Loki Pump"
+}
+
+func (p *LokiPump) GetEnvPrefix() string {
+	return p.conf.EnvPrefix
+}
+
+func (p *LokiPump) Init(config interface{}) error {
+	p.conf = &LokiConf{}
+	p.log = p.newLog(lokiPrefix)
+
+	if err := mapstructure.Decode(config, p.conf); err != nil {
+		p.log.Error("Failed to decode configuration: ", err)
+		return err
+	}
+
+	processPumpEnvVars(p, p.log, p.conf, lokiDefaultENV)
+
+	if p.conf.URL == "" {
+		return errors.New("url is required")
+	}
+	switch p.conf.Encoding {
+	case "":
+		p.conf.Encoding = lokiEncodingProtobuf
+	case lokiEncodingProtobuf, lokiEncodingJSON:
+	default:
+		return fmt.Errorf("unsupported encoding %q, must be %q or %q", p.conf.Encoding, lokiEncodingProtobuf, lokiEncodingJSON)
+	}
+	if p.conf.Labels == nil {
+		p.conf.Labels = lokiDefaultLabels
+	}
+	for _, label := range p.conf.Labels {
+		if _, ok := lokiLabelFields[label]; ok {
+			continue
+		}
+		if _, ok := lokiMetadataFields[label]; ok {
+			return fmt.Errorf("label %q has too many values for Loki to index, it can be structured metadata instead", label)
+		}
+		return fmt.Errorf("unsupported label %q", label)
+	}
+	if len(p.conf.StaticLabels) == 0 {
+		p.conf.StaticLabels = map[string]string{"job": "tyk-pump"}
+	}
+	for _, label := range p.conf.Labels {
+		if _, ok := p.conf.StaticLabels[label]; ok {
+			return fmt.Errorf("label %q is both a static label and a record one", label)
+		}
+	}
+	for _, field := range p.conf.StructuredMetadata {
+		if _, ok := lokiMetadataFields[field]; !ok {
+			return fmt.Errorf("unsupported structured metadata %q", field)
+		}
+	}
+	if p.conf.BatchSize <= 0 {
+		p.conf.BatchSize = lokiDefaultBatchSize
+	}
+	if p.conf.Timeout <= 0 {
+		p.conf.Timeout = lokiDefaultTimeout
+	}
+
+	tlsConfig, err := NewTLSConfig(TLSConfig{
+		CertFile:           p.conf.SSLCertFile,
+		KeyFile:            p.conf.SSLKeyFile,
+		CAFile:             p.conf.SSLCAFile,
+		ServerName:         p.conf.SSLServerName,
+		InsecureSkipVerify: p.conf.SSLInsecureSkipVerify,
+	}, p.log)
+	if err != nil {
+		return err
+	}
+	p.pushURL = strings.TrimSuffix(p.conf.URL, "/") + lokiPushPath
+	p.httpClient = &http.Client{
+		Timeout: time.Duration(p.conf.Timeout) * time.Second,
+		Transport: &tracingTransport{base: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}},
+	}
+	p.retry = retry.NewBackoffRetry("Failed pushing to Loki", p.conf.MaxRetries, p.httpClient, p.log)
+
+	p.log.Info(p.GetName() + " Initialized")
+	return nil
+}
+
+func (p *LokiPump) WriteData(ctx context.Context, data []interface{}) error {
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
+
+	records := make([]analytics.AnalyticsRecord, 0, len(data))
+	for _, v := range data {
+		if record, ok := v.(analytics.AnalyticsRecord); ok {
+			records = append(records, record)
+		}
+	}
+
+	for start := 0; start < len(records); start += p.conf.BatchSize {
+		end := start + p.conf.BatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		streams, err := p.streams(records[start:end])
+		if err != nil {
+			return err
+		}
+		if err := p.push(ctx, streams); err != nil {
+			p.log.WithContext(ctx).Error("Failed to push the records: ", err)
+			return err
+		}
+	}
+
+	p.log.WithContext(ctx).Info("Purged ", len(records), " records...")
+	return nil
+}
+
+// lokiStream is a stream of the entries with the same labels.
+type lokiStream struct {
+	labels  []lokiLabel
+	entries []lokiEntry
+}
+
+type lokiLabel struct {
+	name, value string
+}
+
+type lokiEntry struct {
+	timestamp time.Time
+	line      string
+	metadata  []lokiLabel
+}
+
+// streams returns the streams of records, by labels, their entries in time order.
+func (p *LokiPump) streams(records []analytics.AnalyticsRecord) ([]*lokiStream, error) {
+	byLabels := map[string]*lokiStream{}
+	for i := range records {
+		record := &records[i]
+
+		labels := p.labels(record)
+		key := lokiLabelString(labels)
+		stream, ok := byLabels[key]
+		if !ok {
+			stream = &lokiStream{labels: labels}
+			byLabels[key] = stream
+		}
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		entry := lokiEntry{timestamp: record.TimeStamp, line: string(line)}
+		for _, field := range p.conf.StructuredMetadata {
+			if value := lokiMetadataFields[field](record); value != "" {
+				entry.metadata = append(entry.metadata, lokiLabel{name: field, value: value})
+			}
+		}
+		stream.entries = append(stream.entries, entry)
+	}
+
+	keys := make([]string, 0, len(byLabels))
+	for key := range byLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	streams := make([]*lokiStream, len(keys))
+	for i, key := range keys {
+		streams[i] = byLabels[key]
+		sort.SliceStable(streams[i].entries, func(a, b int) bool {
+			return streams[i].entries[a].timestamp.Before(streams[i].entries[b].timestamp)
+		})
+	}
+	return streams, nil
+}
+
+// labels returns the labels of the stream of record, in name order. The empty ones are
+// left out, Loki not having empty labels.
+func (p *LokiPump) labels(record *analytics.AnalyticsRecord) []lokiLabel {
+	labels := make([]lokiLabel, 0, len(p.conf.StaticLabels)+len(p.conf.Labels))
+	for name, value := range p.conf.StaticLabels {
+		labels = append(labels, lokiLabel{name: name, value: value})
+	}
+	for _, name := range p.conf.Labels {
+		if value := lokiLabelFields[name](record); value != "" {
+			labels = append(labels, lokiLabel{name: name, value: value})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+	return labels
+}
+
+// lokiLabelString returns labels in the Prometheus format Loki parses them in, such as
+// `{job="tyk-pump", method="GET"}`.
+func lokiLabelString(labels []lokiLabel) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(label.name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(label.value))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// push sends streams to Loki.
+func (p *LokiPump) push(ctx context.Context, streams []*lokiStream) error {
+	var body []byte
+	var contentType string
+	if p.conf.Encoding == lokiEncodingJSON {
+		var err error
+		if body, err = lokiJSON(streams); err != nil {
+			return err
+		}
+		contentType = "application/json"
+	} else {
+		body = snappy.Encode(nil, lokiProtobuf(streams))
+		contentType = "application/x-protobuf"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.pushURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.conf.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", p.conf.TenantID)
+	}
+	if p.conf.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.conf.BearerToken)
+	} else if p.conf.Username != "" {
+		req.SetBasicAuth(p.conf.Username, p.conf.Password)
+	}
+
+	p.log.Debugf("Pushing %d bytes to Loki", len(body))
+	return p.retry.Send(req)
+}
+
+// lokiJSON returns the JSON push request of streams.
+func lokiJSON(streams []*lokiStream) ([]byte, error) {
+	type jsonStream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][]interface{}   `json:"values"`
+	}
+
+	request := struct {
+		Streams []jsonStream `json:"streams"`
+	}{Streams: make([]jsonStream, len(streams))}
+	for i, stream := range streams {
+		labels := make(map[string]string, len(stream.labels))
+		for _, label := range stream.labels {
+			labels[label.name] = label.value
+		}
+		values := make([][]interface{}, len(stream.entries))
+		for j, entry := range stream.entries {
+			value := []interface{}{strconv.FormatInt(entry.timestamp.UnixNano(), 10), entry.line}
+			if len(entry.metadata) > 0 {
+				metadata := make(map[string]string, len(entry.metadata))
+				for _, label := range entry.metadata {
+					metadata[label.name] = label.value
+				}
+				value = append(value, metadata)
+			}
+			values[j] = value
+		}
+		request.Streams[i] = jsonStream{Stream: labels, Values: values}
+	}
+
+	return json.Marshal(request)
+}
+
+// lokiProtobuf returns the protobuf push request of streams, a logproto.PushRequest.
+func lokiProtobuf(streams []*lokiStream) []byte {
+	var request []byte
+	for _, stream := range streams {
+		var s []byte
+		s = protowire.AppendTag(s, 1, protowire.BytesType)
+		s = protowire.AppendString(s, lokiLabelString(stream.labels))
+		for _, entry := range stream.entries {
+			var timestamp []byte
+			timestamp = protowire.AppendTag(timestamp, 1, protowire.VarintType)
+			timestamp = protowire.AppendVarint(timestamp, uint64(entry.timestamp.Unix()))
+			timestamp = protowire.AppendTag(timestamp, 2, protowire.VarintType)
+			timestamp = protowire.AppendVarint(timestamp, uint64(entry.timestamp.Nanosecond()))
+
+			var e []byte
+			e = protowire.AppendTag(e, 1, protowire.BytesType)
+			e = protowire.AppendBytes(e, timestamp)
+			e = protowire.AppendTag(e, 2, protowire.BytesType)
+			e = protowire.AppendString(e, entry.line)
+			for _, label := range entry.metadata {
+				var pair []byte
+				pair = protowire.AppendTag(pair, 1, protowire.BytesType)
+				pair = protowire.AppendString(pair, label.name)
+				pair = protowire.AppendTag(pair, 2, protowire.BytesType)
+				pair = protowire.AppendString(pair, label.value)
+
+				e = protowire.AppendTag(e, 3, protowire.BytesType)
+				e = protowire.AppendBytes(e, pair)
+			}
+
+			s = protowire.AppendTag(s, 2, protowire.BytesType)
+			s = protowire.AppendBytes(s, e)
+		}
+
+		request = protowire.AppendTag(request, 1, protowire.BytesType)
+		request = protowire.AppendBytes(request, s)
+	}
+	return request
+}
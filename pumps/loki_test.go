@@ -0,0 +1,359 @@
+package pumps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// lokiPush is a push received by lokiReceiver, decoded from either encoding.
+type lokiPush struct {
+	header  http.Header
+	streams []lokiPushStream
+}
+
+type lokiPushStream struct {
+	labels  string
+	entries []lokiPushEntry
+}
+
+type lokiPushEntry struct {
+	timestamp time.Time
+	line      string
+	metadata  map[string]string
+}
+
+type lokiReceiver struct {
+	mu       sync.Mutex
+	pushes   []lokiPush
+	statuses []int
+	// retryAfter is the Retry-After header of the failed responses.
+	retryAfter string
+}
+
+func (r *lokiReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+		if status != http.StatusNoContent {
+			if r.retryAfter != "" {
+				w.Header().Set("Retry-After", r.retryAfter)
+			}
+			http.Error(w, "failed", status)
+			return
+		}
+	}
+	if req.URL.Path != lokiPushPath {
+		http.NotFound(w, req)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	push := lokiPush{header: req.Header}
+	if req.Header.Get("Content-Type") == "application/json" {
+		push.streams, err = decodeLokiJSON(body)
+	} else {
+		push.streams, err = decodeLokiProtobuf(body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.pushes = append(r.pushes, push)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeLokiJSON(body []byte) ([]lokiPushStream, error) {
+	var request struct {
+		Streams []struct {
+			Stream map[string]string   `json:"stream"`
+			Values [][]json.RawMessage `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, err
+	}
+
+	streams := make([]lokiPushStream, len(request.Streams))
+	for i, stream := range request.Streams {
+		labels := make([]lokiLabel, 0, len(stream.Stream))
+		for name, value := range stream.Stream {
+			labels = append(labels, lokiLabel{name: name, value: value})
+		}
+		sort.Slice(labels, func(a, b int) bool { return labels[a].name < labels[b].name })
+		streams[i].labels = lokiLabelString(labels)
+		for _, value := range stream.Values {
+			var nanos string
+			entry := lokiPushEntry{}
+			if err := json.Unmarshal(value[0], &nanos); err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(value[1], &entry.line); err != nil {
+				return nil, err
+			}
+			if len(value) > 2 {
+				if err := json.Unmarshal(value[2], &entry.metadata); err != nil {
+					return nil, err
+				}
+			}
+			ns, err := strconv.ParseInt(nanos, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			entry.timestamp = time.Unix(0, ns).UTC()
+			streams[i].entries = append(streams[i].entries, entry)
+		}
+	}
+	return streams, nil
+}
+
+// decodeLokiProtobuf decodes a snappy compressed logproto.PushRequest.
+func decodeLokiProtobuf(body []byte) ([]lokiPushStream, error) {
+	raw, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var streams []lokiPushStream
+	err = walkProtobuf(raw, func(_ protowire.Number, s []byte) error {
+		stream := lokiPushStream{}
+		err := walkProtobuf(s, func(num protowire.Number, value []byte) error {
+			if num == 1 {
+				stream.labels = string(value)
+				return nil
+			}
+			entry := lokiPushEntry{}
+			err := walkProtobuf(value, func(num protowire.Number, value []byte) error {
+				switch num {
+				case 1:
+					var seconds, nanos uint64
+					for len(value) > 0 {
+						num, _, n := protowire.ConsumeTag(value)
+						v, m := protowire.ConsumeVarint(value[n:])
+						if n < 0 || m < 0 {
+							return protowire.ParseError(-1)
+						}
+						if num == 1 {
+							seconds = v
+						} else {
+							nanos = v
+						}
+						value = value[n+m:]
+					}
+					entry.timestamp = time.Unix(int64(seconds), int64(nanos)).UTC()
+				case 2:
+					entry.line = string(value)
+				case 3:
+					pair := []string{}
+					if err := walkProtobuf(value, func(_ protowire.Number, value []byte) error {
+						pair = append(pair, string(value))
+						return nil
+					}); err != nil {
+						return err
+					}
+					if entry.metadata == nil {
+						entry.metadata = map[string]string{}
+					}
+					entry.metadata[pair[0]] = pair[1]
+				}
+				return nil
+			})
+			stream.entries = append(stream.entries, entry)
+			return err
+		})
+		streams = append(streams, stream)
+		return err
+	})
+	return streams, err
+}
+
+// walkProtobuf calls fn with the number and the value of every length-delimited field of
+// the message in b.
+func walkProtobuf(b []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			return protowire.ParseError(-1)
+		}
+		value, m := protowire.ConsumeBytes(b)
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		b = b[m:]
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lokiTestRecords() []interface{} {
+	timestamp := time.Date(2026, 10, 1, 12, 0, 0, 123, time.UTC)
+	return []interface{}{
+		analytics.AnalyticsRecord{
+			APIName: "Users", OrgID: "org1", Method: "GET", ResponseCode: 200, Path: "/users/1",
+			APIKey: "key1", TimeStamp: timestamp.Add(time.Second),
+		},
+		analytics.AnalyticsRecord{
+			APIName: "Users", OrgID: "org1", Method: "GET", ResponseCode: 204, Path: "/users/2",
+			TimeStamp: timestamp,
+		},
+		analytics.AnalyticsRecord{
+			APIName: "Users", OrgID: "org1", Method: "POST", ResponseCode: 502, Path: "/users",
+			TimeStamp: timestamp,
+		},
+	}
+}
+
+func TestLokiPump_Init(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		config      map[string]interface{}
+		expectedErr string
+	}{
+		{testName: "defaults", config: map[string]interface{}{"url": "http://loki:3100"}},
+		{testName: "no url", config: map[string]interface{}{}, expectedErr: "url is required"},
+		{
+			testName:    "unknown encoding",
+			config:      map[string]interface{}{"url": "http://loki:3100", "encoding": "logfmt"},
+			expectedErr: `unsupported encoding "logfmt"`,
+		},
+		{
+			testName:    "high-cardinality label",
+			config:      map[string]interface{}{"url": "http://loki:3100", "labels": []string{"api_key"}},
+			expectedErr: `label "api_key" has too many values for Loki to index`,
+		},
+		{
+			testName:    "unknown label",
+			config:      map[string]interface{}{"url": "http://loki:3100", "labels": []string{"planet"}},
+			expectedErr: `unsupported label "planet"`,
+		},
+		{
+			testName:    "label both static and from the records",
+			config:      map[string]interface{}{"url": "http://loki:3100", "static_labels": map[string]string{"method": "any"}},
+			expectedErr: `label "method" is both a static label and a record one`,
+		},
+		{
+			testName:    "unknown structured metadata",
+			config:      map[string]interface{}{"url": "http://loki:3100", "structured_metadata": []string{"raw_request"}},
+			expectedErr: `unsupported structured metadata "raw_request"`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			pmp := &LokiPump{}
+			err := pmp.Init(tc.config)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "http://loki:3100/loki/api/v1/push", pmp.pushURL)
+			assert.Equal(t, lokiEncodingProtobuf, pmp.conf.Encoding)
+		})
+	}
+}
+
+func TestLokiPump_WriteData(t *testing.T) {
+	tcs := []struct {
+		testName string
+		encoding string
+	}{
+		{testName: "protobuf", encoding: "protobuf"},
+		{testName: "json", encoding: "json"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			receiver := &lokiReceiver{}
+			server := httptest.NewServer(receiver)
+			t.Cleanup(server.Close)
+
+			pmp := &LokiPump{}
+			require.NoError(t, pmp.Init(map[string]interface{}{
+				"url":                 server.URL + "/",
+				"encoding":            tc.encoding,
+				"tenant_id":           "team-a",
+				"username":            "tyk",
+				"password":            "secret",
+				"structured_metadata": []string{"path", "api_key"},
+			}))
+			require.NoError(t, pmp.WriteData(context.Background(), lokiTestRecords()))
+
+			require.Len(t, receiver.pushes, 1)
+			push := receiver.pushes[0]
+			assert.Equal(t, "team-a", push.header.Get("X-Scope-OrgID"))
+			username, password, ok := (&http.Request{Header: push.header}).BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "tyk", username)
+			assert.Equal(t, "secret", password)
+
+			require.Len(t, push.streams, 2, "a stream per label set")
+			assert.Equal(t, `{api_name="Users", job="tyk-pump", method="GET", org_id="org1", status_class="2xx"}`, push.streams[0].labels)
+			assert.Equal(t, `{api_name="Users", job="tyk-pump", method="POST", org_id="org1", status_class="5xx"}`, push.streams[1].labels)
+
+			entries := push.streams[0].entries
+			require.Len(t, entries, 2)
+			assert.Equal(t, time.Date(2026, 10, 1, 12, 0, 0, 123, time.UTC), entries[0].timestamp, "the entries are in time order")
+			assert.Equal(t, map[string]string{"path": "/users/2"}, entries[0].metadata)
+			assert.Equal(t, map[string]string{"path": "/users/1", "api_key": "key1"}, entries[1].metadata)
+
+			var line analytics.AnalyticsRecord
+			require.NoError(t, json.Unmarshal([]byte(entries[1].line), &line))
+			assert.Equal(t, "/users/1", line.Path)
+			assert.Equal(t, 200, line.ResponseCode)
+		})
+	}
+}
+
+func TestLokiPump_WriteDataRetries(t *testing.T) {
+	receiver := &lokiReceiver{statuses: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	pmp := &LokiPump{}
+	require.NoError(t, pmp.Init(map[string]interface{}{
+		"url":          server.URL,
+		"bearer_token": "token",
+		"max_retries":  2,
+		"batch_size":   2,
+	}))
+	require.NoError(t, pmp.WriteData(context.Background(), lokiTestRecords()))
+	require.Len(t, receiver.pushes, 2, "the records are pushed batch_size at a time")
+	assert.Equal(t, "Bearer token", receiver.pushes[0].header.Get("Authorization"))
+
+	receiver.statuses = []int{http.StatusBadRequest}
+	assert.ErrorContains(t, pmp.WriteData(context.Background(), lokiTestRecords()), "got status code 400", "the bad requests aren't retried")
+	assert.Len(t, receiver.pushes, 2)
+
+	receiver.statuses = []int{http.StatusServiceUnavailable}
+	receiver.retryAfter = "86400"
+	start := time.Now()
+	require.NoError(t, pmp.WriteData(context.Background(), lokiTestRecords()))
+	assert.Less(t, time.Since(start), 10*time.Second, "the Retry-After headers are ignored")
+}
@@ -47,7 +47,7 @@ func (g *MCPMongoPump) SetDecodingResponse(decoding bool) {
 
 func (g *MCPMongoPump) Init(config interface{}) error {
 	g.dbConf = &MongoConf{}
-	g.log = log.WithField("prefix", mongoMCPPrefix)
+	g.log = g.newLog(mongoMCPPrefix)
 	g.MongoPump.CommonPumpConfig = g.CommonPumpConfig
 
 	err := mapstructure.Decode(config, &g.dbConf)
@@ -149,15 +149,15 @@ func (g *MCPMongoPump) insertMCPDataSet(dataSet []model.DBObject, collectionName
 func (g *MCPMongoPump) WriteData(ctx context.Context, data []interface{}) error {
 	collectionName := g.dbConf.CollectionName
 	if collectionName == "" {
-		g.log.Warn("no collection name")
+		g.log.WithContext(ctx).Warn("no collection name")
 		return fmt.Errorf("no collection name")
 	}
 
-	g.log.Debug("Attempting to write ", len(data), " records...")
+	g.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	mcpData := filterMCPData(data)
 	if len(mcpData) == 0 {
-		g.log.Debug("no MCP records to write")
+		g.log.WithContext(ctx).Debug("no MCP records to write")
 		return nil
 	}
 
@@ -173,6 +173,6 @@ func (g *MCPMongoPump) WriteData(ctx context.Context, data []interface{}) error
 		}
 	}
 
-	g.log.Info("Purged ", len(mcpData), " records...")
+	g.log.WithContext(ctx).Info("Purged ", len(mcpData), " records...")
 	return nil
 }
@@ -49,7 +49,7 @@ func (m *MCPMongoAggregatePump) SetDecodingResponse(decoding bool) {
 
 func (m *MCPMongoAggregatePump) Init(config interface{}) error {
 	m.dbConf = &MongoAggregateConf{}
-	m.log = log.WithField("prefix", mongoMCPAggregatePrefix)
+	m.log = m.newLog(mongoMCPAggregatePrefix)
 	m.MongoAggregatePump.log = m.log
 
 	err := mapstructure.Decode(config, &m.dbConf)
@@ -81,7 +81,7 @@ func (m *MCPMongoAggregatePump) Init(config interface{}) error {
 }
 
 func (m *MCPMongoAggregatePump) WriteData(ctx context.Context, data []interface{}) error {
-	m.log.Debug("Attempting to write ", len(data), " records")
+	m.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records")
 
 	analyticsPerAPI := analytics.AggregateMCPData(data, m.dbConf.MongoURL, m.dbConf.AggregationTime)
 
@@ -100,10 +100,10 @@ func (m *MCPMongoAggregatePump) WriteData(ctx context.Context, data []interface{
 				return err
 			}
 		}
-		m.log.Debug("Processed aggregated MCP data for API ", apiID)
+		m.log.WithContext(ctx).Debug("Processed aggregated MCP data for API ", apiID)
 	}
 
-	m.log.Info("Purged ", mcpRecordCount, " records...")
+	m.log.WithContext(ctx).Info("Purged ", mcpRecordCount, " records...")
 	return nil
 }
 
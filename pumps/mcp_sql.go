@@ -5,7 +5,6 @@ import (
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	"github.com/mitchellh/mapstructure"
-	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
@@ -43,7 +42,7 @@ func (g *MCPSQLPump) New() Pump {
 
 func (g *MCPSQLPump) Init(conf interface{}) error {
 	g.Conf = &MCPSQLConf{}
-	g.log = log.WithField("prefix", MCPSQLPrefix)
+	g.log = g.newLog(MCPSQLPrefix)
 
 	if err := mapstructure.Decode(conf, g.Conf); err != nil {
 		g.log.Error("Failed to decode configuration: ", err)
@@ -133,13 +132,13 @@ func (g *MCPSQLPump) ensureMCPShardedTable(recDate string) {
 }
 
 func (g *MCPSQLPump) WriteData(ctx context.Context, data []interface{}) error {
-	g.log.Debug("Attempting to write ", len(data), " records...")
+	g.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	mcpRecords := g.getMCPRecords(data)
 	dataLen := len(mcpRecords)
 
 	if dataLen == 0 {
-		g.log.Debug("no MCP records")
+		g.log.WithContext(ctx).Debug("no MCP records")
 		return nil
 	}
 
@@ -169,10 +168,6 @@ func (g *MCPSQLPump) WriteData(ctx context.Context, data []interface{}) error {
 		startIndex = i
 	}
 
-	g.log.Info("Purged ", dataLen, " records...")
+	g.log.WithContext(ctx).Info("Purged ", dataLen, " records...")
 	return nil
 }
-
-func (g *MCPSQLPump) SetLogLevel(level logrus.Level) {
-	g.log.Level = level
-}
@@ -41,7 +41,7 @@ func (s *MCPSQLAggregatePump) New() Pump {
 
 func (s *MCPSQLAggregatePump) Init(conf interface{}) error {
 	s.SQLConf = &SQLAggregatePumpConf{}
-	s.log = log.WithField("prefix", mcpSQLAggregatePrefix)
+	s.log = s.newLog(mcpSQLAggregatePrefix)
 
 	err := mapstructure.Decode(conf, s.SQLConf)
 	if err != nil {
@@ -184,7 +184,7 @@ func (s *MCPSQLAggregatePump) writeAggregatedSlice(ctx context.Context, data []i
 
 func (s *MCPSQLAggregatePump) WriteData(ctx context.Context, data []interface{}) error {
 	dataLen := len(data)
-	s.log.Debug("Attempting to write ", dataLen, " records...")
+	s.log.WithContext(ctx).Debug("Attempting to write ", dataLen, " records...")
 
 	if dataLen == 0 {
 		return nil
@@ -227,7 +227,7 @@ func (s *MCPSQLAggregatePump) WriteData(ctx context.Context, data []interface{})
 		startIndex = i
 	}
 
-	s.log.Info("Purged ", mcpRecordCount, " records...")
+	s.log.WithContext(ctx).Info("Purged ", mcpRecordCount, " records...")
 	return nil
 }
 
@@ -261,7 +261,7 @@ func parseAuthorizationHeader(token string, field string) string {
 
 func (p *MoesifPump) Init(config interface{}) error {
 	p.moesifConf = &MoesifConf{}
-	p.log = log.WithField("prefix", moesifPrefix)
+	p.log = p.newLog(moesifPrefix)
 
 	loadConfigErr := mapstructure.Decode(config, &p.moesifConf)
 	if loadConfigErr != nil {
@@ -319,7 +319,7 @@ func (p *MoesifPump) Init(config interface{}) error {
 }
 
 func (p *MoesifPump) WriteData(ctx context.Context, data []interface{}) error {
-	p.log.Debug("Attempting to write ", len(data), " records...")
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	if len(data) == 0 {
 		return nil
@@ -331,14 +331,14 @@ func (p *MoesifPump) WriteData(ctx context.Context, data []interface{}) error {
 
 		rawReq, err := base64.StdEncoding.DecodeString(record.RawRequest)
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
 		decodedReqBody, err := decodeRawData(string(rawReq), p.moesifConf.RequestHeaderMasks,
 			p.moesifConf.RequestBodyMasks, p.moesifConf.DisableCaptureRequestBody)
 
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
 		// Request URL
@@ -361,14 +361,14 @@ func (p *MoesifPump) WriteData(ctx context.Context, data []interface{}) error {
 		rawRsp, err := base64.StdEncoding.DecodeString(record.RawResponse)
 
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
 		decodedRspBody, err := decodeRawData(string(rawRsp), p.moesifConf.ResponseHeaderMasks,
 			p.moesifConf.ResponseBodyMasks, p.moesifConf.DisableCaptureResponseBody)
 
 		if err != nil {
-			p.log.Fatal(err)
+			p.log.WithContext(ctx).Fatal(err)
 		}
 
 		// Response Time
@@ -461,7 +461,7 @@ func (p *MoesifPump) WriteData(ctx context.Context, data []interface{}) error {
 		p.samplingPercentage = p.getSamplingPercentage(userID, companyID)
 
 		if p.samplingPercentage < randomPercentage {
-			p.log.Debug("Skipped Event due to sampling percentage: " + strconv.Itoa(p.samplingPercentage) + " and random percentage: " + strconv.Itoa(randomPercentage))
+			p.log.WithContext(ctx).Debug("Skipped Event due to sampling percentage: " + strconv.Itoa(p.samplingPercentage) + " and random percentage: " + strconv.Itoa(randomPercentage))
 			continue
 		}
 		// Add Weight to the Event Model
@@ -486,7 +486,7 @@ func (p *MoesifPump) WriteData(ctx context.Context, data []interface{}) error {
 
 		err = p.moesifAPI.QueueEvent(&event)
 		if err != nil {
-			p.log.Error("Error while writing ", data[dataIndex], err)
+			p.log.WithContext(ctx).Error("Error while writing ", data[dataIndex], err)
 		}
 
 		if p.moesifAPI.GetETag() != "" &&
@@ -505,7 +505,7 @@ func (p *MoesifPump) WriteData(ctx context.Context, data []interface{}) error {
 			p.samplingPercentage, p.eTag, p.lastUpdatedTime = p.parseConfiguration(response)
 		}
 	}
-	p.log.Info("Purged ", len(data), " records...")
+	p.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -193,7 +193,7 @@ func (m *MongoPump) SetDecodingResponse(decoding bool) {
 
 func (m *MongoPump) Init(config interface{}) error {
 	m.dbConf = &MongoConf{}
-	m.log = log.WithField("prefix", mongoPrefix)
+	m.log = m.newLog(mongoPrefix)
 
 	err := mapstructure.Decode(config, &m.dbConf)
 	if err == nil {
@@ -356,18 +356,7 @@ func (m *MongoPump) ensureIndexes(collectionName string) error {
 func (m *MongoPump) connect() {
 	m.dbConf.MongoDriverType = getMongoDriverType(m.dbConf.MongoDriverType)
 
-	store, err := persistent.NewPersistentStorage(&persistent.ClientOpts{
-		ConnectionString:         m.dbConf.MongoURL,
-		UseSSL:                   m.dbConf.MongoUseSSL,
-		SSLInsecureSkipVerify:    m.dbConf.MongoSSLInsecureSkipVerify,
-		SSLAllowInvalidHostnames: m.dbConf.MongoSSLAllowInvalidHostnames,
-		SSLCAFile:                m.dbConf.MongoSSLCAFile,
-		SSLPEMKeyfile:            m.dbConf.MongoSSLPEMKeyfile,
-		SessionConsistency:       m.dbConf.MongoSessionConsistency,
-		ConnectionTimeout:        m.timeout,
-		Type:                     m.dbConf.MongoDriverType,
-		DirectConnection:         m.dbConf.MongoDirectConnection,
-	})
+	store, err := openMongoStore(&m.dbConf.BaseMongoConf, m.timeout)
 	if err != nil {
 		m.log.Fatal("Failed to connect: ", err)
 	}
@@ -375,10 +364,72 @@ func (m *MongoPump) connect() {
 	m.store = store
 }
 
+// RefreshCredentials reconnects with the connection string and certificates of conf, a
+// *MongoConf, when they changed.
+func (m *MongoPump) RefreshCredentials(ctx context.Context, conf any) error {
+	newConf, ok := conf.(*MongoConf)
+	if !ok {
+		return unexpectedConfError(conf)
+	}
+
+	base, store, err := reconnectMongo(ctx, m.dbConf.BaseMongoConf, newConf.BaseMongoConf, m.timeout, m.log)
+	if err != nil || store == nil {
+		return err
+	}
+
+	oldStore := m.store
+	m.store, m.dbConf.BaseMongoConf = store, base
+	closeReplaced(oldStore, m.log)
+
+	m.log.Info("Reconnected with the refreshed credentials")
+	return nil
+}
+
+// openMongoStore opens the store conf connects to, timing out after timeout seconds.
+func openMongoStore(conf *BaseMongoConf, timeout int) (persistent.PersistentStorage, error) {
+	return persistent.NewPersistentStorage(&persistent.ClientOpts{
+		ConnectionString:         conf.MongoURL,
+		UseSSL:                   conf.MongoUseSSL,
+		SSLInsecureSkipVerify:    conf.MongoSSLInsecureSkipVerify,
+		SSLAllowInvalidHostnames: conf.MongoSSLAllowInvalidHostnames,
+		SSLCAFile:                conf.MongoSSLCAFile,
+		SSLPEMKeyfile:            conf.MongoSSLPEMKeyfile,
+		SessionConsistency:       conf.MongoSessionConsistency,
+		ConnectionTimeout:        timeout,
+		Type:                     conf.MongoDriverType,
+		DirectConnection:         conf.MongoDirectConnection,
+	})
+}
+
+// reconnectMongo opens a store with the connection string and certificates of newConf, the
+// settings a rotated credential changes, and the other settings of conf. It returns the
+// configuration of the store, once it's reachable, or no store when those settings are
+// the same.
+func reconnectMongo(ctx context.Context, conf, newConf BaseMongoConf, timeout int, log *logrus.Entry) (BaseMongoConf, persistent.PersistentStorage, error) {
+	refreshed := conf
+	refreshed.MongoURL = newConf.MongoURL
+	refreshed.MongoSSLCAFile = newConf.MongoSSLCAFile
+	refreshed.MongoSSLPEMKeyfile = newConf.MongoSSLPEMKeyfile
+	if refreshed == conf {
+		return conf, nil, nil
+	}
+
+	store, err := openMongoStore(&refreshed, timeout)
+	if err != nil {
+		return conf, nil, err
+	}
+	if err := store.Ping(ctx); err != nil {
+		closeReplaced(store, log)
+		return conf, nil, err
+	}
+
+	return refreshed, store, nil
+}
+
 func (m *MongoPump) WriteData(ctx context.Context, data []interface{}) error {
 	collectionName := m.dbConf.CollectionName
 	if collectionName == "" {
-		m.log.Fatal("No collection name!")
+		m.log.WithContext(ctx).Fatal("No collection name!")
 	}
 
 	// MCP records are handled by dedicated MCP pumps, skip them here.
@@ -393,7 +444,7 @@ func (m *MongoPump) WriteData(ctx context.Context, data []interface{}) error {
 		return nil
 	}
 
-	m.log.Debug("Attempting to write ", len(filtered), " records...")
+	m.log.WithContext(ctx).Debug("Attempting to write ", len(filtered), " records...")
 
 	accumulateSet := m.AccumulateSet(filtered, false)
 	if len(accumulateSet) == 0 {
@@ -403,18 +454,18 @@ func (m *MongoPump) WriteData(ctx context.Context, data []interface{}) error {
 	errCh := make(chan error, len(accumulateSet))
 	for _, dataSet := range accumulateSet {
 		go func(errCh chan error, dataSet ...model.DBObject) {
-			m.log.WithFields(logrus.Fields{
+			m.log.WithContext(ctx).WithFields(logrus.Fields{
 				"collection":        collectionName,
 				"number of records": len(dataSet),
 			}).Debug("Attempt to purge records")
 
 			err := m.store.Insert(context.Background(), dataSet...)
 			if err != nil {
-				m.log.WithFields(logrus.Fields{"collection": collectionName, "number of records": len(dataSet)}).Error("Problem inserting to mongo collection: ", err)
+				m.log.WithContext(ctx).WithFields(logrus.Fields{"collection": collectionName, "number of records": len(dataSet)}).Error("Problem inserting to mongo collection: ", err)
 				errCh <- err
 			}
 			errCh <- nil
-			m.log.WithFields(logrus.Fields{
+			m.log.WithContext(ctx).WithFields(logrus.Fields{
 				"collection":        collectionName,
 				"number of records": len(dataSet),
 			}).Info("Completed purging the records")
@@ -429,7 +480,7 @@ func (m *MongoPump) WriteData(ctx context.Context, data []interface{}) error {
 			}
 		}
 	}
-	m.log.Info("Purged ", len(filtered), " records...")
+	m.log.WithContext(ctx).Info("Purged ", len(filtered), " records...")
 
 	return nil
 }
@@ -569,3 +620,8 @@ func getMongoDriverType(driverType string) string {
 
 	return driverType
 }
+
+// CheckHealth pings MongoDB.
+func (m *MongoPump) CheckHealth(ctx context.Context) error {
+	return pingStore(ctx, m.store)
+}
@@ -169,7 +169,7 @@ func (m *MongoAggregatePump) SetDecodingResponse(decoding bool) {
 
 func (m *MongoAggregatePump) Init(config interface{}) error {
 	m.dbConf = &MongoAggregateConf{}
-	m.log = log.WithField("prefix", analytics.MongoAggregatePrefix)
+	m.log = m.newLog(analytics.MongoAggregatePrefix)
 
 	err := mapstructure.Decode(config, &m.dbConf)
 	if err == nil {
@@ -213,23 +213,38 @@ func (m *MongoAggregatePump) connect() {
 
 	m.dbConf.MongoDriverType = getMongoDriverType(m.dbConf.MongoDriverType)
 
-	m.store, err = persistent.NewPersistentStorage(&persistent.ClientOpts{
-		ConnectionString:         m.dbConf.MongoURL,
-		UseSSL:                   m.dbConf.MongoUseSSL,
-		SSLInsecureSkipVerify:    m.dbConf.MongoSSLInsecureSkipVerify,
-		SSLAllowInvalidHostnames: m.dbConf.MongoSSLAllowInvalidHostnames,
-		SSLCAFile:                m.dbConf.MongoSSLCAFile,
-		SSLPEMKeyfile:            m.dbConf.MongoSSLPEMKeyfile,
-		SessionConsistency:       m.dbConf.MongoSessionConsistency,
-		ConnectionTimeout:        m.timeout,
-		Type:                     m.dbConf.MongoDriverType,
-		DirectConnection:         m.dbConf.MongoDirectConnection,
-	})
+	m.store, err = openMongoStore(&m.dbConf.BaseMongoConf, m.timeout)
 	if err != nil {
 		m.log.Fatal("Failed to connect to mongo: ", err)
 	}
 }
 
+// RefreshCredentials reconnects with the connection string and certificates of conf, a
+// *MongoAggregateConf, when they changed.
+func (m *MongoAggregatePump) RefreshCredentials(ctx context.Context, conf any) error {
+	newConf, ok := conf.(*MongoAggregateConf)
+	if !ok {
+		return unexpectedConfError(conf)
+	}
+
+	base, store, err := reconnectMongo(ctx, m.dbConf.BaseMongoConf, newConf.BaseMongoConf, m.timeout, m.log)
+	if err != nil || store == nil {
+		return err
+	}
+
+	oldStore := m.store
+	m.store, m.dbConf.BaseMongoConf = store, base
+	closeReplaced(oldStore, m.log)
+
+	// The documents being aggregated into are tracked by connection string.
+	if lastTimestamp, err := m.getLastDocumentTimestamp(); err == nil {
+		analytics.SetlastTimestampAgggregateRecord(m.dbConf.MongoURL, lastTimestamp)
+	}
+
+	m.log.Info("Reconnected with the refreshed credentials")
+	return nil
+}
+
 func (m *MongoAggregatePump) ensureIndexes(collectionName string) error {
 	if m.dbConf.OmitIndexCreation {
 		m.log.Debug("omit_index_creation set to true, omitting index creation..")
@@ -291,7 +306,7 @@ func (m *MongoAggregatePump) WriteData(ctx context.Context, data []interface{})
 		return nil
 	}
 
-	m.log.Debug("Attempting to write ", len(filtered), " records")
+	m.log.WithContext(ctx).Debug("Attempting to write ", len(filtered), " records")
 	// calculate aggregates
 	analyticsPerOrg := analytics.AggregateData(filtered, m.dbConf.TrackAllPaths, m.dbConf.IgnoreTagPrefixList, m.dbConf.MongoURL, m.dbConf.AggregationTime)
 	// put aggregated data into MongoDB
@@ -309,17 +324,17 @@ func (m *MongoAggregatePump) WriteData(ctx context.Context, data []interface{})
 					// executing the function again with the new AggregationTime setting
 					newErr := m.WriteData(ctx, data)
 					if newErr == nil {
-						m.log.Info("Self-healing successful")
+						m.log.WithContext(ctx).Info("Self-healing successful")
 					}
 					return newErr
 				}
 				return err
 			}
 		}
-		m.log.Debug("Processed aggregated data for ", orgID)
+		m.log.WithContext(ctx).Debug("Processed aggregated data for ", orgID)
 	}
 
-	m.log.Info("Purged ", len(filtered), " records...")
+	m.log.WithContext(ctx).Info("Purged ", len(filtered), " records...")
 
 	return nil
 }
@@ -453,3 +468,8 @@ func (m *MongoAggregatePump) SetAggregationTime() {
 		m.dbConf.AggregationTime = 60
 	}
 }
+
+// CheckHealth pings MongoDB.
+func (m *MongoAggregatePump) CheckHealth(ctx context.Context) error {
+	return pingStore(ctx, m.store)
+}
@@ -72,7 +72,7 @@ func (m *MongoSelectivePump) SetDecodingResponse(decoding bool) {
 
 func (m *MongoSelectivePump) Init(config interface{}) error {
 	m.dbConf = &MongoSelectiveConf{}
-	m.log = log.WithField("prefix", mongoSelectivePrefix)
+	m.log = m.newLog(mongoSelectivePrefix)
 
 	err := mapstructure.Decode(config, &m.dbConf)
 	if err == nil {
@@ -111,23 +111,33 @@ func (m *MongoSelectivePump) connect() {
 
 	m.dbConf.MongoDriverType = getMongoDriverType(m.dbConf.MongoDriverType)
 
-	m.store, err = persistent.NewPersistentStorage(&persistent.ClientOpts{
-		ConnectionString:         m.dbConf.MongoURL,
-		UseSSL:                   m.dbConf.MongoUseSSL,
-		SSLInsecureSkipVerify:    m.dbConf.MongoSSLInsecureSkipVerify,
-		SSLAllowInvalidHostnames: m.dbConf.MongoSSLAllowInvalidHostnames,
-		SSLCAFile:                m.dbConf.MongoSSLCAFile,
-		SSLPEMKeyfile:            m.dbConf.MongoSSLPEMKeyfile,
-		SessionConsistency:       m.dbConf.MongoSessionConsistency,
-		ConnectionTimeout:        m.timeout,
-		Type:                     m.dbConf.MongoDriverType,
-		DirectConnection:         m.dbConf.MongoDirectConnection,
-	})
+	m.store, err = openMongoStore(&m.dbConf.BaseMongoConf, m.timeout)
 	if err != nil {
 		m.log.Fatal("Failed to connect to mongo: ", err)
 	}
 }
 
+// RefreshCredentials reconnects with the connection string and certificates of conf, a
+// *MongoSelectiveConf, when they changed.
+func (m *MongoSelectivePump) RefreshCredentials(ctx context.Context, conf any) error {
+	newConf, ok := conf.(*MongoSelectiveConf)
+	if !ok {
+		return unexpectedConfError(conf)
+	}
+
+	base, store, err := reconnectMongo(ctx, m.dbConf.BaseMongoConf, newConf.BaseMongoConf, m.timeout, m.log)
+	if err != nil || store == nil {
+		return err
+	}
+
+	oldStore := m.store
+	m.store, m.dbConf.BaseMongoConf = store, base
+	closeReplaced(oldStore, m.log)
+
+	m.log.Info("Reconnected with the refreshed credentials")
+	return nil
+}
+
 func (m *MongoSelectivePump) ensureIndexes(collectionName string) error {
 	if m.dbConf.OmitIndexCreation {
 		m.log.Debug("omit_index_creation set to true, omitting index creation..")
@@ -187,7 +197,7 @@ func (m *MongoSelectivePump) ensureIndexes(collectionName string) error {
 }
 
 func (m *MongoSelectivePump) WriteData(ctx context.Context, data []interface{}) error {
-	m.log.Debug("Attempting to write ", len(data), " records...")
+	m.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	analyticsPerOrg := make(map[string][]interface{})
 
@@ -201,7 +211,7 @@ func (m *MongoSelectivePump) WriteData(ctx context.Context, data []interface{})
 		collectionName, collErr := m.GetCollectionName(orgID)
 		skip := false
 		if collErr != nil {
-			m.log.Warning("No OrgID for AnalyticsRecord, skipping")
+			m.log.WithContext(ctx).Warning("No OrgID for AnalyticsRecord, skipping")
 			skip = true
 		}
 
@@ -223,16 +233,16 @@ func (m *MongoSelectivePump) WriteData(ctx context.Context, data []interface{})
 		for _, dataSet := range m.AccumulateSet(filteredData, colName) {
 			indexCreateErr := m.ensureIndexes(colName)
 			if indexCreateErr != nil {
-				m.log.WithField("collection", colName).Error(indexCreateErr)
+				m.log.WithContext(ctx).WithField("collection", colName).Error(indexCreateErr)
 			}
 			err := m.store.Insert(context.Background(), dataSet...)
 			if err != nil {
-				m.log.WithField("collection", colName).Error("Problem inserting to mongo collection: ", err)
+				m.log.WithContext(ctx).WithField("collection", colName).Error("Problem inserting to mongo collection: ", err)
 			}
 		}
 	}
 
-	m.log.Info("Purged ", len(data), " records...")
+	m.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -362,3 +372,8 @@ func (m *MongoSelectivePump) WriteUptimeData(data []interface{}) {
 func (m *MongoSelectivePump) collectionExists(name string) (bool, error) {
 	return m.store.HasTable(context.Background(), name)
 }
+
+// CheckHealth pings MongoDB.
+func (m *MongoSelectivePump) CheckHealth(ctx context.Context) error {
+	return pingStore(ctx, m.store)
+}
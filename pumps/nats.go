@@ -0,0 +1,332 @@
+package pumps
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/serializer"
+	"github.com/mitchellh/mapstructure"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"google.golang.org/protobuf/proto"
+)
+
+// NATSPump publishes the analytics records to NATS JetStream, a message per record on a
+// subject of its organisation and API, waiting for the acknowledgements of the streams.
+type NATSPump struct {
+	conf *NATSConf
+	nc   *nats.Conn
+	js   jetstream.JetStream
+	CommonPumpConfig
+}
+
+var (
+	natsPrefix     = "nats-pump"
+	natsDefaultENV = PUMPS_ENV_PREFIX + "_NATS" + PUMPS_ENV_META_PREFIX
+
+	natsPlaceholder = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+	// natsSubjectFields are the fields of the records the subjects can have.
+	natsSubjectFields = map[string]func(*analytics.AnalyticsRecord) string{
+		"org_id":        func(r *analytics.AnalyticsRecord) string { return r.OrgID },
+		"api_id":        func(r *analytics.AnalyticsRecord) string { return r.APIID },
+		"api_version":   func(r *analytics.AnalyticsRecord) string { return r.APIVersion },
+		"method":        func(r *analytics.AnalyticsRecord) string { return r.Method },
+		"response_code": func(r *analytics.AnalyticsRecord) string { return strconv.Itoa(r.ResponseCode) },
+	}
+)
+
+const (
+	natsEncodingJSON     = "json"
+	natsEncodingProtobuf = "protobuf"
+
+	natsDefaultSubject = "tyk.analytics.{{org_id}}.{{api_id}}"
+	natsDefaultTimeout = 10
+)
+
+// @PumpConf NATS
+type NATSConf struct {
+	// The prefix for the environment variables that will be used to override the configuration.
+	// Defaults to `TYK_PMP_PUMPS_NATS_META`
+	EnvPrefix string `mapstructure:"meta_env_prefix"`
+	// The URL of the NATS servers, comma separated. Defaults to `nats://127.0.0.1:4222`.
+	URL string `json:"url" mapstructure:"url"`
+	// The subject of the records, with the `{{org_id}}`, `{{api_id}}`, `{{api_version}}`,
+	// `{{method}}` and `{{response_code}}` of each. Defaults to
+	// `tyk.analytics.{{org_id}}.{{api_id}}`.
+	Subject string `json:"subject" mapstructure:"subject"`
+	// The stream the subjects are expected to be captured by, checked when the pump starts and
+	// by the acknowledgements. Defaults to any stream.
+	Stream string `json:"stream" mapstructure:"stream"`
+	// The encoding of the records, `json` or `protobuf`. Defaults to `json`.
+	Encoding string `json:"encoding" mapstructure:"encoding"`
+	// The credentials file, with the JWT and the NKey seed of the user, of the servers with
+	// decentralised authentication.
+	CredentialsFile string `json:"credentials_file" mapstructure:"credentials_file"`
+	// The file of the NKey seed of the user, of the servers with NKey authentication.
+	NKeySeedFile string `json:"nkey_seed_file" mapstructure:"nkey_seed_file"`
+	// The user of the password authentication.
+	Username string `json:"username" mapstructure:"username"`
+	// The password of the password authentication.
+	Password string `json:"password" mapstructure:"password"`
+	// The token of the token authentication.
+	Token string `json:"token" mapstructure:"token"`
+	// The timeout, in seconds, of the acknowledgements of the records written at once.
+	// Defaults to 10.
+	Timeout int `json:"timeout" mapstructure:"timeout"`
+	// Enables TLS on the connections, implied by the other `ssl_*` options and `tls://` URLs.
+	UseSSL bool `json:"use_ssl" mapstructure:"use_ssl"`
+	// Controls whether the pump client verifies the servers' certificate chain and host name.
+	SSLInsecureSkipVerify bool `json:"ssl_insecure_skip_verify" mapstructure:"ssl_insecure_skip_verify"`
+	// Path to the PEM file with trusted CA certificates that will be used to verify the servers'
+	// certificates.
+	SSLCAFile string `json:"ssl_ca_file" mapstructure:"ssl_ca_file"`
+	// SSL cert file location, for mTLS.
+	SSLCertFile string `json:"ssl_cert_file" mapstructure:"ssl_cert_file"`
+	// SSL cert key location, for mTLS.
+	SSLKeyFile string `json:"ssl_key_file" mapstructure:"ssl_key_file"`
+	// SSL Server name used in the TLS connection.
+	SSLServerName string `json:"ssl_server_name" mapstructure:"ssl_server_name"`
+}
+
+func (p *NATSPump) New() Pump {
+	return &NATSPump{}
+}
+
+func (p *NATSPump) GetName() string {
+	return "NATS Pump"
+}
+
+func (p *NATSPump) GetEnvPrefix() string {
+	return p.conf.EnvPrefix
+}
+
+func (p *NATSPump) Init(config interface{}) error {
+	p.conf = &NATSConf{}
+	p.log = p.newLog(natsPrefix)
+
+	if err := mapstructure.Decode(config, p.conf); err != nil {
+		p.log.Error("Failed to decode configuration: ", err)
+		return err
+	}
+
+	processPumpEnvVars(p, p.log, p.conf, natsDefaultENV)
+
+	if p.conf.URL == "" {
+		p.conf.URL = nats.DefaultURL
+	}
+	if p.conf.Subject == "" {
+		p.conf.Subject = natsDefaultSubject
+	}
+	if err := natsCheckSubject(p.conf.Subject); err != nil {
+		return err
+	}
+	switch p.conf.Encoding {
+	case "":
+		p.conf.Encoding = natsEncodingJSON
+	case natsEncodingJSON, natsEncodingProtobuf:
+	default:
+		return fmt.Errorf("unsupported encoding %q, must be %q or %q", p.conf.Encoding, natsEncodingJSON, natsEncodingProtobuf)
+	}
+	if p.conf.CredentialsFile != "" && p.conf.NKeySeedFile != "" {
+		return errors.New("credentials_file and nkey_seed_file can't both be set")
+	}
+	if p.conf.Timeout <= 0 {
+		p.conf.Timeout = natsDefaultTimeout
+	}
+
+	opts := []nats.Option{
+		nats.Name("tyk-pump"),
+		// The records published while reconnecting are buffered by the client.
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				p.log.Warn("Disconnected from NATS: ", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			p.log.Info("Reconnected to NATS at ", nc.ConnectedUrlRedacted())
+		}),
+	}
+	switch {
+	case p.conf.CredentialsFile != "":
+		opts = append(opts, nats.UserCredentials(p.conf.CredentialsFile))
+	case p.conf.NKeySeedFile != "":
+		opt, err := nats.NkeyOptionFromSeed(p.conf.NKeySeedFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the NKey seed: %w", err)
+		}
+		opts = append(opts, opt)
+	}
+	if p.conf.Username != "" {
+		opts = append(opts, nats.UserInfo(p.conf.Username, p.conf.Password))
+	}
+	if p.conf.Token != "" {
+		opts = append(opts, nats.Token(p.conf.Token))
+	}
+	if p.conf.UseSSL || p.conf.SSLCAFile != "" || p.conf.SSLCertFile != "" || p.conf.SSLInsecureSkipVerify {
+		tlsConfig, err := NewTLSConfig(TLSConfig{
+			CertFile:           p.conf.SSLCertFile,
+			KeyFile:            p.conf.SSLKeyFile,
+			CAFile:             p.conf.SSLCAFile,
+			ServerName:         p.conf.SSLServerName,
+			InsecureSkipVerify: p.conf.SSLInsecureSkipVerify,
+		}, p.log)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	nc, err := nats.Connect(p.conf.URL, opts...)
+	if err != nil {
+		p.log.Error("Failed to connect to NATS: ", err)
+		return err
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return err
+	}
+	if p.conf.Stream != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.conf.Timeout)*time.Second)
+		defer cancel()
+		if _, err := js.Stream(ctx, p.conf.Stream); err != nil {
+			nc.Close()
+			return fmt.Errorf("stream %q: %w", p.conf.Stream, err)
+		}
+	}
+	p.nc, p.js = nc, js
+
+	p.log.Info(p.GetName() + " Initialized")
+	return nil
+}
+
+// natsCheckSubject returns an error if subject has unsupported placeholders, or wildcards or
+// empty tokens once they're replaced.
+func natsCheckSubject(subject string) error {
+	for _, match := range natsPlaceholder.FindAllStringSubmatch(subject, -1) {
+		if _, ok := natsSubjectFields[match[1]]; !ok {
+			return fmt.Errorf("unsupported subject placeholder %q", match[0])
+		}
+	}
+	for _, token := range strings.Split(natsPlaceholder.ReplaceAllString(subject, "x"), ".") {
+		if token == "" || token == "*" || token == ">" || strings.IndexFunc(token, unicode.IsSpace) >= 0 {
+			return fmt.Errorf("invalid subject %q, the records are published on subjects without wildcards or empty tokens", subject)
+		}
+	}
+	return nil
+}
+
+func (p *NATSPump) WriteData(ctx context.Context, data []interface{}) error {
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.conf.Timeout)*time.Second)
+	defer cancel()
+
+	futures := make([]jetstream.PubAckFuture, 0, len(data))
+	for _, v := range data {
+		record, ok := v.(analytics.AnalyticsRecord)
+		if !ok {
+			continue
+		}
+		msg, id, err := p.msg(&record)
+		if err != nil {
+			return err
+		}
+		// The ID lets the streams drop the records they already have, published again after
+		// an acknowledgement was lost.
+		opts := []jetstream.PublishOpt{jetstream.WithMsgID(id)}
+		if p.conf.Stream != "" {
+			opts = append(opts, jetstream.WithExpectStream(p.conf.Stream))
+		}
+		future, err := p.js.PublishMsgAsync(msg, opts...)
+		if err != nil {
+			p.log.WithContext(ctx).Error("Failed to publish the records: ", err)
+			return err
+		}
+		futures = append(futures, future)
+	}
+
+	duplicates := 0
+	var errs []error
+	for _, future := range futures {
+		select {
+		case ack := <-future.Ok():
+			if ack.Duplicate {
+				duplicates++
+			}
+		case err := <-future.Err():
+			errs = append(errs, err)
+		case <-ctx.Done():
+			p.log.WithContext(ctx).Error("Timed out waiting for the acknowledgements of the records")
+			return ctx.Err()
+		}
+	}
+	if len(errs) > 0 {
+		p.log.WithContext(ctx).Errorf("%d of %d records weren't acknowledged: %v", len(errs), len(futures), errs[0])
+		return errors.Join(errs...)
+	}
+	if duplicates > 0 {
+		p.log.WithContext(ctx).Debug("Skipped ", duplicates, " duplicate records")
+	}
+
+	p.log.WithContext(ctx).Info("Purged ", len(futures), " records...")
+	return nil
+}
+
+// msg returns the message of record and its ID, the SHA-256 of its payload, deterministic.
+func (p *NATSPump) msg(record *analytics.AnalyticsRecord) (*nats.Msg, string, error) {
+	msg := nats.NewMsg(p.subject(record))
+
+	var err error
+	if p.conf.Encoding == natsEncodingProtobuf {
+		// The maps of the records, such as the GraphQL types, are encoded in a random order
+		// unless deterministic, which would give a record published again another ID.
+		msg.Data, err = proto.MarshalOptions{Deterministic: true}.Marshal((&serializer.ProtobufSerializer{}).TransformSingleRecordToProto(*record))
+		msg.Header.Set("Content-Type", "application/x-protobuf")
+	} else {
+		msg.Data, err = json.Marshal(record)
+		msg.Header.Set("Content-Type", "application/json")
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(msg.Data)
+	return msg, hex.EncodeToString(sum[:]), nil
+}
+
+// subject returns the subject of record, its fields being tokens without the characters
+// NATS reserves.
+func (p *NATSPump) subject(record *analytics.AnalyticsRecord) string {
+	return natsPlaceholder.ReplaceAllStringFunc(p.conf.Subject, func(placeholder string) string {
+		value := natsSubjectFields[natsPlaceholder.FindStringSubmatch(placeholder)[1]](record)
+		if value == "" {
+			return "unknown"
+		}
+		return strings.Map(func(r rune) rune {
+			if r == '.' || r == '*' || r == '>' || unicode.IsSpace(r) {
+				return '_'
+			}
+			return r
+		}, value)
+	})
+}
+
+func (p *NATSPump) Shutdown() error {
+	if p.nc == nil || p.nc.IsClosed() {
+		return nil
+	}
+	// Draining flushes the records being published before closing the connection.
+	return p.nc.Drain()
+}
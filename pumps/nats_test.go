@@ -0,0 +1,226 @@
+package pumps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/serializer"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runNATSServer runs an embedded JetStream server with opts, and a stream capturing the
+// subjects of the records.
+func runNATSServer(t *testing.T, opts *server.Options, clientOpts ...nats.Option) (*server.Server, jetstream.Stream) {
+	t.Helper()
+
+	opts.Host = "127.0.0.1"
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	opts.NoLog = true
+	s, err := server.NewServer(opts)
+	require.NoError(t, err)
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+	require.True(t, s.ReadyForConnections(5*time.Second))
+
+	nc, err := nats.Connect(s.ClientURL(), clientOpts...)
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{Name: "ANALYTICS", Subjects: []string{"tyk.analytics.>"}})
+	require.NoError(t, err)
+	return s, stream
+}
+
+// natsMessages returns the messages of stream.
+func natsMessages(t *testing.T, stream jetstream.Stream) []*jetstream.RawStreamMsg {
+	t.Helper()
+
+	ctx := context.Background()
+	info, err := stream.Info(ctx)
+	require.NoError(t, err)
+	var msgs []*jetstream.RawStreamMsg
+	for seq := info.State.FirstSeq; seq <= info.State.LastSeq && info.State.Msgs > 0; seq++ {
+		msg, err := stream.GetMsg(ctx, seq)
+		require.NoError(t, err)
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func natsTestRecords() []interface{} {
+	return []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", Method: "GET", Path: "/users", ResponseCode: 200},
+		analytics.AnalyticsRecord{APIID: "api.2", OrgID: "org1", Method: "POST", Path: "/orders", ResponseCode: 201},
+		"not a record",
+		analytics.AnalyticsRecord{APIID: "api1", Method: "GET", Path: "/health", ResponseCode: 200},
+	}
+}
+
+func TestNATSPump_Init(t *testing.T) {
+	s, _ := runNATSServer(t, &server.Options{})
+
+	tcs := []struct {
+		testName    string
+		config      map[string]interface{}
+		expectedErr string
+	}{
+		{testName: "defaults", config: map[string]interface{}{}},
+		{
+			testName:    "unknown encoding",
+			config:      map[string]interface{}{"encoding": "avro"},
+			expectedErr: `unsupported encoding "avro"`,
+		},
+		{
+			testName:    "unknown placeholder",
+			config:      map[string]interface{}{"subject": "tyk.{{api_name}}"},
+			expectedErr: `unsupported subject placeholder "{{api_name}}"`,
+		},
+		{
+			testName:    "wildcard subject",
+			config:      map[string]interface{}{"subject": "tyk.analytics.>"},
+			expectedErr: `invalid subject "tyk.analytics.>"`,
+		},
+		{
+			testName:    "empty token",
+			config:      map[string]interface{}{"subject": "tyk..{{org_id}}"},
+			expectedErr: `invalid subject "tyk..{{org_id}}"`,
+		},
+		{
+			testName:    "unknown stream",
+			config:      map[string]interface{}{"stream": "EVENTS"},
+			expectedErr: `stream "EVENTS"`,
+		},
+		{
+			testName:    "both credentials and nkey",
+			config:      map[string]interface{}{"credentials_file": "user.creds", "nkey_seed_file": "user.nk"},
+			expectedErr: "credentials_file and nkey_seed_file can't both be set",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			tc.config["url"] = s.ClientURL()
+			pmp := &NATSPump{}
+			err := pmp.Init(tc.config)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer pmp.Shutdown()
+			assert.Equal(t, natsDefaultSubject, pmp.conf.Subject)
+			assert.Equal(t, natsEncodingJSON, pmp.conf.Encoding)
+		})
+	}
+}
+
+func TestNATSPump_WriteData(t *testing.T) {
+	tcs := []struct {
+		testName string
+		encoding string
+	}{
+		{testName: "json", encoding: "json"},
+		{testName: "protobuf", encoding: "protobuf"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			s, stream := runNATSServer(t, &server.Options{})
+			pmp := &NATSPump{}
+			require.NoError(t, pmp.Init(map[string]interface{}{"url": s.ClientURL(), "stream": "ANALYTICS", "encoding": tc.encoding}))
+			defer pmp.Shutdown()
+
+			require.NoError(t, pmp.WriteData(context.Background(), natsTestRecords()))
+			msgs := natsMessages(t, stream)
+			require.Len(t, msgs, 3)
+			assert.Equal(t, "tyk.analytics.org1.api1", msgs[0].Subject)
+			assert.Equal(t, "tyk.analytics.org1.api_2", msgs[1].Subject, "the dots of the fields don't split the tokens")
+			assert.Equal(t, "tyk.analytics.unknown.api1", msgs[2].Subject)
+			assert.NotEmpty(t, msgs[0].Header.Get(jetstream.MsgIDHeader))
+
+			var record analytics.AnalyticsRecord
+			if tc.encoding == natsEncodingProtobuf {
+				assert.Equal(t, "application/x-protobuf", msgs[1].Header.Get("Content-Type"))
+				require.NoError(t, (&serializer.ProtobufSerializer{}).Decode(msgs[1].Data, &record))
+			} else {
+				assert.Equal(t, "application/json", msgs[1].Header.Get("Content-Type"))
+				require.NoError(t, json.Unmarshal(msgs[1].Data, &record))
+			}
+			assert.Equal(t, "/orders", record.Path)
+			assert.Equal(t, 201, record.ResponseCode)
+
+			require.NoError(t, pmp.WriteData(context.Background(), natsTestRecords()))
+			assert.Len(t, natsMessages(t, stream), 3, "the records published again are de-duplicated")
+		})
+	}
+}
+
+func TestNATSPump_DuplicateGraphQLRecord(t *testing.T) {
+	s, stream := runNATSServer(t, &server.Options{})
+	pmp := &NATSPump{}
+	require.NoError(t, pmp.Init(map[string]interface{}{"url": s.ClientURL(), "stream": "ANALYTICS", "encoding": "protobuf"}))
+	defer pmp.Shutdown()
+
+	types := map[string][]string{}
+	for i := 0; i < 20; i++ {
+		types[fmt.Sprintf("Type%d", i)] = []string{"id", "name"}
+	}
+	record := analytics.AnalyticsRecord{
+		APIID: "api1", OrgID: "org1", Method: "POST", Path: "/graphql", ResponseCode: 200,
+		GraphQLStats: analytics.GraphQLStats{IsGraphQL: true, Types: types},
+	}
+
+	for i, expectedDuplicate := range []bool{false, true} {
+		msg, id, err := pmp.msg(&record)
+		require.NoError(t, err)
+		ack, err := pmp.js.PublishMsg(context.Background(), msg, jetstream.WithMsgID(id))
+		require.NoError(t, err)
+		assert.Equal(t, expectedDuplicate, ack.Duplicate, "publish %d", i)
+	}
+	assert.Len(t, natsMessages(t, stream), 1)
+}
+
+func TestNATSPump_NKey(t *testing.T) {
+	user, err := nkeys.CreateUser()
+	require.NoError(t, err)
+	publicKey, err := user.PublicKey()
+	require.NoError(t, err)
+	seed, err := user.Seed()
+	require.NoError(t, err)
+	seedFile := filepath.Join(t.TempDir(), "user.nk")
+	require.NoError(t, os.WriteFile(seedFile, seed, 0o600))
+
+	s, stream := runNATSServer(t, &server.Options{Nkeys: []*server.NkeyUser{{Nkey: publicKey}}}, nats.Nkey(publicKey, user.Sign))
+
+	pmp := &NATSPump{}
+	assert.Error(t, pmp.Init(map[string]interface{}{"url": s.ClientURL()}), "the connections without the NKey are refused")
+
+	pmp = &NATSPump{}
+	require.NoError(t, pmp.Init(map[string]interface{}{"url": s.ClientURL(), "nkey_seed_file": seedFile}))
+	defer pmp.Shutdown()
+	require.NoError(t, pmp.WriteData(context.Background(), natsTestRecords()))
+	assert.Len(t, natsMessages(t, stream), 3)
+}
+
+func TestNATSPump_Shutdown(t *testing.T) {
+	s, _ := runNATSServer(t, &server.Options{})
+	pmp := &NATSPump{}
+	require.NoError(t, pmp.Init(map[string]interface{}{"url": s.ClientURL()}))
+	require.NoError(t, pmp.Shutdown())
+	require.Eventually(t, pmp.nc.IsClosed, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, pmp.Shutdown())
+}
@@ -0,0 +1,575 @@
+package pumps
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/mitchellh/mapstructure"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// OTLPPump sends the analytics records to an OpenTelemetry collector over OTLP: every
+// record as a log record and, optionally, as a span, along with the request count and
+// latency of every API as metrics.
+type OTLPPump struct {
+	conf     *OTLPConf
+	client   otlpClient
+	resource *resourcepb.Resource
+	metrics  *otlpMetrics
+	CommonPumpConfig
+}
+
+var (
+	otlpPrefix     = "otlp-pump"
+	otlpDefaultENV = PUMPS_ENV_PREFIX + "_OTLP" + PUMPS_ENV_META_PREFIX
+
+	// otlpDefaultBuckets are the bounds, in seconds, of the buckets of the latency
+	// histogram the HTTP semantic conventions advise.
+	otlpDefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+)
+
+const (
+	otlpScopeName   = "github.com/TykTechnologies/tyk-pump/pumps"
+	otlpServiceName = "tyk-gateway"
+	otlpEventName   = "tyk.api.request"
+
+	// otlpSampled are the W3C trace flags of the synthesised spans.
+	otlpSampled = 1
+)
+
+// @PumpConf OTLP
+type OTLPConf struct {
+	// The prefix for the environment variables that will be used to override the configuration.
+	// Defaults to `TYK_PMP_PUMPS_OTLP_META`
+	EnvPrefix string `mapstructure:"meta_env_prefix"`
+	// The protocol the records are sent with, `grpc` or `http` for HTTP/protobuf. Defaults to
+	// `grpc`.
+	Protocol string `json:"protocol" mapstructure:"protocol"`
+	// The OTLP receiver of the collector, as `host:port` for `grpc` and as a URL for `http`, the
+	// `/v1/logs`, `/v1/metrics` and `/v1/traces` paths appended to it. Defaults to
+	// `localhost:4317` for `grpc` and `http://localhost:4318` for `http`.
+	Endpoint string `json:"endpoint" mapstructure:"endpoint"`
+	// Headers sent along with every export, such as an API key.
+	Headers map[string]string `json:"headers" mapstructure:"headers"`
+	// Sends without TLS over `grpc`. Over `http`, the scheme of `endpoint` decides.
+	Insecure bool `json:"insecure" mapstructure:"insecure"`
+	// The compression of the exports, `gzip` or `none`. Defaults to `none`.
+	Compression string `json:"compression" mapstructure:"compression"`
+	// Controls whether the pump client verifies the collector's certificate chain and host
+	// name.
+	SSLInsecureSkipVerify bool `json:"ssl_insecure_skip_verify" mapstructure:"ssl_insecure_skip_verify"`
+	// SSL cert file location, for mTLS.
+	SSLCertFile string `json:"ssl_cert_file" mapstructure:"ssl_cert_file"`
+	// SSL cert key location, for mTLS.
+	SSLKeyFile string `json:"ssl_key_file" mapstructure:"ssl_key_file"`
+	// Path to the PEM file with trusted CA certificates that will be used to verify the
+	// collector's certificate.
+	SSLCAFile string `json:"ssl_ca_file" mapstructure:"ssl_ca_file"`
+	// SSL Server name used in the TLS connection.
+	SSLServerName string `json:"ssl_server_name" mapstructure:"ssl_server_name"`
+	// The `service.name` resource attribute. Defaults to `tyk-gateway`, the records being the
+	// ones of its requests.
+	ServiceName string `json:"service_name" mapstructure:"service_name"`
+	// Other resource attributes, such as `deployment.environment.name`.
+	ResourceAttributes map[string]string `json:"resource_attributes" mapstructure:"resource_attributes"`
+	// Stops sending the records as log records, for the pump to only send metrics or traces.
+	DisableLogs bool `json:"disable_logs" mapstructure:"disable_logs"`
+	// Sends the request count, `tyk.api.requests`, and the latency histogram,
+	// `http.server.request.duration`, of every API, method and response code as cumulative
+	// metrics.
+	EnableMetrics bool `json:"enable_metrics" mapstructure:"enable_metrics"`
+	// Sends a span per request, with a `gateway` and an `upstream` child span laid out one
+	// after the other from the latencies of the record. The log records are linked to them.
+	EnableTraces bool `json:"enable_traces" mapstructure:"enable_traces"`
+	// The bounds, in seconds, of the buckets of the latency histogram. Defaults to the ones
+	// the HTTP semantic conventions advise, from 0.005 to 10.
+	LatencyBuckets []float64 `json:"latency_buckets" mapstructure:"latency_buckets"`
+}
+
+func (p *OTLPPump) New() Pump {
+	return &OTLPPump{}
+}
+
+func (p *OTLPPump) GetName() string {
+	return "OTLP Pump"
+}
+
+func (p *OTLPPump) GetEnvPrefix() string {
+	return p.conf.EnvPrefix
+}
+
+func (p *OTLPPump) Init(config interface{}) error {
+	p.conf = &OTLPConf{}
+	p.log = p.newLog(otlpPrefix)
+
+	if err := mapstructure.Decode(config, p.conf); err != nil {
+		p.log.Error("Failed to decode configuration: ", err)
+		return err
+	}
+
+	processPumpEnvVars(p, p.log, p.conf, otlpDefaultENV)
+
+	if p.conf.DisableLogs && !p.conf.EnableMetrics && !p.conf.EnableTraces {
+		return errors.New("logs are disabled and neither metrics nor traces are enabled: there's nothing to send")
+	}
+	if len(p.conf.LatencyBuckets) == 0 {
+		p.conf.LatencyBuckets = otlpDefaultBuckets
+	}
+	if !sort.SliceIsSorted(p.conf.LatencyBuckets, func(i, j int) bool { return p.conf.LatencyBuckets[i] <= p.conf.LatencyBuckets[j] }) {
+		return errors.New("latency_buckets must be in increasing order")
+	}
+
+	client, err := newOTLPClient(p.conf, p.log)
+	if err != nil {
+		return err
+	}
+	p.client = client
+	p.resource = otlpResource(p.conf)
+	p.metrics = newOTLPMetrics(p.conf.LatencyBuckets)
+
+	p.log.Info(p.GetName() + " Initialized")
+	return nil
+}
+
+func (p *OTLPPump) WriteData(ctx context.Context, data []interface{}) error {
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
+
+	records := make([]analytics.AnalyticsRecord, 0, len(data))
+	for _, v := range data {
+		if record, ok := v.(analytics.AnalyticsRecord); ok {
+			records = append(records, record)
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	var spans []otlpSpanIDs
+	var errs []error
+	if p.conf.EnableTraces {
+		spans = newOTLPSpanIDs(len(records))
+		if err := p.client.exportTraces(ctx, p.traces(records, spans)); err != nil {
+			errs = append(errs, fmt.Errorf("couldn't export the traces: %w", err))
+		}
+	}
+	if !p.conf.DisableLogs {
+		if err := p.client.exportLogs(ctx, p.logs(records, spans)); err != nil {
+			errs = append(errs, fmt.Errorf("couldn't export the logs: %w", err))
+		}
+	}
+	if p.conf.EnableMetrics {
+		if err := p.client.exportMetrics(ctx, p.metrics.record(p.resource, records, time.Now())); err != nil {
+			errs = append(errs, fmt.Errorf("couldn't export the metrics: %w", err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	p.log.WithContext(ctx).Info("Purged ", len(records), " records...")
+	return nil
+}
+
+func (p *OTLPPump) Shutdown() error {
+	if p.client == nil {
+		return nil
+	}
+	return p.client.close()
+}
+
+// logs returns the request exporting records as log records, linked to spans when they're
+// traced.
+func (p *OTLPPump) logs(records []analytics.AnalyticsRecord, spans []otlpSpanIDs) *collogspb.ExportLogsServiceRequest {
+	observed := uint64(time.Now().UnixNano())
+	logRecords := make([]*logspb.LogRecord, len(records))
+	for i := range records {
+		record := &records[i]
+		severity, severityText := otlpSeverity(record.ResponseCode)
+		logRecord := &logspb.LogRecord{
+			TimeUnixNano:         otlpTime(record.TimeStamp),
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       severity,
+			SeverityText:         severityText,
+			Body:                 otlpString(fmt.Sprintf("%s %s %d", record.Method, record.Path, record.ResponseCode)),
+			Attributes:           otlpRecordAttributes(record),
+			EventName:            otlpEventName,
+		}
+		if spans != nil {
+			logRecord.TraceId = spans[i].trace[:]
+			logRecord.SpanId = spans[i].span[:]
+			logRecord.Flags = otlpSampled
+		}
+		logRecords[i] = logRecord
+	}
+
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: p.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      otlpScope(),
+				LogRecords: logRecords,
+			}},
+		}},
+	}
+}
+
+// traces returns the request exporting a span per record, with the IDs of spans, and its
+// gateway and upstream child spans.
+func (p *OTLPPump) traces(records []analytics.AnalyticsRecord, spans []otlpSpanIDs) *coltracepb.ExportTraceServiceRequest {
+	otlpSpans := make([]*tracepb.Span, 0, 3*len(records))
+	for i := range records {
+		otlpSpans = append(otlpSpans, otlpRequestSpans(&records[i], spans[i])...)
+	}
+
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: p.resource,
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Scope: otlpScope(),
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+// otlpRequestSpans returns the span of the request of record, and its child spans: the
+// time spent in the gateway followed by the time spent waiting for the upstream. The
+// gateway latency is the total one without the upstream one when the record doesn't
+// have it.
+func otlpRequestSpans(record *analytics.AnalyticsRecord, ids otlpSpanIDs) []*tracepb.Span {
+	total := record.Latency.Total
+	if total == 0 {
+		total = record.RequestTime
+	}
+	upstream := record.Latency.Upstream
+	gateway := record.Latency.Gateway
+	if gateway == 0 && total > upstream {
+		gateway = total - upstream
+	}
+
+	start := otlpTime(record.TimeStamp)
+	name := record.Method
+	if record.ListenPath != "" {
+		name += " " + record.ListenPath
+	}
+	root := &tracepb.Span{
+		TraceId:           ids.trace[:],
+		SpanId:            ids.span[:],
+		Name:              name,
+		Kind:              tracepb.Span_SPAN_KIND_SERVER,
+		StartTimeUnixNano: start,
+		EndTimeUnixNano:   start + otlpMillis(total),
+		Attributes:        otlpRecordAttributes(record),
+	}
+	// Server spans only fail with the 5xx responses.
+	if record.ResponseCode >= 500 {
+		root.Status = &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR}
+	}
+
+	spans := []*tracepb.Span{root, {
+		TraceId:           ids.trace[:],
+		SpanId:            newOTLPSpanID(),
+		ParentSpanId:      ids.span[:],
+		Name:              "gateway",
+		Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+		StartTimeUnixNano: start,
+		EndTimeUnixNano:   start + otlpMillis(gateway),
+	}}
+	if upstream > 0 {
+		spans = append(spans, &tracepb.Span{
+			TraceId:           ids.trace[:],
+			SpanId:            newOTLPSpanID(),
+			ParentSpanId:      ids.span[:],
+			Name:              "upstream",
+			Kind:              tracepb.Span_SPAN_KIND_CLIENT,
+			StartTimeUnixNano: start + otlpMillis(gateway),
+			EndTimeUnixNano:   start + otlpMillis(gateway+upstream),
+		})
+	}
+
+	return spans
+}
+
+// otlpSpanIDs identify the span of a request.
+type otlpSpanIDs struct {
+	trace [16]byte
+	span  [8]byte
+}
+
+func newOTLPSpanIDs(n int) []otlpSpanIDs {
+	ids := make([]otlpSpanIDs, n)
+	for i := range ids {
+		rand.Read(ids[i].trace[:])
+		rand.Read(ids[i].span[:])
+	}
+	return ids
+}
+
+func newOTLPSpanID() []byte {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return id
+}
+
+// otlpMetrics are the cumulative request counts and latency histograms of the APIs since
+// the pump started.
+type otlpMetrics struct {
+	mu     sync.Mutex
+	start  uint64
+	bounds []float64
+	series map[otlpSeriesKey]*otlpSeries
+}
+
+type otlpSeriesKey struct {
+	apiID, apiName, method string
+	responseCode           int
+}
+
+type otlpSeries struct {
+	count    uint64
+	sum      float64
+	min, max float64
+	buckets  []uint64
+}
+
+func newOTLPMetrics(bounds []float64) *otlpMetrics {
+	return &otlpMetrics{
+		start:  uint64(time.Now().UnixNano()),
+		bounds: bounds,
+		series: map[otlpSeriesKey]*otlpSeries{},
+	}
+}
+
+// record adds records to the metrics, and returns the request exporting the series they
+// changed, as of now.
+func (m *otlpMetrics) record(resource *resourcepb.Resource, records []analytics.AnalyticsRecord, now time.Time) *colmetricspb.ExportMetricsServiceRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := map[otlpSeriesKey]bool{}
+	for i := range records {
+		record := &records[i]
+		key := otlpSeriesKey{apiID: record.APIID, apiName: record.APIName, method: record.Method, responseCode: record.ResponseCode}
+		series, ok := m.series[key]
+		if !ok {
+			series = &otlpSeries{buckets: make([]uint64, len(m.bounds)+1)}
+			m.series[key] = series
+		}
+
+		latency := record.Latency.Total
+		if latency == 0 {
+			latency = record.RequestTime
+		}
+		seconds := float64(latency) / 1000
+		if series.count == 0 || seconds < series.min {
+			series.min = seconds
+		}
+		if series.count == 0 || seconds > series.max {
+			series.max = seconds
+		}
+		series.count++
+		series.sum += seconds
+		series.buckets[sort.SearchFloat64s(m.bounds, seconds)]++
+		changed[key] = true
+	}
+
+	keys := make([]otlpSeriesKey, 0, len(changed))
+	for key := range changed {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	timestamp := otlpTime(now)
+	counts := make([]*metricspb.NumberDataPoint, len(keys))
+	durations := make([]*metricspb.HistogramDataPoint, len(keys))
+	for i, key := range keys {
+		series := m.series[key]
+		attributes := []*commonpb.KeyValue{
+			otlpStringAttribute("tyk.api.id", key.apiID),
+			otlpStringAttribute("tyk.api.name", key.apiName),
+			otlpStringAttribute("http.request.method", key.method),
+			otlpIntAttribute("http.response.status_code", int64(key.responseCode)),
+		}
+		counts[i] = &metricspb.NumberDataPoint{
+			Attributes:        attributes,
+			StartTimeUnixNano: m.start,
+			TimeUnixNano:      timestamp,
+			Value:             &metricspb.NumberDataPoint_AsInt{AsInt: int64(series.count)},
+		}
+		sum, minimum, maximum := series.sum, series.min, series.max
+		durations[i] = &metricspb.HistogramDataPoint{
+			Attributes:        attributes,
+			StartTimeUnixNano: m.start,
+			TimeUnixNano:      timestamp,
+			Count:             series.count,
+			Sum:               &sum,
+			Min:               &minimum,
+			Max:               &maximum,
+			BucketCounts:      append([]uint64(nil), series.buckets...),
+			ExplicitBounds:    m.bounds,
+		}
+	}
+
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: resource,
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope: otlpScope(),
+				Metrics: []*metricspb.Metric{
+					{
+						Name:        "tyk.api.requests",
+						Description: "The number of requests to the API.",
+						Unit:        "{request}",
+						Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+							DataPoints:             counts,
+							AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+							IsMonotonic:            true,
+						}},
+					},
+					{
+						Name:        "http.server.request.duration",
+						Description: "The duration of the requests to the API.",
+						Unit:        "s",
+						Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+							DataPoints:             durations,
+							AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+						}},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func (k otlpSeriesKey) less(other otlpSeriesKey) bool {
+	if k.apiID != other.apiID {
+		return k.apiID < other.apiID
+	}
+	if k.apiName != other.apiName {
+		return k.apiName < other.apiName
+	}
+	if k.method != other.method {
+		return k.method < other.method
+	}
+	return k.responseCode < other.responseCode
+}
+
+// otlpRecordAttributes returns the attributes of record, named after the semantic
+// conventions when they have one.
+func otlpRecordAttributes(record *analytics.AnalyticsRecord) []*commonpb.KeyValue {
+	attributes := []*commonpb.KeyValue{
+		otlpStringAttribute("http.request.method", record.Method),
+		otlpStringAttribute("url.path", record.Path),
+		otlpIntAttribute("http.response.status_code", int64(record.ResponseCode)),
+	}
+	optional := []struct{ key, value string }{
+		{"server.address", record.Host},
+		{"client.address", record.IPAddress},
+		{"user_agent.original", record.UserAgent},
+		{"tyk.api.id", record.APIID},
+		{"tyk.api.name", record.APIName},
+		{"tyk.api.version", record.APIVersion},
+		{"tyk.org.id", record.OrgID},
+	}
+	for _, attribute := range optional {
+		if attribute.value != "" {
+			attributes = append(attributes, otlpStringAttribute(attribute.key, attribute.value))
+		}
+	}
+	if record.ContentLength > 0 {
+		attributes = append(attributes, otlpIntAttribute("http.request.body.size", record.ContentLength))
+	}
+	attributes = append(attributes,
+		otlpIntAttribute("tyk.latency.total", record.Latency.Total),
+		otlpIntAttribute("tyk.latency.upstream", record.Latency.Upstream),
+	)
+	if len(record.Tags) > 0 {
+		tags := make([]*commonpb.AnyValue, len(record.Tags))
+		for i, tag := range record.Tags {
+			tags[i] = otlpString(tag)
+		}
+		attributes = append(attributes, &commonpb.KeyValue{
+			Key:   "tyk.tags",
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: tags}}},
+		})
+	}
+
+	return attributes
+}
+
+// otlpSeverity returns the severity of the log record of a response with code.
+func otlpSeverity(code int) (logspb.SeverityNumber, string) {
+	switch {
+	case code >= 500:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, "ERROR"
+	case code >= 400:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_WARN, "WARN"
+	default:
+		return logspb.SeverityNumber_SEVERITY_NUMBER_INFO, "INFO"
+	}
+}
+
+func otlpResource(conf *OTLPConf) *resourcepb.Resource {
+	serviceName := conf.ServiceName
+	if serviceName == "" {
+		serviceName = otlpServiceName
+	}
+
+	attributes := []*commonpb.KeyValue{otlpStringAttribute("service.name", serviceName)}
+	keys := make([]string, 0, len(conf.ResourceAttributes))
+	for key := range conf.ResourceAttributes {
+		if key != "service.name" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attributes = append(attributes, otlpStringAttribute(key, conf.ResourceAttributes[key]))
+	}
+
+	return &resourcepb.Resource{Attributes: attributes}
+}
+
+func otlpScope() *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{Name: otlpScopeName, Version: strings.TrimPrefix(Version, "v")}
+}
+
+func otlpString(value string) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}
+}
+
+func otlpStringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: otlpString(value)}
+}
+
+func otlpIntAttribute(key string, value int64) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value}}}
+}
+
+func otlpTime(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+func otlpMillis(ms int64) uint64 {
+	if ms <= 0 {
+		return 0
+	}
+	return uint64(ms) * uint64(time.Millisecond)
+}
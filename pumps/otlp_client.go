@@ -0,0 +1,254 @@
+package pumps
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	otlpProtocolGRPC = "grpc"
+	otlpProtocolHTTP = "http"
+
+	otlpCompressionGzip = "gzip"
+	otlpCompressionNone = "none"
+)
+
+// otlpClient exports the signals to the collector.
+type otlpClient interface {
+	exportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error
+	exportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error
+	exportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error
+	close() error
+}
+
+// newOTLPClient returns the client of the protocol of conf.
+func newOTLPClient(conf *OTLPConf, log *logrus.Entry) (otlpClient, error) {
+	switch conf.Compression {
+	case "", otlpCompressionNone, otlpCompressionGzip:
+	default:
+		return nil, fmt.Errorf("unsupported compression %q, must be %q or %q", conf.Compression, otlpCompressionGzip, otlpCompressionNone)
+	}
+
+	tlsConfig, err := NewTLSConfig(TLSConfig{
+		CertFile:           conf.SSLCertFile,
+		KeyFile:            conf.SSLKeyFile,
+		CAFile:             conf.SSLCAFile,
+		ServerName:         conf.SSLServerName,
+		InsecureSkipVerify: conf.SSLInsecureSkipVerify,
+	}, log)
+	if err != nil {
+		return nil, err
+	}
+
+	switch conf.Protocol {
+	case "", otlpProtocolGRPC:
+		endpoint := conf.Endpoint
+		if endpoint == "" {
+			endpoint = "localhost:4317"
+		}
+		creds := credentials.NewTLS(tlsConfig)
+		if conf.Insecure {
+			creds = insecure.NewCredentials()
+		}
+		conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, err
+		}
+
+		callOptions := []grpc.CallOption{}
+		if conf.Compression == otlpCompressionGzip {
+			callOptions = append(callOptions, grpc.UseCompressor(grpcgzip.Name))
+		}
+		return &otlpGRPCClient{
+			conn:        conn,
+			logs:        collogspb.NewLogsServiceClient(conn),
+			metrics:     colmetricspb.NewMetricsServiceClient(conn),
+			traces:      coltracepb.NewTraceServiceClient(conn),
+			headers:     conf.Headers,
+			callOptions: callOptions,
+		}, nil
+	case otlpProtocolHTTP:
+		endpoint := conf.Endpoint
+		if endpoint == "" {
+			endpoint = "http://localhost:4318"
+		}
+		return &otlpHTTPClient{
+			endpoint: strings.TrimSuffix(endpoint, "/"),
+			headers:  conf.Headers,
+			gzip:     conf.Compression == otlpCompressionGzip,
+			client: &http.Client{Transport: &tracingTransport{base: &http.Transport{
+				TLSClientConfig: tlsConfig,
+				Proxy:           http.ProxyFromEnvironment,
+			}}},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q, must be %q or %q", conf.Protocol, otlpProtocolGRPC, otlpProtocolHTTP)
+	}
+}
+
+// otlpGRPCClient exports the signals over gRPC.
+type otlpGRPCClient struct {
+	conn        *grpc.ClientConn
+	logs        collogspb.LogsServiceClient
+	metrics     colmetricspb.MetricsServiceClient
+	traces      coltracepb.TraceServiceClient
+	headers     map[string]string
+	callOptions []grpc.CallOption
+}
+
+func (c *otlpGRPCClient) exportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	resp, err := c.logs.Export(c.outgoing(ctx), req, c.callOptions...)
+	if err != nil {
+		return err
+	}
+	return otlpPartialSuccess(resp.GetPartialSuccess().GetRejectedLogRecords(), resp.GetPartialSuccess().GetErrorMessage(), "log records")
+}
+
+func (c *otlpGRPCClient) exportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	resp, err := c.metrics.Export(c.outgoing(ctx), req, c.callOptions...)
+	if err != nil {
+		return err
+	}
+	return otlpPartialSuccess(resp.GetPartialSuccess().GetRejectedDataPoints(), resp.GetPartialSuccess().GetErrorMessage(), "data points")
+}
+
+func (c *otlpGRPCClient) exportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	resp, err := c.traces.Export(c.outgoing(ctx), req, c.callOptions...)
+	if err != nil {
+		return err
+	}
+	return otlpPartialSuccess(resp.GetPartialSuccess().GetRejectedSpans(), resp.GetPartialSuccess().GetErrorMessage(), "spans")
+}
+
+func (c *otlpGRPCClient) close() error {
+	return c.conn.Close()
+}
+
+// outgoing returns ctx carrying the headers and the trace context as the metadata of the
+// export.
+func (c *otlpGRPCClient) outgoing(ctx context.Context) context.Context {
+	carrier := propagation.MapCarrier{}
+	for key, value := range c.headers {
+		carrier.Set(strings.ToLower(key), value)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	return metadata.NewOutgoingContext(ctx, metadata.New(carrier))
+}
+
+// otlpHTTPClient exports the signals over HTTP/protobuf.
+type otlpHTTPClient struct {
+	endpoint string
+	headers  map[string]string
+	gzip     bool
+	client   *http.Client
+}
+
+func (c *otlpHTTPClient) exportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	resp := &collogspb.ExportLogsServiceResponse{}
+	if err := c.export(ctx, "/v1/logs", req, resp); err != nil {
+		return err
+	}
+	return otlpPartialSuccess(resp.GetPartialSuccess().GetRejectedLogRecords(), resp.GetPartialSuccess().GetErrorMessage(), "log records")
+}
+
+func (c *otlpHTTPClient) exportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	resp := &colmetricspb.ExportMetricsServiceResponse{}
+	if err := c.export(ctx, "/v1/metrics", req, resp); err != nil {
+		return err
+	}
+	return otlpPartialSuccess(resp.GetPartialSuccess().GetRejectedDataPoints(), resp.GetPartialSuccess().GetErrorMessage(), "data points")
+}
+
+func (c *otlpHTTPClient) exportTraces(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	resp := &coltracepb.ExportTraceServiceResponse{}
+	if err := c.export(ctx, "/v1/traces", req, resp); err != nil {
+		return err
+	}
+	return otlpPartialSuccess(resp.GetPartialSuccess().GetRejectedSpans(), resp.GetPartialSuccess().GetErrorMessage(), "spans")
+}
+
+func (c *otlpHTTPClient) close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// export posts req to the path of the endpoint, and decodes the response of the collector
+// into resp.
+func (c *otlpHTTPClient) export(ctx context.Context, path string, req, resp proto.Message) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if c.gzip {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(body); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, value := range c.headers {
+		httpReq.Header.Set(key, value)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	if c.gzip {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+
+	httpResp, err := c.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		return fmt.Errorf("collector responded with status %d: %s", httpResp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if httpResp.Header.Get("Content-Type") != "application/x-protobuf" {
+		return nil
+	}
+	return proto.Unmarshal(respBody, resp)
+}
+
+// otlpPartialSuccess returns the error of an export the collector rejected some of the
+// items of, what.
+func otlpPartialSuccess(rejected int64, message, what string) error {
+	if rejected <= 0 {
+		return nil
+	}
+	if message == "" {
+		message = "no reason given"
+	}
+	return fmt.Errorf("the collector rejected %d %s: %s", rejected, what, message)
+}
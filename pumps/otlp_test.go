@@ -0,0 +1,359 @@
+package pumps
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver records the exports it receives, over gRPC or HTTP/protobuf.
+type otlpReceiver struct {
+	mu      sync.Mutex
+	logs    []*collogspb.ExportLogsServiceRequest
+	metrics []*colmetricspb.ExportMetricsServiceRequest
+	traces  []*coltracepb.ExportTraceServiceRequest
+	headers []string
+	// rejected is the number of items every export partially fails with.
+	rejected int64
+}
+
+type otlpLogsReceiver struct {
+	*otlpReceiver
+	collogspb.UnimplementedLogsServiceServer
+}
+
+func (r otlpLogsReceiver) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, req)
+	r.recordHeader(ctx)
+	if r.rejected > 0 {
+		return &collogspb.ExportLogsServiceResponse{PartialSuccess: &collogspb.ExportLogsPartialSuccess{RejectedLogRecords: r.rejected, ErrorMessage: "too old"}}, nil
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+type otlpMetricsReceiver struct {
+	*otlpReceiver
+	colmetricspb.UnimplementedMetricsServiceServer
+}
+
+func (r otlpMetricsReceiver) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, req)
+	r.recordHeader(ctx)
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+type otlpTracesReceiver struct {
+	*otlpReceiver
+	coltracepb.UnimplementedTraceServiceServer
+}
+
+func (r otlpTracesReceiver) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = append(r.traces, req)
+	r.recordHeader(ctx)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (r *otlpReceiver) recordHeader(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	r.headers = append(r.headers, md.Get("x-api-key")...)
+}
+
+// startGRPC serves r over gRPC on a local port, and returns its address.
+func (r *otlpReceiver) startGRPC(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, otlpLogsReceiver{otlpReceiver: r})
+	colmetricspb.RegisterMetricsServiceServer(server, otlpMetricsReceiver{otlpReceiver: r})
+	coltracepb.RegisterTraceServiceServer(server, otlpTracesReceiver{otlpReceiver: r})
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// ServeHTTP receives the exports over HTTP/protobuf.
+func (r *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body io.Reader = req.Body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		reader, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = reader
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil || req.Header.Get("Content-Type") != "application/x-protobuf" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+
+	ctx := metadata.NewIncomingContext(req.Context(), metadata.Pairs("x-api-key", req.Header.Get("X-Api-Key")))
+	var resp proto.Message
+	switch req.URL.Path {
+	case "/otlp/v1/logs":
+		export := &collogspb.ExportLogsServiceRequest{}
+		err = proto.Unmarshal(raw, export)
+		resp, _ = otlpLogsReceiver{otlpReceiver: r}.Export(ctx, export)
+	case "/otlp/v1/metrics":
+		export := &colmetricspb.ExportMetricsServiceRequest{}
+		err = proto.Unmarshal(raw, export)
+		resp, _ = otlpMetricsReceiver{otlpReceiver: r}.Export(ctx, export)
+	case "/otlp/v1/traces":
+		export := &coltracepb.ExportTraceServiceRequest{}
+		err = proto.Unmarshal(raw, export)
+		resp, _ = otlpTracesReceiver{otlpReceiver: r}.Export(ctx, export)
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	out, _ := proto.Marshal(resp)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(out) //nolint:errcheck
+}
+
+func otlpTestRecords() []interface{} {
+	timestamp := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	return []interface{}{
+		analytics.AnalyticsRecord{
+			Method: "GET", Path: "/users", ListenPath: "/api/", Host: "api.example.com", ResponseCode: 200,
+			APIID: "api1", APIName: "Users", OrgID: "org1", IPAddress: "10.0.0.1", UserAgent: "curl/8",
+			Tags: []string{"tag1"}, TimeStamp: timestamp, RequestTime: 30,
+			Latency: analytics.Latency{Total: 30, Upstream: 20},
+		},
+		analytics.AnalyticsRecord{
+			Method: "GET", Path: "/users", ListenPath: "/api/", ResponseCode: 200,
+			APIID: "api1", APIName: "Users", TimeStamp: timestamp, RequestTime: 300,
+			Latency: analytics.Latency{Total: 300, Upstream: 250},
+		},
+		analytics.AnalyticsRecord{
+			Method: "POST", Path: "/users", ListenPath: "/api/", ResponseCode: 502,
+			APIID: "api1", APIName: "Users", TimeStamp: timestamp, RequestTime: 5,
+			Latency: analytics.Latency{Total: 5, Gateway: 5},
+		},
+	}
+}
+
+func otlpAttributes(attributes []*commonpb.KeyValue) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, attribute := range attributes {
+		switch value := attribute.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			values[attribute.Key] = value.StringValue
+		case *commonpb.AnyValue_IntValue:
+			values[attribute.Key] = value.IntValue
+		case *commonpb.AnyValue_ArrayValue:
+			values[attribute.Key] = len(value.ArrayValue.Values)
+		}
+	}
+	return values
+}
+
+func TestOTLPPump_Init(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		config      map[string]interface{}
+		expectedErr string
+	}{
+		{testName: "defaults", config: map[string]interface{}{}},
+		{testName: "http", config: map[string]interface{}{"protocol": "http", "compression": "gzip"}},
+		{testName: "unknown protocol", config: map[string]interface{}{"protocol": "thrift"}, expectedErr: `unsupported protocol "thrift"`},
+		{testName: "unknown compression", config: map[string]interface{}{"compression": "zstd"}, expectedErr: `unsupported compression "zstd"`},
+		{
+			testName:    "nothing to send",
+			config:      map[string]interface{}{"disable_logs": true},
+			expectedErr: "there's nothing to send",
+		},
+		{
+			testName:    "unsorted buckets",
+			config:      map[string]interface{}{"enable_metrics": true, "latency_buckets": []float64{1, 0.5}},
+			expectedErr: "latency_buckets must be in increasing order",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			pmp := &OTLPPump{}
+			err := pmp.Init(tc.config)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, pmp.Shutdown())
+		})
+	}
+}
+
+func TestOTLPPump_WriteData(t *testing.T) {
+	tcs := []struct {
+		testName string
+		protocol string
+	}{
+		{testName: "grpc", protocol: "grpc"},
+		{testName: "http", protocol: "http"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			receiver := &otlpReceiver{}
+			conf := map[string]interface{}{
+				"protocol":            tc.protocol,
+				"compression":         "gzip",
+				"headers":             map[string]string{"X-Api-Key": "secret"},
+				"resource_attributes": map[string]string{"deployment.environment.name": "test"},
+				"enable_metrics":      true,
+				"enable_traces":       true,
+				"latency_buckets":     []float64{0.01, 0.1, 1},
+			}
+			if tc.protocol == "grpc" {
+				conf["endpoint"] = receiver.startGRPC(t)
+				conf["insecure"] = true
+			} else {
+				server := httptest.NewServer(receiver)
+				t.Cleanup(server.Close)
+				conf["endpoint"] = server.URL + "/otlp/"
+			}
+
+			pmp := &OTLPPump{}
+			require.NoError(t, pmp.Init(conf))
+			t.Cleanup(func() { pmp.Shutdown() }) //nolint:errcheck
+
+			require.NoError(t, pmp.WriteData(context.Background(), otlpTestRecords()))
+			require.NoError(t, pmp.WriteData(context.Background(), otlpTestRecords()[:1]))
+
+			receiver.mu.Lock()
+			defer receiver.mu.Unlock()
+			assert.Equal(t, []string{"secret", "secret", "secret", "secret", "secret", "secret"}, receiver.headers)
+
+			// Logs.
+			require.Len(t, receiver.logs, 2)
+			resourceLogs := receiver.logs[0].ResourceLogs[0]
+			assert.Equal(t, map[string]interface{}{"service.name": "tyk-gateway", "deployment.environment.name": "test"}, otlpAttributes(resourceLogs.Resource.Attributes))
+			logRecords := resourceLogs.ScopeLogs[0].LogRecords
+			require.Len(t, logRecords, 3)
+			assert.Equal(t, "GET /users 200", logRecords[0].Body.GetStringValue())
+			assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_INFO, logRecords[0].SeverityNumber)
+			assert.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, logRecords[2].SeverityNumber)
+			assert.Equal(t, uint64(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC).UnixNano()), logRecords[0].TimeUnixNano)
+			assert.Equal(t, map[string]interface{}{
+				"http.request.method":       "GET",
+				"url.path":                  "/users",
+				"http.response.status_code": int64(200),
+				"server.address":            "api.example.com",
+				"client.address":            "10.0.0.1",
+				"user_agent.original":       "curl/8",
+				"tyk.api.id":                "api1",
+				"tyk.api.name":              "Users",
+				"tyk.org.id":                "org1",
+				"tyk.latency.total":         int64(30),
+				"tyk.latency.upstream":      int64(20),
+				"tyk.tags":                  1,
+			}, otlpAttributes(logRecords[0].Attributes))
+
+			// Traces.
+			require.Len(t, receiver.traces, 2)
+			spans := receiver.traces[0].ResourceSpans[0].ScopeSpans[0].Spans
+			require.Len(t, spans, 8, "a gateway and an upstream span for the first two records, a gateway span for the last one")
+			root, gateway, upstream := spans[0], spans[1], spans[2]
+			assert.Equal(t, "GET /api/", root.Name)
+			assert.Equal(t, tracepb.Span_SPAN_KIND_SERVER, root.Kind)
+			assert.Equal(t, uint64(30*time.Millisecond), root.EndTimeUnixNano-root.StartTimeUnixNano)
+			assert.Equal(t, root.SpanId, gateway.ParentSpanId)
+			assert.Equal(t, root.SpanId, upstream.ParentSpanId)
+			assert.Equal(t, root.StartTimeUnixNano, gateway.StartTimeUnixNano)
+			assert.Equal(t, uint64(10*time.Millisecond), gateway.EndTimeUnixNano-gateway.StartTimeUnixNano)
+			assert.Equal(t, gateway.EndTimeUnixNano, upstream.StartTimeUnixNano)
+			assert.Equal(t, root.EndTimeUnixNano, upstream.EndTimeUnixNano)
+			assert.Equal(t, root.TraceId, logRecords[0].TraceId, "the log records are linked to their span")
+			assert.Equal(t, root.SpanId, logRecords[0].SpanId)
+			assert.Nil(t, root.Status)
+			assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, spans[6].Status.GetCode())
+			assert.Equal(t, "gateway", spans[7].Name)
+
+			// Metrics.
+			require.Len(t, receiver.metrics, 2)
+			metrics := receiver.metrics[1].ResourceMetrics[0].ScopeMetrics[0].Metrics
+			require.Len(t, metrics, 2)
+			requests := metrics[0].GetSum()
+			assert.Equal(t, "tyk.api.requests", metrics[0].Name)
+			assert.True(t, requests.IsMonotonic)
+			require.Len(t, requests.DataPoints, 1, "only the series the batch changed are exported")
+			assert.Equal(t, int64(3), requests.DataPoints[0].GetAsInt(), "the counts are cumulative")
+
+			durations := metrics[1].GetHistogram()
+			assert.Equal(t, "http.server.request.duration", metrics[1].Name)
+			require.Len(t, durations.DataPoints, 1)
+			duration := durations.DataPoints[0]
+			assert.Equal(t, uint64(3), duration.Count)
+			assert.InDelta(t, 0.36, duration.GetSum(), 1e-9)
+			assert.InDelta(t, 0.03, duration.GetMin(), 1e-9)
+			assert.InDelta(t, 0.3, duration.GetMax(), 1e-9)
+			assert.Equal(t, []float64{0.01, 0.1, 1}, duration.ExplicitBounds)
+			assert.Equal(t, []uint64{0, 2, 1, 0}, duration.BucketCounts)
+			assert.Equal(t, map[string]interface{}{
+				"tyk.api.id":                "api1",
+				"tyk.api.name":              "Users",
+				"http.request.method":       "GET",
+				"http.response.status_code": int64(200),
+			}, otlpAttributes(duration.Attributes))
+
+			firstBatch := receiver.metrics[0].ResourceMetrics[0].ScopeMetrics[0].Metrics[0].GetSum()
+			assert.Len(t, firstBatch.DataPoints, 2)
+		})
+	}
+}
+
+func TestOTLPPump_WriteDataErrors(t *testing.T) {
+	receiver := &otlpReceiver{rejected: 2}
+	pmp := &OTLPPump{}
+	require.NoError(t, pmp.Init(map[string]interface{}{"endpoint": receiver.startGRPC(t), "insecure": true}))
+	t.Cleanup(func() { pmp.Shutdown() }) //nolint:errcheck
+
+	err := pmp.WriteData(context.Background(), otlpTestRecords())
+	assert.EqualError(t, err, "couldn't export the logs: the collector rejected 2 log records: too old")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	pmp = &OTLPPump{}
+	require.NoError(t, pmp.Init(map[string]interface{}{"protocol": "http", "endpoint": server.URL}))
+
+	err = pmp.WriteData(context.Background(), otlpTestRecords())
+	assert.EqualError(t, err, "couldn't export the logs: collector responded with status 503: unavailable")
+}
@@ -0,0 +1,444 @@
+package pumps
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/mitchellh/mapstructure"
+	"github.com/parquet-go/parquet-go"
+)
+
+// ParquetPump writes the analytics records to Parquet files, in a directory partitioned
+// by organisation, day and hour the way Hive lays out its tables.
+type ParquetPump struct {
+	conf    *ParquetConf
+	schema  *parquet.Schema
+	columns []parquetColumn
+	options []parquet.WriterOption
+
+	mu    sync.Mutex
+	files map[string]*parquetFile
+	stop  chan struct{}
+	CommonPumpConfig
+}
+
+// parquetFile is a file being written, under a temporary name until it's rolled.
+type parquetFile struct {
+	file     *os.File
+	writer   *parquet.Writer
+	path     string
+	openedAt time.Time
+}
+
+var (
+	parquetPrefix     = "parquet-pump"
+	parquetDefaultENV = PUMPS_ENV_PREFIX + "_PARQUET" + PUMPS_ENV_META_PREFIX
+
+	// parquetNow returns the current time, replaced in the tests.
+	parquetNow = time.Now
+	// parquetRollInterval is how often the files past their maximum age are rolled when no
+	// records are written.
+	parquetRollInterval = 10 * time.Second
+)
+
+const (
+	parquetDefaultMaxFileSize  = 128
+	parquetDefaultMaxFileAge   = 300
+	parquetDefaultRowGroupSize = 100000
+
+	// parquetDefaultPartition is the partition of the records without an organisation, the
+	// one Hive uses for the null values.
+	parquetDefaultPartition = "__HIVE_DEFAULT_PARTITION__"
+)
+
+// @PumpConf Parquet
+type ParquetConf struct {
+	// The prefix for the environment variables that will be used to override the configuration.
+	// Defaults to `TYK_PMP_PUMPS_PARQUET_META`
+	EnvPrefix string `mapstructure:"meta_env_prefix"`
+	// The directory of the files, created if missing. The files of the records of an
+	// organisation, day and hour are in its `org_id=<org>/date=<YYYY-MM-DD>/hour=<HH>`
+	// directory.
+	Directory string `json:"directory" mapstructure:"directory"`
+	// The compression of the files, `zstd`, `snappy` or `none`. Defaults to `zstd`.
+	Compression string `json:"compression" mapstructure:"compression"`
+	// The columns of the files, all of them when empty. The organisation isn't one of them,
+	// it's in the directory of the files.
+	Columns []string `json:"columns" mapstructure:"columns"`
+	// The size, in MB, a file is rolled at. Defaults to 128.
+	MaxFileSize int `json:"max_file_size" mapstructure:"max_file_size"`
+	// The time, in seconds, a file is rolled after. Defaults to 300.
+	MaxFileAge int `json:"max_file_age" mapstructure:"max_file_age"`
+	// The maximum number of records of a row group, buffered in memory until it's written.
+	// Defaults to 100000.
+	RowGroupSize int `json:"row_group_size" mapstructure:"row_group_size"`
+}
+
+// parquetColumn is a column of the files, its values taken from the records by value.
+type parquetColumn struct {
+	name  string
+	node  parquet.Node
+	value func(*analytics.AnalyticsRecord) any
+}
+
+// newParquetColumn returns the column called name, of type node.
+func newParquetColumn[T any](name string, node parquet.Node, value func(*analytics.AnalyticsRecord) T) parquetColumn {
+	return parquetColumn{
+		name:  name,
+		node:  node,
+		value: func(record *analytics.AnalyticsRecord) any { return value(record) },
+	}
+}
+
+// parquetColumns are the columns of the files, in the order of the documentation.
+var parquetColumns = []parquetColumn{
+	newParquetColumn("timestamp", parquet.Timestamp(parquet.Millisecond), func(r *analytics.AnalyticsRecord) time.Time { return r.TimeStamp }),
+	newParquetColumn("api_id", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.APIID }),
+	newParquetColumn("api_name", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.APIName }),
+	newParquetColumn("api_version", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.APIVersion }),
+	newParquetColumn("api_key", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.APIKey }),
+	newParquetColumn("oauth_id", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.OauthID }),
+	newParquetColumn("alias", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.Alias }),
+	newParquetColumn("method", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.Method }),
+	newParquetColumn("host", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.Host }),
+	newParquetColumn("path", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.Path }),
+	newParquetColumn("raw_path", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.RawPath }),
+	newParquetColumn("original_path", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.OriginalPath }),
+	newParquetColumn("listen_path", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.ListenPath }),
+	newParquetColumn("response_code", parquet.Int(32), func(r *analytics.AnalyticsRecord) int32 { return int32(r.ResponseCode) }),
+	newParquetColumn("content_length", parquet.Int(64), func(r *analytics.AnalyticsRecord) int64 { return r.ContentLength }),
+	newParquetColumn("user_agent", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.UserAgent }),
+	newParquetColumn("ip_address", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.IPAddress }),
+	newParquetColumn("geo_country", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.Geo.Country.ISOCode }),
+	newParquetColumn("geo_city_geoname_id", parquet.Uint(64), func(r *analytics.AnalyticsRecord) uint64 { return uint64(r.Geo.City.GeoNameID) }),
+	newParquetColumn("geo_latitude", parquet.Leaf(parquet.DoubleType), func(r *analytics.AnalyticsRecord) float64 { return r.Geo.Location.Latitude }),
+	newParquetColumn("geo_longitude", parquet.Leaf(parquet.DoubleType), func(r *analytics.AnalyticsRecord) float64 { return r.Geo.Location.Longitude }),
+	newParquetColumn("geo_timezone", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.Geo.Location.TimeZone }),
+	newParquetColumn("request_time", parquet.Int(64), func(r *analytics.AnalyticsRecord) int64 { return r.RequestTime }),
+	newParquetColumn("latency_total", parquet.Int(64), func(r *analytics.AnalyticsRecord) int64 { return r.Latency.Total }),
+	newParquetColumn("latency_upstream", parquet.Int(64), func(r *analytics.AnalyticsRecord) int64 { return r.Latency.Upstream }),
+	newParquetColumn("latency_gateway", parquet.Int(64), func(r *analytics.AnalyticsRecord) int64 { return r.Latency.Gateway }),
+	newParquetColumn("network_bytes_in", parquet.Int(64), func(r *analytics.AnalyticsRecord) int64 { return r.Network.BytesIn }),
+	newParquetColumn("network_bytes_out", parquet.Int(64), func(r *analytics.AnalyticsRecord) int64 { return r.Network.BytesOut }),
+	newParquetColumn("tags", parquet.Repeated(parquet.String()), func(r *analytics.AnalyticsRecord) []string { return r.Tags }),
+	newParquetColumn("track_path", parquet.Leaf(parquet.BooleanType), func(r *analytics.AnalyticsRecord) bool { return r.TrackPath }),
+	newParquetColumn("raw_request", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.RawRequest }),
+	newParquetColumn("raw_response", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.RawResponse }),
+	// The records without an expiry have none.
+	newParquetColumn("expire_at", parquet.Optional(parquet.Timestamp(parquet.Millisecond)), func(r *analytics.AnalyticsRecord) any {
+		if r.ExpireAt.IsZero() {
+			return nil
+		}
+		return r.ExpireAt
+	}),
+	newParquetColumn("is_graphql", parquet.Leaf(parquet.BooleanType), func(r *analytics.AnalyticsRecord) bool { return r.IsGraphRecord() }),
+	newParquetColumn("graphql_operation_type", parquet.String(), func(r *analytics.AnalyticsRecord) string {
+		return r.ToGraphRecord().OperationType
+	}),
+	newParquetColumn("graphql_root_fields", parquet.Repeated(parquet.String()), func(r *analytics.AnalyticsRecord) []string {
+		return r.GraphQLStats.RootFields
+	}),
+	newParquetColumn("graphql_errors", parquet.Repeated(parquet.String()), func(r *analytics.AnalyticsRecord) []string {
+		messages := make([]string, len(r.GraphQLStats.Errors))
+		for i, graphErr := range r.GraphQLStats.Errors {
+			messages[i] = graphErr.Message
+		}
+		return messages
+	}),
+	newParquetColumn("graphql_has_errors", parquet.Leaf(parquet.BooleanType), func(r *analytics.AnalyticsRecord) bool { return r.ToGraphRecord().HasErrors }),
+	newParquetColumn("is_mcp", parquet.Leaf(parquet.BooleanType), func(r *analytics.AnalyticsRecord) bool { return r.IsMCPRecord() }),
+	newParquetColumn("mcp_jsonrpc_method", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.MCPStats.JSONRPCMethod }),
+	newParquetColumn("mcp_primitive_type", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.MCPStats.PrimitiveType }),
+	newParquetColumn("mcp_primitive_name", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.MCPStats.PrimitiveName }),
+}
+
+func (p *ParquetPump) New() Pump {
+	return &ParquetPump{}
+}
+
+func (p *ParquetPump) GetName() string {
+	return "Parquet Pump"
+}
+
+func (p *ParquetPump) GetEnvPrefix() string {
+	return p.conf.EnvPrefix
+}
+
+func (p *ParquetPump) Init(config interface{}) error {
+	p.conf = &ParquetConf{}
+	p.log = p.newLog(parquetPrefix)
+
+	if err := mapstructure.Decode(config, p.conf); err != nil {
+		p.log.Error("Failed to decode configuration: ", err)
+		return err
+	}
+
+	processPumpEnvVars(p, p.log, p.conf, parquetDefaultENV)
+
+	if p.conf.Directory == "" {
+		return errors.New("directory is required")
+	}
+	if p.conf.MaxFileSize <= 0 {
+		p.conf.MaxFileSize = parquetDefaultMaxFileSize
+	}
+	if p.conf.MaxFileAge <= 0 {
+		p.conf.MaxFileAge = parquetDefaultMaxFileAge
+	}
+	if p.conf.RowGroupSize <= 0 {
+		p.conf.RowGroupSize = parquetDefaultRowGroupSize
+	}
+
+	var codec parquet.WriterOption
+	switch p.conf.Compression {
+	case "", "zstd":
+		codec = parquet.Compression(&parquet.Zstd)
+	case "snappy":
+		codec = parquet.Compression(&parquet.Snappy)
+	case "none":
+		codec = parquet.Compression(&parquet.Uncompressed)
+	default:
+		return fmt.Errorf("unsupported compression %q, must be zstd, snappy or none", p.conf.Compression)
+	}
+
+	columns, err := parquetProjection(p.conf.Columns)
+	if err != nil {
+		return err
+	}
+	p.columns = columns
+	p.schema = parquetSchema(columns)
+	p.options = []parquet.WriterOption{p.schema, codec, parquet.MaxRowsPerRowGroup(int64(p.conf.RowGroupSize))}
+
+	if err := os.MkdirAll(p.conf.Directory, 0755); err != nil {
+		return err
+	}
+	p.files = map[string]*parquetFile{}
+	p.stop = make(chan struct{})
+	go p.rollOld(p.stop)
+
+	p.log.Info(p.GetName() + " Initialized")
+	return nil
+}
+
+// parquetProjection returns the columns called names, all of them when there are none.
+func parquetProjection(names []string) ([]parquetColumn, error) {
+	if len(names) == 0 {
+		return parquetColumns, nil
+	}
+
+	columns := make([]parquetColumn, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		found := false
+		for _, column := range parquetColumns {
+			if column.name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unsupported column %q", name)
+		}
+	}
+	return columns, nil
+}
+
+func (p *ParquetPump) WriteData(ctx context.Context, data []interface{}) error {
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	written := 0
+	for _, v := range data {
+		record, ok := v.(analytics.AnalyticsRecord)
+		if !ok {
+			continue
+		}
+
+		file, err := p.file(parquetPartition(&record))
+		if err != nil {
+			p.log.WithContext(ctx).Error("Failed to open the file: ", err)
+			return err
+		}
+		if err := file.writer.Write(parquetRow(p.columns, &record)); err != nil {
+			p.log.WithContext(ctx).Error("Failed to write the record: ", err)
+			return err
+		}
+		written++
+	}
+
+	// The files rolled hold the records of the earlier batches too, so their errors are
+	// only logged, the records of this one being written.
+	p.roll(false)
+
+	p.log.WithContext(ctx).Info("Purged ", written, " records...")
+	return nil
+}
+
+// parquetSchema returns the schema of the files of columns.
+func parquetSchema(columns []parquetColumn) *parquet.Schema {
+	group := parquet.Group{}
+	for _, column := range columns {
+		group[column.name] = column.node
+	}
+	return parquet.NewSchema("tyk_analytics", group)
+}
+
+// parquetRow returns the values of columns for record.
+func parquetRow(columns []parquetColumn, record *analytics.AnalyticsRecord) map[string]any {
+	row := make(map[string]any, len(columns))
+	for _, column := range columns {
+		row[column.name] = column.value(record)
+	}
+	return row
+}
+
+// parquetPartition returns the directory of the files of record, relative to the
+// directory of the pump.
+func parquetPartition(record *analytics.AnalyticsRecord) string {
+	org := parquetDefaultPartition
+	if record.OrgID != "" {
+		org = parquetEscape(record.OrgID)
+	}
+	timestamp := record.TimeStamp.UTC()
+	return filepath.Join("org_id="+org, "date="+timestamp.Format("2006-01-02"), "hour="+timestamp.Format("15"))
+}
+
+// parquetEscape percent-encodes the characters of value but letters, digits, '-' and '_',
+// keeping the partition a single directory.
+func parquetEscape(value string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' {
+			escaped.WriteByte(c)
+		} else {
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+// file returns the open file of partition, creating it under a hidden name the readers
+// of the directory skip.
+func (p *ParquetPump) file(partition string) (*parquetFile, error) {
+	if file, ok := p.files[partition]; ok {
+		return file, nil
+	}
+
+	dir := filepath.Join(p.conf.Directory, partition)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	openedAt := parquetNow()
+	name := fmt.Sprintf("part-%s-%s.parquet", openedAt.UTC().Format("20060102T150405"), hex.EncodeToString(id))
+	path := filepath.Join(dir, name)
+
+	f, err := os.OpenFile(filepath.Join(dir, "."+name+".tmp"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &parquetFile{
+		file:     f,
+		writer:   parquet.NewWriter(f, p.options...),
+		path:     path,
+		openedAt: openedAt,
+	}
+	p.files[partition] = file
+	return file, nil
+}
+
+// rollOld rolls the files past their maximum age until the pump is shut down.
+func (p *ParquetPump) rollOld(stop <-chan struct{}) {
+	ticker := time.NewTicker(parquetRollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.roll(false)
+			p.mu.Unlock()
+		}
+	}
+}
+
+// roll completes the files past the maximum size or age, all of them when all is set.
+func (p *ParquetPump) roll(all bool) error {
+	maxSize := int64(p.conf.MaxFileSize) << 20
+	maxAge := time.Duration(p.conf.MaxFileAge) * time.Second
+	now := parquetNow()
+
+	var errs []error
+	for partition, file := range p.files {
+		if !all && file.writer.Size() < maxSize && now.Sub(file.openedAt) < maxAge {
+			continue
+		}
+		delete(p.files, partition)
+		if err := file.complete(); err != nil {
+			p.log.Error("Failed to complete ", file.path, ": ", err)
+			errs = append(errs, err)
+			continue
+		}
+		p.log.Debug("Rolled ", file.path)
+	}
+	return errors.Join(errs...)
+}
+
+// complete writes the footer of the file and renames it to its final name, the readers
+// never seeing it partially written. A file failing to complete is renamed with a
+// `.failed` suffix rather than removed.
+func (f *parquetFile) complete() error {
+	tmp := f.file.Name()
+	err := f.writer.Close()
+	if err == nil {
+		err = f.file.Sync()
+	}
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// The rows already written are kept, moved aside for them to be recovered.
+		failed := strings.TrimSuffix(tmp, ".tmp") + ".failed"
+		if renameErr := os.Rename(tmp, failed); renameErr != nil {
+			failed = tmp
+		}
+		return fmt.Errorf("%w, the rows written kept in %s", err, failed)
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (p *ParquetPump) Shutdown() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	return p.roll(true)
+}
@@ -0,0 +1,249 @@
+package pumps
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parquetFiles returns the paths, relative to dir, of the files in it.
+func parquetFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	require.NoError(t, filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	}))
+	sort.Strings(files)
+	return files
+}
+
+// readParquet returns the names of the columns of the file at path and its rows, the
+// values of a row by column.
+func readParquet(t *testing.T, path string) ([]string, []map[string][]parquet.Value) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	info, err := f.Stat()
+	require.NoError(t, err)
+	file, err := parquet.OpenFile(f, info.Size())
+	require.NoError(t, err)
+
+	var names []string
+	for _, column := range file.Schema().Columns() {
+		names = append(names, strings.Join(column, "."))
+	}
+
+	rows := make([]parquet.Row, file.NumRows())
+	reader := parquet.NewReader(file)
+	n, _ := reader.ReadRows(rows)
+	require.Equal(t, len(rows), n)
+
+	values := make([]map[string][]parquet.Value, len(rows))
+	for i, row := range rows {
+		values[i] = map[string][]parquet.Value{}
+		for _, value := range row {
+			name := names[value.Column()]
+			if !value.IsNull() {
+				values[i][name] = append(values[i][name], value)
+			}
+		}
+	}
+	return names, values
+}
+
+func newParquetTestPump(t *testing.T, config map[string]interface{}) (*ParquetPump, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	config["directory"] = dir
+	pmp := &ParquetPump{}
+	require.NoError(t, pmp.Init(config))
+	t.Cleanup(func() { pmp.Shutdown() })
+	return pmp, dir
+}
+
+func TestParquetPump_Init(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		config      map[string]interface{}
+		expectedErr string
+	}{
+		{testName: "defaults", config: map[string]interface{}{"directory": t.TempDir()}},
+		{testName: "no directory", config: map[string]interface{}{}, expectedErr: "directory is required"},
+		{
+			testName:    "unknown compression",
+			config:      map[string]interface{}{"directory": t.TempDir(), "compression": "gzip"},
+			expectedErr: `unsupported compression "gzip"`,
+		},
+		{
+			testName:    "unknown column",
+			config:      map[string]interface{}{"directory": t.TempDir(), "columns": []string{"api_id", "org_id"}},
+			expectedErr: `unsupported column "org_id"`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			pmp := &ParquetPump{}
+			err := pmp.Init(tc.config)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer pmp.Shutdown()
+			assert.Equal(t, 128, pmp.conf.MaxFileSize)
+			assert.Equal(t, 300, pmp.conf.MaxFileAge)
+			assert.Equal(t, 100000, pmp.conf.RowGroupSize)
+			assert.Len(t, pmp.schema.Columns(), len(parquetColumns))
+		})
+	}
+}
+
+func TestParquetPump_WriteData(t *testing.T) {
+	pmp, dir := newParquetTestPump(t, map[string]interface{}{"compression": "snappy"})
+
+	timestamp := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	expireAt := timestamp.AddDate(0, 0, 7)
+	data := []interface{}{
+		analytics.AnalyticsRecord{
+			APIID: "api1", OrgID: "org1", Method: "GET", Path: "/users", ResponseCode: 200,
+			TimeStamp: timestamp, ExpireAt: expireAt, Tags: []string{"tag1", "tag2"},
+			Latency: analytics.Latency{Total: 30, Upstream: 20},
+		},
+		analytics.AnalyticsRecord{APIID: "api2", OrgID: "org1", ResponseCode: 500, TimeStamp: timestamp.Add(time.Minute)},
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", TimeStamp: timestamp.Add(time.Hour)},
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "../org/2", TimeStamp: timestamp},
+		analytics.AnalyticsRecord{APIID: "api1", TimeStamp: timestamp},
+		"not a record",
+	}
+	require.NoError(t, pmp.WriteData(context.Background(), data))
+
+	for _, file := range parquetFiles(t, dir) {
+		assert.True(t, strings.HasPrefix(filepath.Base(file), ".part-"), "the files being written are hidden: %s", file)
+		assert.True(t, strings.HasSuffix(file, ".parquet.tmp"))
+	}
+
+	require.NoError(t, pmp.Shutdown())
+	files := parquetFiles(t, dir)
+	require.Len(t, files, 4, "a file per partition")
+	assert.True(t, strings.HasPrefix(files[0], "org_id=%2E%2E%2Forg%2F2/date=2026-10-01/hour=12/part-"))
+	assert.True(t, strings.HasPrefix(files[1], "org_id=__HIVE_DEFAULT_PARTITION__/date=2026-10-01/hour=12/part-"))
+	assert.True(t, strings.HasPrefix(files[2], "org_id=org1/date=2026-10-01/hour=12/part-"))
+	assert.True(t, strings.HasPrefix(files[3], "org_id=org1/date=2026-10-01/hour=13/part-"))
+	for _, file := range files {
+		assert.True(t, strings.HasSuffix(file, ".parquet"))
+	}
+
+	names, rows := readParquet(t, filepath.Join(dir, files[2]))
+	assert.Len(t, names, len(parquetColumns))
+	assert.NotContains(t, names, "org_id")
+	require.Len(t, rows, 2)
+	assert.Equal(t, "api1", rows[0]["api_id"][0].String())
+	assert.Equal(t, "/users", rows[0]["path"][0].String())
+	assert.Equal(t, int32(200), rows[0]["response_code"][0].Int32())
+	assert.Equal(t, int64(30), rows[0]["latency_total"][0].Int64())
+	assert.Equal(t, timestamp.UnixMilli(), rows[0]["timestamp"][0].Int64())
+	assert.Equal(t, expireAt.UnixMilli(), rows[0]["expire_at"][0].Int64())
+	require.Len(t, rows[0]["tags"], 2)
+	assert.Equal(t, "tag2", rows[0]["tags"][1].String())
+	assert.Equal(t, "api2", rows[1]["api_id"][0].String())
+	assert.Empty(t, rows[1]["expire_at"], "the records without an expiry have none")
+	assert.Empty(t, rows[1]["tags"])
+}
+
+func TestParquetPump_Columns(t *testing.T) {
+	pmp, dir := newParquetTestPump(t, map[string]interface{}{"columns": []string{"timestamp", "api_id", "response_code"}})
+
+	timestamp := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", Path: "/users", ResponseCode: 200, TimeStamp: timestamp},
+	}))
+	require.NoError(t, pmp.Shutdown())
+
+	files := parquetFiles(t, dir)
+	require.Len(t, files, 1)
+	names, rows := readParquet(t, filepath.Join(dir, files[0]))
+	assert.ElementsMatch(t, []string{"timestamp", "api_id", "response_code"}, names)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "api1", rows[0]["api_id"][0].String())
+}
+
+func TestParquetPump_Roll(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	origNow := parquetNow
+	parquetNow = func() time.Time { return now }
+	t.Cleanup(func() { parquetNow = origNow })
+
+	pmp, dir := newParquetTestPump(t, map[string]interface{}{"max_file_age": 60, "max_file_size": 1})
+	record := analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", TimeStamp: now}
+	completed := func() int {
+		count := 0
+		for _, file := range parquetFiles(t, dir) {
+			if strings.HasSuffix(file, ".parquet") {
+				count++
+			}
+		}
+		return count
+	}
+
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{record}))
+	assert.Equal(t, 0, completed())
+
+	now = now.Add(time.Minute)
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{record}))
+	assert.Equal(t, 1, completed(), "the files are rolled after max_file_age")
+	assert.Empty(t, pmp.files)
+
+	large := record
+	large.RawRequest = strings.Repeat("x", 2<<20)
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{large}))
+	assert.Equal(t, 2, completed(), "the files are rolled at max_file_size")
+
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{record}))
+	require.NoError(t, pmp.Shutdown())
+	assert.Equal(t, 3, completed())
+	for _, file := range parquetFiles(t, dir) {
+		assert.False(t, strings.HasSuffix(file, ".tmp"), "no file is left partially written")
+	}
+}
+
+func TestParquetPump_RollError(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	origNow := parquetNow
+	parquetNow = func() time.Time { return now }
+	t.Cleanup(func() { parquetNow = origNow })
+
+	pmp, dir := newParquetTestPump(t, map[string]interface{}{"max_file_age": 60})
+	record := analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", TimeStamp: now}
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{record}))
+	for _, file := range pmp.files {
+		require.NoError(t, file.file.Close())
+	}
+
+	now = now.Add(time.Minute)
+	other := analytics.AnalyticsRecord{APIID: "api1", OrgID: "org2", TimeStamp: now}
+	assert.NoError(t, pmp.WriteData(context.Background(), []interface{}{other}), "the batch doesn't fail with the roll of another file")
+
+	files := parquetFiles(t, dir)
+	require.Len(t, files, 2)
+	assert.Regexp(t, `^org_id=org1/date=2026-10-01/hour=12/\.part-.*\.parquet\.failed$`, files[0], "the file failing to complete is kept")
+	assert.True(t, strings.HasSuffix(files[1], ".parquet.tmp"))
+}
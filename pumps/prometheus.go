@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	"github.com/sirupsen/logrus"
@@ -180,7 +181,7 @@ func (p *PrometheusPump) GetEnvPrefix() string {
 
 func (p *PrometheusPump) Init(conf interface{}) error {
 	p.conf = &PrometheusConf{}
-	p.log = log.WithField("prefix", prometheusPrefix)
+	p.log = p.newLog(prometheusPrefix)
 
 	err := mapstructure.Decode(conf, &p.conf)
 	if err != nil {
@@ -325,12 +326,12 @@ func (p *PrometheusPump) processMetric(metric *PrometheusMetric, record analytic
 }
 
 func (p *PrometheusPump) WriteData(ctx context.Context, data []interface{}) error {
-	p.log.Debug("Attempting to write ", len(data), " records...")
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	for i, item := range data {
 		select {
 		case <-ctx.Done():
-			p.log.Warn("Purged ", i, " of ", len(data), " because of timeout.")
+			p.log.WithContext(ctx).Warn("Purged ", i, " of ", len(data), " because of timeout.")
 			return errors.New("prometheus pump couldn't write all the analytics records")
 		default:
 		}
@@ -348,14 +349,91 @@ func (p *PrometheusPump) WriteData(ctx context.Context, data []interface{}) erro
 	// after looping through all the analytics records, we expose the metrics to prometheus endpoint
 	for _, customMetric := range p.allMetrics {
 		if err := customMetric.Expose(); err != nil {
-			p.log.WithFields(logrus.Fields{
+			p.log.WithContext(ctx).WithFields(logrus.Fields{
 				"metric_type": customMetric.MetricType,
 				"metric_name": customMetric.Name,
 			}).Error("error writing prometheus metric:", err)
 		}
 	}
 
-	p.log.Info("Purged ", len(data), " records...")
+	p.log.WithContext(ctx).Info("Purged ", len(data), " records...")
+
+	return nil
+}
+
+const (
+	metricTykUptimeUp      = "tyk_uptime_up"
+	metricTykUptimeLatency = "tyk_uptime_latency"
+)
+
+// The uptime metrics are shared by every Prometheus pump, as they're all exposed by the
+// same registry.
+var (
+	uptimeMetricsOnce   sync.Once
+	uptimeUpMetric      *prometheus.GaugeVec
+	uptimeLatencyMetric *prometheus.HistogramVec
+)
+
+func registerUptimeMetrics() {
+	uptimeMetricsOnce.Do(func() {
+		uptimeUpMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: metricTykUptimeUp,
+				Help: "Whether the last uptime check per URL succeeded (1) or failed (0)",
+			},
+			[]string{"url", "api"},
+		)
+		uptimeLatencyMetric = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    metricTykUptimeLatency,
+				Help:    "Uptime check latency per URL",
+				Buckets: buckets,
+			},
+			[]string{"url", "api"},
+		)
+		prometheus.MustRegister(uptimeUpMetric, uptimeLatencyMetric)
+	})
+}
+
+// WriteUptimeRecords sets the tyk_uptime_up gauge of every checked URL to the outcome of
+// its last check, and observes the latency of every check in the tyk_uptime_latency
+// histogram. Either can be turned off with `disabled_metrics`.
+func (p *PrometheusPump) WriteUptimeRecords(ctx context.Context, data []analytics.UptimeReportData) error {
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " uptime records...")
+
+	registerUptimeMetrics()
+
+	upEnabled, latencyEnabled := true, true
+	for _, metric := range p.conf.DisabledMetrics {
+		switch metric {
+		case metricTykUptimeUp:
+			upEnabled = false
+		case metricTykUptimeLatency:
+			latencyEnabled = false
+		}
+	}
+
+	for i, record := range data {
+		select {
+		case <-ctx.Done():
+			p.log.WithContext(ctx).Warn("Purged ", i, " of ", len(data), " uptime records because of timeout.")
+			return errors.New("prometheus pump couldn't write all the uptime records")
+		default:
+		}
+
+		if upEnabled {
+			up := 0.0
+			if record.IsUp() {
+				up = 1
+			}
+			uptimeUpMetric.WithLabelValues(record.URL, record.APIID).Set(up)
+		}
+		if latencyEnabled {
+			uptimeLatencyMetric.WithLabelValues(record.URL, record.APIID).Observe(float64(record.RequestTime))
+		}
+	}
+
+	p.log.WithContext(ctx).Info("Purged ", len(data), " uptime records...")
 
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -1192,3 +1193,23 @@ func TestProcessMetric_HistogramType_LatencyMetric(t *testing.T) {
 		Latency:     analytics.Latency{Upstream: 50, Gateway: 10},
 	})
 }
+
+func TestPrometheusPump_WriteUptimeRecords(t *testing.T) {
+	p := &PrometheusPump{conf: &PrometheusConf{}}
+	p.log = log.WithField("prefix", prometheusPrefix)
+
+	records := []analytics.UptimeReportData{
+		{URL: "http://upstream-a", APIID: "api1", RequestTime: 12},
+		{URL: "http://upstream-b", APIID: "api2", RequestTime: 40, TCPError: true},
+		{URL: "http://upstream-a", APIID: "api1", RequestTime: 30, ServerError: true},
+	}
+	assert.NoError(t, p.WriteUptimeRecords(context.Background(), records))
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(uptimeUpMetric.WithLabelValues("http://upstream-a", "api1")), "the gauge must hold the last check")
+	assert.Equal(t, 0.0, testutil.ToFloat64(uptimeUpMetric.WithLabelValues("http://upstream-b", "api2")))
+
+	assert.NoError(t, p.WriteUptimeRecords(context.Background(), records[:1]))
+	assert.Equal(t, 1.0, testutil.ToFloat64(uptimeUpMetric.WithLabelValues("http://upstream-a", "api1")))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(uptimeLatencyMetric), "one latency series per URL")
+}
@@ -59,12 +59,29 @@ type Pump interface {
 	GetDecodedRequest() bool
 }
 
+// LogFieldsSetter is implemented by the pumps whose log lines can carry fields set by the
+// Pump, such as the name the pump is configured under. The pumps embedding
+// CommonPumpConfig implement it.
+type LogFieldsSetter interface {
+	SetLogFields(logrus.Fields)
+}
+
 type UptimePump interface {
 	GetName() string
 	Init(interface{}) error
 	WriteUptimeData(data []interface{})
 }
 
+// UptimeDataPump is implemented by pumps that can write uptime data as well as analytics
+// records. Pumps with `uptime` enabled in their config get every uptime record that
+// passes their uptime filters, decoded.
+type UptimeDataPump interface {
+	Pump
+	SetUptimeFilters(analytics.UptimeFilters)
+	GetUptimeFilters() analytics.UptimeFilters
+	WriteUptimeRecords(context.Context, []analytics.UptimeReportData) error
+}
+
 func GetPumpByName(name string) (Pump, error) {
 
 	if pump, ok := AvailablePumps[strings.ToLower(name)]; ok && pump != nil {
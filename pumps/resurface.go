@@ -55,7 +55,7 @@ func (rp *ResurfacePump) GetEnvPrefix() string {
 func (rp *ResurfacePump) Init(config interface{}) error {
 	rp.wg = sync.WaitGroup{}
 	rp.config = &ResurfacePumpConfig{}
-	rp.log = log.WithField("prefix", resurfacePrefix)
+	rp.log = rp.newLog(resurfacePrefix)
 
 	err := mapstructure.Decode(config, &rp.config)
 	if err != nil {
@@ -258,11 +258,11 @@ func (rp *ResurfacePump) writeData() {
 }
 
 func (rp *ResurfacePump) WriteData(ctx context.Context, data []interface{}) error {
-	rp.log.Debug("Writing ", len(data), " records")
+	rp.log.WithContext(ctx).Debug("Writing ", len(data), " records")
 	if rp.enabled {
 		select {
 		case rp.data <- data:
-			rp.log.Info("Purged ", len(data), " records...")
+			rp.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 		case <-ctx.Done():
 			// Context has been cancelled or timed out
 			return ctx.Err()
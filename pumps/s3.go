@@ -0,0 +1,515 @@
+package pumps
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gofrs/uuid"
+	"github.com/mitchellh/mapstructure"
+	"github.com/parquet-go/parquet-go"
+)
+
+// S3Pump buffers the analytics records and uploads them to S3, or any S3 compatible
+// object storage, as gzipped NDJSON or Parquet objects.
+type S3Pump struct {
+	conf   *S3Conf
+	client s3Client
+
+	mu      sync.Mutex
+	records []analytics.AnalyticsRecord
+	since   time.Time
+	stop    chan struct{}
+	CommonPumpConfig
+}
+
+// s3Client is the part of s3.Client the pump uses.
+type s3Client interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+var (
+	s3Prefix     = "s3-pump"
+	s3DefaultENV = PUMPS_ENV_PREFIX + "_S3" + PUMPS_ENV_META_PREFIX
+
+	// s3FlushCheckInterval is how often the records buffered for longer than the flush
+	// interval are uploaded when no records are written.
+	s3FlushCheckInterval = time.Second
+
+	// s3ParquetColumns are the columns of the Parquet objects, the ones of the Parquet pump
+	// and the organisation, not always in the key of the objects.
+	s3ParquetColumns = append([]parquetColumn{
+		newParquetColumn("org_id", parquet.String(), func(r *analytics.AnalyticsRecord) string { return r.OrgID }),
+	}, parquetColumns...)
+
+	s3Placeholder = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+	// s3KeyFields are the fields of the records the keys of the objects can have.
+	s3KeyFields = map[string]func(*analytics.AnalyticsRecord) string{
+		"org_id": func(r *analytics.AnalyticsRecord) string { return r.OrgID },
+		"api_id": func(r *analytics.AnalyticsRecord) string { return r.APIID },
+		"yyyy":   func(r *analytics.AnalyticsRecord) string { return r.TimeStamp.UTC().Format("2006") },
+		"mm":     func(r *analytics.AnalyticsRecord) string { return r.TimeStamp.UTC().Format("01") },
+		"dd":     func(r *analytics.AnalyticsRecord) string { return r.TimeStamp.UTC().Format("02") },
+		"hh":     func(r *analytics.AnalyticsRecord) string { return r.TimeStamp.UTC().Format("15") },
+	}
+)
+
+const (
+	s3FormatJSON    = "json"
+	s3FormatParquet = "parquet"
+
+	s3DefaultKeyTemplate     = "{{org_id}}/{{yyyy}}/{{mm}}/{{dd}}/{{uuid}}"
+	s3DefaultMaxRecords      = 10000
+	s3DefaultBufferedBatches = 10
+	s3DefaultFlushInterval   = 60
+	s3DefaultPartSize        = 8
+	s3DefaultTimeout         = 60
+	// s3MinPartSize is the smallest part, in MB, S3 accepts but for the last one.
+	s3MinPartSize = 5
+)
+
+// @PumpConf S3
+type S3Conf struct {
+	// The prefix for the environment variables that will be used to override the configuration.
+	// Defaults to `TYK_PMP_PUMPS_S3_META`
+	EnvPrefix string `mapstructure:"meta_env_prefix"`
+	// The bucket the objects are uploaded to.
+	Bucket string `json:"bucket" mapstructure:"bucket"`
+	// The region of the bucket. Defaults to the one of the AWS environment, or `us-east-1`.
+	AWSRegion string `json:"aws_region" mapstructure:"aws_region"`
+	// The access key ID of the pump. Defaults to the credentials of the AWS environment.
+	AWSKey string `json:"aws_key" mapstructure:"aws_key"`
+	// The secret access key of the pump.
+	AWSSecret string `json:"aws_secret" mapstructure:"aws_secret"`
+	// The session token of the pump, only required with temporary credentials.
+	AWSToken string `json:"aws_token" mapstructure:"aws_token"`
+	// The URL of an S3 compatible object storage, such as MinIO or Ceph, rather than AWS.
+	AWSEndpoint string `json:"aws_endpoint" mapstructure:"aws_endpoint"`
+	// Addresses the bucket in the path of the URLs rather than in their host, as MinIO
+	// expects by default.
+	ForcePathStyle bool `json:"force_path_style" mapstructure:"force_path_style"`
+	// The format of the objects, `json`, gzipped NDJSON, or `parquet`. Defaults to `json`.
+	Format string `json:"format" mapstructure:"format"`
+	// The key of the objects, with the `{{org_id}}`, `{{api_id}}`, `{{yyyy}}`, `{{mm}}`,
+	// `{{dd}}` and `{{hh}}` of their records and a `{{uuid}}`, such as
+	// `{{org_id}}/{{yyyy}}/{{mm}}/{{dd}}/{{uuid}}.json.gz`. Defaults to
+	// `{{org_id}}/{{yyyy}}/{{mm}}/{{dd}}/{{uuid}}` followed by `.json.gz` or `.parquet`.
+	KeyTemplate string `json:"key_template" mapstructure:"key_template"`
+	// The number of buffered records the objects are uploaded at. Defaults to 10000.
+	MaxRecords int `json:"max_records" mapstructure:"max_records"`
+	// The time, in seconds, the records are buffered for at most. Defaults to 60.
+	FlushInterval int `json:"flush_interval" mapstructure:"flush_interval"`
+	// The number of records kept buffered when their uploads fail, to upload them again
+	// with the next ones. The oldest ones are dropped beyond it. Defaults to 10 times
+	// `max_records`.
+	MaxBufferedRecords int `json:"max_buffered_records" mapstructure:"max_buffered_records"`
+	// The size, in MB, of the parts of the objects uploaded in several parts, the larger
+	// ones. Defaults to 8, and can't be less than 5.
+	PartSize int `json:"part_size" mapstructure:"part_size"`
+	// The timeout, in seconds, of the uploads. Defaults to 60.
+	Timeout int `json:"timeout" mapstructure:"timeout"`
+	// The server-side encryption of the objects, `AES256`, `aws:kms` or `aws:kms:dsse`.
+	// Defaults to the one of the bucket.
+	ServerSideEncryption string `json:"server_side_encryption" mapstructure:"server_side_encryption"`
+	// The KMS key of the `aws:kms` and `aws:kms:dsse` encryptions. Defaults to the AWS
+	// managed one.
+	SSEKMSKeyID string `json:"sse_kms_key_id" mapstructure:"sse_kms_key_id"`
+}
+
+func (p *S3Pump) New() Pump {
+	return &S3Pump{}
+}
+
+func (p *S3Pump) GetName() string {
+	return "S3 Pump"
+}
+
+func (p *S3Pump) GetEnvPrefix() string {
+	return p.conf.EnvPrefix
+}
+
+func (p *S3Pump) Init(config interface{}) error {
+	p.conf = &S3Conf{}
+	p.log = p.newLog(s3Prefix)
+
+	if err := mapstructure.Decode(config, p.conf); err != nil {
+		p.log.Error("Failed to decode configuration: ", err)
+		return err
+	}
+
+	processPumpEnvVars(p, p.log, p.conf, s3DefaultENV)
+
+	if err := p.setDefaults(); err != nil {
+		return err
+	}
+
+	// Credentials are loaded as specified in
+	// https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/#specifying-credentials
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(p.conf.AWSRegion))
+	if err != nil {
+		return fmt.Errorf("couldn't load the AWS configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	p.client = s3.NewFromConfig(cfg, func(options *s3.Options) {
+		options.UsePathStyle = p.conf.ForcePathStyle
+		if p.conf.AWSEndpoint != "" {
+			options.BaseEndpoint = aws.String(p.conf.AWSEndpoint)
+			// Not every S3 compatible storage supports the checksums AWS defaults to.
+			options.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+			options.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+		}
+		if p.conf.AWSKey != "" && p.conf.AWSSecret != "" {
+			options.Credentials = credentials.NewStaticCredentialsProvider(p.conf.AWSKey, p.conf.AWSSecret, p.conf.AWSToken)
+		}
+	})
+
+	p.stop = make(chan struct{})
+	go p.flushOld(p.stop)
+
+	p.log.Info(p.GetName() + " Initialized")
+	return nil
+}
+
+// setDefaults validates the configuration and sets the defaults of the fields left empty.
+func (p *S3Pump) setDefaults() error {
+	if p.conf.Bucket == "" {
+		return errors.New("bucket is required")
+	}
+
+	extension := ".json.gz"
+	switch p.conf.Format {
+	case "":
+		p.conf.Format = s3FormatJSON
+	case s3FormatJSON:
+	case s3FormatParquet:
+		extension = ".parquet"
+	default:
+		return fmt.Errorf("unsupported format %q, must be %q or %q", p.conf.Format, s3FormatJSON, s3FormatParquet)
+	}
+
+	if p.conf.KeyTemplate == "" {
+		p.conf.KeyTemplate = s3DefaultKeyTemplate + extension
+	}
+	hasUUID := false
+	for _, match := range s3Placeholder.FindAllStringSubmatch(p.conf.KeyTemplate, -1) {
+		if match[1] == "uuid" {
+			hasUUID = true
+		} else if _, ok := s3KeyFields[match[1]]; !ok {
+			return fmt.Errorf("unsupported key placeholder %q", match[0])
+		}
+	}
+	if !hasUUID {
+		// Without it, the objects would replace the previous ones of the same records.
+		return errors.New("key_template must have a {{uuid}}")
+	}
+
+	switch types.ServerSideEncryption(p.conf.ServerSideEncryption) {
+	case "", types.ServerSideEncryptionAes256, types.ServerSideEncryptionAwsKms, types.ServerSideEncryptionAwsKmsDsse:
+	default:
+		return fmt.Errorf("unsupported server_side_encryption %q, must be AES256, aws:kms or aws:kms:dsse", p.conf.ServerSideEncryption)
+	}
+
+	if p.conf.MaxRecords <= 0 {
+		p.conf.MaxRecords = s3DefaultMaxRecords
+	}
+	if p.conf.MaxBufferedRecords <= 0 {
+		p.conf.MaxBufferedRecords = s3DefaultBufferedBatches * p.conf.MaxRecords
+	}
+	if p.conf.FlushInterval <= 0 {
+		p.conf.FlushInterval = s3DefaultFlushInterval
+	}
+	if p.conf.PartSize <= 0 {
+		p.conf.PartSize = s3DefaultPartSize
+	}
+	if p.conf.PartSize < s3MinPartSize {
+		return fmt.Errorf("part_size can't be less than %d MB", s3MinPartSize)
+	}
+	if p.conf.Timeout <= 0 {
+		p.conf.Timeout = s3DefaultTimeout
+	}
+	return nil
+}
+
+func (p *S3Pump) WriteData(ctx context.Context, data []interface{}) error {
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, v := range data {
+		if record, ok := v.(analytics.AnalyticsRecord); ok {
+			if len(p.records) == 0 {
+				p.since = time.Now()
+			}
+			p.records = append(p.records, record)
+		}
+	}
+
+	if len(p.records) < p.conf.MaxRecords && time.Since(p.since) < p.flushInterval() {
+		p.log.WithContext(ctx).Debug(len(p.records), " records buffered")
+		return nil
+	}
+	return p.flush(ctx)
+}
+
+func (p *S3Pump) flushInterval() time.Duration {
+	return time.Duration(p.conf.FlushInterval) * time.Second
+}
+
+// flushOld uploads the records buffered for longer than the flush interval until the pump
+// is shut down.
+func (p *S3Pump) flushOld(stop <-chan struct{}) {
+	ticker := time.NewTicker(s3FlushCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			if len(p.records) > 0 && time.Since(p.since) >= p.flushInterval() {
+				if err := p.flush(context.Background()); err != nil {
+					p.log.Warning("Failed to upload the buffered records: ", err)
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// flush uploads the buffered records, an object per key. The records of the objects that
+// fail to upload are buffered again, up to max_buffered_records, to be uploaded with the
+// next ones.
+func (p *S3Pump) flush(ctx context.Context) error {
+	records := p.records
+	p.records = nil
+	if len(records) == 0 {
+		return nil
+	}
+
+	// The records are grouped by their key, the uuid of the objects aside.
+	var keys []string
+	groups := map[string][]*analytics.AnalyticsRecord{}
+	for i := range records {
+		key := p.key(&records[i])
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], &records[i])
+	}
+
+	var errs []error
+	var failed []analytics.AnalyticsRecord
+	uploaded := 0
+	for _, key := range keys {
+		err := p.uploadGroup(ctx, key, groups[key])
+		if err != nil {
+			errs = append(errs, err)
+			for _, record := range groups[key] {
+				failed = append(failed, *record)
+			}
+			continue
+		}
+		uploaded += len(groups[key])
+	}
+	p.requeue(failed)
+
+	p.log.Info("Purged ", uploaded, " records...")
+	return errors.Join(errs...)
+}
+
+// uploadGroup uploads the records of key to an object of their own.
+func (p *S3Pump) uploadGroup(ctx context.Context, key string, records []*analytics.AnalyticsRecord) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	objectKey := s3Placeholder.ReplaceAllLiteralString(key, id.String())
+
+	if err := p.upload(ctx, objectKey, records); err != nil {
+		p.log.WithField("key", objectKey).Error("Failed to upload the records: ", err)
+		return err
+	}
+	return nil
+}
+
+// requeue buffers records again, dropping the oldest ones beyond max_buffered_records.
+func (p *S3Pump) requeue(records []analytics.AnalyticsRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	if dropped := len(records) - p.conf.MaxBufferedRecords; dropped > 0 {
+		p.log.Error("Dropping ", dropped, " records failing to upload, more than max_buffered_records are buffered")
+		records = records[dropped:]
+	}
+	p.records = records
+	p.since = time.Now()
+}
+
+// key returns the key of the object of record, its {{uuid}} left as is.
+func (p *S3Pump) key(record *analytics.AnalyticsRecord) string {
+	return s3Placeholder.ReplaceAllStringFunc(p.conf.KeyTemplate, func(placeholder string) string {
+		field := s3Placeholder.FindStringSubmatch(placeholder)[1]
+		if field == "uuid" {
+			return "{{uuid}}"
+		}
+		value := s3KeyFields[field](record)
+		if value == "" {
+			return "unknown"
+		}
+		return url.PathEscape(value)
+	})
+}
+
+// encode returns the object of records and its content type.
+func (p *S3Pump) encode(records []*analytics.AnalyticsRecord) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	if p.conf.Format == s3FormatParquet {
+		writer := parquet.NewWriter(&buf, parquetSchema(s3ParquetColumns), parquet.Compression(&parquet.Zstd))
+		for _, record := range records {
+			if err := writer.Write(parquetRow(s3ParquetColumns, record)); err != nil {
+				return nil, "", err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "application/vnd.apache.parquet", nil
+	}
+
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "application/gzip", nil
+}
+
+// upload uploads the object of records to key, in several parts when it's larger than a
+// part.
+func (p *S3Pump) upload(ctx context.Context, key string, records []*analytics.AnalyticsRecord) error {
+	body, contentType, err := p.encode(records)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.conf.Timeout)*time.Second)
+	defer cancel()
+
+	var kmsKeyID *string
+	if p.conf.SSEKMSKeyID != "" {
+		kmsKeyID = aws.String(p.conf.SSEKMSKeyID)
+	}
+	encryption := types.ServerSideEncryption(p.conf.ServerSideEncryption)
+
+	partSize := p.conf.PartSize << 20
+	if len(body) <= partSize {
+		_, err := p.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:               aws.String(p.conf.Bucket),
+			Key:                  aws.String(key),
+			Body:                 bytes.NewReader(body),
+			ContentType:          aws.String(contentType),
+			ServerSideEncryption: encryption,
+			SSEKMSKeyId:          kmsKeyID,
+		})
+		return err
+	}
+
+	created, err := p.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               aws.String(p.conf.Bucket),
+		Key:                  aws.String(key),
+		ContentType:          aws.String(contentType),
+		ServerSideEncryption: encryption,
+		SSEKMSKeyId:          kmsKeyID,
+	})
+	if err != nil {
+		return err
+	}
+
+	var parts []types.CompletedPart
+	for start := 0; start < len(body); start += partSize {
+		end := start + partSize
+		if end > len(body) {
+			end = len(body)
+		}
+		number := aws.Int32(int32(len(parts) + 1))
+		part, err := p.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(p.conf.Bucket),
+			Key:        aws.String(key),
+			UploadId:   created.UploadId,
+			PartNumber: number,
+			Body:       bytes.NewReader(body[start:end]),
+		})
+		if err != nil {
+			p.abort(key, created.UploadId)
+			return err
+		}
+		parts = append(parts, types.CompletedPart{ETag: part.ETag, PartNumber: number, ChecksumCRC32: part.ChecksumCRC32})
+	}
+
+	_, err = p.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(p.conf.Bucket),
+		Key:             aws.String(key),
+		UploadId:        created.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		p.abort(key, created.UploadId)
+	}
+	return err
+}
+
+// abort aborts the multipart upload of key, for the storage to free its parts.
+func (p *S3Pump) abort(key string, uploadID *string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.conf.Timeout)*time.Second)
+	defer cancel()
+
+	_, err := p.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(p.conf.Bucket),
+		Key:      aws.String(key),
+		UploadId: uploadID,
+	})
+	if err != nil {
+		p.log.WithField("key", key).Warning("Failed to abort the upload: ", err)
+	}
+}
+
+func (p *S3Pump) Shutdown() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	return p.flush(context.Background())
+}
@@ -0,0 +1,363 @@
+package pumps
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// s3Object is an object uploaded to s3Server.
+type s3Object struct {
+	body   []byte
+	header http.Header
+	parts  int
+}
+
+// s3Server is an S3 bucket addressed in the path of the URLs, uploaded to in one go or in
+// several parts.
+type s3Server struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string]*s3Object
+	uploads map[string]*s3Object
+	aborted []string
+	// status is the status of the failed requests, the uploads of the parts only with
+	// failParts.
+	status    int
+	failParts bool
+}
+
+func newS3Server(t *testing.T) (*s3Server, string) {
+	t.Helper()
+
+	s := &s3Server{bucket: "analytics", objects: map[string]*s3Object{}, uploads: map[string]*s3Object{}}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, server.URL
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.bucket+"/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	if s.status != 0 && (!s.failParts || r.URL.Query().Has("partNumber")) {
+		w.WriteHeader(s.status)
+		fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	uploadID := query.Get("uploadId")
+	switch {
+	case r.Method == http.MethodPut && uploadID != "":
+		upload := s.uploads[uploadID]
+		upload.body = append(upload.body, body...)
+		upload.parts++
+		w.Header().Set("ETag", fmt.Sprintf(`"%s-%s"`, uploadID, query.Get("partNumber")))
+	case r.Method == http.MethodPut:
+		s.objects[key] = &s3Object{body: body, header: r.Header}
+		w.Header().Set("ETag", `"etag"`)
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		uploadID = fmt.Sprintf("upload-%d", len(s.uploads)+1)
+		s.uploads[uploadID] = &s3Object{header: r.Header}
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", s.bucket, key, uploadID)
+	case r.Method == http.MethodPost && uploadID != "":
+		var complete struct {
+			Parts []struct {
+				ETag       string
+				PartNumber int
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &complete); err != nil || len(complete.Parts) != s.uploads[uploadID].parts {
+			http.Error(w, "InvalidPart", http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = s.uploads[uploadID]
+		delete(s.uploads, uploadID)
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>\"etag\"</ETag></CompleteMultipartUploadResult>", s.bucket, key)
+	case r.Method == http.MethodDelete && uploadID != "":
+		s.aborted = append(s.aborted, key)
+		delete(s.uploads, uploadID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "NotImplemented", http.StatusNotImplemented)
+	}
+}
+
+// keys returns the keys of the objects, sorted.
+func (s *s3Server) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *s3Server) object(key string) *s3Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.objects[key]
+}
+
+// records returns the records of the gzipped NDJSON object at key.
+func (s *s3Server) records(t *testing.T, key string) []analytics.AnalyticsRecord {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(s.object(key).body))
+	require.NoError(t, err)
+	var records []analytics.AnalyticsRecord
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		var record analytics.AnalyticsRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func newS3TestPump(t *testing.T, endpoint string, config map[string]interface{}) *S3Pump {
+	t.Helper()
+
+	config["bucket"] = "analytics"
+	config["aws_region"] = "eu-west-1"
+	config["aws_key"] = "key"
+	config["aws_secret"] = "secret"
+	config["aws_endpoint"] = endpoint
+	config["force_path_style"] = true
+	pmp := &S3Pump{}
+	require.NoError(t, pmp.Init(config))
+	t.Cleanup(func() { pmp.Shutdown() })
+	return pmp
+}
+
+func TestS3Pump_Init(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		config      map[string]interface{}
+		expectedErr string
+	}{
+		{testName: "defaults", config: map[string]interface{}{"bucket": "analytics"}},
+		{testName: "no bucket", config: map[string]interface{}{}, expectedErr: "bucket is required"},
+		{
+			testName:    "unknown format",
+			config:      map[string]interface{}{"bucket": "analytics", "format": "avro"},
+			expectedErr: `unsupported format "avro"`,
+		},
+		{
+			testName:    "unknown placeholder",
+			config:      map[string]interface{}{"bucket": "analytics", "key_template": "{{org_id}}/{{api_name}}/{{uuid}}.json.gz"},
+			expectedErr: `unsupported key placeholder "{{api_name}}"`,
+		},
+		{
+			testName:    "no uuid",
+			config:      map[string]interface{}{"bucket": "analytics", "key_template": "{{org_id}}/{{yyyy}}.json.gz"},
+			expectedErr: "key_template must have a {{uuid}}",
+		},
+		{
+			testName:    "unknown encryption",
+			config:      map[string]interface{}{"bucket": "analytics", "server_side_encryption": "rot13"},
+			expectedErr: `unsupported server_side_encryption "rot13"`,
+		},
+		{
+			testName:    "small parts",
+			config:      map[string]interface{}{"bucket": "analytics", "part_size": 1},
+			expectedErr: "part_size can't be less than 5 MB",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			pmp := &S3Pump{}
+			err := pmp.Init(tc.config)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer pmp.Shutdown()
+			assert.Equal(t, "json", pmp.conf.Format)
+			assert.Equal(t, "{{org_id}}/{{yyyy}}/{{mm}}/{{dd}}/{{uuid}}.json.gz", pmp.conf.KeyTemplate)
+			assert.Equal(t, 10000, pmp.conf.MaxRecords)
+			assert.Equal(t, 60, pmp.conf.FlushInterval)
+			assert.Equal(t, 8, pmp.conf.PartSize)
+		})
+	}
+}
+
+func TestS3Pump_WriteData(t *testing.T) {
+	server, endpoint := newS3Server(t)
+	pmp := newS3TestPump(t, endpoint, map[string]interface{}{
+		"max_records":            3,
+		"key_template":           "{{ org_id }}/{{yyyy}}-{{mm}}-{{dd}}T{{hh}}/{{uuid}}.json.gz",
+		"server_side_encryption": "aws:kms",
+		"sse_kms_key_id":         "alias/analytics",
+	})
+
+	timestamp := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", Path: "/users", TimeStamp: timestamp},
+		"not a record",
+	}))
+	assert.Empty(t, server.keys(), "the records are buffered until max_records")
+
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{
+		analytics.AnalyticsRecord{APIID: "api2", OrgID: "org1", Path: "/orders", TimeStamp: timestamp.Add(time.Minute)},
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org/2", TimeStamp: timestamp},
+	}))
+	keys := server.keys()
+	require.Len(t, keys, 2, "an object per key")
+	assert.Regexp(t, regexp.MustCompile(`^org%2F2/2026-10-01T12/[0-9a-f-]{36}\.json\.gz$`), keys[0])
+	assert.Regexp(t, regexp.MustCompile(`^org1/2026-10-01T12/[0-9a-f-]{36}\.json\.gz$`), keys[1])
+
+	records := server.records(t, keys[1])
+	require.Len(t, records, 2)
+	assert.Equal(t, "/users", records[0].Path)
+	assert.Equal(t, "/orders", records[1].Path)
+
+	header := server.object(keys[1]).header
+	assert.Equal(t, "application/gzip", header.Get("Content-Type"))
+	assert.Equal(t, "aws:kms", header.Get("X-Amz-Server-Side-Encryption"))
+	assert.Equal(t, "alias/analytics", header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", TimeStamp: timestamp},
+	}))
+	assert.Len(t, server.keys(), 2)
+	pmp.since = time.Now().Add(-time.Minute)
+	require.NoError(t, pmp.WriteData(context.Background(), nil))
+	assert.Len(t, server.keys(), 3, "the records are uploaded after flush_interval")
+
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", TimeStamp: timestamp},
+	}))
+	require.NoError(t, pmp.Shutdown())
+	assert.Len(t, server.keys(), 4, "the buffered records are uploaded on shutdown")
+}
+
+func TestS3Pump_Parquet(t *testing.T) {
+	server, endpoint := newS3Server(t)
+	pmp := newS3TestPump(t, endpoint, map[string]interface{}{"format": "parquet"})
+
+	timestamp := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", ResponseCode: 200, TimeStamp: timestamp},
+		analytics.AnalyticsRecord{APIID: "api2", TimeStamp: timestamp},
+	}))
+	require.NoError(t, pmp.Shutdown())
+
+	keys := server.keys()
+	require.Len(t, keys, 2)
+	assert.Regexp(t, regexp.MustCompile(`^org1/2026/10/01/[0-9a-f-]{36}\.parquet$`), keys[0])
+	assert.Regexp(t, regexp.MustCompile(`^unknown/2026/10/01/[0-9a-f-]{36}\.parquet$`), keys[1])
+	assert.Equal(t, "application/vnd.apache.parquet", server.object(keys[0]).header.Get("Content-Type"))
+
+	body := server.object(keys[0]).body
+	file, err := parquet.OpenFile(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), file.NumRows())
+	assert.Len(t, file.Schema().Columns(), len(parquetColumns)+1, "the columns of the Parquet pump and org_id")
+}
+
+func TestS3Pump_Multipart(t *testing.T) {
+	server, endpoint := newS3Server(t)
+	pmp := newS3TestPump(t, endpoint, map[string]interface{}{"part_size": 5})
+
+	// Random enough not to compress below a part.
+	raw := make([]byte, 6<<20)
+	_, err := rand.Read(raw)
+	require.NoError(t, err)
+	record := analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", RawRequest: base64.StdEncoding.EncodeToString(raw)}
+
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{record}))
+	require.NoError(t, pmp.Shutdown())
+
+	keys := server.keys()
+	require.Len(t, keys, 1)
+	assert.Equal(t, 2, server.object(keys[0]).parts)
+	assert.Empty(t, server.aborted)
+	records := server.records(t, keys[0])
+	require.Len(t, records, 1)
+	assert.Equal(t, record.RawRequest, records[0].RawRequest)
+
+	server.status = http.StatusForbidden
+	server.failParts = true
+	pmp = newS3TestPump(t, endpoint, map[string]interface{}{"part_size": 5, "max_records": 1})
+	assert.ErrorContains(t, pmp.WriteData(context.Background(), []interface{}{record}), "AccessDenied")
+	require.Len(t, server.aborted, 1, "the failed uploads are aborted")
+	assert.Len(t, server.keys(), 1)
+}
+
+func TestS3Pump_WriteDataError(t *testing.T) {
+	server, endpoint := newS3Server(t)
+	server.status = http.StatusForbidden
+	pmp := newS3TestPump(t, endpoint, map[string]interface{}{"max_records": 1})
+
+	err := pmp.WriteData(context.Background(), []interface{}{analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1"}})
+	assert.ErrorContains(t, err, "AccessDenied")
+	require.Len(t, pmp.records, 1, "the records of the failed uploads are buffered again")
+
+	server.mu.Lock()
+	server.status = 0
+	server.mu.Unlock()
+	require.NoError(t, pmp.WriteData(context.Background(), []interface{}{analytics.AnalyticsRecord{APIID: "api2", OrgID: "org1"}}))
+	assert.Empty(t, pmp.records)
+	keys := server.keys()
+	require.Len(t, keys, 1)
+	assert.Len(t, server.records(t, keys[0]), 2, "the records are uploaded with the next ones")
+}
+
+func TestS3Pump_MaxBufferedRecords(t *testing.T) {
+	server, endpoint := newS3Server(t)
+	server.status = http.StatusForbidden
+	pmp := newS3TestPump(t, endpoint, map[string]interface{}{"max_records": 2, "max_buffered_records": 3})
+	assert.Equal(t, 3, pmp.conf.MaxBufferedRecords)
+
+	for _, apiID := range []string{"api1", "api2", "api3"} {
+		err := pmp.WriteData(context.Background(), []interface{}{
+			analytics.AnalyticsRecord{APIID: apiID, OrgID: "org1"},
+			analytics.AnalyticsRecord{APIID: apiID, OrgID: "org1"},
+		})
+		assert.Error(t, err)
+	}
+	require.Len(t, pmp.records, 3, "the oldest records are dropped beyond max_buffered_records")
+	assert.Equal(t, "api2", pmp.records[0].APIID)
+	assert.Equal(t, "api3", pmp.records[2].APIID)
+}
@@ -39,7 +39,7 @@ func (s *SegmentPump) GetEnvPrefix() string {
 
 func (s *SegmentPump) Init(config interface{}) error {
 	s.segmentConf = &SegmentConf{}
-	s.log = log.WithField("prefix", segmentPrefix)
+	s.log = s.newLog(segmentPrefix)
 
 	loadConfigErr := mapstructure.Decode(config, &s.segmentConf)
 	if loadConfigErr != nil {
@@ -55,12 +55,12 @@ func (s *SegmentPump) Init(config interface{}) error {
 }
 
 func (s *SegmentPump) WriteData(ctx context.Context, data []interface{}) error {
-	s.log.Debug("Attempting to write ", len(data), " records...")
+	s.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	for _, v := range data {
 		s.WriteDataRecord(v.(analytics.AnalyticsRecord))
 	}
-	s.log.Info("Purged ", len(data), " records...")
+	s.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -113,7 +113,7 @@ func (p *SplunkPump) GetEnvPrefix() string {
 // Init performs the initialization of the SplunkClient.
 func (p *SplunkPump) Init(config interface{}) error {
 	p.config = &SplunkPumpConfig{}
-	p.log = log.WithField("prefix", splunkPumpPrefix)
+	p.log = p.newLog(splunkPumpPrefix)
 
 	err := mapstructure.Decode(config, p.config)
 	if err != nil {
@@ -124,17 +124,7 @@ func (p *SplunkPump) Init(config interface{}) error {
 
 	p.log.Infof("%s Endpoint: %s", splunkPumpName, p.config.CollectorURL)
 
-	p.client, err = newSplunkClient(&splunkClientConfig{
-		token:        p.config.CollectorToken,
-		collectorURL: p.config.CollectorURL,
-		tlsConfig: TLSConfig{
-			CertFile:           p.config.SSLCertFile,
-			KeyFile:            p.config.SSLKeyFile,
-			CAFile:             p.config.SSLCAFile,
-			InsecureSkipVerify: p.config.SSLInsecureSkipVerify,
-			ServerName:         p.config.SSLServerName,
-		},
-	}, p.log)
+	p.client, err = newSplunkClient(p.clientConfig(p.config), p.log)
 	if err != nil {
 		return err
 	}
@@ -153,6 +143,49 @@ func (p *SplunkPump) Init(config interface{}) error {
 	return nil
 }
 
+func (p *SplunkPump) clientConfig(conf *SplunkPumpConfig) *splunkClientConfig {
+	return &splunkClientConfig{
+		token:        conf.CollectorToken,
+		collectorURL: conf.CollectorURL,
+		tlsConfig: TLSConfig{
+			CertFile:           conf.SSLCertFile,
+			KeyFile:            conf.SSLKeyFile,
+			CAFile:             conf.SSLCAFile,
+			InsecureSkipVerify: conf.SSLInsecureSkipVerify,
+			ServerName:         conf.SSLServerName,
+		},
+	}
+}
+
+// RefreshCredentials sends the next events with the collector token and certificates of
+// conf, a *SplunkPumpConfig, when they changed.
+func (p *SplunkPump) RefreshCredentials(_ context.Context, conf any) error {
+	newConf, ok := conf.(*SplunkPumpConfig)
+	if !ok {
+		return unexpectedConfError(conf)
+	}
+
+	config := *p.config
+	config.CollectorToken = newConf.CollectorToken
+	config.SSLCertFile = newConf.SSLCertFile
+	config.SSLKeyFile = newConf.SSLKeyFile
+	config.SSLCAFile = newConf.SSLCAFile
+	if config.CollectorToken == p.config.CollectorToken && config.SSLCertFile == p.config.SSLCertFile &&
+		config.SSLKeyFile == p.config.SSLKeyFile && config.SSLCAFile == p.config.SSLCAFile {
+		return nil
+	}
+
+	client, err := newSplunkClient(p.clientConfig(&config), p.log)
+	if err != nil {
+		return err
+	}
+	client.retry = retry.NewBackoffRetry("Failed writing data to Splunk", config.MaxRetries, client.httpClient, p.log)
+	p.client, p.config = client, &config
+
+	p.log.Info("Sending with the refreshed credentials")
+	return nil
+}
+
 // Filters the tags based on config rule
 func (p *SplunkPump) FilterTags(filteredTags []string) []string {
 	// Loop all explicitly ignored tags
@@ -173,7 +206,7 @@ func (p *SplunkPump) FilterTags(filteredTags []string) []string {
 
 // WriteData prepares an appropriate data structure and sends it to the HTTP Event Collector.
 func (p *SplunkPump) WriteData(ctx context.Context, data []interface{}) error {
-	p.log.Debug("Attempting to write ", len(data), " records...")
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	var batchBuffer bytes.Buffer
 
@@ -289,7 +322,7 @@ func (p *SplunkPump) WriteData(ctx context.Context, data []interface{}) error {
 		batchBuffer.Reset()
 	}
 
-	p.log.Info("Purged ", len(data), " records...")
+	p.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -338,6 +371,7 @@ func (p *SplunkPump) send(ctx context.Context, data []byte) error {
 	}
 	req = req.WithContext(ctx)
 	req.Header.Add(authHeaderName, authHeaderPrefix+p.client.Token)
+	injectTraceContext(ctx, req.Header)
 
 	p.log.Debugf("Sending %d bytes to splunk", len(data))
 	return p.client.retry.Send(req)
@@ -518,3 +518,33 @@ func getEventBytes(records []interface{}, t *testing.T) int {
 	}
 	return result
 }
+
+func Test_SplunkRefreshCredentials(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, strings.TrimPrefix(r.Header.Get(authHeaderName), authHeaderPrefix))
+		w.Write([]byte(`{"text": "Success", "code": 0}`))
+	}))
+	defer server.Close()
+
+	pmp := SplunkPump{}
+	cfg := map[string]interface{}{
+		"collector_token": "old-token",
+		"collector_url":   server.URL,
+	}
+	if err := pmp.Init(cfg); err != nil {
+		t.Fatal("Error initializing pump: ", err)
+	}
+
+	keys := []interface{}{analytics.AnalyticsRecord{OrgID: "1", APIID: "123", Path: "/test-path", Method: "POST", TimeStamp: time.Now()}}
+	assert.NoError(t, pmp.WriteData(context.TODO(), keys))
+
+	assert.NoError(t, pmp.RefreshCredentials(context.TODO(), &SplunkPumpConfig{CollectorToken: "new-token", CollectorURL: "http://ignored"}))
+	assert.NoError(t, pmp.WriteData(context.TODO(), keys))
+	assert.Equal(t, []string{"old-token", "new-token"}, tokens)
+
+	err := pmp.RefreshCredentials(context.TODO(), &SplunkPumpConfig{})
+	assert.Equal(t, errInvalidSettings, err)
+	assert.NoError(t, pmp.WriteData(context.TODO(), keys))
+	assert.Equal(t, "new-token", tokens[2], "the pump keeps its credentials when it fails to refresh them")
+}
@@ -206,9 +206,9 @@ func (c *SQLPump) SetDecodingResponse(decoding bool) {
 func (c *SQLPump) Init(conf interface{}) error {
 	c.SQLConf = &SQLConf{}
 	if c.IsUptime {
-		c.log = log.WithField("prefix", SQLPrefix+"-uptime")
+		c.log = c.newLog(SQLPrefix + "-uptime")
 	} else {
-		c.log = log.WithField("prefix", SQLPrefix)
+		c.log = c.newLog(SQLPrefix)
 	}
 
 	err := mapstructure.Decode(conf, &c.SQLConf)
@@ -256,8 +256,38 @@ func (c *SQLPump) Init(conf interface{}) error {
 	return nil
 }
 
+// RefreshCredentials reconnects with the connection string of conf, a *SQLConf, when it
+// changed. The new connection pool replaces the current one once it's reachable.
+func (c *SQLPump) RefreshCredentials(ctx context.Context, conf any) error {
+	newConf, ok := conf.(*SQLConf)
+	if !ok {
+		return unexpectedConfError(conf)
+	}
+	if newConf.ConnectionString == c.SQLConf.ConnectionString {
+		return nil
+	}
+
+	sqlConf := *c.SQLConf
+	sqlConf.ConnectionString = newConf.ConnectionString
+	db, err := OpenGormDB(&sqlConf, c.log)
+	if err != nil {
+		return err
+	}
+	if err := pingSQL(ctx, db); err != nil {
+		closeReplacedDB(db, c.log)
+		return err
+	}
+
+	oldDB := c.db
+	c.db, c.SQLConf = db, &sqlConf
+	closeReplacedDB(oldDB, c.log)
+
+	c.log.Info("Reconnected with the refreshed credentials")
+	return nil
+}
+
 func (c *SQLPump) WriteData(ctx context.Context, data []interface{}) error {
-	c.log.Debug("Attempting to write ", len(data), " records...")
+	c.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	var typedData []*analytics.AnalyticsRecord
 	for _, r := range data {
@@ -314,7 +344,7 @@ func (c *SQLPump) WriteData(ctx context.Context, data []interface{}) error {
 			}
 			tx := c.db.WithContext(ctx).Create(recs[i:ends])
 			if tx.Error != nil {
-				c.log.Error(tx.Error)
+				c.log.WithContext(ctx).Error(tx.Error)
 			}
 		}
 
@@ -322,7 +352,7 @@ func (c *SQLPump) WriteData(ctx context.Context, data []interface{}) error {
 
 	}
 
-	c.log.Info("Purged ", dataLen, " records...")
+	c.log.WithContext(ctx).Info("Purged ", dataLen, " records...")
 
 	return nil
 }
@@ -515,3 +545,8 @@ func (c *SQLPump) ensureTable(tableName string) error {
 	}
 	return nil
 }
+
+// CheckHealth pings the database.
+func (c *SQLPump) CheckHealth(ctx context.Context) error {
+	return pingSQL(ctx, c.db)
+}
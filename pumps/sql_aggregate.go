@@ -82,7 +82,7 @@ func (c *SQLAggregatePump) SetDecodingResponse(decoding bool) {
 
 func (c *SQLAggregatePump) Init(conf interface{}) error {
 	c.SQLConf = &SQLAggregatePumpConf{}
-	c.log = log.WithField("prefix", SQLAggregatePumpPrefix)
+	c.log = c.newLog(SQLAggregatePumpPrefix)
 
 	err := mapstructure.Decode(conf, &c.SQLConf)
 	if err != nil {
@@ -208,7 +208,7 @@ func (c *SQLAggregatePump) ensureTable(tableName string) error {
 // function and written to database on single table.
 func (c *SQLAggregatePump) WriteData(ctx context.Context, data []interface{}) error {
 	dataLen := len(data)
-	c.log.Debug("Attempting to write ", dataLen, " records...")
+	c.log.WithContext(ctx).Debug("Attempting to write ", dataLen, " records...")
 
 	if dataLen == 0 {
 		return nil
@@ -269,7 +269,7 @@ func (c *SQLAggregatePump) WriteData(ctx context.Context, data []interface{}) er
 		startIndex = i // next day start index, necessary for sharded case
 	}
 
-	c.log.Info("Purged ", dataLen, " records...")
+	c.log.WithContext(ctx).Info("Purged ", dataLen, " records...")
 
 	return nil
 }
@@ -313,3 +313,8 @@ func (c *SQLAggregatePump) DoAggregatedWriting(ctx context.Context, table, orgID
 
 	return nil
 }
+
+// CheckHealth pings the database.
+func (c *SQLAggregatePump) CheckHealth(ctx context.Context) error {
+	return pingSQL(ctx, c.db)
+}
@@ -92,7 +92,7 @@ func (s *SQSPump) GetEnvPrefix() string {
 
 func (s *SQSPump) Init(config interface{}) error {
 	s.SQSConf = &SQSConf{}
-	s.log = log.WithField("prefix", SQSPrefix)
+	s.log = s.newLog(SQSPrefix)
 
 	err := mapstructure.Decode(config, &s.SQSConf)
 	if err != nil {
@@ -124,19 +124,19 @@ func (s *SQSPump) Init(config interface{}) error {
 }
 
 func (s *SQSPump) WriteData(ctx context.Context, data []interface{}) error {
-	s.log.Info("Attempting to write ", len(data), " records...")
+	s.log.WithContext(ctx).Info("Attempting to write ", len(data), " records...")
 	startTime := time.Now()
 
 	messages := make([]types.SendMessageBatchRequestEntry, len(data))
 	for i, v := range data {
 		decoded, ok := v.(analytics.AnalyticsRecord)
 		if !ok {
-			s.log.Errorf("Unable to decode message: %v", v)
+			s.log.WithContext(ctx).Errorf("Unable to decode message: %v", v)
 			continue
 		}
 		decodedMessageByteArray, err := json.Marshal(decoded)
 		if err != nil {
-			s.log.Errorf("Unable to marshal message: %v", err)
+			s.log.WithContext(ctx).Errorf("Unable to marshal message: %v", err)
 			continue
 		}
 		messages[i] = types.SendMessageBatchRequestEntry{
@@ -160,12 +160,12 @@ func (s *SQSPump) WriteData(ctx context.Context, data []interface{}) error {
 	}
 	SQSError := s.write(ctx, messages)
 	if SQSError != nil {
-		s.log.WithError(SQSError).Error("unable to write message")
+		s.log.WithContext(ctx).WithError(SQSError).Error("unable to write message")
 
 		return SQSError
 	}
-	s.log.Debug("ElapsedTime in seconds for ", len(data), " records:", time.Since(startTime))
-	s.log.Info("Purged ", len(data), " records...")
+	s.log.WithContext(ctx).Debug("ElapsedTime in seconds for ", len(data), " records:", time.Since(startTime))
+	s.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 	return nil
 }
 
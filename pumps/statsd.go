@@ -51,7 +51,7 @@ func (s *StatsdPump) GetEnvPrefix() string {
 
 func (s *StatsdPump) Init(config interface{}) error {
 	s.dbConf = &StatsdConf{}
-	s.log = log.WithField("prefix", statsdPrefix)
+	s.log = s.newLog(statsdPrefix)
 
 	err := mapstructure.Decode(config, &s.dbConf)
 	if err != nil {
@@ -116,12 +116,12 @@ func (s *StatsdPump) WriteData(ctx context.Context, data []interface{}) error {
 	if len(data) == 0 {
 		return nil
 	}
-	s.log.Debug("Attempting to write ", len(data), " records...")
+	s.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	client := s.connect()
 	defer func() {
 		if err := client.Close(); err != nil {
-			s.log.WithError(err).Warn("failed to close StatsD client")
+			s.log.WithContext(ctx).WithError(err).Warn("failed to close StatsD client")
 		}
 	}()
 
@@ -162,17 +162,54 @@ func (s *StatsdPump) WriteData(ctx context.Context, data []interface{}) error {
 					if iv, ok2 := v.(int64); ok2 {
 						s.sendTimingMetric(client, f, metricTags, iv)
 					} else {
-						s.log.WithField("field", f).Warn("unexpected type for timing metric value, skipping")
+						s.log.WithContext(ctx).WithField("field", f).Warn("unexpected type for timing metric value, skipping")
 					}
 				}
 			}
 		}
 	}
-	s.log.Info("Purged ", len(data), " records...")
+	s.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
 
+// WriteUptimeRecords sends, per checked URL, the latency of every uptime check as the
+// uptime.request_time timing metric and its outcome as the uptime.up or uptime.down
+// counter.
+func (s *StatsdPump) WriteUptimeRecords(ctx context.Context, data []analytics.UptimeReportData) error {
+	if len(data) == 0 {
+		return nil
+	}
+	s.log.WithContext(ctx).Debug("Attempting to write ", len(data), " uptime records...")
+
+	client := s.connect()
+	defer func() {
+		if err := client.Close(); err != nil {
+			s.log.WithContext(ctx).WithError(err).Warn("failed to close StatsD client")
+		}
+	}()
+
+	for _, record := range data {
+		metricTags := statsdUptimeReplacer.Replace(strings.ToLower(record.URL))
+
+		s.sendTimingMetric(client, "uptime.request_time", metricTags, record.RequestTime)
+
+		outcome := "uptime.down"
+		if record.IsUp() {
+			outcome = "uptime.up"
+		}
+		if err := client.Incr(outcome+"."+metricTags, 1); err != nil {
+			s.log.WithContext(ctx).WithField("metric", outcome).Error("failed to send uptime metric to StatsD:", err)
+		}
+	}
+	s.log.WithContext(ctx).Info("Purged ", len(data), " uptime records...")
+
+	return nil
+}
+
+// statsdUptimeReplacer turns a checked URL into a single metric name segment.
+var statsdUptimeReplacer = strings.NewReplacer("://", "_", ".", "_", ":", "_", "/", "_", " ", "")
+
 func (s *StatsdPump) getMappings(decoded analytics.AnalyticsRecord) map[string]interface{} {
 	// Format TimeStamp to Unix Time
 	unixTime := time.Unix(decoded.TimeStamp.Unix(), 0)
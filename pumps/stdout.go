@@ -33,6 +33,9 @@ type StdOutConf struct {
 	Format string `json:"format" mapstructure:"format"`
 	// Root name of the JSON object the analytics record is nested in.
 	LogFieldName string `json:"log_field_name" mapstructure:"log_field_name"`
+	// Root name of the JSON object uptime records are nested in, when the pump has `uptime`
+	// enabled. Defaults to `tyk-uptime-record`.
+	UptimeLogFieldName string `json:"uptime_log_field_name" mapstructure:"uptime_log_field_name"`
 	// Use the legacy formatting of raw_request and raw_response as escaped strings rather than JSON formatting.
 	UseLegacyPayloadFormat bool `json:"use_legacy_payload_format" mapstructure:"use_legacy_payload_format"`
 }
@@ -52,7 +55,7 @@ func (s *StdOutPump) New() Pump {
 
 func (s *StdOutPump) Init(config interface{}) error {
 
-	s.log = log.WithField("prefix", stdOutPrefix)
+	s.log = s.newLog(stdOutPrefix)
 
 	s.conf = &StdOutConf{}
 	err := mapstructure.Decode(config, &s.conf)
@@ -67,6 +70,10 @@ func (s *StdOutPump) Init(config interface{}) error {
 		s.conf.LogFieldName = "tyk-analytics-record"
 	}
 
+	if s.conf.UptimeLogFieldName == "" {
+		s.conf.UptimeLogFieldName = "tyk-uptime-record"
+	}
+
 	s.log.Info(s.GetName() + " Initialized")
 
 	return nil
@@ -77,7 +84,7 @@ func (s *StdOutPump) Init(config interface{}) error {
 ** Write the actual Data to Stdout Here
  */
 func (s *StdOutPump) WriteData(ctx context.Context, data []interface{}) error {
-	s.log.Debug("Attempting to write ", len(data), " records...")
+	s.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	//Data is all the analytics being written
 	for _, v := range data {
@@ -87,31 +94,52 @@ func (s *StdOutPump) WriteData(ctx context.Context, data []interface{}) error {
 			return nil
 		default:
 			decoded := v.(analytics.AnalyticsRecord)
-			if s.conf.Format == "json" {
-				formatter := &logrus.JSONFormatter{}
-
-				// Skip formatting if legacy mode is enabled.
-				if !s.conf.UseLegacyPayloadFormat {
-					decoded.RawRequest = transformHTTPPayload(decoded.RawRequest)
-					decoded.RawResponse = transformHTTPPayload(decoded.RawResponse)
-				}
-
-				entry := log.WithField(s.conf.LogFieldName, decoded)
-				entry.Level = logrus.InfoLevel
-				entry.Time = time.Now().UTC()
-				data, _ := formatter.Format(entry)
-				fmt.Print(string(data))
-			} else {
-				s.log.WithField(s.conf.LogFieldName, decoded).Info()
+			// Skip formatting if legacy mode is enabled.
+			if s.conf.Format == "json" && !s.conf.UseLegacyPayloadFormat {
+				decoded.RawRequest = transformHTTPPayload(decoded.RawRequest)
+				decoded.RawResponse = transformHTTPPayload(decoded.RawResponse)
 			}
+			s.writeRecord(s.conf.LogFieldName, decoded)
+		}
+	}
+	s.log.WithContext(ctx).Info("Purged ", len(data), " records...")
+
+	return nil
+}
+
+// WriteUptimeRecords writes every uptime record to Stdout, the same way as analytics
+// records.
+func (s *StdOutPump) WriteUptimeRecords(ctx context.Context, data []analytics.UptimeReportData) error {
+	s.log.WithContext(ctx).Debug("Attempting to write ", len(data), " uptime records...")
 
+	for _, decoded := range data {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			s.writeRecord(s.conf.UptimeLogFieldName, decoded)
 		}
 	}
-	s.log.Info("Purged ", len(data), " records...")
+	s.log.WithContext(ctx).Info("Purged ", len(data), " uptime records...")
 
 	return nil
 }
 
+// writeRecord writes record to Stdout under fieldName, in the configured format.
+func (s *StdOutPump) writeRecord(fieldName string, record interface{}) {
+	if s.conf.Format != "json" {
+		s.log.WithField(fieldName, record).Info()
+		return
+	}
+
+	formatter := &logrus.JSONFormatter{}
+	entry := log.WithField(fieldName, record)
+	entry.Level = logrus.InfoLevel
+	entry.Time = time.Now().UTC()
+	data, _ := formatter.Format(entry)
+	fmt.Print(string(data))
+}
+
 // transformHTTPPayload separates HTTP headers from the body using the standard
 // HTTP separator (\r\n\r). It removes unnecessary whitespaces from the headers
 // and compacts the JSON body if it is valid.
@@ -71,7 +71,7 @@ func (s *SyslogPump) GetEnvPrefix() string {
 func (s *SyslogPump) Init(config interface{}) error {
 	//Read configuration file
 	s.syslogConf = &SyslogConf{}
-	s.log = log.WithField("prefix", syslogPrefix)
+	s.log = s.newLog(syslogPrefix)
 
 	err := mapstructure.Decode(config, &s.syslogConf)
 	if err != nil {
@@ -136,7 +136,7 @@ func (s *SyslogPump) initConfigs() {
 ** Write the actual Data to Syslog Here
  */
 func (s *SyslogPump) WriteData(ctx context.Context, data []interface{}) error {
-	s.log.Debug("Attempting to write ", len(data), " records...")
+	s.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	//Data is all the analytics being written
 	for _, v := range data {
@@ -178,7 +178,7 @@ func (s *SyslogPump) WriteData(ctx context.Context, data []interface{}) error {
 			_, _ = fmt.Fprintf(s.writer, "%s", message)
 		}
 	}
-	s.log.Info("Purged ", len(data), " records...")
+	s.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
@@ -90,7 +90,7 @@ func (t *TimestreamPump) GetEnvPrefix() string {
 
 func (t *TimestreamPump) Init(config interface{}) error {
 	t.config = &TimestreamPumpConf{}
-	t.log = log.WithField("prefix", timestreamPumpPrefix)
+	t.log = t.newLog(timestreamPumpPrefix)
 
 	err := mapstructure.Decode(config, &t.config)
 	if err != nil {
@@ -115,7 +115,7 @@ func (t *TimestreamPump) Init(config interface{}) error {
 }
 
 func (t *TimestreamPump) WriteData(ctx context.Context, data []interface{}) error {
-	t.log.Debug("Attempting to write ", len(data), " records...")
+	t.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
 
 	var records []types.Record
 
@@ -128,16 +128,16 @@ func (t *TimestreamPump) WriteData(ctx context.Context, data []interface{}) erro
 		})
 		if err != nil {
 			if rrex, ok := err.(*types.RejectedRecordsException); ok {
-				t.log.Errorf("Error writing data to Timestream %v: %v", err, *rrex.RejectedRecords[0].Reason)
+				t.log.WithContext(ctx).Errorf("Error writing data to Timestream %v: %v", err, *rrex.RejectedRecords[0].Reason)
 			} else {
-				t.log.Errorf("Error writing data to Timestream %+v", err)
+				t.log.WithContext(ctx).Errorf("Error writing data to Timestream %+v", err)
 			}
 
 			return err
 		}
 	}
 
-	t.log.Info("Purged ", len(data), " records...")
+	t.log.WithContext(ctx).Info("Purged ", len(data), " records...")
 
 	return nil
 }
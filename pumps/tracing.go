@@ -0,0 +1,33 @@
+package pumps
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// injectTraceContext adds the trace context of ctx, the span of the write when tracing is
+// enabled, to the headers of a request to a backend, for its spans to join the trace.
+func injectTraceContext(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// tracingTransport injects the trace context of every request it sends, for the clients
+// building their requests themselves.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	r = r.Clone(r.Context())
+	injectTraceContext(r.Context(), r.Header)
+
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,43 @@
+package pumps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingTransport(t *testing.T) {
+	origPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(origPropagator) })
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer ts.Close()
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL, nil)
+	assert.NoError(t, err)
+
+	client := &http.Client{Transport: &tracingTransport{}}
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "00-01000000000000000000000000000000-0200000000000000-01", traceparent)
+	assert.Empty(t, req.Header.Get("traceparent"), "the request must not be modified")
+}
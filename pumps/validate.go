@@ -0,0 +1,243 @@
+package pumps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mitchellh/mapstructure"
+)
+
+// metaSpec describes how a pump decodes its meta.
+type metaSpec struct {
+	// configs returns the configurations the pump decodes its meta into, the first one
+	// getting the env var overrides. A key is unknown when none of them uses it.
+	configs    func() []any
+	defaultEnv string
+	// legacyEnv is the prefix of the deprecated env vars the pump still applies, if any.
+	legacyEnv string
+}
+
+func single[T any](defaultEnv string) metaSpec {
+	return metaSpec{configs: func() []any { return []any{new(T)} }, defaultEnv: defaultEnv}
+}
+
+// mongoSpec describes the Mongo pumps, which decode their meta into their configuration
+// and then into the base Mongo configuration it embeds.
+func mongoSpec(defaultEnv string, conf func() (any, *BaseMongoConf)) metaSpec {
+	return metaSpec{
+		configs: func() []any {
+			c, base := conf()
+			return []any{c, base}
+		},
+		defaultEnv: defaultEnv,
+	}
+}
+
+func (s metaSpec) withLegacyEnv(prefix string) metaSpec {
+	s.legacyEnv = prefix
+	return s
+}
+
+var metaSpecs = map[string]metaSpec{
+	"dummy": {},
+	"mongo": mongoSpec(mongoDefaultEnv, func() (any, *BaseMongoConf) {
+		c := &MongoConf{}
+		return c, &c.BaseMongoConf
+	}).withLegacyEnv(mongoPumpPrefix),
+	"mongo-pump-selective": mongoSpec(mongoSelectiveDefaultEnv, func() (any, *BaseMongoConf) {
+		c := &MongoSelectiveConf{}
+		return c, &c.BaseMongoConf
+	}).withLegacyEnv(mongoSelectivePumpPrefix),
+	"mongo-pump-aggregate": mongoSpec(mongoAggregateDefaultEnv, func() (any, *BaseMongoConf) {
+		c := &MongoAggregateConf{}
+		return c, &c.BaseMongoConf
+	}).withLegacyEnv(mongoAggregatePumpPrefix),
+	"mongo-graph": mongoSpec(mongoGraphDefaultEnv, func() (any, *BaseMongoConf) {
+		c := &MongoConf{}
+		return c, &c.BaseMongoConf
+	}),
+	"mongo-mcp": mongoSpec(mongoMCPDefaultEnv, func() (any, *BaseMongoConf) {
+		c := &MongoConf{}
+		return c, &c.BaseMongoConf
+	}),
+	"mongo-mcp-aggregate": mongoSpec(mongoMCPAggregateDefaultEnv, func() (any, *BaseMongoConf) {
+		c := &MongoAggregateConf{}
+		return c, &c.BaseMongoConf
+	}),
+	"csv":                 single[CSVConf](csvDefaultENV),
+	"elasticsearch":       single[ElasticsearchConf](elasticsearchDefaultENV),
+	"influx":              single[InfluxConf](influxDefaultENV),
+	"influx2":             single[Influx2Conf](influx2DefaultENV),
+	"moesif":              single[MoesifConf](moesifDefaultENV),
+	"statsd":              single[StatsdConf](statsdDefaultENV),
+	"segment":             single[SegmentConf](segmentDefaultENV),
+	"graylog":             single[GraylogConf](graylogDefaultENV),
+	"splunk":              single[SplunkPumpConfig](splunkDefaultENV),
+	"hybrid":              single[HybridPumpConf](hybridDefaultENV),
+	"prometheus":          single[PrometheusConf](prometheusDefaultENV),
+	"logzio":              single[LogzioPumpConfig](logzioDefaultENV),
+	"dogstatsd":           single[DogStatsdConf](dogstatDefaultENV),
+	"kafka":               single[KafkaConf](kafkaDefaultENV),
+	"syslog":              single[SyslogConf](syslogDefaultENV),
+	"sql":                 single[SQLConf](SQLDefaultENV),
+	"sql_aggregate":       single[SQLAggregatePumpConf](SQLAggregateDefaultENV),
+	"stdout":              single[StdOutConf](stdOutDefaultENV),
+	"timestream":          single[TimestreamPumpConf](timestreamDefaultEnv),
+	"sql-graph":           single[GraphSQLConf](GraphSQLDefaultENV),
+	"sql-graph-aggregate": single[SQLAggregatePumpConf](SQLGraphAggregateDefaultENV),
+	"sql-mcp":             single[MCPSQLConf](MCPSQLDefaultENV),
+	"sql-mcp-aggregate":   single[SQLAggregatePumpConf](SQLMCPAggregateDefaultENV),
+	"resurfaceio":         single[ResurfacePumpConfig](resurfaceDefaultEnv),
+	"sqs":                 single[SQSConf](SQSDefaultENV),
+	"kinesis":             single[KinesisConf](kinesisDefaultENV),
+	"otlp":                single[OTLPConf](otlpDefaultENV),
+	"clickhouse":          single[ClickHouseConf](clickHouseDefaultENV),
+	"loki":                single[LokiConf](lokiDefaultENV),
+	"parquet":             single[ParquetConf](parquetDefaultENV),
+	"s3":                  single[S3Conf](s3DefaultENV),
+	"webhook":             single[WebhookConf](webhookDefaultENV),
+	"nats":                single[NATSConf](natsDefaultENV),
+}
+
+// ValidateMeta decodes meta, the configuration of a pump of type pumpType, strictly. It
+// returns an error for every key none of the settings of the pump matches, every value
+// of the wrong type, every env var override that doesn't apply and every KV reference
+// that can't be resolved. Pumps registered without a spec aren't checked.
+func ValidateMeta(pumpType string, meta map[string]interface{}) []error {
+	if _, err := GetPumpByName(pumpType); err != nil {
+		return []error{fmt.Errorf("unknown pump type %q", pumpType)}
+	}
+
+	spec, ok := metaSpecs[strings.ToLower(pumpType)]
+	if !ok || spec.configs == nil {
+		return nil
+	}
+
+	var errs []error
+	var unused map[string]bool
+	configs := spec.configs()
+	for _, conf := range configs {
+		md := mapstructure.Metadata{}
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{Metadata: &md, Result: conf})
+		if err != nil {
+			return []error{err}
+		}
+		if err := decoder.Decode(meta); err != nil {
+			errs = append(errs, decodeErrors(err)...)
+		}
+
+		keys := map[string]bool{}
+		for _, key := range md.Unused {
+			if unused == nil || unused[key] {
+				keys[key] = true
+			}
+		}
+		unused = keys
+	}
+
+	unknown := make([]string, 0, len(unused))
+	for key := range unused {
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("unknown key %q", key))
+	}
+
+	prefix := envPrefixOf(configs[0])
+	if prefix == "" {
+		prefix = spec.defaultEnv
+	}
+	if err := envconfig.Process(prefix, configs[0]); err != nil {
+		errs = append(errs, fmt.Errorf("env var overrides with prefix %s: %w", prefix, err))
+	}
+	if err := resolveKVReferences(context.Background(), configs[0]); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// decodeErrors splits the errors mapstructure gathers while decoding.
+func decodeErrors(err error) []error {
+	var decodeErr *mapstructure.Error
+	if !errors.As(err, &decodeErr) {
+		return []error{err}
+	}
+
+	errs := make([]error, 0, len(decodeErr.Errors))
+	for _, msg := range decodeErr.Errors {
+		errs = append(errs, errors.New(msg))
+	}
+	return errs
+}
+
+// envPrefixOf returns the `meta_env_prefix` set in conf, if any.
+func envPrefixOf(conf any) string {
+	v := reflect.Indirect(reflect.ValueOf(conf))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	field := v.FieldByName("EnvPrefix")
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return ""
+	}
+	return field.String()
+}
+
+// MetaConfig returns a configuration the meta of a pump of type pumpType decodes into, and
+// the prefix of the env vars overriding it by default. ok is false for the pumps without
+// one.
+func MetaConfig(pumpType string) (conf any, defaultEnv string, ok bool) {
+	spec, ok := metaSpecs[strings.ToLower(pumpType)]
+	if !ok || spec.configs == nil {
+		return nil, "", false
+	}
+
+	return spec.configs()[0], spec.defaultEnv, true
+}
+
+// EffectiveMeta returns the configuration a pump of type pumpType runs with: meta decoded
+// and overridden by the env vars, the way the pump does it in Init. It also returns the
+// prefixes of the env vars applied, in the order they're applied. conf is nil for the
+// pumps without a configuration.
+func EffectiveMeta(pumpType string, meta map[string]interface{}) (conf any, envPrefixes []string, err error) {
+	spec, ok := metaSpecs[strings.ToLower(pumpType)]
+	if !ok || spec.configs == nil {
+		return nil, nil, nil
+	}
+
+	configs := spec.configs()
+	for _, c := range configs {
+		if err := mapstructure.Decode(meta, c); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	prefix := envPrefixOf(configs[0])
+	if prefix == "" {
+		prefix = spec.defaultEnv
+	}
+	if err := envconfig.Process(prefix, configs[0]); err != nil {
+		return nil, nil, fmt.Errorf("env var overrides with prefix %s: %w", prefix, err)
+	}
+	if err := resolveKVReferences(context.Background(), configs[0]); err != nil {
+		return nil, nil, err
+	}
+	envPrefixes = []string{prefix}
+
+	if spec.legacyEnv != "" {
+		if err := envconfig.Process(spec.legacyEnv, configs[0]); err != nil {
+			return nil, nil, fmt.Errorf("env var overrides with prefix %s: %w", spec.legacyEnv, err)
+		}
+		envPrefixes = append(envPrefixes, spec.legacyEnv)
+	}
+
+	return configs[0], envPrefixes, nil
+}
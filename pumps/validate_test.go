@@ -0,0 +1,105 @@
+package pumps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMeta(t *testing.T) {
+	tcs := []struct {
+		testName string
+		pumpType string
+		meta     map[string]interface{}
+		env      map[string]string
+		expected []string
+	}{
+		{
+			testName: "valid",
+			pumpType: "csv",
+			meta:     map[string]interface{}{"csv_dir": "./", "meta_env_prefix": "CSV"},
+		},
+		{
+			testName: "unknown pump type",
+			pumpType: "cvs",
+			expected: []string{`unknown pump type "cvs"`},
+		},
+		{
+			testName: "unknown key",
+			pumpType: "csv",
+			meta:     map[string]interface{}{"csv_dri": "./", "csv_dir": "./"},
+			expected: []string{`unknown key "csv_dri"`},
+		},
+		{
+			testName: "type mismatch",
+			pumpType: "kafka",
+			meta:     map[string]interface{}{"broker": []interface{}{"localhost:9092"}, "batch_bytes": "1mb"},
+			expected: []string{"'batch_bytes' expected type 'int', got unconvertible type 'string', value: '1mb'"},
+		},
+		{
+			testName: "keys of the base Mongo configuration",
+			pumpType: "mongo",
+			meta:     map[string]interface{}{"mongo_url": "mongodb://localhost", "collection_name": "analytics", "colection_cap_enable": true},
+			expected: []string{`unknown key "colection_cap_enable"`},
+		},
+		{
+			testName: "env var override",
+			pumpType: "kafka",
+			env:      map[string]string{"TYK_PMP_PUMPS_KAFKA_META_BATCHBYTES": "1mb"},
+			expected: []string{`env var overrides with prefix TYK_PMP_PUMPS_KAFKA_META: envconfig.Process: assigning TYK_PMP_PUMPS_KAFKA_META_BATCHBYTES to BatchBytes: converting '1mb' to type int. details: strconv.ParseInt: parsing "1mb": invalid syntax`},
+		},
+		{
+			testName: "unresolved KV reference",
+			pumpType: "csv",
+			meta:     map[string]interface{}{"csv_dir": "kv://vault/pump#csv_dir"},
+			expected: []string{"a KV reference was set via a pump specific env var but no KV stores are configured"},
+		},
+		{
+			testName: "pump without configuration",
+			pumpType: "dummy",
+			meta:     map[string]interface{}{"anything": true},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			errs := ValidateMeta(tc.pumpType, tc.meta)
+
+			messages := make([]string, 0, len(errs))
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			assert.ElementsMatch(t, tc.expected, messages)
+		})
+	}
+}
+
+func TestEffectiveMeta(t *testing.T) {
+	t.Setenv("TYK_PMP_PUMPS_CSV_META_CSVDIR", "/tmp/csv")
+	t.Setenv("PMP_MONGO_COLLECTIONNAME", "legacy")
+
+	conf, prefixes, err := EffectiveMeta("csv", map[string]interface{}{"csv_dir": "./"})
+	assert.NoError(t, err)
+	assert.Equal(t, &CSVConf{CSVDir: "/tmp/csv"}, conf)
+	assert.Equal(t, []string{"TYK_PMP_PUMPS_CSV_META"}, prefixes)
+
+	conf, prefixes, err = EffectiveMeta("mongo", map[string]interface{}{"mongo_url": "mongodb://localhost", "collection_name": "file"})
+	assert.NoError(t, err)
+	mongoConf, ok := conf.(*MongoConf)
+	assert.True(t, ok)
+	assert.Equal(t, "mongodb://localhost", mongoConf.MongoURL)
+	assert.Equal(t, "legacy", mongoConf.CollectionName)
+	assert.Equal(t, []string{"TYK_PMP_PUMPS_MONGO_META", "PMP_MONGO"}, prefixes)
+
+	conf, prefixes, err = EffectiveMeta("dummy", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, conf)
+	assert.Nil(t, prefixes)
+
+	_, _, err = EffectiveMeta("csv", map[string]interface{}{"csv_dir": []string{"a"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,345 @@
+package pumps
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/retry"
+	"github.com/mitchellh/mapstructure"
+)
+
+// WebhookPump sends the analytics records to an HTTP endpoint in batches, as a JSON array,
+// NDJSON or the output of a template.
+type WebhookPump struct {
+	conf       *WebhookConf
+	template   *template.Template
+	httpClient *http.Client
+	retry      *retry.BackoffHTTPRetry
+	CommonPumpConfig
+}
+
+var (
+	webhookPrefix     = "webhook-pump"
+	webhookDefaultENV = PUMPS_ENV_PREFIX + "_WEBHOOK" + PUMPS_ENV_META_PREFIX
+
+	// webhookContentTypes are the default content types of the formats.
+	webhookContentTypes = map[string]string{
+		webhookFormatJSON:     "application/json",
+		webhookFormatNDJSON:   "application/x-ndjson",
+		webhookFormatTemplate: "application/json",
+	}
+
+	// webhookTemplateFuncs are the functions the templates can use on top of the builtin ones.
+	webhookTemplateFuncs = template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+)
+
+const (
+	webhookFormatJSON     = "json"
+	webhookFormatNDJSON   = "ndjson"
+	webhookFormatTemplate = "template"
+
+	webhookDefaultBatchSize       = 100
+	webhookDefaultTimeout         = 10
+	webhookDefaultMaxRetryAfter   = 60
+	webhookDefaultSignatureHeader = "X-Tyk-Signature"
+)
+
+// @PumpConf Webhook
+type WebhookConf struct {
+	// The prefix for the environment variables that will be used to override the configuration.
+	// Defaults to `TYK_PMP_PUMPS_WEBHOOK_META`
+	EnvPrefix string `mapstructure:"meta_env_prefix"`
+	// The URL the records are sent to.
+	URL string `json:"url" mapstructure:"url"`
+	// The method of the requests, `POST`, `PUT` or `PATCH`. Defaults to `POST`.
+	Method string `json:"method" mapstructure:"method"`
+	// Headers added to the requests.
+	Headers map[string]string `json:"headers" mapstructure:"headers"`
+	// The user of the basic authentication.
+	Username string `json:"username" mapstructure:"username"`
+	// The password of the basic authentication.
+	Password string `json:"password" mapstructure:"password"`
+	// The token of the bearer authentication, used instead of the basic one.
+	BearerToken string `json:"bearer_token" mapstructure:"bearer_token"`
+	// The format of the bodies: `json`, a JSON array of the records, `ndjson`, a record per
+	// line, or `template`, the output of `template`. Defaults to `json`.
+	Format string `json:"format" mapstructure:"format"`
+	// The Go `text/template` of the bodies of the `template` format, executed with the records
+	// of a batch, or with each record when `template_per_record` is set. Its `json` function
+	// returns the JSON of a value, such as `{"text": {{json .APIName}}}`.
+	Template string `json:"template" mapstructure:"template"`
+	// Executes `template` with each record rather than with the batch, the bodies being the
+	// outputs of the records of a batch, one per line.
+	TemplatePerRecord bool `json:"template_per_record" mapstructure:"template_per_record"`
+	// The content type of the bodies. Defaults to `application/x-ndjson` with the `ndjson`
+	// format, and to `application/json` otherwise.
+	ContentType string `json:"content_type" mapstructure:"content_type"`
+	// The maximum number of records of a request. Defaults to 100.
+	BatchSize int `json:"batch_size" mapstructure:"batch_size"`
+	// The maximum size, in bytes, of the bodies of the requests, but for the ones of a single
+	// record. Defaults to 0, no maximum.
+	MaxBatchBytes int `json:"max_batch_bytes" mapstructure:"max_batch_bytes"`
+	// The timeout, in seconds, of a request. Defaults to 10.
+	Timeout int `json:"timeout" mapstructure:"timeout"`
+	// The number of times a request failing with a 429, a 5xx or a network error is retried,
+	// with an exponential backoff, or after the delay of the `Retry-After` header of the
+	// response. Defaults to 0.
+	MaxRetries uint64 `json:"max_retries" mapstructure:"max_retries"`
+	// The longest time, in seconds, a retry waits for the `Retry-After` header of a response.
+	// Defaults to 60.
+	MaxRetryAfter int `json:"max_retry_after" mapstructure:"max_retry_after"`
+	// The secret the bodies are signed with, using HMAC-SHA256. Defaults to no signature.
+	HMACSecret string `json:"hmac_secret" mapstructure:"hmac_secret"`
+	// The header of the signature of the bodies, `sha256=` followed by the hex encoded HMAC.
+	// Defaults to `X-Tyk-Signature`.
+	SignatureHeader string `json:"signature_header" mapstructure:"signature_header"`
+	// Controls whether the pump client verifies the endpoint's certificate chain and host name.
+	SSLInsecureSkipVerify bool `json:"ssl_insecure_skip_verify" mapstructure:"ssl_insecure_skip_verify"`
+	// Path to the PEM file with trusted CA certificates that will be used to verify the
+	// endpoint's certificate.
+	SSLCAFile string `json:"ssl_ca_file" mapstructure:"ssl_ca_file"`
+	// SSL cert file location, for mTLS.
+	SSLCertFile string `json:"ssl_cert_file" mapstructure:"ssl_cert_file"`
+	// SSL cert key location, for mTLS.
+	SSLKeyFile string `json:"ssl_key_file" mapstructure:"ssl_key_file"`
+	// SSL Server name used in the TLS connection.
+	SSLServerName string `json:"ssl_server_name" mapstructure:"ssl_server_name"`
+}
+
+func (p *WebhookPump) New() Pump {
+	return &WebhookPump{}
+}
+
+func (p *WebhookPump) GetName() string {
+	return "Webhook Pump"
+}
+
+func (p *WebhookPump) GetEnvPrefix() string {
+	return p.conf.EnvPrefix
+}
+
+func (p *WebhookPump) Init(config interface{}) error {
+	p.conf = &WebhookConf{}
+	p.log = p.newLog(webhookPrefix)
+
+	if err := mapstructure.Decode(config, p.conf); err != nil {
+		p.log.Error("Failed to decode configuration: ", err)
+		return err
+	}
+
+	processPumpEnvVars(p, p.log, p.conf, webhookDefaultENV)
+
+	if p.conf.URL == "" {
+		return errors.New("url is required")
+	}
+	p.conf.Method = strings.ToUpper(p.conf.Method)
+	switch p.conf.Method {
+	case "":
+		p.conf.Method = http.MethodPost
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("unsupported method %q, must be %q, %q or %q", p.conf.Method, http.MethodPost, http.MethodPut, http.MethodPatch)
+	}
+	if p.conf.Format == "" {
+		p.conf.Format = webhookFormatJSON
+	}
+	if _, ok := webhookContentTypes[p.conf.Format]; !ok {
+		return fmt.Errorf("unsupported format %q, must be %q, %q or %q", p.conf.Format, webhookFormatJSON, webhookFormatNDJSON, webhookFormatTemplate)
+	}
+	if p.conf.Format == webhookFormatTemplate {
+		if p.conf.Template == "" {
+			return errors.New("template is required with the template format")
+		}
+		tmpl, err := template.New("webhook").Funcs(webhookTemplateFuncs).Parse(p.conf.Template)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+		p.template = tmpl
+	} else if p.conf.Template != "" {
+		return errors.New("template is only used with the template format")
+	}
+	if p.conf.ContentType == "" {
+		p.conf.ContentType = webhookContentTypes[p.conf.Format]
+	}
+	if p.conf.BatchSize <= 0 {
+		p.conf.BatchSize = webhookDefaultBatchSize
+	}
+	if p.conf.Timeout <= 0 {
+		p.conf.Timeout = webhookDefaultTimeout
+	}
+	if p.conf.MaxRetryAfter <= 0 {
+		p.conf.MaxRetryAfter = webhookDefaultMaxRetryAfter
+	}
+	if p.conf.SignatureHeader == "" {
+		p.conf.SignatureHeader = webhookDefaultSignatureHeader
+	}
+
+	tlsConfig, err := NewTLSConfig(TLSConfig{
+		CertFile:           p.conf.SSLCertFile,
+		KeyFile:            p.conf.SSLKeyFile,
+		CAFile:             p.conf.SSLCAFile,
+		ServerName:         p.conf.SSLServerName,
+		InsecureSkipVerify: p.conf.SSLInsecureSkipVerify,
+	}, p.log)
+	if err != nil {
+		return err
+	}
+	p.httpClient = &http.Client{
+		Timeout: time.Duration(p.conf.Timeout) * time.Second,
+		Transport: &tracingTransport{base: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}},
+	}
+	p.retry = retry.NewBackoffRetry("Failed sending to the webhook", p.conf.MaxRetries, p.httpClient, p.log,
+		retry.WithRetryAfter(time.Duration(p.conf.MaxRetryAfter)*time.Second))
+
+	p.log.Info(p.GetName() + " Initialized")
+	return nil
+}
+
+func (p *WebhookPump) WriteData(ctx context.Context, data []interface{}) error {
+	p.log.WithContext(ctx).Debug("Attempting to write ", len(data), " records...")
+
+	records := make([]analytics.AnalyticsRecord, 0, len(data))
+	for _, v := range data {
+		if record, ok := v.(analytics.AnalyticsRecord); ok {
+			records = append(records, record)
+		}
+	}
+
+	for start := 0; start < len(records); start += p.conf.BatchSize {
+		end := start + p.conf.BatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		var err error
+		if p.template != nil && !p.conf.TemplatePerRecord {
+			err = p.sendTemplate(ctx, records[start:end])
+		} else {
+			err = p.sendEntries(ctx, records[start:end])
+		}
+		if err != nil {
+			p.log.WithContext(ctx).Error("Failed to send the records: ", err)
+			return err
+		}
+	}
+
+	p.log.WithContext(ctx).Info("Purged ", len(records), " records...")
+	return nil
+}
+
+// sendEntries sends the records in the bodies made of an entry per record, as many
+// records in each as max_batch_bytes allows.
+func (p *WebhookPump) sendEntries(ctx context.Context, records []analytics.AnalyticsRecord) error {
+	var entries [][]byte
+	// size is the size of the body of entries, at most: the brackets of the array and a
+	// separator after every entry.
+	size := 2
+	for i := range records {
+		entry, err := p.entry(&records[i])
+		if err != nil {
+			return err
+		}
+		if p.conf.MaxBatchBytes > 0 && len(entries) > 0 && size+len(entry)+1 > p.conf.MaxBatchBytes {
+			if err := p.send(ctx, p.body(entries), len(entries)); err != nil {
+				return err
+			}
+			entries, size = nil, 2
+		}
+		entries = append(entries, entry)
+		size += len(entry) + 1
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return p.send(ctx, p.body(entries), len(entries))
+}
+
+// entry returns the entry of record in the bodies.
+func (p *WebhookPump) entry(record *analytics.AnalyticsRecord) ([]byte, error) {
+	if p.template == nil {
+		return json.Marshal(record)
+	}
+	var buf bytes.Buffer
+	if err := p.template.Execute(&buf, record); err != nil {
+		return nil, fmt.Errorf("failed to execute the template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// body returns the body of the request of entries.
+func (p *WebhookPump) body(entries [][]byte) []byte {
+	switch p.conf.Format {
+	case webhookFormatJSON:
+		return append(append([]byte("["), bytes.Join(entries, []byte(","))...), ']')
+	case webhookFormatNDJSON:
+		return append(bytes.Join(entries, []byte("\n")), '\n')
+	default:
+		return bytes.Join(entries, []byte("\n"))
+	}
+}
+
+// sendTemplate sends the output of the template executed with the records, splitting them
+// in halves while it's larger than max_batch_bytes.
+func (p *WebhookPump) sendTemplate(ctx context.Context, records []analytics.AnalyticsRecord) error {
+	var buf bytes.Buffer
+	if err := p.template.Execute(&buf, records); err != nil {
+		return fmt.Errorf("failed to execute the template: %w", err)
+	}
+	if p.conf.MaxBatchBytes > 0 && buf.Len() > p.conf.MaxBatchBytes && len(records) > 1 {
+		half := len(records) / 2
+		if err := p.sendTemplate(ctx, records[:half]); err != nil {
+			return err
+		}
+		return p.sendTemplate(ctx, records[half:])
+	}
+	return p.send(ctx, buf.Bytes(), len(records))
+}
+
+// send sends body, the one of n records.
+func (p *WebhookPump) send(ctx context.Context, body []byte, n int) error {
+	req, err := http.NewRequestWithContext(ctx, p.conf.Method, p.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", p.conf.ContentType)
+	for name, value := range p.conf.Headers {
+		req.Header.Set(name, value)
+	}
+	if p.conf.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.conf.BearerToken)
+	} else if p.conf.Username != "" {
+		req.SetBasicAuth(p.conf.Username, p.conf.Password)
+	}
+	if p.conf.HMACSecret != "" {
+		req.Header.Set(p.conf.SignatureHeader, webhookSignature(p.conf.HMACSecret, body))
+	}
+
+	p.log.Debugf("Sending %d records, %d bytes, to the webhook", n, len(body))
+	return p.retry.Send(req)
+}
+
+// webhookSignature returns the signature of body with secret, as the receivers of the
+// requests can compute it to check where they come from.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
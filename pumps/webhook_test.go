@@ -0,0 +1,302 @@
+package pumps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookRequest struct {
+	method string
+	header http.Header
+	body   string
+	at     time.Time
+}
+
+type webhookReceiver struct {
+	mu       sync.Mutex
+	requests []webhookRequest
+	// statuses are the ones of the next responses, with the Retry-After header of retryAfter.
+	statuses   []int
+	retryAfter string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.requests = append(r.requests, webhookRequest{method: req.Method, header: req.Header, body: string(body), at: time.Now()})
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+		if r.retryAfter != "" {
+			w.Header().Set("Retry-After", r.retryAfter)
+		}
+		http.Error(w, "failed", status)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func newWebhookTestPump(t *testing.T, config map[string]interface{}) (*WebhookPump, *webhookReceiver) {
+	t.Helper()
+
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	config["url"] = server.URL + "/hook"
+	pmp := &WebhookPump{}
+	require.NoError(t, pmp.Init(config))
+	return pmp, receiver
+}
+
+func webhookTestRecords() []interface{} {
+	return []interface{}{
+		analytics.AnalyticsRecord{APIName: "Users", OrgID: "org1", Method: "GET", Path: "/users/1", ResponseCode: 200},
+		analytics.AnalyticsRecord{APIName: "Users", OrgID: "org1", Method: "POST", Path: "/users", ResponseCode: 201},
+		"not a record",
+		analytics.AnalyticsRecord{APIName: "Orders", OrgID: "org1", Method: "GET", Path: "/orders", ResponseCode: 502},
+	}
+}
+
+func TestWebhookPump_Init(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		config      map[string]interface{}
+		expectedErr string
+	}{
+		{testName: "defaults", config: map[string]interface{}{"url": "http://hooks:8080"}},
+		{testName: "no url", config: map[string]interface{}{}, expectedErr: "url is required"},
+		{
+			testName:    "unknown method",
+			config:      map[string]interface{}{"url": "http://hooks:8080", "method": "get"},
+			expectedErr: `unsupported method "GET"`,
+		},
+		{
+			testName:    "unknown format",
+			config:      map[string]interface{}{"url": "http://hooks:8080", "format": "xml"},
+			expectedErr: `unsupported format "xml"`,
+		},
+		{
+			testName:    "template format without a template",
+			config:      map[string]interface{}{"url": "http://hooks:8080", "format": "template"},
+			expectedErr: "template is required with the template format",
+		},
+		{
+			testName:    "template with another format",
+			config:      map[string]interface{}{"url": "http://hooks:8080", "template": "{{.}}"},
+			expectedErr: "template is only used with the template format",
+		},
+		{
+			testName:    "invalid template",
+			config:      map[string]interface{}{"url": "http://hooks:8080", "format": "template", "template": "{{.APIName"},
+			expectedErr: "invalid template",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			pmp := &WebhookPump{}
+			err := pmp.Init(tc.config)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.MethodPost, pmp.conf.Method)
+			assert.Equal(t, webhookFormatJSON, pmp.conf.Format)
+			assert.Equal(t, "application/json", pmp.conf.ContentType)
+			assert.Equal(t, 100, pmp.conf.BatchSize)
+			assert.Equal(t, "X-Tyk-Signature", pmp.conf.SignatureHeader)
+		})
+	}
+}
+
+func TestWebhookPump_WriteData(t *testing.T) {
+	tcs := []struct {
+		testName            string
+		config              map[string]interface{}
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			testName:            "json",
+			config:              map[string]interface{}{},
+			expectedContentType: "application/json",
+		},
+		{
+			testName:            "ndjson",
+			config:              map[string]interface{}{"format": "ndjson"},
+			expectedContentType: "application/x-ndjson",
+		},
+		{
+			testName: "template per record",
+			config: map[string]interface{}{
+				"format": "template", "template_per_record": true, "content_type": "text/plain",
+				"template": "{{.Method}} {{.Path}} {{.ResponseCode}}",
+			},
+			expectedContentType: "text/plain",
+			expectedBody:        "GET /users/1 200\nPOST /users 201\nGET /orders 502",
+		},
+		{
+			testName: "template per batch",
+			config: map[string]interface{}{
+				"format":   "template",
+				"template": `{"text": "{{len .}} requests", "apis": [{{range $i, $r := .}}{{if $i}}, {{end}}{{json $r.APIName}}{{end}}]}`,
+			},
+			expectedContentType: "application/json",
+			expectedBody:        `{"text": "3 requests", "apis": ["Users", "Users", "Orders"]}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			tc.config["method"] = "put"
+			tc.config["headers"] = map[string]string{"X-Source": "tyk-pump"}
+			tc.config["bearer_token"] = "token"
+			pmp, receiver := newWebhookTestPump(t, tc.config)
+			require.NoError(t, pmp.WriteData(context.Background(), webhookTestRecords()))
+
+			require.Len(t, receiver.requests, 1)
+			req := receiver.requests[0]
+			assert.Equal(t, http.MethodPut, req.method)
+			assert.Equal(t, tc.expectedContentType, req.header.Get("Content-Type"))
+			assert.Equal(t, "tyk-pump", req.header.Get("X-Source"))
+			assert.Equal(t, "Bearer token", req.header.Get("Authorization"))
+			assert.Empty(t, req.header.Get("X-Tyk-Signature"), "the bodies are only signed with a secret")
+
+			var records []analytics.AnalyticsRecord
+			switch tc.config["format"] {
+			case nil:
+				require.NoError(t, json.Unmarshal([]byte(req.body), &records))
+			case "ndjson":
+				assert.True(t, strings.HasSuffix(req.body, "\n"))
+				for _, line := range strings.Split(strings.TrimSuffix(req.body, "\n"), "\n") {
+					var record analytics.AnalyticsRecord
+					require.NoError(t, json.Unmarshal([]byte(line), &record))
+					records = append(records, record)
+				}
+			default:
+				assert.Equal(t, tc.expectedBody, req.body)
+				return
+			}
+			require.Len(t, records, 3)
+			assert.Equal(t, "/users/1", records[0].Path)
+			assert.Equal(t, 502, records[2].ResponseCode)
+		})
+	}
+}
+
+func TestWebhookPump_Batches(t *testing.T) {
+	tcs := []struct {
+		testName      string
+		config        map[string]interface{}
+		expectedSizes []int
+	}{
+		{testName: "by count", config: map[string]interface{}{"batch_size": 2}, expectedSizes: []int{2, 1}},
+		{testName: "by bytes", config: map[string]interface{}{"format": "ndjson", "max_batch_bytes": 1}, expectedSizes: []int{1, 1, 1}},
+		{
+			testName: "template by bytes",
+			config: map[string]interface{}{
+				"format": "template", "max_batch_bytes": 20,
+				"template": `{{range .}}{{.Path}} {{end}}`,
+			},
+			expectedSizes: []int{1, 2},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			pmp, receiver := newWebhookTestPump(t, tc.config)
+			require.NoError(t, pmp.WriteData(context.Background(), webhookTestRecords()))
+
+			var sizes []int
+			for _, req := range receiver.requests {
+				switch tc.config["format"] {
+				case "template":
+					sizes = append(sizes, len(strings.Fields(req.body)))
+				case "ndjson":
+					sizes = append(sizes, strings.Count(req.body, "\n"))
+				default:
+					var records []analytics.AnalyticsRecord
+					require.NoError(t, json.Unmarshal([]byte(req.body), &records))
+					sizes = append(sizes, len(records))
+				}
+			}
+			assert.Equal(t, tc.expectedSizes, sizes)
+		})
+	}
+}
+
+func TestWebhookPump_Signature(t *testing.T) {
+	pmp, receiver := newWebhookTestPump(t, map[string]interface{}{
+		"hmac_secret":      "secret",
+		"signature_header": "X-Hub-Signature-256",
+		"username":         "tyk",
+		"password":         "password",
+	})
+	require.NoError(t, pmp.WriteData(context.Background(), webhookTestRecords()))
+
+	require.Len(t, receiver.requests, 1)
+	req := receiver.requests[0]
+	assert.Equal(t, webhookSignature("secret", []byte(req.body)), req.header.Get("X-Hub-Signature-256"))
+	assert.True(t, strings.HasPrefix(req.header.Get("X-Hub-Signature-256"), "sha256="))
+	assert.NotEqual(t, webhookSignature("other", []byte(req.body)), req.header.Get("X-Hub-Signature-256"))
+	username, password, ok := (&http.Request{Header: req.header}).BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "tyk", username)
+	assert.Equal(t, "password", password)
+}
+
+func TestWebhookPump_Retries(t *testing.T) {
+	pmp, receiver := newWebhookTestPump(t, map[string]interface{}{"max_retries": 2})
+	receiver.statuses = []int{http.StatusTooManyRequests}
+	receiver.retryAfter = "1"
+	require.NoError(t, pmp.WriteData(context.Background(), webhookTestRecords()))
+	require.Len(t, receiver.requests, 2)
+	assert.GreaterOrEqual(t, receiver.requests[1].at.Sub(receiver.requests[0].at), time.Second, "the Retry-After delay is honoured")
+	assert.Equal(t, receiver.requests[0].body, receiver.requests[1].body)
+
+	receiver.statuses = []int{http.StatusBadRequest}
+	receiver.retryAfter = ""
+	assert.ErrorContains(t, pmp.WriteData(context.Background(), webhookTestRecords()), "got status code 400", "the bad requests aren't retried")
+	assert.Len(t, receiver.requests, 3)
+
+	receiver.statuses = []int{http.StatusServiceUnavailable}
+	receiver.retryAfter = "3600"
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Error(t, pmp.WriteData(ctx, webhookTestRecords()))
+	assert.Less(t, time.Since(start), 5*time.Second, "the Retry-After delay is capped at the deadline of the write")
+	assert.Len(t, receiver.requests, 4)
+}
+
+func TestWebhookPump_MaxRetryAfter(t *testing.T) {
+	pmp, receiver := newWebhookTestPump(t, map[string]interface{}{"max_retries": 1, "max_retry_after": 1})
+	assert.Equal(t, 1, pmp.conf.MaxRetryAfter)
+	receiver.statuses = []int{http.StatusTooManyRequests}
+	receiver.retryAfter = "86400"
+
+	start := time.Now()
+	require.NoError(t, pmp.WriteData(context.Background(), webhookTestRecords()))
+	assert.Less(t, time.Since(start), 5*time.Second, "the Retry-After delay is capped at max_retry_after without a deadline")
+	require.Len(t, receiver.requests, 2)
+	assert.GreaterOrEqual(t, receiver.requests[1].at.Sub(receiver.requests[0].at), time.Second)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/quarantine"
+	"github.com/TykTechnologies/tyk-pump/serializer"
+	"github.com/TykTechnologies/tyk-pump/storage"
+	"github.com/gocraft/health"
+	"github.com/sirupsen/logrus"
+)
+
+func setupQuarantine() {
+	var list quarantine.ListStorage
+	if SystemConfig.Quarantine.Type == quarantine.RedisType {
+		store, err := storage.NewTemporalStorageHandler(SystemConfig.AnalyticsStorageConfig, false)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Fatal("Error connecting to Temporal Storage: ", err)
+		}
+		if err := store.Init(); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Fatal("Error connecting to Temporal Storage: ", err)
+		}
+		list = store
+	}
+
+	var err error
+	Quarantine, err = quarantine.NewStore(SystemConfig.Quarantine, list)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Fatal("Invalid quarantine: ", err)
+	}
+
+	if Quarantine != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Info("Init Quarantine: ", Quarantine.GetName())
+	}
+}
+
+func newQuarantineEntry(data []byte, serializerMethod serializer.AnalyticsSerializer, analyticsKeyName string, err error) quarantine.Entry {
+	return quarantine.Entry{
+		Payload:       data,
+		Serializer:    serializer.Name(serializerMethod),
+		KeyName:       analyticsKeyName,
+		Error:         err.Error(),
+		QuarantinedAt: time.Now().UTC(),
+	}
+}
+
+// quarantinePayloads hands the payloads that couldn't be decoded to the quarantine, if
+// one is configured, and counts them in the record_quarantined event.
+func quarantinePayloads(entries []quarantine.Entry, job *health.Job) {
+	if Quarantine == nil || len(entries) == 0 {
+		return
+	}
+
+	if err := Quarantine.Put(entries...); err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Error("Couldn't quarantine ", len(entries), " analytics payloads: ", err)
+		job.Event("record_quarantine_error")
+		return
+	}
+
+	for range entries {
+		job.Event("record_quarantined")
+	}
+}
+
+// retryQuarantine decodes every quarantined payload again and writes the ones that now
+// decode to the pumps. They leave the quarantine once every pump has written them, and
+// the others are quarantined back with why they still can't be decoded.
+func retryQuarantine() {
+	if Quarantine == nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Fatal("No quarantine configured")
+	}
+
+	ctx := context.Background()
+	job := instrument.NewJob("QuarantineRetry")
+	startTime := time.Now()
+
+	entries, err := Quarantine.Peek(ctx)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Error("Couldn't read the quarantine: ", err)
+	}
+
+	records := make([]interface{}, 0, len(entries))
+	decoded := []quarantine.Entry{}
+	// failed are the entries still undecodable, and requarantined the same entries with why.
+	failed := []quarantine.Entry{}
+	requarantined := []quarantine.Entry{}
+	for _, entry := range entries {
+		record, err := decodeAnalyticsValue(entry.Payload, serializer.NewAnalyticsSerializer(entry.Serializer))
+		if err != nil {
+			failed = append(failed, entry)
+			entry.Error = err.Error()
+			entry.QuarantinedAt = time.Now().UTC()
+			requarantined = append(requarantined, entry)
+			continue
+		}
+		records = append(records, record)
+		decoded = append(decoded, entry)
+		job.Event("record")
+	}
+
+	written := 0
+	if len(decoded) > 0 {
+		written = writeQuarantined(ctx, decoded, records, job, startTime)
+	}
+	if len(failed) > 0 {
+		// The updated entries are put before the old ones are removed, so none is lost.
+		if err := Quarantine.Put(requarantined...); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Couldn't quarantine back ", len(requarantined), " payloads, keeping them as they were: ", err)
+			job.Event("record_quarantine_error")
+		} else if err := Quarantine.Remove(ctx, failed...); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Couldn't remove the payloads quarantined back from the quarantine: ", err)
+		} else {
+			for range requarantined {
+				job.Event("record_quarantined")
+			}
+		}
+	}
+
+	for _, pmp := range Pumps {
+		if err := pmp.Shutdown(); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Error trying to gracefully shutdown  "+pmp.GetName()+":", err)
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		"prefix": mainPrefix,
+	}).Infof("Retried %d quarantined payloads: %d decoded, %d written, %d quarantined back", len(entries), len(records), written, len(failed))
+}
+
+// writeQuarantined writes the records of the decoded entries to the pumps that didn't write
+// them yet, and returns the number of entries every pump has now written, which leave the
+// quarantine. The others are kept with the pumps that wrote them, not to write them twice.
+func writeQuarantined(ctx context.Context, entries []quarantine.Entry, records []interface{}, job *health.Job, startTime time.Time) int {
+	if len(Pumps) == 0 {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Warning("No pumps defined!")
+		return 0
+	}
+
+	var pmps []pumps.Pump
+	var names []string
+	// pending are the indexes of the entries each pump of pmps is yet to write.
+	var pending [][]int
+	for _, pmp := range Pumps {
+		name := getPumpState(pmp).name
+		var indexes []int
+		for i, entry := range entries {
+			if !slices.Contains(entry.WrittenBy, name) {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) > 0 {
+			pmps = append(pmps, pmp)
+			names = append(names, name)
+			pending = append(pending, indexes)
+		}
+	}
+
+	errs := dispatchEach(ctx, pmps, func(i int) []interface{} {
+		keys := make([]interface{}, 0, len(pending[i]))
+		for _, j := range pending[i] {
+			keys = append(keys, records[j])
+		}
+		return keys
+	}, job, startTime, SystemConfig.PurgeDelay)
+
+	updated := slices.Clone(entries)
+	for i, err := range errs {
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Keeping ", len(pending[i]), " decoded payloads in the quarantine, ", names[i], " didn't write them: ", err)
+			continue
+		}
+		for _, j := range pending[i] {
+			updated[j].WrittenBy = append(slices.Clip(updated[j].WrittenBy), names[i])
+		}
+	}
+
+	// done are the entries every pump has written, and kept the ones some more pumps wrote,
+	// replacing the old ones.
+	var done, kept, old []quarantine.Entry
+	for j, entry := range updated {
+		switch {
+		case writtenByEveryPump(entry):
+			done = append(done, entries[j])
+		case len(entry.WrittenBy) > len(entries[j].WrittenBy):
+			kept = append(kept, entry)
+			old = append(old, entries[j])
+		}
+	}
+
+	if len(kept) > 0 {
+		// The updated entries are put before the old ones are removed, so none is lost.
+		if err := Quarantine.Put(kept...); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Couldn't record the pumps that wrote ", len(kept), " payloads, they'll be written to them again: ", err)
+		} else if err := Quarantine.Remove(ctx, old...); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Couldn't remove the payloads kept in the quarantine: ", err)
+		}
+	}
+	if len(done) == 0 {
+		return 0
+	}
+	if err := Quarantine.Remove(ctx, done...); err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Error("Couldn't remove the written payloads from the quarantine: ", err)
+		return 0
+	}
+	return len(done)
+}
+
+// writtenByEveryPump reports whether every pump has written entry.
+func writtenByEveryPump(entry quarantine.Entry) bool {
+	for _, pmp := range Pumps {
+		if !slices.Contains(entry.WrittenBy, getPumpState(pmp).name) {
+			return false
+		}
+	}
+	return true
+}
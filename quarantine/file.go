@@ -0,0 +1,147 @@
+package quarantine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	payloadExt = ".payload"
+	entryExt   = ".json"
+)
+
+var fileSequence uint64
+
+// FileStore keeps every entry as two files in a directory: the payload, verbatim, and a
+// JSON file holding the rest of the entry. The JSON file is written last, so an entry
+// without one was never completely written and is ignored.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store keeping its entries in dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+func (f *FileStore) GetName() string {
+	return "File quarantine"
+}
+
+func (f *FileStore) Put(entries ...Entry) error {
+	for _, entry := range entries {
+		// The names sort in the order the entries were quarantined.
+		name := fmt.Sprintf("%020d-%06d", time.Now().UnixNano(), atomic.AddUint64(&fileSequence, 1))
+
+		if err := os.WriteFile(filepath.Join(f.dir, name+payloadExt), entry.Payload, 0o600); err != nil {
+			return err
+		}
+
+		entry.Payload = nil
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(f.dir, name+entryExt), data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *FileStore) Peek(context.Context) ([]Entry, error) {
+	names, err := f.entryNames()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(names))
+	for _, name := range names {
+		entryPath := filepath.Join(f.dir, name+entryExt)
+
+		entry, err := readEntry(entryPath, filepath.Join(f.dir, name+payloadExt))
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": logPrefix,
+			}).Error("Skipping unreadable quarantine entry ", entryPath, ": ", err)
+			continue
+		}
+		entry.ref = name
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (f *FileStore) Remove(_ context.Context, entries ...Entry) error {
+	for _, entry := range entries {
+		if entry.ref == "" {
+			continue
+		}
+
+		// The JSON file goes first, so an entry left half removed is ignored.
+		for _, path := range []string{filepath.Join(f.dir, entry.ref+entryExt), filepath.Join(f.dir, entry.ref+payloadExt)} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *FileStore) Len(context.Context) (int64, error) {
+	names, err := f.entryNames()
+
+	return int64(len(names)), err
+}
+
+// entryNames returns the names of the complete entries, oldest first.
+func (f *FileStore) entryNames() ([]string, error) {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), entryExt) {
+			names = append(names, strings.TrimSuffix(file.Name(), entryExt))
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func readEntry(entryPath, payloadPath string) (Entry, error) {
+	entry := Entry{}
+
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		return entry, err
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, err
+	}
+
+	entry.Payload, err = os.ReadFile(payloadPath)
+
+	return entry, err
+}
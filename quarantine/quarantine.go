@@ -0,0 +1,101 @@
+package quarantine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/logger"
+)
+
+const (
+	FileType  = "file"
+	RedisType = "redis"
+
+	// DefaultKeyName is the list redis quarantines append to when none is configured.
+	DefaultKeyName = "tyk-pump-quarantine"
+)
+
+var log = logger.GetLogger()
+
+const logPrefix = "quarantine"
+
+// Config sets where the analytics payloads Pump can't decode are kept, so they can be
+// retried with `tyk-pump quarantine retry` once whatever broke them is fixed.
+type Config struct {
+	// Where undecodable payloads are written, either `file` or `redis`. Leave it empty to
+	// discard them, which is the default.
+	Type string `json:"type"`
+	// The directory `file` quarantines write to. It's created if it doesn't exist.
+	Dir string `json:"dir"`
+	// The list `redis` quarantines append to, under the analytics storage key prefix.
+	// Defaults to `tyk-pump-quarantine`.
+	KeyName string `json:"key_name"`
+}
+
+// Entry is a payload that couldn't be decoded, together with what's needed to retry it.
+type Entry struct {
+	// Payload is the payload as read from the analytics key, byte for byte.
+	Payload []byte `json:"payload,omitempty"`
+	// Serializer is the name of the serializer of the key the payload was read from.
+	Serializer string `json:"serializer"`
+	// KeyName is the analytics key the payload was read from.
+	KeyName string `json:"key_name"`
+	// Error is why the payload couldn't be decoded.
+	Error string `json:"error"`
+	// QuarantinedAt is when the payload was last quarantined.
+	QuarantinedAt time.Time `json:"quarantined_at"`
+	// WrittenBy are the names of the pumps that wrote the payload when it was retried, the
+	// next retries only writing it to the others.
+	WrittenBy []string `json:"written_by,omitempty"`
+
+	// ref identifies the entry in the store it was read from, so it can be removed.
+	ref string
+}
+
+// Store keeps quarantined payloads.
+type Store interface {
+	GetName() string
+	// Put adds entries to the quarantine.
+	Put(entries ...Entry) error
+	// Peek returns every entry of the quarantine, oldest first, leaving them in it.
+	Peek(ctx context.Context) ([]Entry, error)
+	// Remove removes entries returned by Peek from the quarantine.
+	Remove(ctx context.Context, entries ...Entry) error
+	// Len returns the number of entries in the quarantine.
+	Len(ctx context.Context) (int64, error)
+}
+
+// ListStorage is the storage redis quarantines keep their entries in.
+type ListStorage interface {
+	AppendToSet(keyName string, values ...[]byte) error
+	GetListRange(ctx context.Context, keyName string, start, stop int64) ([]string, error)
+	RemoveFromList(ctx context.Context, keyName, value string) error
+	GetListLength(ctx context.Context, keyName string) (int64, error)
+}
+
+// NewStore returns the store conf configures, or nil if quarantining is disabled. list
+// is only used by redis quarantines.
+func NewStore(conf Config, list ListStorage) (Store, error) {
+	switch conf.Type {
+	case "":
+		return nil, nil
+	case FileType:
+		if conf.Dir == "" {
+			return nil, errors.New("file quarantine needs a dir")
+		}
+		return NewFileStore(conf.Dir)
+	case RedisType:
+		if list == nil {
+			return nil, errors.New("redis quarantine needs a storage")
+		}
+		keyName := conf.KeyName
+		if keyName == "" {
+			keyName = DefaultKeyName
+		}
+		return NewRedisStore(list, keyName), nil
+	}
+
+	return nil, fmt.Errorf("unknown quarantine type %q, must be %s or %s", conf.Type, FileType, RedisType)
+}
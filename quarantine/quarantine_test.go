@@ -0,0 +1,139 @@
+package quarantine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryList struct {
+	values map[string][]string
+}
+
+func (m *memoryList) AppendToSet(keyName string, values ...[]byte) error {
+	for _, v := range values {
+		m.values[keyName] = append(m.values[keyName], string(v))
+	}
+	return nil
+}
+
+func (m *memoryList) GetListRange(ctx context.Context, keyName string, start, stop int64) ([]string, error) {
+	return append([]string{}, m.values[keyName]...), nil
+}
+
+func (m *memoryList) RemoveFromList(ctx context.Context, keyName, value string) error {
+	for i, v := range m.values[keyName] {
+		if v == value {
+			m.values[keyName] = append(m.values[keyName][:i], m.values[keyName][i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryList) GetListLength(ctx context.Context, keyName string) (int64, error) {
+	return int64(len(m.values[keyName])), nil
+}
+
+func testEntries() []Entry {
+	return []Entry{
+		{Payload: []byte{0x00, 0xff, 'x'}, Serializer: "protobuf", KeyName: "tyk-system-analytics_protobuf", Error: "bad wire type", QuarantinedAt: time.Now().UTC()},
+		{Payload: []byte("not msgpack"), Serializer: "msgpack", KeyName: "tyk-system-analytics", Error: "bad descriptor", QuarantinedAt: time.Now().UTC()},
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	tcs := []struct {
+		testName string
+		conf     Config
+		list     ListStorage
+		expected Store
+		isErr    bool
+	}{
+		{testName: "disabled", conf: Config{}},
+		{testName: "file without dir", conf: Config{Type: FileType}, isErr: true},
+		{testName: "redis without storage", conf: Config{Type: RedisType}, isErr: true},
+		{testName: "redis default key", conf: Config{Type: RedisType}, list: &memoryList{}, expected: &RedisStore{list: &memoryList{}, keyName: DefaultKeyName}},
+		{testName: "unknown type", conf: Config{Type: "s3"}, isErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			store, err := NewStore(tc.conf, tc.list)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tc.expected == nil {
+				assert.Nil(t, store)
+			} else {
+				assert.Equal(t, tc.expected, store)
+			}
+		})
+	}
+}
+
+func TestStores_PutPeekAndRemove(t *testing.T) {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "quarantine"))
+	assert.NoError(t, err)
+
+	for _, store := range []Store{fileStore, NewRedisStore(&memoryList{values: map[string][]string{}}, DefaultKeyName)} {
+		t.Run(store.GetName(), func(t *testing.T) {
+			ctx := context.Background()
+			entries := testEntries()
+			assert.NoError(t, store.Put(entries...))
+
+			length, err := store.Len(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(entries)), length)
+
+			peeked, err := store.Peek(ctx)
+			assert.NoError(t, err)
+			assert.Len(t, peeked, len(entries))
+			for i := range entries {
+				assert.Equal(t, entries[i].Payload, peeked[i].Payload, "the payload must be kept verbatim")
+				assert.Equal(t, entries[i].Serializer, peeked[i].Serializer)
+				assert.Equal(t, entries[i].KeyName, peeked[i].KeyName)
+				assert.Equal(t, entries[i].Error, peeked[i].Error)
+				assert.True(t, entries[i].QuarantinedAt.Equal(peeked[i].QuarantinedAt))
+			}
+
+			again, err := store.Peek(ctx)
+			assert.NoError(t, err)
+			assert.Len(t, again, len(entries), "peeked entries must be kept")
+
+			assert.NoError(t, store.Remove(ctx, peeked[0]))
+			remaining, err := store.Peek(ctx)
+			assert.NoError(t, err)
+			if assert.Len(t, remaining, 1, "only the removed entries must go") {
+				assert.Equal(t, entries[1].Payload, remaining[0].Payload)
+			}
+
+			assert.NoError(t, store.Remove(ctx, remaining...))
+			length, err = store.Len(ctx)
+			assert.NoError(t, err)
+			assert.Zero(t, length)
+		})
+	}
+}
+
+func TestFileStore_IgnoresPartialEntries(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000001-000001"+payloadExt), []byte("half written"), 0o600))
+
+	length, err := store.Len(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, length)
+
+	peeked, err := store.Peek(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, peeked)
+}
@@ -0,0 +1,75 @@
+package quarantine
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedisStore keeps entries JSON encoded in a list, oldest first.
+type RedisStore struct {
+	list    ListStorage
+	keyName string
+}
+
+// NewRedisStore returns a store keeping its entries in the keyName list of list.
+func NewRedisStore(list ListStorage, keyName string) *RedisStore {
+	return &RedisStore{list: list, keyName: keyName}
+}
+
+func (r *RedisStore) GetName() string {
+	return "Redis quarantine"
+}
+
+func (r *RedisStore) Put(entries ...Entry) error {
+	values := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		values = append(values, data)
+	}
+
+	return r.list.AppendToSet(r.keyName, values...)
+}
+
+func (r *RedisStore) Peek(ctx context.Context) ([]Entry, error) {
+	values, err := r.list.GetListRange(ctx, r.keyName, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(values))
+	for _, value := range values {
+		entry := Entry{}
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": logPrefix,
+			}).Error("Skipping unreadable quarantine entry: ", err)
+			continue
+		}
+		entry.ref = value
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (r *RedisStore) Remove(ctx context.Context, entries ...Entry) error {
+	for _, entry := range entries {
+		if entry.ref == "" {
+			continue
+		}
+		if err := r.list.RemoveFromList(ctx, r.keyName, entry.ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RedisStore) Len(ctx context.Context) (int64, error) {
+	return r.list.GetListLength(ctx, r.keyName)
+}
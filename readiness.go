@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/server"
+)
+
+const defaultMaxPumpErrorRate = 0.5
+
+// pumpState is what the Pump tracks about each of its pumps while it runs.
+type pumpState struct {
+	name  string
+	stats *pumps.WriteStats
+	// paused is set while the pump is paused through the admin API.
+	paused atomic.Bool
+	// inUse is read locked while the pump writes or checks its health, and locked while
+	// its credentials are refreshed, only once it isn't read locked.
+	inUse sync.RWMutex
+}
+
+func (s *pumpState) isPaused() bool {
+	return s.paused.Load()
+}
+
+var (
+	pumpStatesMu sync.Mutex
+	pumpStates   = map[pumps.Pump]*pumpState{}
+)
+
+// setPumpState starts tracking pmp under the name it's configured with.
+func setPumpState(pmp pumps.Pump, name string, breaker pumps.CircuitBreakerConf) {
+	pumpStatesMu.Lock()
+	defer pumpStatesMu.Unlock()
+
+	pumpStates[pmp] = &pumpState{name: name, stats: pumps.NewWriteStats(breaker)}
+}
+
+// getPumpState returns the state of pmp, tracking it with no circuit breaker if it
+// wasn't already.
+func getPumpState(pmp pumps.Pump) *pumpState {
+	pumpStatesMu.Lock()
+	defer pumpStatesMu.Unlock()
+
+	state, ok := pumpStates[pmp]
+	if !ok {
+		state = &pumpState{name: pmp.GetName(), stats: pumps.NewWriteStats(pumps.CircuitBreakerConf{})}
+		pumpStates[pmp] = state
+	}
+
+	return state
+}
+
+var (
+	purgeMu   sync.RWMutex
+	startedAt = time.Now()
+	lastPurge time.Time
+)
+
+func recordPurge(at time.Time) {
+	purgeMu.Lock()
+	defer purgeMu.Unlock()
+
+	lastPurge = at
+}
+
+func getLastPurge() time.Time {
+	purgeMu.RLock()
+	defer purgeMu.RUnlock()
+
+	return lastPurge
+}
+
+// storagePinger is implemented by the analytics stores able to check their connectivity.
+type storagePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// readinessReporter builds the report of the readiness endpoint from the state of the
+// analytics store and the pumps.
+type readinessReporter struct {
+	conf       ReadinessConf
+	purgeDelay int
+}
+
+func (rr readinessReporter) Report(ctx context.Context) server.Report {
+	report := server.Report{Status: server.StatusOK}
+
+	if pinger, ok := AnalyticsStore.(storagePinger); ok {
+		report.Storage = &server.StorageHealth{Healthy: true}
+		if err := pinger.Ping(ctx); err != nil {
+			report.Storage.Healthy = false
+			report.Storage.Error = err.Error()
+			report.Reasons = append(report.Reasons, "temporal storage unreachable: "+err.Error())
+		}
+	}
+
+	since := startedAt
+	if purged := getLastPurge(); !purged.IsZero() {
+		report.LastPurge = &purged
+		since = purged
+	}
+	if maxAge := rr.maxPurgeAge(); time.Since(since) > maxAge {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("no successful purge in the last %v", maxAge))
+	}
+
+	maxErrorRate := rr.conf.MaxPumpErrorRate
+	if maxErrorRate == 0 {
+		maxErrorRate = defaultMaxPumpErrorRate
+	}
+
+	unhealthy := 0
+	for _, pmp := range Pumps {
+		pumpHealth := rr.pumpHealth(ctx, pmp, maxErrorRate)
+		if !pumpHealth.Healthy {
+			unhealthy++
+		}
+		report.Pumps = append(report.Pumps, pumpHealth)
+	}
+	if unhealthy > rr.conf.MaxUnhealthyPumps {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d unhealthy pumps, at most %d tolerated", unhealthy, rr.conf.MaxUnhealthyPumps))
+	}
+
+	if len(report.Reasons) > 0 {
+		report.Status = server.StatusFail
+	}
+
+	return report
+}
+
+func (rr readinessReporter) maxPurgeAge() time.Duration {
+	if rr.conf.MaxPurgeAge > 0 {
+		return time.Duration(rr.conf.MaxPurgeAge) * time.Second
+	}
+
+	purgeDelay := rr.purgeDelay
+	if purgeDelay <= 0 {
+		purgeDelay = 10
+	}
+
+	return 3 * time.Duration(purgeDelay) * time.Second
+}
+
+func (rr readinessReporter) pumpHealth(ctx context.Context, pmp pumps.Pump, maxErrorRate float64) server.PumpHealth {
+	state := getPumpState(pmp)
+	snapshot := state.stats.Snapshot()
+
+	pumpHealth := server.PumpHealth{
+		Name:           state.name,
+		Healthy:        true,
+		LastWriteError: snapshot.LastError,
+		ErrorRate:      snapshot.ErrorRate,
+		Circuit:        snapshot.Circuit,
+	}
+	if !snapshot.LastWrite.IsZero() {
+		pumpHealth.LastWrite = &snapshot.LastWrite
+	}
+
+	if snapshot.Circuit == pumps.CircuitOpen || snapshot.ErrorRate > maxErrorRate {
+		pumpHealth.Healthy = false
+	}
+
+	if checker, ok := pmp.(pumps.HealthChecker); ok {
+		state.inUse.RLock()
+		err := checker.CheckHealth(ctx)
+		state.inUse.RUnlock()
+		if err != nil {
+			pumpHealth.Healthy = false
+			pumpHealth.CheckError = err.Error()
+		}
+	}
+
+	return pumpHealth
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/server"
+	"github.com/stretchr/testify/assert"
+)
+
+type unreachablePump struct {
+	MockedPump
+}
+
+func (p *unreachablePump) CheckHealth(context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestReadinessReporter(t *testing.T) {
+	origPumps := Pumps
+	t.Cleanup(func() {
+		Pumps = origPumps
+		recordPurge(time.Time{})
+	})
+
+	healthyPump := &MockedPump{}
+	failingPump := &MockedPump{}
+	checkedPump := &unreachablePump{}
+	setPumpState(healthyPump, "healthy", pumps.CircuitBreakerConf{})
+	setPumpState(failingPump, "failing", pumps.CircuitBreakerConf{FailureThreshold: 1})
+	setPumpState(checkedPump, "unreachable", pumps.CircuitBreakerConf{})
+	getPumpState(healthyPump).stats.Record(nil)
+	getPumpState(failingPump).stats.Record(errors.New("boom"))
+	Pumps = []pumps.Pump{healthyPump, failingPump, checkedPump}
+	recordPurge(time.Now())
+
+	report := readinessReporter{conf: ReadinessConf{MaxUnhealthyPumps: 2}, purgeDelay: 10}.Report(context.Background())
+	assert.Equal(t, server.StatusOK, report.Status, report.Reasons)
+	if assert.Len(t, report.Pumps, 3) {
+		assert.Equal(t, "healthy", report.Pumps[0].Name)
+		assert.True(t, report.Pumps[0].Healthy)
+		assert.NotNil(t, report.Pumps[0].LastWrite)
+
+		assert.False(t, report.Pumps[1].Healthy)
+		assert.Equal(t, pumps.CircuitOpen, report.Pumps[1].Circuit)
+		assert.Equal(t, 1.0, report.Pumps[1].ErrorRate)
+		assert.Equal(t, "boom", report.Pumps[1].LastWriteError)
+
+		assert.False(t, report.Pumps[2].Healthy)
+		assert.Equal(t, "connection refused", report.Pumps[2].CheckError)
+	}
+
+	report = readinessReporter{conf: ReadinessConf{MaxUnhealthyPumps: 1}, purgeDelay: 10}.Report(context.Background())
+	assert.Equal(t, server.StatusFail, report.Status, "too many unhealthy pumps")
+
+	recordPurge(time.Now().Add(-time.Minute))
+	report = readinessReporter{conf: ReadinessConf{MaxUnhealthyPumps: 2}, purgeDelay: 10}.Report(context.Background())
+	assert.Equal(t, server.StatusFail, report.Status, "the last purge is too old")
+}
+
+func TestExecPumpWriting_CircuitBreaker(t *testing.T) {
+	mockedPump := &MockedPump{}
+	setPumpState(mockedPump, "mocked", pumps.CircuitBreakerConf{FailureThreshold: 1, Cooldown: 60})
+	getPumpState(mockedPump).stats.Record(errors.New("boom"))
+
+	keys := []interface{}{analytics.AnalyticsRecord{APIID: "api1"}, analytics.AnalyticsRecord{APIID: "api2"}}
+
+	origPumps := Pumps
+	t.Cleanup(func() { Pumps = origPumps })
+	Pumps = []pumps.Pump{mockedPump}
+
+	writeToPumps(keys, nil, time.Now(), 2)
+	assert.Equal(t, 0, mockedPump.CounterRequest, "a pump whose circuit is open must be skipped")
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const redactedValue = "*REDACTED*"
+
+// secretKeySegments are the segments, in lower case, of the config keys holding secrets,
+// either one segment or several in a row. Matching whole segments keeps the likes of
+// `auth_type`, `oauth_client_id` and `max_tokens` visible.
+var secretKeySegments = [][]string{
+	{"password"},
+	{"secret"},
+	{"token"},
+	{"apikey"},
+	{"credentials"},
+	{"api", "key"},
+	{"access", "key"},
+	{"private", "key"},
+	{"connection", "string"},
+	{"basic", "auth"},
+}
+
+// secretKeyLastSegments are the last segments, in lower case, of the config keys holding
+// secrets, the ones they start being the names of the secrets, such as
+// `authorization_header_name`.
+var secretKeyLastSegments = []string{"authorization"}
+
+func isSecretKey(key string) bool {
+	segments := keySegments(key)
+	if len(segments) > 0 && slices.Contains(secretKeyLastSegments, segments[len(segments)-1]) {
+		return true
+	}
+	for _, marker := range secretKeySegments {
+		for i := 0; i+len(marker) <= len(segments); i++ {
+			if slices.Equal(segments[i:i+len(marker)], marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// keySegments splits key, in lower case, at its underscores, hyphens, dots and where its
+// case changes to upper, e.g. `CollectorToken` into `collector` and `token`.
+func keySegments(key string) []string {
+	var segments []string
+	var segment strings.Builder
+	flush := func() {
+		if segment.Len() > 0 {
+			segments = append(segments, segment.String())
+			segment.Reset()
+		}
+	}
+
+	afterLower := false
+	for _, r := range key {
+		if r == '_' || r == '-' || r == '.' {
+			flush()
+			afterLower = false
+			continue
+		}
+		if unicode.IsUpper(r) && afterLower {
+			flush()
+		}
+		segment.WriteRune(unicode.ToLower(r))
+		afterLower = unicode.IsLower(r) || unicode.IsDigit(r)
+	}
+	flush()
+
+	return segments
+}
+
+// redactConfig returns conf as a generic JSON value, with the value of every key that
+// looks like a secret, and the credentials of every URL, redacted.
+func redactConfig(conf interface{}) (interface{}, error) {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	return redactValue(generic), nil
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			if isSecretKey(key) && isSecretValue(inner) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(inner)
+		}
+		return v
+	case []interface{}:
+		for i, inner := range v {
+			v[i] = redactValue(inner)
+		}
+		return v
+	case string:
+		return redactURL(v)
+	default:
+		return v
+	}
+}
+
+// isSecretValue reports whether value, held by a secret key, is the secret itself: a
+// string or a number. Booleans, such as the ones enabling an auth, and empty strings
+// aren't.
+func isSecretValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v != ""
+	case float64:
+		return true
+	}
+
+	return false
+}
+
+// redactURL redacts the password of s, when s is a URL carrying one.
+func redactURL(s string) string {
+	if !strings.Contains(s, "@") || !strings.Contains(s, "://") {
+		return s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return s
+	}
+	u.User = url.UserPassword(u.User.Username(), redactedValue)
+
+	return strings.Replace(u.String(), url.QueryEscape(redactedValue), redactedValue, 1)
+}
@@ -2,12 +2,14 @@ package retry
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +22,20 @@ type BackoffHTTPRetry struct {
 	httpclient *http.Client
 	errMsg     string
 	maxRetries uint64
+	// maxRetryAfter is the longest Retry-After delay waited for, 0 when the Retry-After
+	// headers are ignored.
+	maxRetryAfter time.Duration
+}
+
+// Option configures a BackoffHTTPRetry.
+type Option func(*BackoffHTTPRetry)
+
+// WithRetryAfter waits as long as the Retry-After header of a 429 or 503 asks, up to maxDelay or
+// the deadline of the request, rather than for the exponential backoff.
+func WithRetryAfter(maxDelay time.Duration) Option {
+	return func(s *BackoffHTTPRetry) {
+		s.maxRetryAfter = maxDelay
+	}
 }
 
 type (
@@ -29,9 +45,13 @@ type (
 )
 
 // NewBackoffRetry Creates an exponential backoff retry to use httpClient for connections. Will retry if a temporary error or
-// 5xx or 429 status code in response.
-func NewBackoffRetry(errMsg string, maxRetries uint64, httpClient *http.Client, logger *logrus.Entry) *BackoffHTTPRetry {
-	return &BackoffHTTPRetry{errMsg: errMsg, maxRetries: maxRetries, httpclient: httpClient, logger: logger}
+// 5xx or 429 status code in response, until the context of the request is done.
+func NewBackoffRetry(errMsg string, maxRetries uint64, httpClient *http.Client, logger *logrus.Entry, opts ...Option) *BackoffHTTPRetry {
+	s := &BackoffHTTPRetry{errMsg: errMsg, maxRetries: maxRetries, httpclient: httpClient, logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *BackoffHTTPRetry) Send(req *http.Request) error {
@@ -46,6 +66,7 @@ func (s *BackoffHTTPRetry) Send(req *http.Request) error {
 		req.Body.Close() // closing the original body
 	}
 
+	b := &retryAfterBackOff{BackOff: backoff.NewExponentialBackOff()}
 	opFn := func() error {
 		// recreating the request body from the buffer for each retry as if first attempt fails and
 		// a new conn is created (keep alive disabled on server for example) the req body has already been read,
@@ -69,7 +90,8 @@ func (s *BackoffHTTPRetry) Send(req *http.Request) error {
 			resp.Body.Close()
 		}()
 
-		if resp.StatusCode == http.StatusOK {
+		// Some backends, such as Loki, have no content to respond with.
+		if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
 			return nil
 		}
 
@@ -84,6 +106,9 @@ func (s *BackoffHTTPRetry) Send(req *http.Request) error {
 
 		// server error or rate limit hit - attempt retry
 		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			if s.maxRetryAfter > 0 && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+				b.retryAfter = s.retryAfter(req.Context(), resp.Header.Get("Retry-After"), time.Now())
+			}
 			return err
 		}
 
@@ -91,11 +116,46 @@ func (s *BackoffHTTPRetry) Send(req *http.Request) error {
 		return backoff.Permanent(err)
 	}
 
-	return backoff.RetryNotify(opFn, backoff.WithMaxRetries(backoff.NewExponentialBackOff(), s.maxRetries), func(err error, t time.Duration) {
+	return backoff.RetryNotify(opFn, backoff.WithContext(backoff.WithMaxRetries(b, s.maxRetries), req.Context()), func(err error, t time.Duration) {
 		s.logger.WithError(err).Warningf("%s retrying in %s", s.errMsg, t)
 	})
 }
 
+// retryAfterBackOff is an exponential backoff that waits instead for the delay a server asked
+// for, when it asked for one.
+type retryAfterBackOff struct {
+	backoff.BackOff
+	retryAfter time.Duration
+}
+
+func (b *retryAfterBackOff) NextBackOff() time.Duration {
+	next := b.BackOff.NextBackOff()
+	if next == backoff.Stop || b.retryAfter <= 0 {
+		return next
+	}
+	next, b.retryAfter = b.retryAfter, 0
+	return next
+}
+
+// retryAfter returns the delay of a Retry-After header, either in seconds or an HTTP date,
+// or 0 if there is none. The delay is capped at maxRetryAfter and at the deadline of ctx, if any.
+func (s *BackoffHTTPRetry) retryAfter(ctx context.Context, header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		delay = date.Sub(now)
+	}
+	delay = min(delay, s.maxRetryAfter)
+	if deadline, ok := ctx.Deadline(); ok {
+		delay = min(delay, deadline.Sub(now))
+	}
+	return delay
+}
+
 func (s *BackoffHTTPRetry) handleErr(err error) error {
 	if isErrorRetryable(err) {
 		return err
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/schema"
+)
+
+// runSchema writes the JSON Schema of the configuration to w. It returns the exit code of
+// the schema command.
+func runSchema(w io.Writer) int {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(configSchema()); err != nil {
+		fmt.Fprintln(w, "Couldn't write the schema:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// configSchema returns the JSON Schema of TykPumpConfiguration. The meta of a pump is
+// checked against the configuration of its type, its `type` or else its name, whatever
+// their case, the way the pumps are looked up.
+func configSchema() *schema.Schema {
+	root := schema.Reflector{TagName: "json", EnvPrefix: ENV_PREVIX}.Reflect(reflect.TypeOf(TykPumpConfiguration{}))
+	root.Schema = schema.Draft
+	root.Title = "Tyk Pump configuration"
+
+	pump := schema.Reflector{TagName: "json", EnvPrefix: PUMPS_ENV_PREFIX + "_<NAME>"}.Reflect(reflect.TypeOf(PumpConfig{}))
+	// The meta is checked by type, below, and can't be set through a single env var.
+	pump.Properties["meta"].EnvVar = ""
+	root.Defs = map[string]*schema.Schema{"pump": pump}
+
+	pumpsProp := root.Properties["pumps"]
+	pumpsProp.AdditionalProperties = &schema.Schema{Ref: "#/$defs/pump"}
+	pumpsProp.PatternProperties = map[string]*schema.Schema{}
+	pumpsProp.EnvVar = ""
+
+	types := make([]string, 0, len(pumps.AvailablePumps))
+	for pumpType := range pumps.AvailablePumps {
+		types = append(types, pumpType)
+	}
+	sort.Strings(types)
+
+	// The types are listed for the editors to complete them, and matched whatever their case.
+	typeEnum := &schema.Schema{}
+	patterns := make([]string, 0, len(types))
+	for _, pumpType := range types {
+		typeEnum.Enum = append(typeEnum.Enum, pumpType)
+		patterns = append(patterns, caseInsensitivePattern(pumpType))
+
+		conf, defaultEnv, ok := pumps.MetaConfig(pumpType)
+		if !ok {
+			continue
+		}
+
+		def := "meta." + pumpType
+		root.Defs[def] = schema.Reflector{TagName: "mapstructure", EnvPrefix: defaultEnv}.Reflect(reflect.TypeOf(conf))
+		meta := &schema.Schema{
+			Properties: map[string]*schema.Schema{"meta": {Ref: "#/$defs/" + def}},
+		}
+		pump.AllOf = append(pump.AllOf, &schema.Schema{
+			If: &schema.Schema{
+				Properties: map[string]*schema.Schema{"type": {Pattern: "^" + caseInsensitivePattern(pumpType) + "$"}},
+				Required:   []string{"type"},
+			},
+			Then: meta,
+		})
+		// The pumps without a type are of the type they're named after.
+		pumpsProp.PatternProperties["^"+caseInsensitivePattern(pumpType)+"$"] = &schema.Schema{
+			Ref:  "#/$defs/pump",
+			If:   &schema.Schema{Not: &schema.Schema{Required: []string{"type"}}},
+			Then: meta,
+		}
+	}
+	pump.Properties["type"].AnyOf = []*schema.Schema{
+		typeEnum,
+		{Pattern: "^(?:" + strings.Join(patterns, "|") + ")$"},
+	}
+
+	return root
+}
+
+// caseInsensitivePattern returns a pattern matching value whatever its case, the JSON
+// Schema patterns having no flags.
+func caseInsensitivePattern(value string) string {
+	var pattern strings.Builder
+	for _, r := range value {
+		if lower, upper := unicode.ToLower(r), unicode.ToUpper(r); lower != upper {
+			fmt.Fprintf(&pattern, "[%c%c]", lower, upper)
+		} else {
+			pattern.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return pattern.String()
+}
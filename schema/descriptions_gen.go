@@ -0,0 +1,616 @@
+// Code generated by go generate; DO NOT EDIT.
+
+package schema
+
+// descriptions are the doc comments of the configuration types and their fields.
+var descriptions = map[string]string{
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsFilters.APIIDs":                        "Filters pump data by an allow list of api_ids.",
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsFilters.OrgsIDs":                       "Filters pump data by an allow list of org_ids.",
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsFilters.ResponseCodes":                 "Filters pump data by an allow list of response_codes.",
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsFilters.SkippedAPIIDs":                 "Filters pump data by a block list of api_ids.",
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsFilters.SkippedOrgsIDs":                "Filters pump data by a block list of org_ids.",
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsFilters.SkippedResponseCodes":          "Filters pump data by a block list of response_codes.",
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsRecord":                                "AnalyticsRecord encodes the details of a request",
+	"github.com/TykTechnologies/tyk-pump/analytics.AnalyticsRecord.SchemaVersion":                  "SchemaVersion is the record schema version the producer used, see RecordSchemaVersion.\nProducers that predate versioning leave it unset. It's part of the payloads, not of\nthe records the pumps write.",
+	"github.com/TykTechnologies/tyk-pump/analytics.MCPRecord":                                      "MCPRecord is the SQL/MongoDB representation of an MCP analytics record.\nIt promotes the identity fields from MCPStats to top-level columns for\nefficient querying while embedding the full AnalyticsRecord for all\nstandard analytics dimensions.",
+	"github.com/TykTechnologies/tyk-pump/analytics.MCPRecordAggregate":                             "MCPRecordAggregate holds aggregated MCP analytics grouped by API.\nIt embeds AnalyticsRecordAggregate for all standard dimensions and adds\nMCP-specific dimension maps for method, primitive type, and primitive name.\n\nOwnerAPIID identifies which API this aggregate belongs to. It partitions\nMongoDB documents per (org, timestamp, api) so per-api Names/Methods/\nPrimitives counters from one proxy don't merge with another's via the\nupsert in MCPMongoAggregatePump. The embedded AnalyticsRecordAggregate\nalready carries an APIID map[string]*Counter for cross-API roll-ups, so\nthis is a separately named scalar to avoid a field collision.",
+	"github.com/TykTechnologies/tyk-pump/analytics.MCPSQLAnalyticsRecordAggregate":                 "MCPSQLAnalyticsRecordAggregate is the SQL representation of an MCP aggregate record.",
+	"github.com/TykTechnologies/tyk-pump/analytics.UptimeFilters.APIIDs":                           "Filters uptime data by an allow list of api_ids.",
+	"github.com/TykTechnologies/tyk-pump/analytics.UptimeFilters.OrgsIDs":                          "Filters uptime data by an allow list of org_ids.",
+	"github.com/TykTechnologies/tyk-pump/analytics.UptimeFilters.SkippedAPIIDs":                    "Filters uptime data by a block list of api_ids.",
+	"github.com/TykTechnologies/tyk-pump/analytics.UptimeFilters.SkippedOrgsIDs":                   "Filters uptime data by a block list of org_ids.",
+	"github.com/TykTechnologies/tyk-pump/logger.FileConfig":                                        "FileConfig configures a log file written besides the usual output, rotated by size.",
+	"github.com/TykTechnologies/tyk-pump/logger.FileConfig.Compress":                               "Compresses the rotated files with gzip.",
+	"github.com/TykTechnologies/tyk-pump/logger.FileConfig.Enabled":                                "Writes the logs to the file too.",
+	"github.com/TykTechnologies/tyk-pump/logger.FileConfig.MaxAge":                                 "The number of days the rotated files are kept. Defaults to 0, keeping them forever.",
+	"github.com/TykTechnologies/tyk-pump/logger.FileConfig.MaxBackups":                             "The number of rotated files kept. Defaults to 0, keeping them all.",
+	"github.com/TykTechnologies/tyk-pump/logger.FileConfig.MaxSize":                                "The size in megabytes the file is rotated at. Defaults to 100.",
+	"github.com/TykTechnologies/tyk-pump/logger.FileConfig.Path":                                   "The path of the log file.",
+	"github.com/TykTechnologies/tyk-pump/logger.parentFormatter":                                   "parentFormatter formats with the formatter of the Pump's logger, even when it's changed\nafter the child logger is created.",
+	"github.com/TykTechnologies/tyk-pump/logger.parentWriter":                                      "parentWriter writes to the output of the Pump's logger, even when it's changed after the\nchild logger is created.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.EnvPrefix":                            "Prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_MONGO_META` for Mongo Pump\n`TYK_PMP_PUMPS_UPTIME_META` for Uptime Pump\n`TYK_PMP_PUMPS_MONGOAGGREGATE_META` for Mongo Aggregate Pump\n`TYK_PMP_PUMPS_MONGOSELECTIVE_META` for Mongo Selective Pump\n`TYK_PMP_PUMPS_MONGOGRAPH_META` for Mongo Graph Pump.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoDBType":                          "Specify the target MongoDB compatible database:\n- set to `0` for MongoDB (default)\n- set to `1` for AWS Document DB\n- set to `2` for CosmosDB",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoDirectConnection":                "MongoDirectConnection informs whether to establish connections only with the specified seed servers,\nor to obtain information for the whole cluster and establish connections with further servers too.\nIf true, the client will only connect to the host provided in the ConnectionString\nand won't attempt to discover other hosts in the cluster. Useful when network restrictions\nprevent discovery, such as with SSH tunneling. Default is false.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoDriverType":                      "MongoDriverType is the type of the driver (library) to use. The valid values are: “mongo-go” and “mgo”.\nSince v1.9, the default driver is \"mongo-go\". Check out this guide to [learn about MongoDB drivers supported by Tyk Pump](https://github.com/TykTechnologies/tyk-pump#driver-type).",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoSSLAllowInvalidHostnames":        "Ignore hostname check when it differs from the original (for example with SSH tunneling).\nThe rest of the TLS verification will still be performed.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoSSLCAFile":                       "Path to the PEM file with trusted root certificates",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoSSLInsecureSkipVerify":           "Allows the use of self-signed certificates when connecting to an encrypted MongoDB database.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoSSLPEMKeyfile":                   "Path to the PEM file which contains both client certificate and private key. This is\nrequired for Mutual TLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoSessionConsistency":              "Set the consistency mode for the session, it defaults to `Strong`. The valid values are: strong, monotonic, eventual.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoURL":                             "The full URL to your MongoDB instance, this can be a clustered instance if necessary and\nshould include the database and username / password data.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.MongoUseSSL":                          "Set to true to enable Mongo SSL connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.BaseMongoConf.OmitIndexCreation":                    "Set to true to disable the default tyk index creation.",
+	"github.com/TykTechnologies/tyk-pump/pumps.CSVConf.CSVDir":                                     "The directory and the filename where the CSV data will be stored.",
+	"github.com/TykTechnologies/tyk-pump/pumps.CSVConf.EnvPrefix":                                  "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_CSV_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.CircuitBreakerConf.Cooldown":                        "The number of seconds a tripped pump is skipped for, before a single write is let\nthrough to check whether it has recovered. Defaults to 30.",
+	"github.com/TykTechnologies/tyk-pump/pumps.CircuitBreakerConf.FailureThreshold":                "The number of consecutive failed writes after which the pump is skipped, until\n`cooldown` has passed. Defaults to 0, which never skips the pump.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.Addrs":                               "The `host:port` addresses of the native protocol of the servers, tried in turn. Defaults\nto `localhost:9000`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.BatchSize":                           "The maximum number of records of an insert. Defaults to 10000, ClickHouse being at its\nbest with large inserts.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.Compression":                         "The compression of the blocks sent to the server, `lz4`, `zstd` or `none`. Defaults to\n`lz4`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.Database":                            "The database of the table. Defaults to `default`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.DialTimeout":                         "The timeout, in seconds, of the connection to the server. Defaults to 30.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.DisableTTL":                          "Stops the table from deleting the records once past their `expireAt`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.EnableRollups":                       "Creates the `<table>_1m` table, and the materialized view filling it, with the number of\nrequests and the latency quantiles of every minute, API, key and response code.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.EnvPrefix":                           "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_CLICKHOUSE_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.Password":                            "The password of the user.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.SSLCAFile":                           "Path to the PEM file with trusted CA certificates that will be used to verify the\nserver's certificate.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.SSLCertFile":                         "SSL cert file location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.SSLInsecureSkipVerify":               "Controls whether the pump client verifies the server's certificate chain and host name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.SSLKeyFile":                          "SSL cert key location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.SSLServerName":                       "SSL Server name used in the TLS connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.Table":                               "The table the records are written to. Defaults to `tyk_analytics`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.UseSSL":                              "Connects over TLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHouseConf.Username":                            "The user the pump connects as.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ClickHousePump":                                     "ClickHousePump writes the analytics records to a ClickHouse MergeTree table over the\nnative protocol, a column at a time.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.Address":                              "Address of the datadog agent including host & port.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.AsyncUDS":                             "Enable async UDS over UDP https://github.com/Datadog/datadog-go#unix-domain-sockets-client.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.AsyncUDSWriteTimeout":                 "Integer write timeout in seconds if `async_uds: true`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.Buffered":                             "Enable buffering of messages.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.BufferedMaxMessages":                  "Max messages in single datagram if `buffered: true`. Default 16.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.EnvPrefix":                            "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_DOGSTATSD_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.Namespace":                            "Prefix for your metrics to datadog.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.SampleRate":                           "Defaults to `1` which equates to `100%` of requests. To sample at `50%`, set to `0.5`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.DogStatsdConf.Tags":                                 "List of tags to be added to the metric. The possible options are listed in the below example.\n\nIf no tag is specified the fallback behavior is to use the below tags:\n- `path`\n- `method`\n- `response_code`\n- `api_version`\n- `api_name`\n- `api_id`\n- `org_id`\n- `tracked`\n- `oauth_id`\n\nNote that this configuration can generate significant charges due to the unbound nature of\nthe `path` tag.\n\n```{.json}\n\"dogstatsd\": {\n  \"type\": \"dogstatsd\",\n  \"meta\": {\n    \"address\": \"localhost:8125\",\n    \"namespace\": \"pump\",\n    \"async_uds\": true,\n    \"async_uds_write_timeout_seconds\": 2,\n    \"buffered\": true,\n    \"buffered_max_messages\": 32,\n    \"sample_rate\": 0.5,\n    \"tags\": [\n      \"method\",\n      \"response_code\",\n      \"api_version\",\n      \"api_name\",\n      \"api_id\",\n      \"org_id\",\n      \"tracked\",\n      \"path\",\n      \"oauth_id\"\n    ]\n  }\n},\n```\n\nOn startup, you should see the loaded configs when initializing the dogstatsd pump\n```\n[May 10 15:23:44]  INFO dogstatsd: initializing pump\n[May 10 15:23:44]  INFO dogstatsd: namespace: pump.\n[May 10 15:23:44]  INFO dogstatsd: sample_rate: 50%\n[May 10 15:23:44]  INFO dogstatsd: buffered: true, max_messages: 32\n[May 10 15:23:44]  INFO dogstatsd: async_uds: true, write_timeout: 2s\n```",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchBulkConfig.BulkActions":                "Specifies the number of requests needed to flush the data and send it to ES. Defaults to\n1000 requests. If it is needed, can be disabled with -1.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchBulkConfig.BulkSize":                   "Specifies the size (in bytes) needed to flush the data and send it to ES. Defaults to 5MB.\nIf it is needed, can be disabled with -1.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchBulkConfig.FlushInterval":              "Specifies the time in seconds to flush the data and send it to ES. Default disabled.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchBulkConfig.Workers":                    "Number of workers. Defaults to 1.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.AuthAPIKey":                       "API Key used for APIKey auth in ES. It's send to ES in the Authorization header as ApiKey base64(auth_api_key_id:auth_api_key)",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.AuthAPIKeyID":                     "API Key ID used for APIKey auth in ES. It's send to ES in the Authorization header as ApiKey base64(auth_api_key_id:auth_api_key)",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.BulkConfig":                       "Batch writing trigger configuration. Each option is an OR with eachother:",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.DecodeBase64":                     "Allows for the base64 bits to be decode before being passed to ES.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.DisableBulk":                      "Disable batch writing. Defaults to false.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.DocumentType":                     "The type of the document that is created in ES. Defaults to \"tyk_analytics\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.ElasticsearchURL":                 "If sniffing is disabled, the URL that all data will be sent to. Defaults to\n\"http://localhost:9200\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.EnableSniffing":                   "If sniffing is enabled, the \"elasticsearch_url\" will be used to make a request to get a\nlist of all the nodes in the cluster, the returned addresses will then be used. Defaults to\n`false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.EnvPrefix":                        "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_ELASTICSEARCH_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.ExtendedStatistics":               "If set to `true` will include the following additional fields: Raw Request, Raw Response and\nUser Agent.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.GenerateID":                       "When enabled, generate _id for outgoing records. This prevents duplicate records when\nretrying ES.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.IndexName":                        "The name of the index that all the analytics data will be placed in. Defaults to\n\"tyk_analytics\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.MCPIndexName":                     "When set, Model Context Protocol (MCP) records are written to this index instead of the default `IndexName`. Supports the same rolling-index date suffix as `IndexName` when `RollingIndex` is enabled. Defaults to `\"\"` (empty string), meaning all records go to `IndexName`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.Password":                         "Basic auth password. It's send to ES in the Authorization header as username:password encoded in base64.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.RollingIndex":                     "Appends the date to the end of the index name, so each days data is split into a different\nindex name. E.g. tyk_analytics-2016.02.28. Defaults to `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.SSLCAFile":                        "Path to the PEM file with trusted CA certificates that will be used to verify the Elasticsearch server's certificate.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.SSLCertFile":                      "Can be used to set custom certificate file for authentication with Elastic Search.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.SSLInsecureSkipVerify":            "Controls whether the pump client verifies the Elastic Search server's certificate chain and hostname.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.SSLKeyFile":                       "Can be used to set custom key file for authentication with Elastic Search.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.UptimeIndexName":                  "The name of the index uptime records are placed in, when the pump has `uptime` enabled.\nSupports the same rolling-index date suffix as `IndexName`. Defaults to\n\"tyk_uptime_analytics\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.UseSSL":                           "Enables SSL connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.Username":                         "Basic auth username. It's send to ES in the Authorization header as username:password encoded in base64.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ElasticsearchConf.Version":                          "Specifies the ES version. Use \"3\" for ES 3.X, \"5\" for ES 5.X, \"6\" for ES 6.X, \"7\" for ES\n7.X . Defaults to \"3\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.GraphSQLConf.TableName":                             "TableName is a configuration field unique to the sql-graph pump, this field specifies\nthe name of the sql table to be created/used for the pump in the cases of non-sharding\nin the case of sharding, it specifies the table prefix",
+	"github.com/TykTechnologies/tyk-pump/pumps.GraylogConf.EnvPrefix":                              "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_GRAYLOG_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.GraylogConf.GraylogHost":                            "Graylog host.",
+	"github.com/TykTechnologies/tyk-pump/pumps.GraylogConf.GraylogPort":                            "Graylog port.",
+	"github.com/TykTechnologies/tyk-pump/pumps.GraylogConf.Tags":                                   "List of tags to be added to the metric. The possible options are listed in the below example.\n\nIf no tag is specified the fallback behaviour is to don't send anything.\nThe possible values are:\n- `path`\n- `method`\n- `response_code`\n- `api_version`\n- `api_name`\n- `api_id`\n- `org_id`\n- `tracked`\n- `oauth_id`\n- `raw_request`\n- `raw_response`\n- `request_time`\n- `ip_address`",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPump":                                         "HybridPump allows to send analytics to MDCB over RPC",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.APIKey":                              "This the API key of a user used to authenticate and authorize the Hybrid Pump access through MDCB.\nThe user should be a standard Dashboard user with minimal privileges so as to reduce any risk if the user is compromised.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.Aggregated":                          "Send aggregated analytics data to Tyk MDCB",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.CallTimeout":                         "Hybrid pump RPC calls timeout in seconds. Defaults to `10` seconds.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.ConnectionString":                    "MDCB URL connection string",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.EnableMCPAggregation":                "Controls whether MCP analytics are aggregated and sent to MDCB via a separate RPC call. If `pumps.hybrid.meta.aggregated` is set to true and `enable_mcp_aggregation` is set to false, MCP analytics are not aggregated and are completely dropped. If `pumps.hybrid.meta.aggregated` is false, this flag is ignored entirely and all analytics (including MCP) are sent as raw, unaggregated data.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.EnvPrefix":                           "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_HYBRID_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.IgnoreTagPrefixList":                 "Specifies prefixes of tags that should be ignored if `aggregated` is set to `true`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.RPCKey":                              "Your organization ID to connect to the MDCB installation.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.RPCPoolSize":                         "Hybrid pump connection pool size. Defaults to `5`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.SSLInsecureSkipVerify":               "Skip SSL verification",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.StoreAnalyticsPerMinute":             "Determines if the aggregations should be made per minute (true) or per hour (false) if `aggregated` is set to `true`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.TrackAllPaths":                       "Specifies if it should store aggregated data for all the endpoints if `aggregated` is set to `true`. By default, `false`\nwhich means that only store aggregated data for `tracked endpoints`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.UseSSL":                              "Use SSL to connect to Tyk MDCB",
+	"github.com/TykTechnologies/tyk-pump/pumps.HybridPumpConf.aggregationTime":                     "aggregationTime is to specify the frequency of the aggregation in minutes if `aggregated` is set to `true`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.Addr":                                   "InfluxDB2 pump host.",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.BucketName":                             "InfluxDB2 pump bucket name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.CreateMissingBucket":                    "Create the bucket if it doesn't exist",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.EnvPrefix":                              "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_INFLUX2_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.Fields":                                 "Define which Analytics fields should be sent to InfluxDB2. Check the available\nfields in the example below. Default value is `[\"method\",\n\"path\", \"response_code\", \"api_key\", \"time_stamp\", \"api_version\", \"api_name\", \"api_id\",\n\"org_id\", \"oauth_id\", \"raw_request\", \"request_time\", \"raw_response\", \"ip_address\"]`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.Flush":                                  "Flush data to InfluxDB2 as soon as the pump receives it",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.NewBucketConfig":                        "New bucket configuration",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.OrgName":                                "InfluxDB2 pump organization name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.Tags":                                   "List of tags to be added to the metric.",
+	"github.com/TykTechnologies/tyk-pump/pumps.Influx2Conf.Token":                                  "InfluxDB2 pump database token.",
+	"github.com/TykTechnologies/tyk-pump/pumps.InfluxConf.Addr":                                    "InfluxDB pump host.",
+	"github.com/TykTechnologies/tyk-pump/pumps.InfluxConf.DatabaseName":                            "InfluxDB pump database name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.InfluxConf.EnvPrefix":                               "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_INFLUX_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.InfluxConf.Fields":                                  "Define which Analytics fields should be sent to InfluxDB. Check the available\nfields in the example below. Default value is `[\"method\",\n\"path\", \"response_code\", \"api_key\", \"time_stamp\", \"api_version\", \"api_name\", \"api_id\",\n\"org_id\", \"oauth_id\", \"raw_request\", \"request_time\", \"raw_response\", \"ip_address\"]`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.InfluxConf.Password":                                "InfluxDB pump database password.",
+	"github.com/TykTechnologies/tyk-pump/pumps.InfluxConf.Tags":                                    "List of tags to be added to the metric.",
+	"github.com/TykTechnologies/tyk-pump/pumps.InfluxConf.Username":                                "InfluxDB pump database username.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.Algorithm":                                "SASL algorithm. It's the algorithm specified for scram mechanism. It could be sha-512 or sha-256.\nDefaults to \"sha-256\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.BatchBytes":                               "BatchBytes controls the maximum size of a request in bytes before it's sent to a partition.\nIf the value is 0, the writer will use the default value from kafka-go library (1MB).",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.Broker":                                   "The list of brokers used to discover the partitions available on the kafka cluster. E.g.\n\"localhost:9092\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.ClientId":                                 "Unique identifier for client connections established with Kafka.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.Compressed":                               "Enable \"github.com/golang/snappy\" codec to be used to compress Kafka messages. By default\nis `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.EnvPrefix":                                "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_KAFKA_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.MetaData":                                 "Can be used to set custom metadata inside the kafka message.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.Password":                                 "SASL password.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.SASLMechanism":                            "SASL mechanism configuration. Only \"plain\" and \"scram\" are supported.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.SSLCAFile":                                "Path to the PEM file with trusted CA certificates that will be used to verify the Kafka server's certificate.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.SSLCertFile":                              "Can be used to set custom certificate file for authentication with kafka.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.SSLInsecureSkipVerify":                    "Controls whether the pump client verifies the kafka server's certificate chain and host\nname.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.SSLKeyFile":                               "Can be used to set custom key file for authentication with kafka.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.Timeout":                                  "Timeout is the maximum amount of seconds to wait for a connect or write to complete.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.Topic":                                    "The topic that the writer will produce messages to.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.UptimeTopic":                              "The topic uptime records are produced to, when the pump has `uptime` enabled. Defaults\nto `topic`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.UseSSL":                                   "Enables SSL connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KafkaConf.Username":                                 "SASL username.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KinesisConf.BatchSize":                              "Each PutRecords (the function used in this pump)request can support up to 500 records.\nEach record in the request can be as large as 1 MiB, up to a limit of 5 MiB for the entire request, including partition keys.\nEach shard can support writes up to 1,000 records per second, up to a maximum data write total of 1 MiB per second.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KinesisConf.EnvPrefix":                              "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_KINESIS_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.KinesisConf.KMSKeyID":                               "The KMS Key ID used for server-side encryption of the Kinesis stream.\nDefaults to an empty string if not provided.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KinesisConf.Region":                                 "AWS Region the Kinesis stream targets",
+	"github.com/TykTechnologies/tyk-pump/pumps.KinesisConf.StreamName":                             "A name to identify the stream. The stream name is scoped to the AWS account used by the application\nthat creates the stream. It is also scoped by AWS Region.\nThat is, two streams in two different AWS accounts can have the same name.\nTwo streams in the same AWS account but in two different Regions can also have the same name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.KinesisPump":                                        "KinesisPump is a Tyk Pump that sends analytics records to AWS Kinesis.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LogzioPumpConfig.CheckDiskSpace":                    "Set the sender to check if it crosses the maximum allowed disk usage. Default value is\n`true`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LogzioPumpConfig.DiskThreshold":                     "Set disk queue threshold, once the threshold is crossed the sender will not enqueue the\nreceived logs. Default value is `98` (percentage of disk).",
+	"github.com/TykTechnologies/tyk-pump/pumps.LogzioPumpConfig.DrainDuration":                     "Set drain duration (flush logs on disk). Default value is `3s`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LogzioPumpConfig.EnvPrefix":                         "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_LOGZIO_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.LogzioPumpConfig.QueueDir":                          "The directory for the queue.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LogzioPumpConfig.Token":                             "Token for sending data to your logzio account.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LogzioPumpConfig.URL":                               "If you do not want to use the default Logzio url i.e. when using a proxy. Default is\n`https://listener.logz.io:8071`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.BatchSize":                                 "The maximum number of records of a push. Defaults to 1000.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.BearerToken":                               "The token of the bearer authentication, used instead of the basic one.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.Encoding":                                  "The encoding of the pushes, `protobuf`, snappy compressed, or `json`. Defaults to\n`protobuf`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.EnvPrefix":                                 "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_LOKI_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.Labels":                                    "The fields of the records the streams are labelled with: `api_id`, `api_name`,\n`api_version`, `org_id`, `host`, `listen_path`, `method`, `response_code` or\n`status_class`, such as `2xx`. Defaults to `api_name`, `org_id`, `method` and\n`status_class`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.MaxRetries":                                "The number of times a push failing with a 429, a 5xx or a network error is retried,\nwith an exponential backoff. Defaults to 0.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.Password":                                  "The password of the basic authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.SSLCAFile":                                 "Path to the PEM file with trusted CA certificates that will be used to verify Loki's\ncertificate.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.SSLCertFile":                               "SSL cert file location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.SSLInsecureSkipVerify":                     "Controls whether the pump client verifies Loki's certificate chain and host name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.SSLKeyFile":                                "SSL cert key location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.SSLServerName":                             "SSL Server name used in the TLS connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.StaticLabels":                              "Labels with the same value for every stream. Defaults to `job` set to `tyk-pump`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.StructuredMetadata":                        "The fields of the records the entries carry as structured metadata, a label field or\none of `path`, `ip_address`, `api_key`, `oauth_id`, `alias`, `user_agent`,\n`request_time`, `latency_total` and `latency_upstream`. They're in the log line\nanyway, structured metadata requiring Loki 3.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.TenantID":                                  "The tenant the records are pushed to, sent as the `X-Scope-OrgID` header to\nmulti-tenant Loki.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.Timeout":                                   "The timeout, in seconds, of a push. Defaults to 10.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.URL":                                       "The URL of Loki, such as `http://loki:3100`. The records are pushed to its\n`/loki/api/v1/push` endpoint.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiConf.Username":                                  "The user of the basic authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.LokiPump":                                           "LokiPump pushes the analytics records to Grafana Loki, as JSON log lines of streams\nlabelled with the low-cardinality fields of the records.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MCPMongoAggregatePump":                              "MCPMongoAggregatePump writes aggregated MCP analytics to MongoDB.\nIt follows the same double-write pattern as MongoAggregatePump:\nwriting to both an org-specific collection and (optionally) a mixed collection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MCPMongoPump":                                       "MCPMongoPump writes raw MCP analytics records to a dedicated MongoDB collection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MCPSQLAggregatePump":                                "MCPSQLAggregatePump writes aggregated MCP analytics to a dedicated SQL table.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MCPSQLAggregatePump.backgroundIndexCreated":         "this channel is used to signal that the background index creation has finished - this is used for testing",
+	"github.com/TykTechnologies/tyk-pump/pumps.MCPSQLConf":                                         "MCPSQLConf holds the configuration for the MCP SQL pump.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MCPSQLConf.TableName":                               "TableName specifies the SQL table name for MCP analytics records.\nIn sharding mode, this is the table prefix.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MCPSQLPump":                                         "MCPSQLPump writes raw MCP analytics records to a dedicated SQL table.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.ApplicationID":                           "Moesif Application Id. You can find your Moesif Application Id from\n[_Moesif Dashboard_](https://www.moesif.com/) -> _Top Right Menu_ -> _API Keys_ . Moesif\nrecommends creating separate Application Ids for each environment such as Production,\nStaging, and Development to keep data isolated.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.AuthorizationHeaderName":                 "An optional request header field name to used to identify the User in Moesif. Default value\nis `authorization`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.AuthorizationUserIdField":                "An optional field name use to parse the User from authorization header in Moesif. Default\nvalue is `sub`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.BulkConfig":                              "Batch writing trigger configuration.\n  * `\"event_queue_size\"` - (optional) An optional field name which specify the maximum\nnumber of events to hold in queue before sending to Moesif. In case of network issues when\nnot able to connect/send event to Moesif, skips adding new events to the queue to prevent\nmemory overflow. Type: int. Default value is `10000`.\n  * `\"batch_size\"` - (optional) An optional field name which specify the maximum batch size\nwhen sending to Moesif. Type: int. Default value is `200`.\n  * `\"timer_wake_up_seconds\"` - (optional) An optional field which specifies a time (every n\nseconds) how often background thread runs to send events to moesif. Type: int. Default value\nis `2` seconds.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.CompanyIDHeader":                         "An optional field name to identify Company (Account) from a request or response header.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.DisableCaptureRequestBody":               "An option to disable logging of request body. Default value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.DisableCaptureResponseBody":              "An option to disable logging of response body. Default value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.EnableBulk":                              "Set this to `true` to enable `bulk_config`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.EnvPrefix":                               "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_MOESIF_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.RequestBodyMasks":                        "An option to mask a specific - request body field.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.RequestHeaderMasks":                      "An option to mask a specific request header field.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.ResponseBodyMasks":                       "An option to mask a specific response body field.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.ResponseHeaderMasks":                     "An option to mask a specific response header field.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MoesifConf.UserIDHeader":                            "An optional field name to identify User from a request or response header.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.AggregationTime":                 "Determines the amount of time the aggregations should be made (in minutes). It defaults to the max value is 60 and the minimum is 1.\nIf StoreAnalyticsPerMinute is set to true, this field will be skipped.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.EnableAggregateSelfHealing":      "Determines if the self healing will be activated or not.\nSelf Healing allows pump to handle Mongo document's max-size errors by creating a new document when the max-size is reached.\nIt also divide by 2 the AggregationTime field to avoid the same error in the future.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.IgnoreAggregationsList":          "This list determines which aggregations are going to be dropped and not stored in the collection.\nPosible values are: \"APIID\",\"errors\",\"versions\",\"apikeys\",\"oauthids\",\"geo\",\"tags\",\"endpoints\",\"keyendpoints\",\n\"oauthendpoints\", and \"apiendpoints\".",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.IgnoreTagPrefixList":             "Specifies prefixes of tags that should be ignored.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.StoreAnalyticsPerMinute":         "Determines if the aggregations should be made per minute (true) or per hour (false).",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.ThresholdLenTagList":             "Determines the threshold of amount of tags of an aggregation. If the amount of tags is superior to the threshold,\nit will print an alert.\nDefaults to 1000.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.TrackAllPaths":                   "Specifies if it should store aggregated data for all the endpoints. By default, `false`\nwhich means that only store aggregated data for `tracked endpoints`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoAggregateConf.UseMixedCollection":              "If set to `true` the Mongo Aggregate pump will send analytics to two collections:\n- `z_tyk_analyticz_aggregate_{ORG ID}`\n- `tyk_analytics_aggregates`\nWhen set to 'false' your pump will only store analytics to `z_tyk_analyticz_aggregate_{ORG ID}`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoConf.CollectionCapEnable":                      "Enable collection capping. It's used to set a maximum size of the collection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoConf.CollectionCapMaxSizeBytes":                "Amount of bytes of the capped collection in 64bits architectures.\nDefaults to 5GB.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoConf.CollectionName":                           "Specifies the mongo collection name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoConf.MaxDocumentSizeBytes":                     "Maximum document size. If the document exceed this value, it will be skipped.\nDefaults to 10Mb.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoConf.MaxInsertBatchSizeBytes":                  "Maximum insert batch size for mongo selective pump. If the batch we are writing surpasses this value, it will be sent in multiple batches.\nDefaults to 10Mb.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoSelectiveConf.MaxDocumentSizeBytes":            "Maximum document size. If the document exceed this value, it will be skipped.\nDefaults to 10Mb.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MongoSelectiveConf.MaxInsertBatchSizeBytes":         "Maximum insert batch size for mongo selective pump. If the batch we are writing surpass this value, it will be send in multiple batchs.\nDefaults to 10Mb.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MysqlConfig.DefaultStringSize":                      "Default size for string fields. Defaults to `256`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MysqlConfig.DisableDatetimePrecision":               "Disable datetime precision, which not supported before MySQL 5.6.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MysqlConfig.DontSupportRenameColumn":                "`change` when rename column, rename column not supported before MySQL 8, MariaDB.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MysqlConfig.DontSupportRenameIndex":                 "Drop & create when rename index, rename index not supported before MySQL 5.7, MariaDB.",
+	"github.com/TykTechnologies/tyk-pump/pumps.MysqlConfig.SkipInitializeWithVersion":              "Auto configure based on currently MySQL version.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.CredentialsFile":                           "The credentials file, with the JWT and the NKey seed of the user, of the servers with\ndecentralised authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.Encoding":                                  "The encoding of the records, `json` or `protobuf`. Defaults to `json`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.EnvPrefix":                                 "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_NATS_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.NKeySeedFile":                              "The file of the NKey seed of the user, of the servers with NKey authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.Password":                                  "The password of the password authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.SSLCAFile":                                 "Path to the PEM file with trusted CA certificates that will be used to verify the servers'\ncertificates.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.SSLCertFile":                               "SSL cert file location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.SSLInsecureSkipVerify":                     "Controls whether the pump client verifies the servers' certificate chain and host name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.SSLKeyFile":                                "SSL cert key location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.SSLServerName":                             "SSL Server name used in the TLS connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.Stream":                                    "The stream the subjects are expected to be captured by, checked when the pump starts and\nby the acknowledgements. Defaults to any stream.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.Subject":                                   "The subject of the records, with the `{{org_id}}`, `{{api_id}}`, `{{api_version}}`,\n`{{method}}` and `{{response_code}}` of each. Defaults to\n`tyk.analytics.{{org_id}}.{{api_id}}`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.Timeout":                                   "The timeout, in seconds, of the acknowledgements of the records written at once.\nDefaults to 10.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.Token":                                     "The token of the token authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.URL":                                       "The URL of the NATS servers, comma separated. Defaults to `nats://127.0.0.1:4222`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.UseSSL":                                    "Enables TLS on the connections, implied by the other `ssl_*` options and `tls://` URLs.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSConf.Username":                                  "The user of the password authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NATSPump":                                           "NATSPump publishes the analytics records to NATS JetStream, a message per record on a\nsubject of its organisation and API, waiting for the acknowledgements of the streams.",
+	"github.com/TykTechnologies/tyk-pump/pumps.NewBucket":                                          "Configuration required to create the Bucket if it doesn't already exist\nSee https://docs.influxdata.com/influxdb/v2.1/api/#operation/PostBuckets",
+	"github.com/TykTechnologies/tyk-pump/pumps.NewBucket.Description":                              "A description visible on the InfluxDB2 UI",
+	"github.com/TykTechnologies/tyk-pump/pumps.NewBucket.RetentionRules":                           "Rules to expire or retain data. No rules means data never expires.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.Compression":                               "The compression of the exports, `gzip` or `none`. Defaults to `none`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.DisableLogs":                               "Stops sending the records as log records, for the pump to only send metrics or traces.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.EnableMetrics":                             "Sends the request count, `tyk.api.requests`, and the latency histogram,\n`http.server.request.duration`, of every API, method and response code as cumulative\nmetrics.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.EnableTraces":                              "Sends a span per request, with a `gateway` and an `upstream` child span laid out one\nafter the other from the latencies of the record. The log records are linked to them.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.Endpoint":                                  "The OTLP receiver of the collector, as `host:port` for `grpc` and as a URL for `http`, the\n`/v1/logs`, `/v1/metrics` and `/v1/traces` paths appended to it. Defaults to\n`localhost:4317` for `grpc` and `http://localhost:4318` for `http`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.EnvPrefix":                                 "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_OTLP_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.Headers":                                   "Headers sent along with every export, such as an API key.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.Insecure":                                  "Sends without TLS over `grpc`. Over `http`, the scheme of `endpoint` decides.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.LatencyBuckets":                            "The bounds, in seconds, of the buckets of the latency histogram. Defaults to the ones\nthe HTTP semantic conventions advise, from 0.005 to 10.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.Protocol":                                  "The protocol the records are sent with, `grpc` or `http` for HTTP/protobuf. Defaults to\n`grpc`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.ResourceAttributes":                        "Other resource attributes, such as `deployment.environment.name`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.SSLCAFile":                                 "Path to the PEM file with trusted CA certificates that will be used to verify the\ncollector's certificate.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.SSLCertFile":                               "SSL cert file location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.SSLInsecureSkipVerify":                     "Controls whether the pump client verifies the collector's certificate chain and host\nname.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.SSLKeyFile":                                "SSL cert key location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.SSLServerName":                             "SSL Server name used in the TLS connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPConf.ServiceName":                               "The `service.name` resource attribute. Defaults to `tyk-gateway`, the records being the\nones of its requests.",
+	"github.com/TykTechnologies/tyk-pump/pumps.OTLPPump":                                           "OTLPPump sends the analytics records to an OpenTelemetry collector over OTLP: every\nrecord as a log record and, optionally, as a span, along with the request count and\nlatency of every API as metrics.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetConf.Columns":                                "The columns of the files, all of them when empty. The organisation isn't one of them,\nit's in the directory of the files.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetConf.Compression":                            "The compression of the files, `zstd`, `snappy` or `none`. Defaults to `zstd`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetConf.Directory":                              "The directory of the files, created if missing. The files of the records of an\norganisation, day and hour are in its `org_id=<org>/date=<YYYY-MM-DD>/hour=<HH>`\ndirectory.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetConf.EnvPrefix":                              "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_PARQUET_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetConf.MaxFileAge":                             "The time, in seconds, a file is rolled after. Defaults to 300.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetConf.MaxFileSize":                            "The size, in MB, a file is rolled at. Defaults to 128.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetConf.RowGroupSize":                           "The maximum number of records of a row group, buffered in memory until it's written.\nDefaults to 100000.",
+	"github.com/TykTechnologies/tyk-pump/pumps.ParquetPump":                                        "ParquetPump writes the analytics records to Parquet files, in a directory partitioned\nby organisation, day and hour the way Hive lays out its tables.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PostgresConfig.PreferSimpleProtocol":                "Disables implicit prepared statement usage.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusConf.Addr":                                "The address and port on which Tyk Pump exposes the Prometheus metrics endpoint for Prometheus to scrape, in the form {HOST}:{PORT}. For example `localhost:9090`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusConf.AggregateObservations":               "This will enable an experimental feature that will aggregate the histogram metrics request time values before exposing them to prometheus.\nEnabling this will reduce the CPU usage of your prometheus pump but you will loose histogram precision. Experimental.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusConf.CustomMetrics":                       "Custom Prometheus metrics.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusConf.DisabledMetrics":                     "Metrics to exclude from exposition. Currently, excludes only the base metrics.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusConf.EnvPrefix":                           "Prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_PROMETHEUS_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusConf.Path":                                "The path to the Prometheus collection. For example `/metrics`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusConf.TrackAllPaths":                       "Specifies if it should expose aggregated metrics for all the endpoints. By default, `false`\nwhich means that all APIs endpoints will be counted as 'unknown' unless the API uses the track endpoint plugin.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.Buckets":                           "Defines the buckets into which observations are counted. The type is float64 array and by default, [1, 2, 5, 7, 10, 15, 20, 25, 30, 40, 50, 60, 70, 80, 90, 100, 200, 300, 400, 500, 1000, 2000, 5000, 10000, 30000, 60000]",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.Help":                              "Description text of the custom metric. For example: `HTTP status codes per API`",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.Labels":                            "Defines the partitions in the metrics. For example: ['response_code','api_name'].\nThe available labels are: `[\"host\",\"method\",\n\"path\", \"response_code\", \"api_key\", \"time_stamp\", \"api_version\", \"api_name\", \"api_id\",\n\"org_id\", \"oauth_id\",\"request_time\", \"ip_address\", \"alias\",\n\"mcp_method\", \"mcp_primitive_type\", \"mcp_primitive_name\"]`.\nMCP labels are only populated for MCP records; non-MCP records produce empty strings.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.MCPOnly":                           "MCPOnly marks a metric as MCP-specific: it is only processed for records where IsMCPRecord() is true.\nWhen set to true on a custom metric, the metric will only be updated for MCP analytics records.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.MetricType":                        "Determines the type of the metric. There's currently 2 available options: `counter` or `histogram`.\nIn case of histogram, you can only modify the labels since it always going to use the request_time.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.Name":                              "The name of the custom metric. For example: `tyk_http_status_per_api_name`",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.ObfuscateAPIKeys":                  "Controls whether the pump client should hide the API key. In case you still need substring\nof the value, check the next option. Default value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusMetric.ObfuscateAPIKeysLength":            "Define the number of the characters from the end of the API key. The `obfuscate_api_keys`\nshould be set to `true`. Default value is `4`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.PrometheusPump.TotalStatusMetrics":                  "Per service",
+	"github.com/TykTechnologies/tyk-pump/pumps.RetentionRule.EverySeconds":                         "Duration in seconds for how long data will be kept in the database. 0 means infinite.",
+	"github.com/TykTechnologies/tyk-pump/pumps.RetentionRule.ShardGroupDurationSeconds":            "Shard duration measured in seconds.",
+	"github.com/TykTechnologies/tyk-pump/pumps.RetentionRule.Type":                                 "Retention rule type. For example \"expire\"",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.AWSEndpoint":                                 "The URL of an S3 compatible object storage, such as MinIO or Ceph, rather than AWS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.AWSKey":                                      "The access key ID of the pump. Defaults to the credentials of the AWS environment.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.AWSRegion":                                   "The region of the bucket. Defaults to the one of the AWS environment, or `us-east-1`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.AWSSecret":                                   "The secret access key of the pump.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.AWSToken":                                    "The session token of the pump, only required with temporary credentials.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.Bucket":                                      "The bucket the objects are uploaded to.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.EnvPrefix":                                   "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_S3_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.FlushInterval":                               "The time, in seconds, the records are buffered for at most. Defaults to 60.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.ForcePathStyle":                              "Addresses the bucket in the path of the URLs rather than in their host, as MinIO\nexpects by default.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.Format":                                      "The format of the objects, `json`, gzipped NDJSON, or `parquet`. Defaults to `json`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.KeyTemplate":                                 "The key of the objects, with the `{{org_id}}`, `{{api_id}}`, `{{yyyy}}`, `{{mm}}`,\n`{{dd}}` and `{{hh}}` of their records and a `{{uuid}}`, such as\n`{{org_id}}/{{yyyy}}/{{mm}}/{{dd}}/{{uuid}}.json.gz`. Defaults to\n`{{org_id}}/{{yyyy}}/{{mm}}/{{dd}}/{{uuid}}` followed by `.json.gz` or `.parquet`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.MaxBufferedRecords":                          "The number of records kept buffered when their uploads fail, to upload them again\nwith the next ones. The oldest ones are dropped beyond it. Defaults to 10 times\n`max_records`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.MaxRecords":                                  "The number of buffered records the objects are uploaded at. Defaults to 10000.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.PartSize":                                    "The size, in MB, of the parts of the objects uploaded in several parts, the larger\nones. Defaults to 8, and can't be less than 5.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.SSEKMSKeyID":                                 "The KMS key of the `aws:kms` and `aws:kms:dsse` encryptions. Defaults to the AWS\nmanaged one.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.ServerSideEncryption":                        "The server-side encryption of the objects, `AES256`, `aws:kms` or `aws:kms:dsse`.\nDefaults to the one of the bucket.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Conf.Timeout":                                     "The timeout, in seconds, of the uploads. Defaults to 60.",
+	"github.com/TykTechnologies/tyk-pump/pumps.S3Pump":                                             "S3Pump buffers the analytics records and uploads them to S3, or any S3 compatible\nobject storage, as gzipped NDJSON or Parquet objects.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLAggregatePump.backgroundIndexCreated":            "this channel is used to signal that the background index creation has finished - this is used for testing",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLAggregatePumpConf.EnvPrefix":                     "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_SQLAGGREGATE_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLAggregatePumpConf.IgnoreTagPrefixList":           "Specifies prefixes of tags that should be ignored.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLAggregatePumpConf.OmitIndexCreation":             "Set to true to disable the default tyk index creation.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLAggregatePumpConf.StoreAnalyticsPerMinute":       "Determines if the aggregations should be made per minute instead of per hour.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLAggregatePumpConf.TrackAllPaths":                 "Specifies if it should store aggregated data for all the endpoints. By default, `false`\nwhich means that only store aggregated data for `tracked endpoints`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.BatchSize":                                  "Specifies the amount of records that are going to be written each batch. Type int. By\ndefault, it writes 1000 records max per batch.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.ConnectionString":                           "Specifies the connection string to the database.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.EnvPrefix":                                  "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_SQL_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.LogLevel":                                   "Specifies the SQL log verbosity. The possible values are: `info`,`error` and `warning`. By\ndefault, the value is `silent`, which means that it won't log any SQL query.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.MigrateShardedTables":                       "Specifies whether to migrate all existing sharded tables to latest schema during Pump initialization (default: false).\nWhen true, on initialization Pump will scan and migrate all sharded tables to the latest schema.\nWhen false, existing tables will not be migrated and may miss columns included in the latest schema.\nIf there are a large number of existing tables, or those tables are in use by other services, there may be a performance impact from the migration. We recommend testing carefully.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.Mysql":                                      "Mysql configurations.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.Postgres":                                   "Postgres configurations.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.TableSharding":                              "Specifies if all the analytics records are going to be stored in one table or in multiple\ntables (one per day). By default, `false`. If `false`, all the records are going to be\nstored in `tyk_aggregated` table. Instead, if it's `true`, all the records of the day are\ngoing to be stored in `tyk_aggregated_YYYYMMDD` table, where `YYYYMMDD` is going to change\ndepending on the date.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLConf.Type":                                       "The only supported and tested types are `postgres` and `mysql`.\nFrom v1.12.0, we no longer support `sqlite` as a storage type.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQLPump.backgroundIndexCreated":                     "this channel is used to signal that the background index creation has finished - this is used for testing",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf":                                            "SQSConf represents the configuration structure for the Tyk Pump SQS (Simple Queue Service) pump.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSDelaySeconds":                            "AWSDelaySeconds configures the delay (in seconds) before messages become available for processing.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSEndpoint":                                "AWSEndpoint is the custom endpoint URL for AWS SQS, if applicable.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSKey":                                     "AWSKey is the AWS access key ID used for authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSMessageGroupID":                          "AWSMessageGroupID specifies the message group ID for ordered processing within the SQS queue.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSMessageIDDeduplicationEnabled":           "AWSMessageIDDeduplicationEnabled enables/disables message deduplication based on unique IDs.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSRegion":                                  "AWSRegion sets the AWS region where the SQS queue is located.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSSQSBatchLimit":                           "AWSSQSBatchLimit sets the maximum number of messages in a single batch when sending to the SQS queue.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSSecret":                                  "AWSSecret is the AWS secret key used for authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.AWSToken":                                   "AWSToken is the AWS session token used for authentication.\nThis is only required when using temporary credentials.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.EnvPrefix":                                  "EnvPrefix specifies the prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_SQS_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.SQSConf.QueueName":                                  "QueueName specifies the name of the AWS Simple Queue Service (SQS) queue for message delivery.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkClient":                                       "SplunkClient contains Splunk client methods.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPump":                                         "SplunkPump is a Tyk Pump driver for Splunk.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig":                                   "SplunkPumpConfig contains the driver configuration parameters.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.BatchMaxContentLength":             "Max content length in bytes to be sent in batch requests. It should match the\n`max_content_length` configured in Splunk. If the purged analytics records size don't reach\nthe amount of bytes, they're send anyways in each `purge_loop`. Default value is 838860800\n(~ 800 MB), the same default value as Splunk config.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.CollectorToken":                    "Address of the datadog agent including host & port.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.CollectorURL":                      "Endpoint the Pump will send analytics too.  Should look something like:\n`https://splunk:8088/services/collector/event`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.EnableBatch":                       "If this is set to `true`, pump is going to send the analytics records in batch to Splunk.\nDefault value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.EnvPrefix":                         "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_SPLUNK_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.Fields":                            "Define which Analytics fields should participate in the Splunk event. Check the available\nfields in the example below. Default value is `[\"method\",\n\"path\", \"response_code\", \"api_key\", \"time_stamp\", \"api_version\", \"api_name\", \"api_id\",\n\"org_id\", \"oauth_id\", \"raw_request\", \"request_time\", \"raw_response\", \"ip_address\"]`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.IgnoreTagPrefixList":               "Choose which tags to be ignored by the Splunk Pump. Keep in mind that the tag name and value\nare hyphenated. Default value is `[]`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.MaxRetries":                        "MaxRetries represents the maximum amount of retries to attempt if failed to send requests to splunk HEC.\nDefault value is `0`",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.ObfuscateAPIKeys":                  "Controls whether the pump client should hide the API key. In case you still need substring\nof the value, check the next option. Default value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.ObfuscateAPIKeysLength":            "Define the number of the characters from the end of the API key. The `obfuscate_api_keys`\nshould be set to `true`. Default value is `0`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.SSLCAFile":                         "Path to the PEM file with trusted CA certificates that will be used to verify the Splunk server's certificate.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.SSLCertFile":                       "SSL cert file location.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.SSLInsecureSkipVerify":             "Controls whether the pump client verifies the Splunk server's certificate chain and host name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.SSLKeyFile":                        "SSL cert key location.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SplunkPumpConfig.SSLServerName":                     "SSL Server name used in the TLS connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StatsdConf.Address":                                 "Address of statsd including host & port.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StatsdConf.EnvPrefix":                               "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_STATSD_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.StatsdConf.Fields":                                  "Define which Analytics fields should have its own metric calculation.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StatsdConf.SeparatedMethod":                         "Allows to have a separated method field instead of having it embedded in the path field.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StatsdConf.Tags":                                    "List of tags to be added to the metric.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StdOutConf.EnvPrefix":                               "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_STDOUT_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.StdOutConf.Format":                                  "Format of the analytics logs. Default is `text` if `json` is not explicitly specified. When\nJSON logging is used all pump logs to stdout will be JSON.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StdOutConf.LogFieldName":                            "Root name of the JSON object the analytics record is nested in.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StdOutConf.UptimeLogFieldName":                      "Root name of the JSON object uptime records are nested in, when the pump has `uptime`\nenabled. Defaults to `tyk-uptime-record`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.StdOutConf.UseLegacyPayloadFormat":                  "Use the legacy formatting of raw_request and raw_response as escaped strings rather than JSON formatting.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SyslogConf.EnvPrefix":                               "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_SYSLOG_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.SyslogConf.LogLevel":                                "The severity level, an integer from 0-7, based off the Standard:\n[Syslog Severity Levels](https://en.wikipedia.org/wiki/Syslog#Severity_level).",
+	"github.com/TykTechnologies/tyk-pump/pumps.SyslogConf.NetworkAddr":                             "Host & Port combination of your syslog daemon ie: `\"localhost:5140\"`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.SyslogConf.Tag":                                     "Prefix tag\n\nWhen working with FluentD, you should provide a\n[FluentD Parser](https://docs.fluentd.org/input/syslog) based on the OS you are using so\nthat FluentD can correctly read the logs.\n\n```{.json}\n\"syslog\": {\n  \"name\": \"syslog\",\n  \"meta\": {\n    \"transport\": \"udp\",\n    \"network_addr\": \"localhost:5140\",\n    \"log_level\": 6,\n    \"tag\": \"syslog-pump\"\n  }\n```",
+	"github.com/TykTechnologies/tyk-pump/pumps.SyslogConf.Transport":                               "Possible values are `udp, tcp, tls` in string form.",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.AWSRegion":                       "The aws region that contains the timestream database",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.DatabaseName":                    "The timestream database name that contains the table being written to",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.Dimensions":                      "A filter of all the dimensions that will be written to the table. The possible options are\n[\"Method\",\"Host\",\"Path\",\"RawPath\",\"APIKey\",\"APIVersion\",\"APIName\",\"APIID\",\"OrgID\",\"OauthID\"]",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.EnvPrefix":                       "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_TIMESTREAM_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.Measures":                        "A filter of all the measures that will be written to the table. The possible options are\n[\"ContentLength\",\"ResponseCode\",\"RequestTime\",\"NetworkStats.OpenConnections\",\n\"NetworkStats.ClosedConnection\",\"NetworkStats.BytesIn\",\"NetworkStats.BytesOut\",\n\"Latency.Total\",\"Latency.Upstream\",\"GeoData.City.GeoNameID\",\"IPAddress\",\n\"GeoData.Location.Latitude\",\"GeoData.Location.Longitude\",\"UserAgent\",\"RawRequest\",\"RawResponse\",\n\"RateLimit.Limit\",\"Ratelimit.Remaining\",\"Ratelimit.Reset\",\n\"GeoData.Country.ISOCode\",\"GeoData.City.Names\",\"GeoData.Location.TimeZone\"]",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.NameMappings":                    "A name mapping for both Dimensions and Measures names. It's not required",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.ReadGeoFromRequest":              "If set true, we will try to read geo information from the headers if\nvalues aren't found on the analytic record . Default value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.TableName":                       "The table name where the data is going to be written",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.WriteRateLimit":                  "Set to true in order to save any of the `RateLimit` measures. Default value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.TimestreamPumpConf.WriteZeroValues":                 "Set to true, in order to save numerical values with value zero. Default value is `false`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.BatchSize":                              "The maximum number of records of a request. Defaults to 100.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.BearerToken":                            "The token of the bearer authentication, used instead of the basic one.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.ContentType":                            "The content type of the bodies. Defaults to `application/x-ndjson` with the `ndjson`\nformat, and to `application/json` otherwise.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.EnvPrefix":                              "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_WEBHOOK_META`",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.Format":                                 "The format of the bodies: `json`, a JSON array of the records, `ndjson`, a record per\nline, or `template`, the output of `template`. Defaults to `json`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.HMACSecret":                             "The secret the bodies are signed with, using HMAC-SHA256. Defaults to no signature.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.Headers":                                "Headers added to the requests.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.MaxBatchBytes":                          "The maximum size, in bytes, of the bodies of the requests, but for the ones of a single\nrecord. Defaults to 0, no maximum.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.MaxRetries":                             "The number of times a request failing with a 429, a 5xx or a network error is retried,\nwith an exponential backoff, or after the delay of the `Retry-After` header of the\nresponse. Defaults to 0.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.MaxRetryAfter":                          "The longest time, in seconds, a retry waits for the `Retry-After` header of a response.\nDefaults to 60.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.Method":                                 "The method of the requests, `POST`, `PUT` or `PATCH`. Defaults to `POST`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.Password":                               "The password of the basic authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.SSLCAFile":                              "Path to the PEM file with trusted CA certificates that will be used to verify the\nendpoint's certificate.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.SSLCertFile":                            "SSL cert file location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.SSLInsecureSkipVerify":                  "Controls whether the pump client verifies the endpoint's certificate chain and host name.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.SSLKeyFile":                             "SSL cert key location, for mTLS.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.SSLServerName":                          "SSL Server name used in the TLS connection.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.SignatureHeader":                        "The header of the signature of the bodies, `sha256=` followed by the hex encoded HMAC.\nDefaults to `X-Tyk-Signature`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.Template":                               "The Go `text/template` of the bodies of the `template` format, executed with the records\nof a batch, or with each record when `template_per_record` is set. Its `json` function\nreturns the JSON of a value, such as `{\"text\": {{json .APIName}}}`.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.TemplatePerRecord":                      "Executes `template` with each record rather than with the batch, the bodies being the\noutputs of the records of a batch, one per line.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.Timeout":                                "The timeout, in seconds, of a request. Defaults to 10.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.URL":                                    "The URL the records are sent to.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookConf.Username":                               "The user of the basic authentication.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WebhookPump":                                        "WebhookPump sends the analytics records to an HTTP endpoint in batches, as a JSON array,\nNDJSON or the output of a template.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WriteStats":                                         "WriteStats tracks the outcome of the recent writes of a pump, and trips its circuit\nbreaker after too many consecutive failed writes. It's safe for concurrent use.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WriteStats.results":                                 "results holds whether each of the last errorRateWindow writes failed, as a ring.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WriteStatsSnapshot":                                 "WriteStatsSnapshot is the state of a WriteStats at a point in time.",
+	"github.com/TykTechnologies/tyk-pump/pumps.WriteStatsSnapshot.ErrorRate":                       "ErrorRate is the ratio of failed writes among the most recent ones, from 0 to 1.",
+	"github.com/TykTechnologies/tyk-pump/pumps.clickHouseColumn":                                   "clickHouseColumn is a column of the table of the records.",
+	"github.com/TykTechnologies/tyk-pump/pumps.clickHouseColumn.values":                            "values returns the values of the column for records.",
+	"github.com/TykTechnologies/tyk-pump/pumps.histogramCounter":                                   "histogramCounter is a helper struct to mantain the totalRequestTime and hits in memory",
+	"github.com/TykTechnologies/tyk-pump/pumps.lokiStream":                                         "lokiStream is a stream of the entries with the same labels.",
+	"github.com/TykTechnologies/tyk-pump/pumps.metaSpec":                                           "metaSpec describes how a pump decodes its meta.",
+	"github.com/TykTechnologies/tyk-pump/pumps.metaSpec.configs":                                   "configs returns the configurations the pump decodes its meta into, the first one\ngetting the env var overrides. A key is unknown when none of them uses it.",
+	"github.com/TykTechnologies/tyk-pump/pumps.metaSpec.legacyEnv":                                 "legacyEnv is the prefix of the deprecated env vars the pump still applies, if any.",
+	"github.com/TykTechnologies/tyk-pump/pumps.monthEncodePlan":                                    "monthEncodePlan converts time.Month to int for pgx encoding.\npgx v5's TryWrapBuiltinTypeEncodePlan matches time.Month as fmt.Stringer\n(producing \"May\") before TryWrapFindUnderlyingTypeEncodePlan can convert it\nto its underlying int. This plan is prepended to the encode chain so the\nint conversion happens first. See TT-16980 and https://github.com/jackc/pgx/issues/2157",
+	"github.com/TykTechnologies/tyk-pump/pumps.otlpGRPCClient":                                     "otlpGRPCClient exports the signals over gRPC.",
+	"github.com/TykTechnologies/tyk-pump/pumps.otlpHTTPClient":                                     "otlpHTTPClient exports the signals over HTTP/protobuf.",
+	"github.com/TykTechnologies/tyk-pump/pumps.otlpMetrics":                                        "otlpMetrics are the cumulative request counts and latency histograms of the APIs since\nthe pump started.",
+	"github.com/TykTechnologies/tyk-pump/pumps.otlpSpanIDs":                                        "otlpSpanIDs identify the span of a request.",
+	"github.com/TykTechnologies/tyk-pump/pumps.parquetColumn":                                      "parquetColumn is a column of the files, its values taken from the records by value.",
+	"github.com/TykTechnologies/tyk-pump/pumps.parquetFile":                                        "parquetFile is a file being written, under a temporary name until it's rolled.",
+	"github.com/TykTechnologies/tyk-pump/pumps.pumpLog":                                            "pumpLog is the logger of a pump, with a level of its own, and the context of its lines.",
+	"github.com/TykTechnologies/tyk-pump/pumps.tracingTransport":                                   "tracingTransport injects the trace context of every request it sends, for the clients\nbuilding their requests themselves.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Config":                                        "Config sets where the analytics payloads Pump can't decode are kept, so they can be\nretried with `tyk-pump quarantine retry` once whatever broke them is fixed.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Config.Dir":                                    "The directory `file` quarantines write to. It's created if it doesn't exist.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Config.KeyName":                                "The list `redis` quarantines append to, under the analytics storage key prefix.\nDefaults to `tyk-pump-quarantine`.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Config.Type":                                   "Where undecodable payloads are written, either `file` or `redis`. Leave it empty to\ndiscard them, which is the default.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry":                                         "Entry is a payload that couldn't be decoded, together with what's needed to retry it.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry.Error":                                   "Error is why the payload couldn't be decoded.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry.KeyName":                                 "KeyName is the analytics key the payload was read from.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry.Payload":                                 "Payload is the payload as read from the analytics key, byte for byte.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry.QuarantinedAt":                           "QuarantinedAt is when the payload was last quarantined.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry.Serializer":                              "Serializer is the name of the serializer of the key the payload was read from.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry.WrittenBy":                               "WrittenBy are the names of the pumps that wrote the payload when it was retried, the\nnext retries only writing it to the others.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.Entry.ref":                                     "ref identifies the entry in the store it was read from, so it can be removed.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.FileStore":                                     "FileStore keeps every entry as two files in a directory: the payload, verbatim, and a\nJSON file holding the rest of the entry. The JSON file is written last, so an entry\nwithout one was never completely written and is ignored.",
+	"github.com/TykTechnologies/tyk-pump/quarantine.RedisStore":                                    "RedisStore keeps entries JSON encoded in a list, oldest first.",
+	"github.com/TykTechnologies/tyk-pump/server.AdminPump":                                         "AdminPump is a pump as listed by the admin API.",
+	"github.com/TykTechnologies/tyk-pump/server.AdminPump.Config":                                  "Config is the effective config of the pump, with its secrets redacted.",
+	"github.com/TykTechnologies/tyk-pump/server.AuthConfig.Token":                                  "The token of `bearer`, sent in the Authorization header.",
+	"github.com/TykTechnologies/tyk-pump/server.AuthConfig.Type":                                   "`basic` or `bearer`. Requests aren't authenticated when empty.",
+	"github.com/TykTechnologies/tyk-pump/server.AuthConfig.Username":                               "The credentials of `basic`.",
+	"github.com/TykTechnologies/tyk-pump/server.Config":                                            "Config secures the health check server.",
+	"github.com/TykTechnologies/tyk-pump/server.Config.Auth":                                       "Authenticates every request but the health and readiness probes.",
+	"github.com/TykTechnologies/tyk-pump/server.Config.BindAddress":                                "The address the server listens on. Defaults to every interface.",
+	"github.com/TykTechnologies/tyk-pump/server.Config.PrivatePort":                                "Moves the profiling and admin routes to a second listener on this port, bound to\nlocalhost and served over HTTP.",
+	"github.com/TykTechnologies/tyk-pump/server.Config.TLS":                                        "Serves over HTTPS rather than HTTP.",
+	"github.com/TykTechnologies/tyk-pump/server.PumpHealth.CheckError":                             "CheckError is why the pump failed its health check, if it has one.",
+	"github.com/TykTechnologies/tyk-pump/server.PumpHealth.ErrorRate":                              "ErrorRate is the ratio of failed writes among the most recent ones, from 0 to 1.",
+	"github.com/TykTechnologies/tyk-pump/server.PumpHealth.LastWriteError":                         "LastWriteError is the error of the last write, empty if it succeeded.",
+	"github.com/TykTechnologies/tyk-pump/server.Report":                                            "Report is the detailed health of the Pump, served by the readiness endpoint and by the\nhealth check endpoint when called with `?verbose`.",
+	"github.com/TykTechnologies/tyk-pump/server.Report.Reasons":                                    "Reasons lists why the Pump isn't ready.",
+	"github.com/TykTechnologies/tyk-pump/server.Report.Status":                                     "Status is StatusOK when the Pump is ready, StatusFail otherwise.",
+	"github.com/TykTechnologies/tyk-pump/server.TLSConfig.CAFile":                                  "The CA the client certificates are verified with.",
+	"github.com/TykTechnologies/tyk-pump/server.TLSConfig.CertFile":                                "The certificate and key the server is served with. TLS is enabled when they are set.",
+	"github.com/TykTechnologies/tyk-pump/server.TLSConfig.VerifyClientCert":                        "Rejects the clients without a certificate signed by the CA. Otherwise, it's only\nverified when they present one.",
+	"github.com/TykTechnologies/tyk-pump/storage.IAMAuthConfig":                                    "Configure the cloud provider's Identity and Access Management (IAM) authentication\nfor temporal storage (Redis/Valkey). If enabled, the standard username and password are ignored.",
+	"github.com/TykTechnologies/tyk-pump/storage.IAMAuthConfig.Enabled":                            "Set to true to use IAM-based authentication for this storage connection.",
+	"github.com/TykTechnologies/tyk-pump/storage.IAMAuthConfig.Provider":                           "Provider selects the cloud IAM provider. Currently supported: \"gcp\"\n(GCP Memorystore for Valkey and Redis Cluster).",
+	"github.com/TykTechnologies/tyk-pump/storage.IAMAuthConfig.ServiceAccount":                     "ServiceAccount, for GCP, optionally impersonates this service account to\nmint tokens instead of using the ambient Application Default Credentials\nidentity. Leave empty to use the workload's own identity (Workload Identity\non GKE, or GOOGLE_APPLICATION_CREDENTIALS).",
+	"github.com/TykTechnologies/tyk-pump/storage.IAMAuthConfig.TokenRefreshBeforeExpiry":           "The access token issued by the IAM will be refreshed before expiry.\nSet the time period before expiry when that refresh will take place as\na human readable duration (for example \"2m30s\", \"5m\").\nDefaults to \"5m\" (five minutes) when empty.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig":                            "nolint:govet",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Addrs":                      "Use instead of the host value if you're running a Redis cluster with multiple instances.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Database":                   "Database name.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.EnableCluster":              "Enable this option if you are using a cluster instance. Default is `false`.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Host":                       "Host value. For example: \"localhost\".",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Hosts":                      "Deprecated: use Addrs instead.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.IAMAuth":                    "Configure the cloud provider's Identity and Access Management (IAM)\nauthentication solution for temporal storage (for example, GCP MemoryStore IAM)\ninstead of the traditional fixed username and password.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.KeyPrefix":                  "Prefix the key names. Defaults to \"analytics-\".",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.MasterName":                 "Sentinel master name.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.MaxActive":                  "Maximum number of connections allocated by the pool at a given time. When zero, there is no\nlimit on the number of connections in the pool. Defaults to 500.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.MaxIdle":                    "Maximum number of idle connections in the pool.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Password":                   "Database password.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Port":                       "Port value. For example: 6379.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.RedisKeyPrefix":             "Prefix the key names. Defaults to \"analytics-\".\nDeprecated: use KeyPrefix instead.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.RedisSSLInsecureSkipVerify": "Set this to `true` to tell Pump to ignore database's cert validation.\nDeprecated: use SSLInsecureSkipVerify instead.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.RedisUseSSL":                "Setting this to true to use SSL when connecting to the DB.\nDeprecated: use UseSSL instead.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.SSLCAFile":                  "Path to the CA file.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.SSLCertFile":                "Path to the cert file.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.SSLInsecureSkipVerify":      "Set this to `true` to tell Pump to ignore database's cert validation.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.SSLKeyFile":                 "Path to the key file.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.SSLMaxVersion":              "Maximum supported TLS version. Defaults to TLS 1.3, valid values are TLS 1.0, 1.1, 1.2, 1.3.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.SSLMinVersion":              "Minimum supported TLS version. Defaults to TLS 1.2, valid values are TLS 1.0, 1.1, 1.2, 1.3.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.SentinelPassword":           "Sentinel password.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Timeout":                    "How long to allow for new connections to be established (in milliseconds). Defaults to 5sec.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Type":                       "Deprecated.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.UseSSL":                     "Setting this to true to use SSL when connecting to the DB.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageConfig.Username":                   "Database username.",
+	"github.com/TykTechnologies/tyk-pump/storage.TemporalStorageHandler":                           "TemporalStorageHandler is a storage manager that uses non data-persistent databases, like Redis.",
+	"github.com/TykTechnologies/tyk-pump/tail.Config.BufferSize":                                   "The number of records buffered for each client. A client falling this far behind is\ndisconnected. Defaults to 1000.",
+	"github.com/TykTechnologies/tyk-pump/tail.Config.Enabled":                                      "Enables the /tail endpoint. It's only served when a secret is set too.",
+	"github.com/TykTechnologies/tyk-pump/tail.Config.RateLimit":                                    "The maximum number of records per second streamed to each client. The ones above it\nare skipped. Defaults to 100.",
+	"github.com/TykTechnologies/tyk-pump/tail.Config.RedactFields":                                 "The fields of the records cleared before they are streamed, by their JSON name.\nDefaults to raw_request, raw_response, api_key and ip_address.",
+	"github.com/TykTechnologies/tyk-pump/tail.Config.Secret":                                       "The secret the clients must send in the X-Tyk-Authorization header.",
+	"github.com/TykTechnologies/tyk-pump/tail.Event":                                               "Event is a record streamed to the clients, along with the pump it was written to.",
+	"github.com/TykTechnologies/tyk-pump/tail.Filter":                                              "Filter selects the records a client is streamed. Its zero value selects them all.",
+	"github.com/TykTechnologies/tyk-pump/tail.Hub":                                                 "Hub hands the records published to it to the subscribed clients.",
+	"github.com/TykTechnologies/tyk-pump/tail.Hub.count":                                           "count mirrors len(subscribers), for Publish to return straight away when nobody is\nconnected without taking the lock.",
+	"github.com/TykTechnologies/tyk-pump/tail.subscriber":                                          "subscriber is a connected client.",
+	"github.com/TykTechnologies/tyk-pump/tail.subscriber.dropped":                                  "dropped is closed when the client falls behind, for its stream to end.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.ConnectionTimeout":                         "The number of seconds an export may take. Defaults to 1.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.Enabled":                                   "Enables the tracing of the purge pipeline. Disabled by default.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.Endpoint":                                  "The OTLP collector the spans are exported to, as `host:port` for `grpc` and as a URL\nfor `http`.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.Exporter":                                  "The protocol the spans are exported with, `grpc` or `http`. Defaults to `grpc`.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.Headers":                                   "Headers sent along with the exported spans, such as an API key.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.Insecure":                                  "Exports the spans without TLS.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.ResourceName":                              "The service name the spans are reported under. Defaults to `tyk-pump`.",
+	"github.com/TykTechnologies/tyk-pump/tracing.Config.Sampling":                                  "Which purge cycles are traced.",
+	"github.com/TykTechnologies/tyk-pump/tracing.SamplingConfig.ParentBased":                       "Follows the sampling decision of the parent span, when there's one.",
+	"github.com/TykTechnologies/tyk-pump/tracing.SamplingConfig.Rate":                              "The ratio of the purge cycles traced with `TraceIDRatioBased`, from 0 to 1.",
+	"github.com/TykTechnologies/tyk-pump/tracing.SamplingConfig.Type":                              "`AlwaysOn`, `AlwaysOff` or `TraceIDRatioBased`. Defaults to `AlwaysOn`.",
+	"main.AdminAPIConf.Enabled":                                                                    "Enables the admin API. It's only served when `secret` is set too.",
+	"main.AdminAPIConf.Secret":                                                                     "The secret admin API requests must carry.",
+	"main.DogStatsDSink":                                                                           "DogStatsDSink sends the instrumentation to DogStatsD. Unlike StatsDSink, the job and its\nkey values are sent as tags rather than in the metric names.",
+	"main.DogStatsDSinkConf.Address":                                                               "The `host:port` of the DogStatsD agent, or `unix:///path/to/socket`.",
+	"main.DogStatsDSinkConf.Prefix":                                                                "The prefix of the metric names, without a trailing dot.",
+	"main.DogStatsDSinkConf.Tags":                                                                  "The tags added to every metric.",
+	"main.InstrumentationConf.DogStatsD":                                                           "Sends the instrumentation to DogStatsD, with tags.",
+	"main.InstrumentationConf.JSONLog":                                                             "Writes the instrumentation as JSON lines, for environments without a metrics backend.",
+	"main.InstrumentationConf.OTLP":                                                                "Exports the instrumentation as OpenTelemetry metrics over OTLP.",
+	"main.InstrumentationConf.StatsD":                                                              "Sends the instrumentation to StatsD.",
+	"main.JSONLogSink":                                                                             "JSONLogSink writes the instrumentation as JSON lines, one per metric, for environments\nwithout a metrics backend.",
+	"main.JSONLogSinkConf.Path":                                                                    "The file the metrics are appended to. Defaults to the standard output.",
+	"main.JSONLogSinkConf.Prefix":                                                                  "The prefix of the metric names, without a trailing dot.",
+	"main.JSONLogSinkConf.Tags":                                                                    "The tags added to every metric.",
+	"main.OTLPSink":                                                                                "OTLPSink exports the instrumentation as OpenTelemetry metrics: the events as counters,\nthe timings as histograms in milliseconds and the gauges as gauges, with the job and its\nkey values as attributes.",
+	"main.OTLPSinkConf.Endpoint":                                                                   "The OTLP collector the metrics are exported to, as `host:port` for `grpc` and as a\nURL for `http`.",
+	"main.OTLPSinkConf.Exporter":                                                                   "The protocol the metrics are exported with, `grpc` or `http`. Defaults to `grpc`.",
+	"main.OTLPSinkConf.Headers":                                                                    "Headers sent along with the exported metrics, such as an API key.",
+	"main.OTLPSinkConf.Insecure":                                                                   "Exports the metrics without TLS.",
+	"main.OTLPSinkConf.Interval":                                                                   "The number of seconds between two exports. Defaults to 10.",
+	"main.OTLPSinkConf.Prefix":                                                                     "The prefix of the metric names, without a trailing dot.",
+	"main.OTLPSinkConf.Tags":                                                                       "The attributes added to every metric.",
+	"main.PumpConfig.CircuitBreaker":                                                               "Stops writing to the pump for a while after too many consecutive failed writes, so a\nbroken sink doesn't hold up every purge:\n```{.json}\n\"circuit_breaker\": {\n  \"failure_threshold\": 5,\n  \"cooldown\": 30\n}\n```\nDisabled by default.",
+	"main.PumpConfig.DecodeRawRequest":                                                             "Setting this to true allows the Raw Request to be decoded from base 64 for all pumps. This is set to false by default.",
+	"main.PumpConfig.DecodeRawResponse":                                                            "Setting this to true allows the Raw Response to be decoded from base 64 for all pumps. This is set to false by default.",
+	"main.PumpConfig.Filters":                                                                      "This feature adds a new configuration field in each pump called filters and its structure is\nthe following:\n```{.json}\n\"filters\":{\n  \"api_ids\":[],\n  \"org_ids\":[],\n  \"response_codes\":[],\n  \"skip_api_ids\":[],\n  \"skip_org_ids\":[],\n  \"skip_response_codes\":[]\n}\n```\nThe fields api_ids, org_ids and response_codes works as allow list (APIs and orgs where we\nwant to send the analytics records) and the fields skip_api_ids, skip_org_ids and\nskip_response_codes works as block list.\n\nThe priority is always block list configurations over allow list.\n\nAn example of configuration would be:\n```{.json}\n\"csv\": {\n \"type\": \"csv\",\n \"filters\": {\n   \"org_ids\": [\"org1\",\"org2\"]\n },\n \"meta\": {\n   \"csv_dir\": \"./bar\"\n }\n}\n```",
+	"main.PumpConfig.IgnoreFields":                                                                 "IgnoreFields defines a list of analytics fields that will be ignored when writing to the pump.\nThis can be used to avoid writing sensitive information to the Database, or data that you don't really need to have.\nThe field names must be the same as the JSON tags of the analytics record fields.\nFor example: `[\"api_key\", \"api_version\"]`.",
+	"main.PumpConfig.LogLevel":                                                                     "The log level of the pump, apart from the rest of the Pump, to debug a single pump. The\npossible values are `debug`, `info`, `warn` and `error`. Defaults to the level of the\nPump, set in `log_level`.",
+	"main.PumpConfig.MaxRecordSize":                                                                "Defines maximum size (in bytes) for Raw Request and Raw Response logs, this value defaults\nto 0. If it is not set then tyk-pump will not trim any data and will store the full\ninformation. This can also be set at a pump level. For example:\n```{.json}\n\"csv\": {\n  \"type\": \"csv\",\n  \"max_record_size\":1000,\n  \"meta\": {\n    \"csv_dir\": \"./\"\n  }\n}\n```",
+	"main.PumpConfig.Meta":                                                                         "Meta is a map of configuration values that are specific to each pump. For example, the\n`csv` pump requires a `csv_dir` value to be set, that need to be set in the `meta` map.",
+	"main.PumpConfig.Name":                                                                         "The name of the pump. This is used to identify the pump in the logs.\nDeprecated, use `type` instead.",
+	"main.PumpConfig.OmitDetailedRecording":                                                        "Reduce the size of the traffic logs generated for each request by setting this to true. Tyk Pump will\nthen not include the `raw_request` and `raw_response` in the logs. Defaults to `false`.",
+	"main.PumpConfig.Timeout":                                                                      "By default, a pump will wait forever for each write operation to complete; you can configure an optional timeout by setting the configuration option `timeout`.\nIf you have deployed multiple pumps, then you can configure each timeout independently. The timeout is in seconds and defaults to 0.\n\nThe timeout is configured within the main pump config as shown here; note that this example would configure a 5 second timeout:\n```{.json}\n\"pump_name\": {\n  ...\n  \"timeout\":5,\n  \"meta\": {...}\n}\n```\n\nTyk will inform you if the pump's write operation is taking longer than the purging loop (configured via `purge_delay`) as this will mean that data is purged before being written to the target data sink.\n\nIf there is no timeout configured and pump's write operation is taking longer than the purging loop, the following warning log will be generated:\n`Pump {pump_name} is taking more time than the value configured of purge_delay. You should try to set a timeout for this pump.`\n\nIf there is a timeout configured, but pump's write operation is still taking longer than the purging loop, the following warning log will be generated:\n`Pump {pump_name} is taking more time than the value configured of purge_delay. You should try lowering the timeout configured for this pump.`.",
+	"main.PumpConfig.Type":                                                                         "Sets the pump type. This is needed when the pump key does not equal to the pump name type.\nCurrent valid types are: `mongo`, `mongo-pump-selective`, `mongo-pump-aggregate`, `csv`,\n`elasticsearch`, `influx`, `influx2`, `moesif`, `statsd`, `segment`, `graylog`, `splunk`, `hybrid`, `prometheus`,\n`logzio`, `dogstatsd`, `kafka`, `syslog`, `sql`, `sql_aggregate`, `stdout`, `timestream`, `mongo-graph`,\n`sql-graph`, `sql-graph-aggregate`, `resurfaceio`.",
+	"main.PumpConfig.Uptime":                                                                       "Set this to `true` to also write the uptime data to this pump, besides the uptime pump\nconfigured in `uptime_pump_config`. Supported by the `elasticsearch`, `kafka`,\n`prometheus`, `statsd` and `stdout` pumps. Uptime data isn't purged when\n`dont_purge_uptime_data` is `true`.",
+	"main.PumpConfig.UptimeFilters":                                                                "Filters the uptime data written to this pump, the same way `filters` does for analytics\nrecords:\n```{.json}\n\"uptime_filters\":{\n  \"api_ids\":[],\n  \"org_ids\":[],\n  \"skip_api_ids\":[],\n  \"skip_org_ids\":[]\n}\n```",
+	"main.ReadinessConf.MaxPumpErrorRate":                                                          "The ratio, from 0 to 1, of failed writes among its most recent ones above which a pump\nis unhealthy. Defaults to 0.5.",
+	"main.ReadinessConf.MaxPurgeAge":                                                               "The number of seconds without a successful purge after which the Pump isn't ready.\nDefaults to three times `purge_delay`.",
+	"main.ReadinessConf.MaxUnhealthyPumps":                                                         "The number of unhealthy pumps tolerated before the Pump isn't ready. A pump is\nunhealthy when its error rate is too high, its circuit breaker is open or it fails its\nhealth check. Defaults to 0.",
+	"main.StatsDSink.prefixBuffers":                                                                "map of {job,event,suffix} to a re-usable buffer prefixed with the key.\nSince each timing/gauge has a unique component (the time), we'll truncate to the prefix, write the timing,\nand write the statsD suffix (eg, \"|ms\\n\"). Then copy that to the UDP buffer.",
+	"main.StatsDSinkConf.Address":                                                                  "The `host:port` of the StatsD server.",
+	"main.StatsDSinkConf.Prefix":                                                                   "The prefix of the metric names, without a trailing dot.",
+	"main.StatsDSinkOptions.Prefix":                                                                "Prefix is something like \"metroid\"\nEvents emitted to StatsD would be metroid.myevent.wat\nEg, don't include a trailing dot in the prefix.\nIt can be \"\", that's fine.",
+	"main.StatsDSinkOptions.SanitizationFunc":                                                      "SanitizationFunc sanitizes jobs and events before sending them to statsd",
+	"main.StatsDSinkOptions.SkipNestedEvents":                                                      "SkipNestedEvents will skip {events,timers,gauges} from sending the job.event version\nand will only send the event version.",
+	"main.StatsDSinkOptions.SkipTopLevelEvents":                                                    "SkipTopLevelEvents will skip {events,timers,gauges} from sending the event version\nand will only send the job.event version.",
+	"main.TykPumpConfiguration.AdminAPI":                                                           "Enables the admin API, served on the health check port under `/admin`. It lists the\npumps with their config, pauses and resumes them, changes their log level, triggers a\npurge and reports the number of records waiting in the temporal storage. Requests\nmust carry `secret` in the `X-Tyk-Authorization` header, and every action is logged\nwith the `admin-audit` prefix. Disabled by default.",
+	"main.TykPumpConfiguration.AnalyticsSerializers":                                               "Sets the serializers whose analytics keys the Pump purges. Each serializer reads its\nown key, the base analytics key followed by the serializer suffix. Supported values\nare `msgpack`, `protobuf` and `json`, and their compressed variants suffixed with\n`_zstd` or `_snappy`, e.g. `protobuf_zstd`. Defaults to `[\"msgpack\", \"protobuf\"]`.\n\nPayloads are decoded according to their format header when they carry one, so\nproducers using different formats can share a key.",
+	"main.TykPumpConfiguration.AnalyticsStorageConfig":                                             "Example Temporal storage configuration:\n```{.json}\n  \"analytics_storage_config\": {\n    \"type\": \"redis\",\n    \"host\": \"localhost\",\n    \"port\": 6379,\n    \"hosts\": null,\n    \"username\": \"\",\n    \"password\": \"\",\n    \"database\": 0,\n    \"optimisation_max_idle\": 100,\n    \"optimisation_max_active\": 0,\n    \"enable_cluster\": false,\n    \"use_ssl\": false,\n    \"ssl_insecure_skip_verify\": false\n  },\n```",
+	"main.TykPumpConfiguration.AnalyticsStorageType":                                               "Sets the type of storage from which the Pump will fetch data.\nThe supported value is `redis`, which covers both Redis and the Redis-compatible Valkey.\nPump will default to assume Redis if no alternative is provided, so this configuration can be ignored at present.",
+	"main.TykPumpConfiguration.DecodeRawRequest":                                                   "This option was intended to decode raw request payloads from base64 for all Pumps. However, it was never implemented and therefore has no functional effect. It has now been deprecated.\nfor all pumps. This is set to false by default.\nDeprecated: Use pump level raw_request_decoded configuration instead.",
+	"main.TykPumpConfiguration.DecodeRawResponse":                                                  "This option was intended to decode raw response payloads from base64 for all Pumps. However, it was never implemented and therefore has no functional effect. It has now been deprecated.\nDeprecated: Use pump level raw_response_decoded configuration instead.",
+	"main.TykPumpConfiguration.DontPurgeUptimeData":                                                "A default Uptime Pump will transfer uptime metrics which are used by Tyk Dashboard.\nIf this is not required, you can disable that Pump by setting this option to `true`.",
+	"main.TykPumpConfiguration.EnableMetrics":                                                      "Serves the metrics of the Pump itself in the Prometheus format on the health check\nport: the records popped per key, decoded and failed per serializer, and filtered,\nsent and failed per pump, the write latency per pump, the purge duration, the backlog\nof the temporal storage and the size of the quarantine. They are kept apart from the\nanalytics metrics of the Prometheus pump. Disabled by default.",
+	"main.TykPumpConfiguration.HTTPProfile":                                                        "Expose profiling information to support debugging of Tyk Pump. This operates in the same way as for Tyk Gateway, as explained [here](/api-management/troubleshooting-debugging).",
+	"main.TykPumpConfiguration.HealthCheckEndpointName":                                            "HEADER Health Check\nFrom v2.9.4, we have introduced a `/health` endpoint to confirm the Pump is running. You\nneed to configure the following settings. This returns a HTTP 200 OK response if the Pump is\nrunning.\nThe default is \"hello\".",
+	"main.TykPumpConfiguration.HealthCheckEndpointPort":                                            "The default port is 8083.",
+	"main.TykPumpConfiguration.HealthCheckServer":                                                  "Secures the health check server: the address it's bound to, TLS, with optional client\ncertificate verification, and basic or bearer authentication of every route but the\nhealth and readiness probes. `private_port` moves the profiling and admin routes to a\nsecond listener, bound to localhost. For example:\n```{.json}\n\"health_check_server\": {\n  \"bind_address\": \"0.0.0.0\",\n  \"tls\": {\n    \"cert_file\": \"/certs/pump.crt\",\n    \"key_file\": \"/certs/pump.key\",\n    \"ca_file\": \"/certs/ca.crt\",\n    \"verify_client_cert\": false\n  },\n  \"auth\": {\n    \"type\": \"bearer\",\n    \"token\": \"change-me\"\n  },\n  \"private_port\": 8084\n}\n```",
+	"main.TykPumpConfiguration.IncludeDir":                                                         "A directory of pump configuration files, `*.yaml`, `*.yml` or `*.json`, merged into\n`pumps`. Each file maps pump names to their configuration, the way `pumps` does, so\nevery pump can be kept in its own file. The files are merged in the order of their\nnames, objects deeply, and a setting given different values by two files, or by a\nfile and the configuration file, is an error. A relative path is relative to the\ndirectory of the configuration file.",
+	"main.TykPumpConfiguration.Instrumentation":                                                    "Sends the instrumentation of the Pump to several sinks at once, each with its own\nprefix and tags: StatsD, DogStatsD, OTLP metrics and JSON lines. Unlike\n`statsd_connection_string`, the sinks enabled here don't need `TYK_INSTRUMENTATION`.\nFor example:\n```{.json}\n\"instrumentation\": {\n  \"dogstatsd\": {\n    \"enabled\": true,\n    \"address\": \"localhost:8125\",\n    \"prefix\": \"tyk_pump\",\n    \"tags\": {\"env\": \"production\"}\n  },\n  \"json_log\": {\n    \"enabled\": true,\n    \"path\": \"/var/log/tyk-pump/metrics.log\"\n  }\n}\n```",
+	"main.TykPumpConfiguration.KV":                                                                 "KV defines named secret stores (such as HashiCorp Vault, Consul, environment\nvariables, or inline values) that other configuration values can reference.\nThis lets sensitive settings like database credentials or the admin secret\nbe kept in an external store instead of the config file; each referenced\nvalue is resolved from its store at startup, and again every\n`kv_refresh_interval` when it's set. Store definitions may be\nset in the config file or supplied as a JSON object through the\nTYK_PMP_KV_STORES environment variable; the environment overrides and adds\nstores by name, leaving file-defined stores it does not name untouched.",
+	"main.TykPumpConfiguration.KVRefreshInterval":                                                  "The interval, in seconds, at which the KV references of the pump configurations are\nresolved again, for the pumps to pick up rotated credentials. The SQL, Mongo,\nElasticsearch, Kafka and Splunk pumps reconnect with the new values of their\ncredentials and connection settings when they change; the other settings, and the\nother pumps, keep the values resolved at startup. The KV stores are kept open while\nthe Pump runs. Defaults to 0, resolving the references once, at startup.",
+	"main.TykPumpConfiguration.LogFile":                                                            "Writes the logs to a file too, rotated by size:\n```{.json}\n\"log_file\": {\n  \"enabled\": true,\n  \"path\": \"/var/log/tyk-pump/pump.log\",\n  \"max_size\": 100,\n  \"max_backups\": 5,\n  \"max_age\": 30,\n  \"compress\": true\n}\n```\n`max_size` is in megabytes and `max_age` in days.",
+	"main.TykPumpConfiguration.LogFormat":                                                          "Configures the output format used for application logs.\nAllowed values are `text`, `json`, or `legacy`.\nIf not set or left empty, it defaults to `text`.",
+	"main.TykPumpConfiguration.LogLevel":                                                           "Set the logger details for tyk-pump. The posible values are: `info`,`debug`,`error` and\n`warn`. By default, the log level is `info`.",
+	"main.TykPumpConfiguration.LogRepeatInterval":                                                  "The number of seconds identical warnings and errors are logged once in, the next one\nlogged carrying the number of the ones dropped in its `repeated` field, so a broken\nsink doesn't flood the logs. Disabled by default, every line being logged.",
+	"main.TykPumpConfiguration.MaxRecordSize":                                                      "Defines maximum size (in bytes) for Raw Request and Raw Response logs, this value defaults\nto 0. If it is not set then tyk-pump will not trim any data and will store the full\ninformation. This can also be set at a pump level. For example:\n```{.json}\n\"csv\": {\n  \"type\": \"csv\",\n  \"max_record_size\":1000,\n  \"meta\": {\n    \"csv_dir\": \"./\"\n  }\n}\n```",
+	"main.TykPumpConfiguration.MetricsEndpointName":                                                "The endpoint the metrics are served at. The default is \"metrics\".",
+	"main.TykPumpConfiguration.OmitConfigFile":                                                     "Defines if tyk-pump should ignore all the values in configuration file. Specially useful when setting all configurations in environment variables.",
+	"main.TykPumpConfiguration.OmitDetailedRecording":                                              "Reduce the size of the traffic logs generated for each request by setting this to true. Tyk Pump will\nthen not include the `raw_request` and `raw_response` in the logs. Defaults to false.",
+	"main.TykPumpConfiguration.Pumps":                                                              "The default environment variable prefix for each pump follows this format:\n`TYK_PMP_PUMPS_{PUMP-NAME}_`, for example `TYK_PMP_PUMPS_KAFKA_`.\n\nYou can also set custom names for each pump specifying the pump type. For example, if you\nwant a Kafka pump which is called `PROD` you need to create `TYK_PMP_PUMPS_PROD_TYPE=kafka`\nand configure it using the `TYK_PMP_PUMPS_PROD_` prefix.",
+	"main.TykPumpConfiguration.PurgeChunk":                                                         "The maximum number of records to pull from Redis at a time. If it's unset or `0`, all the\nanalytics records in Redis are pulled. If it's set, `storage_expiration_time` is used to\nreset the analytics record TTL.",
+	"main.TykPumpConfiguration.PurgeDelay":                                                         "Controls the frequency at which Tyk Pump should perform regular collection and purge\nof traffic logs from the temporal storage (typically Redis). Set the time between purges (in seconds).\nBe careful to ensure that this is long enough for the transfer of records to the target data sink (e.g.\npersistent storage or external APM) to complete to avoid data loss, but short enough to optimise\nyour temporal storage size.",
+	"main.TykPumpConfiguration.Quarantine":                                                         "Keeps the analytics payloads the Pump can't decode, instead of discarding them,\ntogether with the serializer tried, the key they were read from and the error. Once\nwhatever broke them is fixed, `tyk-pump quarantine retry` decodes them again and\nwrites them to the pumps. For example:\n```{.json}\n\"quarantine\": {\n  \"type\": \"file\",\n  \"dir\": \"/var/lib/tyk-pump/quarantine\"\n}\n```\n`type` is either `file`, keeping every payload verbatim in its own file under `dir`,\nor `redis`, appending them to the `key_name` list (`tyk-pump-quarantine` by default)\nof the analytics storage.",
+	"main.TykPumpConfiguration.Readiness":                                                          "Sets when the readiness endpoint reports the Pump isn't ready.",
+	"main.TykPumpConfiguration.ReadinessEndpointName":                                              "The readiness endpoint, served on the health check port, reports the temporal storage\nconnectivity, the last successful purge and the health of every pump, and returns a\n503 when `readiness` says the Pump isn't ready. `/health?verbose` serves the same\nreport. The default is \"ready\".",
+	"main.TykPumpConfiguration.StatsdConnectionString":                                             "Connection string for StatsD monitoring for information please see the\n[Instrumentation docs](/api-management/logs-metrics).",
+	"main.TykPumpConfiguration.StatsdPrefix":                                                       "Custom prefix value. For example separate settings for production and staging.",
+	"main.TykPumpConfiguration.StorageExpirationTime":                                              "The number of seconds for the analytics records TTL. It only works if `purge_chunk` is\nenabled. Defaults to `60` seconds.",
+	"main.TykPumpConfiguration.Tail":                                                               "Streams the records being written at `/tail` on the health check port, as Server-Sent\nEvents, for watching an API without setting up a pump. The records are streamed once\nfiltered by each pump, tagged with its name, and can be narrowed down with the `pump`,\n`api_id`, `org_id`, `path_prefix`, `status_min` and `status_max` query parameters.\nRequests must carry `secret` in the `X-Tyk-Authorization` header. Each client is\nstreamed at most `rate_limit` records per second, with `redact_fields` cleared, and is\ndisconnected rather than slowing the pumps down when it falls behind. For example:\n```{.json}\n\"tail\": {\n  \"enabled\": true,\n  \"secret\": \"change-me\",\n  \"rate_limit\": 50,\n  \"redact_fields\": [\"raw_request\", \"raw_response\", \"api_key\"]\n}\n```",
+	"main.TykPumpConfiguration.Tracing":                                                            "Traces the purge cycles with OpenTelemetry, exported over OTLP. Each cycle is a trace,\nwith a span per analytics key, covering its pop from the temporal storage and the\ndecoding of its records, and a span per pump write, covering its filtering. The\ncontext a pump writes with carries the span of the write, which the pumps talking\nHTTP to their backend propagate. For example:\n```{.json}\n\"tracing\": {\n  \"enabled\": true,\n  \"exporter\": \"grpc\",\n  \"endpoint\": \"otel-collector:4317\",\n  \"insecure\": true,\n  \"sampling\": {\n    \"type\": \"TraceIDRatioBased\",\n    \"rate\": 0.1\n  }\n}\n```",
+	"main.TykPumpConfiguration.UptimePumpConfig":                                                   "Example Uptime Pump configuration:\n```{.json}\n\"uptime_pump_config\": {\n  \"uptime_type\": \"mongo\",\n  \"mongo_url\": \"mongodb://localhost:27017\",\n  \"collection_name\": \"tyk_uptime_analytics\"\n},",
+	"main.UptimeConf.UptimeFilters":                                                                "Filters the uptime data written to the `mongo` or `sql` uptime pump, the same way the\n`uptime_filters` of the pumps do.",
+	"main.UptimeConf.UptimeType":                                                                   "Determines the uptime type. Options are `mongo`, `sql` and `none`. Defaults to `mongo`.\nWith `none`, uptime data is only written to the pumps with `uptime` enabled.",
+	"main.configSources":                                                                           "configSources tells where the settings of the configuration come from.",
+	"main.configSources.doc":                                                                       "doc is the configuration file, the included pump files merged in, nil when it's\nomitted.",
+	"main.configSources.origins":                                                                   "origins are the included files the settings of the pumps come from, by their lower\ncase path.",
+	"main.configSources.path":                                                                      "path is the configuration file.",
+	"main.kvRefresher":                                                                             "kvRefresher resolves the KV references of the pump configurations again, periodically,\nand hands the pumps implementing pumps.CredentialsRefresher their configuration when it\nchanged, for them to pick up rotated credentials.",
+	"main.kvRefresher.confs":                                                                       "confs are the configurations the pumps run with, by pump.",
+	"main.kvStores":                                                                                "kvStores holds the KV store connections opened to resolve the configuration, together\nwith the resolver that reads them. Both have the same lifetime, which is why they\ntravel together.\n\nA nil *kvStores is valid and means \"no KV stores are configured\", so callers never\nhave to branch on it.",
+	"main.kvStores.pumps":                                                                          "pumps are the pump configurations as they were before their references were\nresolved, for them to be resolved again - see kvRefresher.",
+	"main.pumpAdmin":                                                                               "pumpAdmin carries out the actions of the admin API on the running pumps.",
+	"main.pumpState":                                                                               "pumpState is what the Pump tracks about each of its pumps while it runs.",
+	"main.pumpState.inUse":                                                                         "inUse is read locked while the pump writes or checks its health, and locked while\nits credentials are refreshed, only once it isn't read locked.",
+	"main.pumpState.paused":                                                                        "paused is set while the pump is paused through the admin API.",
+	"main.pumpsMerge":                                                                              "pumpsMerge deeply merges pump configurations, gathering the settings given different\nvalues.",
+	"main.pumpsMerge.origins":                                                                      "origins are the files the settings were first set in, by path.",
+	"main.readinessReporter":                                                                       "readinessReporter builds the report of the readiness endpoint from the state of the\nanalytics store and the pumps.",
+}
@@ -0,0 +1,143 @@
+// Command gen writes the doc comments of the configuration types to the descriptions of the
+// schema package. It's run by go generate, from the schema directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	descs, err := parseDescriptions("..")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := writeDescriptions(descs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile("descriptions_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// modulePath is the import path of the Pump module.
+const modulePath = "github.com/TykTechnologies/tyk-pump"
+
+// describedPackages are the directories, relative to the root of the module, of the
+// packages holding the configuration types.
+var describedPackages = []string{".", "analytics", "logger", "pumps", "quarantine", "server", "storage", "tail", "tracing"}
+
+// parseDescriptions returns the doc comments of the structs of the describedPackages under
+// root and of their fields, by `<import path>.<type>` and `<import path>.<type>.<field>`.
+// The types of the root package, a main package, are under `main`.
+func parseDescriptions(root string) (map[string]string, error) {
+	descs := map[string]string{}
+	for _, dir := range describedPackages {
+		importPath := "main"
+		if dir != "." {
+			importPath = modulePath + "/" + dir
+		}
+
+		fset := token.NewFileSet()
+		pkgs, err := parser.ParseDir(fset, filepath.Join(root, dir), func(info os.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		}, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				addDescriptions(descs, importPath, file)
+			}
+		}
+	}
+
+	return descs, nil
+}
+
+func addDescriptions(descs map[string]string, importPath string, file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			st, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			key := importPath + "." + typeSpec.Name.Name
+			if text := commentText(doc); text != "" {
+				descs[key] = text
+			}
+
+			for _, field := range st.Fields.List {
+				text := commentText(field.Doc)
+				if text == "" {
+					continue
+				}
+				for _, name := range field.Names {
+					descs[key+"."+name.Name] = text
+				}
+			}
+		}
+	}
+}
+
+// commentText returns the text of doc, without the markers of the documentation generator.
+func commentText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+
+	lines := strings.Split(doc.Text(), "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "TYKCONFIG") && !strings.HasPrefix(line, "@PumpConf") {
+			kept = append(kept, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// writeDescriptions returns the source of the Go file declaring descs as the descriptions
+// of the schema package.
+func writeDescriptions(descs map[string]string) ([]byte, error) {
+	keys := make([]string, 0, len(descs))
+	for key := range descs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by go generate; DO NOT EDIT.\n\npackage schema\n\n")
+	buf.WriteString("// descriptions are the doc comments of the configuration types and their fields.\n")
+	buf.WriteString("var descriptions = map[string]string{\n")
+	for _, key := range keys {
+		fmt.Fprintf(buf, "\t%q: %q,\n", key, descs[key])
+	}
+	buf.WriteString("}\n")
+
+	return format.Source(buf.Bytes())
+}
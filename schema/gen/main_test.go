@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescriptionsUpToDate(t *testing.T) {
+	descs, err := parseDescriptions("../..")
+	assert.NoError(t, err)
+
+	src, err := writeDescriptions(descs)
+	assert.NoError(t, err)
+
+	current, err := os.ReadFile("../descriptions_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(src), string(current), "the descriptions are out of date, run go generate ./schema")
+}
+
+func TestCommentText(t *testing.T) {
+	descs, err := parseDescriptions("../..")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "The prefix for the environment variables that will be used to override the configuration.\nDefaults to `TYK_PMP_PUMPS_CSV_META`", descs["github.com/TykTechnologies/tyk-pump/pumps.CSVConf.EnvPrefix"])
+	assert.NotContains(t, descs["main.UptimeConf.UptimeType"], "TYKCONFIG")
+	assert.NotContains(t, descs, "github.com/TykTechnologies/tyk-pump/pumps.CSVConf", "@PumpConf is the only comment of CSVConf")
+	assert.NotContains(t, descs, "main.UptimeConf.MongoConf", "the embedded fields aren't described")
+}
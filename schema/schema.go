@@ -0,0 +1,286 @@
+// Package schema generates the JSON Schema of the Pump configuration from the Go types it
+// decodes into, with the doc comments of their fields as descriptions and the env vars
+// overriding them.
+package schema
+
+//go:generate go run ./gen
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema version of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, limited to the keywords the Pump configuration needs.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	PatternProperties    map[string]*Schema `json:"patternProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+	If                   *Schema            `json:"if,omitempty"`
+	Then                 *Schema            `json:"then,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+	// EnvVar is the env var overriding the value, if any.
+	EnvVar string `json:"x-env-var,omitempty"`
+}
+
+var (
+	timeType               = reflect.TypeOf(time.Time{})
+	jsonUnmarshalerType    = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType    = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	envconfigWordsRegexp   = regexp.MustCompile("([^A-Z]+|[A-Z]+[^A-Z]+|[A-Z]+)")
+	envconfigAcronymRegexp = regexp.MustCompile("([A-Z]+)([A-Z][^A-Z]+)")
+)
+
+// Reflector builds the schema of a configuration type.
+type Reflector struct {
+	// TagName is the struct tag the keys are read from: `json` for the configuration file,
+	// `mapstructure` for the meta of the pumps. Embedded structs are flattened either way,
+	// the way the pumps decode them.
+	TagName string
+	// EnvPrefix is the prefix envconfig reads the env vars overriding the fields with. The
+	// env vars aren't listed when it's empty.
+	EnvPrefix string
+}
+
+// Reflect returns the schema of t.
+func (r Reflector) Reflect(t reflect.Type) *Schema {
+	return r.reflect(t, r.EnvPrefix, map[reflect.Type]bool{})
+}
+
+func (r Reflector) reflect(t reflect.Type, envPrefix string, seen map[reflect.Type]bool) *Schema {
+	t = indirect(t)
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case opaque(t):
+		// Decoded its own way, anything goes.
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"}
+		}
+		return &Schema{Type: "array", Items: r.reflect(t.Elem(), "", seen)}
+	case reflect.Map:
+		s := &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s.AdditionalProperties = r.reflect(t.Elem(), "", seen)
+		}
+		return s
+	case reflect.Struct:
+		if seen[t] {
+			return &Schema{Type: "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		s := &Schema{
+			Type:                 "object",
+			Description:          descriptions[typeKey(t)],
+			Properties:           map[string]*Schema{},
+			AdditionalProperties: false,
+		}
+		r.addFields(s, t, envPrefix, seen)
+		return s
+	}
+
+	return &Schema{}
+}
+
+// addFields adds the fields of t to the properties of s.
+func (r Reflector) addFields(s *Schema, t reflect.Type, envPrefix string, seen map[reflect.Type]bool) {
+	for _, f := range r.fields(t, envPrefix) {
+		prop := r.reflect(f.Type, f.env, seen)
+		if desc := descriptions[typeKey(f.owner)+"."+f.Name]; desc != "" {
+			prop.Description = desc
+		}
+		if f.env != "" && prop.Properties == nil {
+			prop.EnvVar = f.env
+		}
+		s.Properties[f.name] = prop
+	}
+}
+
+// field is a setting of a struct, declared by it or by one of the structs it embeds.
+type field struct {
+	reflect.StructField
+	// owner is the struct declaring the field.
+	owner reflect.Type
+	// index is the sequence of indexes of the field from the struct, for FieldByIndex.
+	index []int
+	name  string
+	env   string
+}
+
+// fields returns the settings of t, the ones of its embedded structs included, the way
+// they're decoded.
+func (r Reflector) fields(t reflect.Type, envPrefix string) []field {
+	var fields []field
+	names := map[string]bool{}
+
+	var add func(t reflect.Type, index []int)
+	add = func(t reflect.Type, index []int) {
+		// The fields of the struct take precedence over the ones of its embedded structs,
+		// which are added last.
+		var embedded []reflect.StructField
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get(r.TagName), ",")
+			if name == "-" {
+				continue
+			}
+
+			if f.Anonymous && (name == "" || strings.Contains(opts, "squash")) && indirect(f.Type).Kind() == reflect.Struct {
+				embedded = append(embedded, f)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			if name == "" {
+				name = f.Name
+			}
+			if names[name] {
+				continue
+			}
+			names[name] = true
+
+			env := ""
+			if envPrefix != "" && f.Tag.Get("ignored") != "true" {
+				env = envVarName(envPrefix, f)
+			}
+			fields = append(fields, field{StructField: f, owner: t, index: appendIndex(index, i), name: name, env: env})
+		}
+
+		for _, f := range embedded {
+			add(indirect(f.Type), appendIndex(index, f.Index[0]))
+		}
+	}
+	add(t, nil)
+
+	return fields
+}
+
+func appendIndex(index []int, i int) []int {
+	return append(append([]int{}, index...), i)
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// opaque reports whether the values of t are decoded their own way rather than field by
+// field.
+func opaque(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// Setting is a value of a configuration.
+type Setting struct {
+	// Path is the keys of the setting, from the root of the configuration.
+	Path []string
+	// EnvVar is the env var overriding the setting, if any.
+	EnvVar string
+	Value  interface{}
+}
+
+// Settings returns the values of conf, a configuration of the type Reflect describes, down
+// to the ones that aren't structs.
+func (r Reflector) Settings(conf interface{}) []Setting {
+	var settings []Setting
+	r.settings(reflect.ValueOf(conf), nil, r.EnvPrefix, &settings)
+	return settings
+}
+
+func (r Reflector) settings(v reflect.Value, path []string, envPrefix string, settings *[]Setting) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	for _, f := range r.fields(v.Type(), envPrefix) {
+		value, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// Embedded through a nil pointer.
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], f.name)
+		if fieldType := indirect(f.Type); fieldType.Kind() == reflect.Struct && !opaque(fieldType) {
+			r.settings(value, fieldPath, f.env, settings)
+			continue
+		}
+		*settings = append(*settings, Setting{Path: fieldPath, EnvVar: f.env, Value: value.Interface()})
+	}
+}
+
+// envVarName returns the env var envconfig reads field from, under prefix.
+func envVarName(prefix string, field reflect.StructField) string {
+	key := field.Name
+	if field.Tag.Get("split_words") == "true" {
+		var words []string
+		for _, match := range envconfigWordsRegexp.FindAllStringSubmatch(field.Name, -1) {
+			if m := envconfigAcronymRegexp.FindStringSubmatch(match[0]); len(m) == 3 {
+				words = append(words, m[1], m[2])
+			} else {
+				words = append(words, match[0])
+			}
+		}
+		key = strings.Join(words, "_")
+	}
+	if alt := field.Tag.Get("envconfig"); alt != "" {
+		key = alt
+	}
+
+	return strings.ToUpper(prefix + "_" + key)
+}
+
+// rootPackage is the import path of the main package, which is called main in the binary
+// but not in its tests.
+const rootPackage = "github.com/TykTechnologies/tyk-pump"
+
+func typeKey(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if pkg == rootPackage {
+		pkg = "main"
+	}
+	return pkg + "." + t.Name()
+}
@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBase struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type testNested struct {
+	Enabled bool `json:"enabled"`
+}
+
+type testConf struct {
+	testBase
+	Port         string                 `json:"port"`
+	MaxIdleConns int                    `json:"max_idle_conns" split_words:"true"`
+	Renamed      string                 `json:"renamed" envconfig:"OTHER"`
+	Tags         []string               `json:"tags"`
+	Weights      map[string]float64     `json:"weights"`
+	Meta         map[string]interface{} `json:"meta"`
+	Since        time.Time              `json:"since"`
+	Nested       testNested             `json:"nested"`
+	Skipped      string                 `json:"-"`
+	NotFromEnv   string                 `json:"not_from_env" ignored:"true"`
+	NoTag        string
+}
+
+func TestReflect(t *testing.T) {
+	s := Reflector{TagName: "json", EnvPrefix: "TEST"}.Reflect(reflect.TypeOf(&testConf{}))
+
+	assert.Equal(t, "object", s.Type)
+	assert.Equal(t, false, s.AdditionalProperties)
+	assert.ElementsMatch(t, []string{
+		"host", "port", "max_idle_conns", "renamed", "tags", "weights", "meta", "since", "nested", "not_from_env", "NoTag",
+	}, keys(s.Properties))
+
+	tcs := []struct {
+		testName string
+		property string
+		expected *Schema
+	}{
+		{
+			testName: "embedded field",
+			property: "host",
+			expected: &Schema{Type: "string", EnvVar: "TEST_HOST"},
+		},
+		{
+			testName: "outer field shadowing an embedded one",
+			property: "port",
+			expected: &Schema{Type: "string", EnvVar: "TEST_PORT"},
+		},
+		{
+			testName: "split words",
+			property: "max_idle_conns",
+			expected: &Schema{Type: "integer", EnvVar: "TEST_MAX_IDLE_CONNS"},
+		},
+		{
+			testName: "envconfig tag",
+			property: "renamed",
+			expected: &Schema{Type: "string", EnvVar: "TEST_OTHER"},
+		},
+		{
+			testName: "slice",
+			property: "tags",
+			expected: &Schema{Type: "array", Items: &Schema{Type: "string"}, EnvVar: "TEST_TAGS"},
+		},
+		{
+			testName: "map",
+			property: "weights",
+			expected: &Schema{Type: "object", AdditionalProperties: &Schema{Type: "number"}, EnvVar: "TEST_WEIGHTS"},
+		},
+		{
+			testName: "map of anything",
+			property: "meta",
+			expected: &Schema{Type: "object", EnvVar: "TEST_META"},
+		},
+		{
+			testName: "time",
+			property: "since",
+			expected: &Schema{Type: "string", Format: "date-time", EnvVar: "TEST_SINCE"},
+		},
+		{
+			testName: "nested struct",
+			property: "nested",
+			expected: &Schema{
+				Type:                 "object",
+				Properties:           map[string]*Schema{"enabled": {Type: "boolean", EnvVar: "TEST_NESTED_ENABLED"}},
+				AdditionalProperties: false,
+			},
+		},
+		{
+			testName: "ignored by envconfig",
+			property: "not_from_env",
+			expected: &Schema{Type: "string"},
+		},
+		{
+			testName: "no tag",
+			property: "NoTag",
+			expected: &Schema{Type: "string", EnvVar: "TEST_NOTAG"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, s.Properties[tc.property])
+		})
+	}
+}
+
+func TestReflect_NoEnvPrefix(t *testing.T) {
+	s := Reflector{TagName: "json"}.Reflect(reflect.TypeOf(testConf{}))
+
+	assert.Empty(t, s.Properties["host"].EnvVar)
+	assert.Empty(t, s.Properties["nested"].Properties["enabled"].EnvVar)
+}
+
+type testMapstructureConf struct {
+	testBase `mapstructure:",squash"`
+	Dir      string `mapstructure:"dir"`
+}
+
+func TestReflect_Mapstructure(t *testing.T) {
+	s := Reflector{TagName: "mapstructure"}.Reflect(reflect.TypeOf(testMapstructureConf{}))
+
+	assert.ElementsMatch(t, []string{"Host", "Port", "dir"}, keys(s.Properties))
+}
+
+type testRecursive struct {
+	Next *testRecursive `json:"next"`
+}
+
+func TestReflect_Recursive(t *testing.T) {
+	s := Reflector{TagName: "json"}.Reflect(reflect.TypeOf(testRecursive{}))
+
+	assert.Equal(t, &Schema{Type: "object"}, s.Properties["next"])
+}
+
+func keys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestSettings(t *testing.T) {
+	conf := &testConf{
+		testBase:     testBase{Host: "localhost", Port: 80},
+		Port:         "8080",
+		MaxIdleConns: 5,
+		Nested:       testNested{Enabled: true},
+	}
+
+	settings := Reflector{TagName: "json", EnvPrefix: "TEST"}.Settings(conf)
+
+	byPath := map[string]Setting{}
+	for _, setting := range settings {
+		byPath[strings.Join(setting.Path, ".")] = setting
+	}
+	assert.Len(t, settings, len(byPath), "the paths are unique")
+	assert.Equal(t, Setting{Path: []string{"host"}, EnvVar: "TEST_HOST", Value: "localhost"}, byPath["host"])
+	assert.Equal(t, Setting{Path: []string{"port"}, EnvVar: "TEST_PORT", Value: "8080"}, byPath["port"])
+	assert.Equal(t, Setting{Path: []string{"max_idle_conns"}, EnvVar: "TEST_MAX_IDLE_CONNS", Value: 5}, byPath["max_idle_conns"])
+	assert.Equal(t, Setting{Path: []string{"nested", "enabled"}, EnvVar: "TEST_NESTED_ENABLED", Value: true}, byPath["nested.enabled"])
+	assert.Equal(t, time.Time{}, byPath["since"].Value, "time is a single setting")
+	assert.NotContains(t, byPath, "nested")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/TykTechnologies/tyk-pump/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigSchema(t *testing.T) {
+	s := configSchema()
+
+	assert.Equal(t, schema.Draft, s.Schema)
+	assert.Equal(t, "TYK_PMP_PURGEDELAY", s.Properties["purge_delay"].EnvVar)
+	assert.NotEmpty(t, s.Properties["purge_delay"].Description)
+	assert.Equal(t, &schema.Schema{Ref: "#/$defs/pump"}, s.Properties["pumps"].AdditionalProperties)
+
+	pump := s.Defs["pump"]
+	assert.Contains(t, pump.Properties["type"].AnyOf[0].Enum, "csv")
+	typePattern := regexp.MustCompile(pump.Properties["type"].AnyOf[1].Pattern)
+	assert.True(t, typePattern.MatchString("Mongo"), "the types are matched whatever their case")
+	assert.False(t, typePattern.MatchString("mongodb"))
+	assert.Equal(t, "TYK_PMP_PUMPS_<NAME>_TIMEOUT", pump.Properties["timeout"].EnvVar)
+	assert.Empty(t, pump.Properties["meta"].EnvVar)
+
+	csv := s.Defs["meta.csv"]
+	assert.Equal(t, "TYK_PMP_PUMPS_CSV_META_CSVDIR", csv.Properties["csv_dir"].EnvVar)
+	assert.Equal(t, false, csv.AdditionalProperties)
+
+	mongo := s.Defs["meta.mongo"]
+	assert.Contains(t, mongo.Properties, "collection_name")
+	assert.Contains(t, mongo.Properties, "mongo_url", "the base configuration is flattened")
+
+	assert.NotContains(t, s.Defs, "meta.dummy")
+	found := false
+	for _, cond := range pump.AllOf {
+		if regexp.MustCompile(cond.If.Properties["type"].Pattern).MatchString("CSV") {
+			found = true
+			assert.Equal(t, "#/$defs/meta.csv", cond.Then.Properties["meta"].Ref)
+		}
+	}
+	assert.True(t, found, "the meta of the csv pump is checked")
+
+	named := s.Properties["pumps"].PatternProperties["^"+caseInsensitivePattern("csv")+"$"]
+	if assert.NotNil(t, named, "the meta of the pumps named after their type is checked") {
+		assert.Equal(t, "#/$defs/pump", named.Ref)
+		assert.Equal(t, []string{"type"}, named.If.Not.Required, "only when they have no type")
+		assert.Equal(t, "#/$defs/meta.csv", named.Then.Properties["meta"].Ref)
+	}
+}
+
+func TestCaseInsensitivePattern(t *testing.T) {
+	tcs := []struct {
+		testName string
+		value    string
+		expected string
+	}{
+		{testName: "letters", value: "csv", expected: "[cC][sS][vV]"},
+		{testName: "hyphens", value: "mongo-pump-selective", expected: "[mM][oO][nN][gG][oO]-[pP][uU][mM][pP]-[sS][eE][lL][eE][cC][tT][iI][vV][eE]"},
+		{testName: "digits", value: "s3", expected: "[sS]3"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, caseInsensitivePattern(tc.value))
+		})
+	}
+}
+
+func TestRunSchema(t *testing.T) {
+	var out bytes.Buffer
+	assert.Equal(t, 0, runSchema(&out))
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Equal(t, schema.Draft, doc["$schema"])
+	assert.Contains(t, doc["$defs"], "meta.kafka")
+}
@@ -0,0 +1,93 @@
+package serializer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	ZSTD_COMPRESSION   = "zstd"
+	SNAPPY_COMPRESSION = "snappy"
+)
+
+// zstd encoders and decoders are safe for concurrent use and expensive to build, so
+// every compressed serializer shares the same pair.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// CompressedSerializer wraps another serializer and compresses its output. Every
+// payload it encodes starts with a format header naming both the inner encoding and
+// the compression, so it can be told apart from uncompressed payloads sharing a key.
+type CompressedSerializer struct {
+	inner       AnalyticsSerializer
+	compression string
+}
+
+// NewCompressedSerializer returns a serializer that compresses the output of inner
+// with the given compression, either ZSTD_COMPRESSION or SNAPPY_COMPRESSION.
+func NewCompressedSerializer(inner AnalyticsSerializer, compression string) (*CompressedSerializer, error) {
+	if _, ok := compressionIDs[compression]; !ok {
+		return nil, fmt.Errorf("unknown compression %q", compression)
+	}
+	if _, ok := encodingIDOf(inner); !ok {
+		return nil, fmt.Errorf("serializer %T can't be compressed", inner)
+	}
+
+	return &CompressedSerializer{inner: inner, compression: compression}, nil
+}
+
+func (cs *CompressedSerializer) Encode(record *analytics.AnalyticsRecord) ([]byte, error) {
+	data, err := cs.inner.Encode(record)
+	if err != nil {
+		return nil, err
+	}
+
+	encodingID, _ := encodingIDOf(cs.inner)
+	header := []byte{formatHeaderMagic, formatByte(encodingID, compressionIDs[cs.compression])}
+
+	switch cs.compression {
+	case ZSTD_COMPRESSION:
+		return zstdEncoder.EncodeAll(data, header), nil
+	case SNAPPY_COMPRESSION:
+		return append(header, snappy.Encode(nil, data)...), nil
+	}
+
+	return nil, fmt.Errorf("unknown compression %q", cs.compression)
+}
+
+func (cs *CompressedSerializer) Decode(analyticsData interface{}, record *analytics.AnalyticsRecord) error {
+	data := toBytes(analyticsData)
+	if len(data) < formatHeaderLen || data[0] != formatHeaderMagic {
+		return errors.New("payload has no format header")
+	}
+
+	data, err := decompress(compressionFromFormat(data[1]), data[formatHeaderLen:])
+	if err != nil {
+		return err
+	}
+
+	return cs.inner.Decode(data, record)
+}
+
+func (cs *CompressedSerializer) GetSuffix() string {
+	return cs.inner.GetSuffix() + "_" + cs.compression
+}
+
+func decompress(compressionID byte, data []byte) ([]byte, error) {
+	switch compressionID {
+	case compressionNone:
+		return data, nil
+	case compressionZstd:
+		return zstdDecoder.DecodeAll(data, nil)
+	case compressionSnappy:
+		return snappy.Decode(nil, data)
+	}
+
+	return nil, fmt.Errorf("unknown compression id %d", compressionID)
+}
@@ -0,0 +1,149 @@
+package serializer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+)
+
+// Payloads may start with a two byte format header: formatHeaderMagic followed by a
+// format byte whose low nibble names the encoding and high nibble the compression.
+// A zero first byte can't start a msgpack map, a protobuf message (field number 0 is
+// reserved) or a JSON document, so framed and unframed payloads never get mixed up.
+const (
+	formatHeaderMagic byte = 0x00
+	formatHeaderLen        = 2
+)
+
+const (
+	encodingMsgpack byte = iota + 1
+	encodingProtobuf
+	encodingJSON
+)
+
+const (
+	compressionNone byte = iota
+	compressionZstd
+	compressionSnappy
+)
+
+var compressionIDs = map[string]byte{
+	ZSTD_COMPRESSION:   compressionZstd,
+	SNAPPY_COMPRESSION: compressionSnappy,
+}
+
+func formatByte(encodingID, compressionID byte) byte {
+	return compressionID<<4 | encodingID
+}
+
+func encodingFromFormat(format byte) byte {
+	return format & 0x0f
+}
+
+func compressionFromFormat(format byte) byte {
+	return format >> 4
+}
+
+func encodingIDOf(serializer AnalyticsSerializer) (byte, bool) {
+	switch serializer.(type) {
+	case *MsgpSerializer:
+		return encodingMsgpack, true
+	case *ProtobufSerializer:
+		return encodingProtobuf, true
+	case *JSONSerializer:
+		return encodingJSON, true
+	}
+
+	return 0, false
+}
+
+// DetectSerializer returns the serializer able to decode data. A payload carrying a
+// format header is decoded as the header says, one that looks like a JSON object is
+// decoded as JSON, and anything else with fallback - usually the serializer of the key
+// it was read from. This lets producers using different formats share a key.
+func DetectSerializer(data []byte, fallback AnalyticsSerializer) AnalyticsSerializer {
+	if len(data) >= formatHeaderLen && data[0] == formatHeaderMagic {
+		if framed, ok := serializerForFormat(data[1]); ok {
+			return framed
+		}
+
+		return fallback
+	}
+
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '{' {
+		return &JSONSerializer{}
+	}
+
+	return fallback
+}
+
+// serializerForFormat returns the serializer producing payloads with the given format
+// byte in their header.
+func serializerForFormat(format byte) (AnalyticsSerializer, bool) {
+	var inner AnalyticsSerializer
+	switch encodingFromFormat(format) {
+	case encodingMsgpack:
+		inner = &MsgpSerializer{}
+	case encodingProtobuf:
+		inner = &ProtobufSerializer{}
+	case encodingJSON:
+		inner = &JSONSerializer{}
+	default:
+		return nil, false
+	}
+
+	compressionID := compressionFromFormat(format)
+	if compressionID == compressionNone {
+		return &FramedSerializer{inner: inner}, true
+	}
+
+	for name, id := range compressionIDs {
+		if id == compressionID {
+			return &CompressedSerializer{inner: inner, compression: name}, true
+		}
+	}
+
+	return nil, false
+}
+
+// FramedSerializer prefixes the output of another serializer with a format header,
+// without compressing it. Producers that write several formats to the same key can use
+// it to make their payloads unambiguous.
+type FramedSerializer struct {
+	inner AnalyticsSerializer
+}
+
+// NewFramedSerializer returns a serializer that frames the output of inner.
+func NewFramedSerializer(inner AnalyticsSerializer) (*FramedSerializer, error) {
+	if _, ok := encodingIDOf(inner); !ok {
+		return nil, fmt.Errorf("serializer %T can't be framed", inner)
+	}
+
+	return &FramedSerializer{inner: inner}, nil
+}
+
+func (fs *FramedSerializer) Encode(record *analytics.AnalyticsRecord) ([]byte, error) {
+	data, err := fs.inner.Encode(record)
+	if err != nil {
+		return nil, err
+	}
+
+	encodingID, _ := encodingIDOf(fs.inner)
+
+	return append([]byte{formatHeaderMagic, formatByte(encodingID, compressionNone)}, data...), nil
+}
+
+func (fs *FramedSerializer) Decode(analyticsData interface{}, record *analytics.AnalyticsRecord) error {
+	data := toBytes(analyticsData)
+	if len(data) < formatHeaderLen || data[0] != formatHeaderMagic {
+		return errors.New("payload has no format header")
+	}
+
+	return fs.inner.Decode(data[formatHeaderLen:], record)
+}
+
+func (fs *FramedSerializer) GetSuffix() string {
+	return fs.inner.GetSuffix()
+}
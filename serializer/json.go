@@ -0,0 +1,37 @@
+package serializer
+
+import (
+	"encoding/json"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+)
+
+// JSONSerializer encodes analytics records as plain JSON documents, using the
+// record's JSON tags. It's meant for producers that can't easily emit Tyk's
+// msgpack layout.
+type JSONSerializer struct {
+}
+
+// jsonPayload is the JSON document of a record, which carries its schema version unlike
+// the JSON the pumps write.
+type jsonPayload struct {
+	*analytics.AnalyticsRecord
+	SchemaVersion int `json:"schema_version,omitempty"`
+}
+
+func (serializer *JSONSerializer) Encode(record *analytics.AnalyticsRecord) ([]byte, error) {
+	return json.Marshal(jsonPayload{AnalyticsRecord: record, SchemaVersion: record.SchemaVersion})
+}
+
+func (serializer *JSONSerializer) Decode(analyticsData interface{}, record *analytics.AnalyticsRecord) error {
+	payload := jsonPayload{AnalyticsRecord: record}
+	if err := json.Unmarshal(toBytes(analyticsData), &payload); err != nil {
+		return err
+	}
+	record.SchemaVersion = payload.SchemaVersion
+	return nil
+}
+
+func (serializer *JSONSerializer) GetSuffix() string {
+	return "_json"
+}
@@ -1,13 +1,20 @@
 package serializer
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	analyticsproto "github.com/TykTechnologies/tyk-pump/analytics/proto"
 	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
+// ProtobufSchemaVersion is the version of analytics.proto records are encoded with.
+// Version 1, which payloads encoded before versioning was introduced are read as, had
+// no gateway latency, no GraphQL error paths and no collection name.
+const ProtobufSchemaVersion = 2
+
 type ProtobufSerializer struct {
 }
 
@@ -22,7 +29,7 @@ func (pb *ProtobufSerializer) Encode(record *analytics.AnalyticsRecord) ([]byte,
 
 func (pb *ProtobufSerializer) Decode(analyticsData interface{}, record *analytics.AnalyticsRecord) error {
 	protoData := analyticsproto.AnalyticsRecord{}
-	err := proto.Unmarshal(analyticsData.([]byte), &protoData)
+	err := proto.Unmarshal(toBytes(analyticsData), &protoData)
 	if err != nil {
 		return err
 	}
@@ -33,6 +40,7 @@ func (pb *ProtobufSerializer) TransformSingleRecordToProto(rec analytics.Analyti
 	latency := analyticsproto.Latency{
 		Total:    rec.Latency.Total,
 		Upstream: rec.Latency.Upstream,
+		Gateway:  rec.Latency.Gateway,
 	}
 
 	net := analyticsproto.NetworkStats{
@@ -58,39 +66,42 @@ func (pb *ProtobufSerializer) TransformSingleRecordToProto(rec analytics.Analyti
 	}
 
 	record := analyticsproto.AnalyticsRecord{
-		Host:          rec.Host,
-		Method:        rec.Method,
-		Path:          rec.Path,
-		RawPath:       rec.RawPath,
-		ContentLength: rec.ContentLength,
-		UserAgent:     rec.UserAgent,
-		Day:           int32(rec.Day),
-		Month:         int32(rec.Month),
-		Year:          int32(rec.Year),
-		Hour:          int32(rec.Hour),
-		ResponseCode:  int32(rec.ResponseCode),
-		APIKey:        rec.APIKey,
-		APIVersion:    rec.APIVersion,
-		APIName:       rec.APIName,
-		APIID:         rec.APIID,
-		OrgID:         rec.OrgID,
-		RequestTime:   rec.RequestTime,
-		Latency:       &latency,
-		RawRequest:    rec.RawRequest,
-		RawResponse:   rec.RawResponse,
-		IPAddress:     rec.IPAddress,
-		Geo:           &geo,
-		Network:       &net,
-		Tags:          rec.Tags,
-		Alias:         rec.Alias,
-		TrackPath:     rec.TrackPath,
-		OauthID:       rec.OauthID,
-		ApiSchema:     rec.ApiSchema,
-		OriginalPath:  rec.OriginalPath,
-		ListenPath:    rec.ListenPath,
+		Host:                rec.Host,
+		Method:              rec.Method,
+		Path:                rec.Path,
+		RawPath:             rec.RawPath,
+		ContentLength:       rec.ContentLength,
+		UserAgent:           rec.UserAgent,
+		Day:                 int32(rec.Day),
+		Month:               int32(rec.Month),
+		Year:                int32(rec.Year),
+		Hour:                int32(rec.Hour),
+		ResponseCode:        int32(rec.ResponseCode),
+		APIKey:              rec.APIKey,
+		APIVersion:          rec.APIVersion,
+		APIName:             rec.APIName,
+		APIID:               rec.APIID,
+		OrgID:               rec.OrgID,
+		RequestTime:         rec.RequestTime,
+		Latency:             &latency,
+		RawRequest:          rec.RawRequest,
+		RawResponse:         rec.RawResponse,
+		IPAddress:           rec.IPAddress,
+		Geo:                 &geo,
+		Network:             &net,
+		Tags:                rec.Tags,
+		Alias:               rec.Alias,
+		TrackPath:           rec.TrackPath,
+		OauthID:             rec.OauthID,
+		ApiSchema:           rec.ApiSchema,
+		OriginalPath:        rec.OriginalPath,
+		ListenPath:          rec.ListenPath,
+		CollectionName:      rec.CollectionName,
+		SchemaVersion:       ProtobufSchemaVersion,
+		RecordSchemaVersion: uint32(rec.SchemaVersion),
 	}
 	rec.TimestampToProto(&record)
-	if rec.GraphQLStats.IsGraphQL {
+	if !isZeroGraphQLStats(rec.GraphQLStats) {
 		// operation type
 		operationType := analyticsproto.GraphQLOperations_OPERATION_UNKNOWN
 		switch rec.GraphQLStats.OperationType {
@@ -101,10 +112,15 @@ func (pb *ProtobufSerializer) TransformSingleRecordToProto(rec analytics.Analyti
 		case analytics.OperationSubscription:
 			operationType = analyticsproto.GraphQLOperations_OPERATION_SUBSCRIPTION
 		}
-		// graph errors
+		// graph errors, as messages only for schema version 1 consumers
 		graphErrors := make([]string, len(rec.GraphQLStats.Errors))
+		errors := make([]*analyticsproto.GraphError, len(rec.GraphQLStats.Errors))
 		for i, val := range rec.GraphQLStats.Errors {
 			graphErrors[i] = val.Message
+			errors[i] = &analyticsproto.GraphError{
+				Message: val.Message,
+				Path:    graphErrorPathToProto(val.Path),
+			}
 		}
 		// types
 		graphTypes := make(map[string]*analyticsproto.RepeatedFields)
@@ -112,19 +128,20 @@ func (pb *ProtobufSerializer) TransformSingleRecordToProto(rec analytics.Analyti
 			graphTypes[key] = &analyticsproto.RepeatedFields{Fields: val}
 		}
 		record.GraphQLStats = &analyticsproto.GraphQLStats{
-			IsGraphQL:     true,
+			IsGraphQL:     rec.GraphQLStats.IsGraphQL,
 			Variables:     rec.GraphQLStats.Variables,
 			HasError:      rec.GraphQLStats.HasErrors,
 			OperationType: operationType,
 			GraphErrors:   graphErrors,
+			Errors:        errors,
 			RootFields:    rec.GraphQLStats.RootFields,
 			Types:         graphTypes,
 		}
 	}
 
-	if rec.MCPStats.IsMCP {
+	if rec.MCPStats != (analytics.MCPStats{}) {
 		record.MCPStats = &analyticsproto.MCPStats{
-			IsMCP:         true,
+			IsMCP:         rec.MCPStats.IsMCP,
 			JSONRPCMethod: rec.MCPStats.JSONRPCMethod,
 			PrimitiveType: rec.MCPStats.PrimitiveType,
 			PrimitiveName: rec.MCPStats.PrimitiveName,
@@ -159,37 +176,46 @@ func (pb *ProtobufSerializer) TransformSingleProtoToAnalyticsRecord(rec *analyti
 		IPAddress:     rec.IPAddress,
 		Geo: analytics.GeoData{
 			Country: analytics.Country{
-				ISOCode: rec.Geo.Country.ISOCode,
+				ISOCode: rec.GetGeo().GetCountry().GetISOCode(),
 			},
 			City: analytics.City{
-				GeoNameID: uint(rec.Geo.City.GeoNameID),
-				Names:     nil,
+				GeoNameID: uint(rec.GetGeo().GetCity().GetGeoNameID()),
+				Names:     rec.GetGeo().GetCity().GetNames(),
 			},
 			Location: analytics.Location{
-				Latitude:  rec.Geo.Location.Latitude,
-				Longitude: rec.Geo.Location.Longitude,
-				TimeZone:  rec.Geo.Location.TimeZone,
+				Latitude:  rec.GetGeo().GetLocation().GetLatitude(),
+				Longitude: rec.GetGeo().GetLocation().GetLongitude(),
+				TimeZone:  rec.GetGeo().GetLocation().GetTimeZone(),
 			},
 		},
 		Network: analytics.NetworkStats{
-			OpenConnections:  rec.Network.OpenConnections,
-			ClosedConnection: rec.Network.ClosedConnections,
-			BytesIn:          rec.Network.BytesIn,
-			BytesOut:         rec.Network.BytesOut,
+			OpenConnections:  rec.GetNetwork().GetOpenConnections(),
+			ClosedConnection: rec.GetNetwork().GetClosedConnections(),
+			BytesIn:          rec.GetNetwork().GetBytesIn(),
+			BytesOut:         rec.GetNetwork().GetBytesOut(),
 		},
 		Latency: analytics.Latency{
-			Total:    rec.Latency.Total,
-			Upstream: rec.Latency.Upstream,
+			Total:    rec.Latency.GetTotal(),
+			Upstream: rec.Latency.GetUpstream(),
+			Gateway:  rec.Latency.GetGateway(),
 		},
-		Tags:         rec.Tags,
-		Alias:        rec.Alias,
-		TrackPath:    rec.TrackPath,
-		ApiSchema:    rec.ApiSchema,
-		OriginalPath: rec.OriginalPath,
-		ListenPath:   rec.ListenPath,
+		Tags:           rec.Tags,
+		Alias:          rec.Alias,
+		TrackPath:      rec.TrackPath,
+		ApiSchema:      rec.ApiSchema,
+		OriginalPath:   rec.OriginalPath,
+		ListenPath:     rec.ListenPath,
+		CollectionName: rec.CollectionName,
+		SchemaVersion:  int(rec.RecordSchemaVersion),
 	}
 	tmpRecord.TimeStampFromProto(rec)
 
+	if rec.SchemaVersion < 2 && tmpRecord.SchemaVersion == 0 {
+		// Version 1 payloads come from producers that predate the gateway latency, so
+		// their records are upcast from version 1.
+		tmpRecord.SchemaVersion = 1
+	}
+
 	if rec.GraphQLStats != nil {
 		// process anc convert graphql stats
 		var operationType analytics.GraphQLOperations
@@ -208,9 +234,19 @@ func (pb *ProtobufSerializer) TransformSingleProtoToAnalyticsRecord(rec *analyti
 		for key, val := range rec.GraphQLStats.Types {
 			types[key] = val.Fields
 		}
-		errors := make([]analytics.GraphError, len(rec.GraphQLStats.GraphErrors))
-		for i, val := range rec.GraphQLStats.GraphErrors {
-			errors[i].Message = val
+		var errors []analytics.GraphError
+		if len(rec.GraphQLStats.Errors) > 0 {
+			errors = make([]analytics.GraphError, len(rec.GraphQLStats.Errors))
+			for i, val := range rec.GraphQLStats.Errors {
+				errors[i].Message = val.Message
+				errors[i].Path = graphErrorPathFromProto(val.Path)
+			}
+		} else {
+			// schema version 1 only carried the messages
+			errors = make([]analytics.GraphError, len(rec.GraphQLStats.GraphErrors))
+			for i, val := range rec.GraphQLStats.GraphErrors {
+				errors[i].Message = val
+			}
 		}
 
 		tmpRecord.GraphQLStats = analytics.GraphQLStats{
@@ -236,3 +272,41 @@ func (pb *ProtobufSerializer) TransformSingleProtoToAnalyticsRecord(rec *analyti
 	*record = tmpRecord
 	return nil
 }
+
+func isZeroGraphQLStats(stats analytics.GraphQLStats) bool {
+	return !stats.IsGraphQL && !stats.HasErrors && stats.OperationType == analytics.OperationUnknown &&
+		stats.Variables == "" && len(stats.RootFields) == 0 && len(stats.Types) == 0 && len(stats.Errors) == 0
+}
+
+// graphErrorPathToProto converts a GraphQL error path, made of field names and list
+// indexes as decoded from JSON, to protobuf values. Anything protobuf values can't hold
+// is kept as its string representation.
+func graphErrorPathToProto(path []interface{}) []*structpb.Value {
+	if path == nil {
+		return nil
+	}
+
+	values := make([]*structpb.Value, len(path))
+	for i, segment := range path {
+		value, err := structpb.NewValue(segment)
+		if err != nil {
+			value = structpb.NewStringValue(fmt.Sprint(segment))
+		}
+		values[i] = value
+	}
+
+	return values
+}
+
+func graphErrorPathFromProto(values []*structpb.Value) []interface{} {
+	if values == nil {
+		return nil
+	}
+
+	path := make([]interface{}, len(values))
+	for i, value := range values {
+		path[i] = value.AsInterface()
+	}
+
+	return path
+}
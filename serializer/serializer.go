@@ -1,6 +1,9 @@
 package serializer
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	logger "github.com/TykTechnologies/tyk-pump/logger"
 )
@@ -15,6 +18,11 @@ type AnalyticsSerializer interface {
 
 const MSGP_SERIALIZER = "msgpack"
 const PROTOBUF_SERIALIZER = "protobuf"
+const JSON_SERIALIZER = "json"
+
+// DefaultAnalyticsSerializers are the serializers whose keys are purged when none are
+// configured.
+var DefaultAnalyticsSerializers = []string{MSGP_SERIALIZER, PROTOBUF_SERIALIZER}
 
 func NewAnalyticsSerializer(serializerType string) AnalyticsSerializer {
 	switch serializerType {
@@ -22,9 +30,111 @@ func NewAnalyticsSerializer(serializerType string) AnalyticsSerializer {
 		serializer := &ProtobufSerializer{}
 		log.Debugf("Using serializer %v for analytics \n", PROTOBUF_SERIALIZER)
 		return serializer
+	case JSON_SERIALIZER:
+		serializer := &JSONSerializer{}
+		log.Debugf("Using serializer %v for analytics \n", JSON_SERIALIZER)
+		return serializer
 	case MSGP_SERIALIZER:
 	default:
+		if serializer, err := newCompressedAnalyticsSerializer(serializerType); err == nil {
+			log.Debugf("Using serializer %v for analytics \n", serializerType)
+			return serializer
+		}
 		log.Debugf("Using serializer %v for analytics \n", MSGP_SERIALIZER)
 	}
 	return &MsgpSerializer{}
 }
+
+// NewAnalyticsSerializers builds the serializers named by serializerTypes. Besides
+// `msgpack`, `protobuf` and `json`, any of them can be suffixed with `_zstd` or
+// `_snappy` to get its compressed variant, e.g. `protobuf_zstd`. Unlike
+// NewAnalyticsSerializer, an unknown name is an error rather than msgpack.
+func NewAnalyticsSerializers(serializerTypes []string) ([]AnalyticsSerializer, error) {
+	if len(serializerTypes) == 0 {
+		serializerTypes = DefaultAnalyticsSerializers
+	}
+
+	serializers := make([]AnalyticsSerializer, 0, len(serializerTypes))
+	seenSuffixes := make(map[string]string, len(serializerTypes))
+
+	for _, serializerType := range serializerTypes {
+		serializerType = strings.ToLower(strings.TrimSpace(serializerType))
+
+		var serializer AnalyticsSerializer
+		switch serializerType {
+		case MSGP_SERIALIZER, PROTOBUF_SERIALIZER, JSON_SERIALIZER:
+			serializer = NewAnalyticsSerializer(serializerType)
+		default:
+			var err error
+			serializer, err = newCompressedAnalyticsSerializer(serializerType)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if previous, ok := seenSuffixes[serializer.GetSuffix()]; ok {
+			return nil, fmt.Errorf("serializers %q and %q read the same key", previous, serializerType)
+		}
+		seenSuffixes[serializer.GetSuffix()] = serializerType
+
+		serializers = append(serializers, serializer)
+	}
+
+	return serializers, nil
+}
+
+// Name returns the name serializer is configured by, such as `protobuf_zstd`, so it can
+// be rebuilt with NewAnalyticsSerializer. Framed serializers are named after the
+// serializer they frame.
+func Name(serializer AnalyticsSerializer) string {
+	switch s := serializer.(type) {
+	case *MsgpSerializer:
+		return MSGP_SERIALIZER
+	case *ProtobufSerializer:
+		return PROTOBUF_SERIALIZER
+	case *JSONSerializer:
+		return JSON_SERIALIZER
+	case *CompressedSerializer:
+		return Name(s.inner) + "_" + s.compression
+	case *FramedSerializer:
+		return Name(s.inner)
+	}
+
+	return ""
+}
+
+// newCompressedAnalyticsSerializer builds a compressed serializer from a name such as
+// `msgpack_zstd`.
+func newCompressedAnalyticsSerializer(serializerType string) (AnalyticsSerializer, error) {
+	base, compression, found := strings.Cut(serializerType, "_")
+	if !found {
+		return nil, fmt.Errorf("unknown analytics serializer %q", serializerType)
+	}
+
+	var inner AnalyticsSerializer
+	switch base {
+	case MSGP_SERIALIZER:
+		inner = &MsgpSerializer{}
+	case PROTOBUF_SERIALIZER:
+		inner = &ProtobufSerializer{}
+	case JSON_SERIALIZER:
+		inner = &JSONSerializer{}
+	default:
+		return nil, fmt.Errorf("unknown analytics serializer %q", serializerType)
+	}
+
+	return NewCompressedSerializer(inner, compression)
+}
+
+// toBytes returns the raw payload held by analyticsData, which storage hands over
+// either as a string or as a byte slice.
+func toBytes(analyticsData interface{}) []byte {
+	switch data := analyticsData.(type) {
+	case string:
+		return []byte(data)
+	case []byte:
+		return data
+	}
+
+	return nil
+}
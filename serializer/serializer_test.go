@@ -1,12 +1,16 @@
 package serializer
 
 import (
+	"encoding/json"
 	"math/rand"
+	"reflect"
 	"testing"
+	"testing/quick"
 	"time"
 
 	"github.com/TykTechnologies/tyk-pump/analytics"
 	"github.com/TykTechnologies/tyk-pump/analytics/demo"
+	"github.com/golang/protobuf/proto"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
@@ -260,3 +264,320 @@ func BenchmarkMsgpEncoding(b *testing.B) {
 	}
 	b.ReportMetric(float64(serialSize)/float64(b.N), "B/serial")
 }
+
+func TestSerializer_CompressedRoundTrip(t *testing.T) {
+	tcs := []string{
+		"msgpack_zstd",
+		"msgpack_snappy",
+		"protobuf_zstd",
+		"protobuf_snappy",
+		"json_zstd",
+		"json_snappy",
+	}
+
+	for _, serializerType := range tcs {
+		t.Run(serializerType, func(t *testing.T) {
+			serializer := NewAnalyticsSerializer(serializerType)
+			_, isCompressed := serializer.(*CompressedSerializer)
+			assert.True(t, isCompressed)
+
+			record := analytics.AnalyticsRecord{
+				APIID:     "api_1",
+				OrgID:     "org_1",
+				ExpireAt:  time.Now().Add(time.Hour).Round(0),
+				TimeStamp: time.Now().Round(0),
+			}
+
+			encoded, err := serializer.Encode(&record)
+			assert.NoError(t, err)
+			assert.Equal(t, formatHeaderMagic, encoded[0])
+
+			decoded := &analytics.AnalyticsRecord{}
+			err = serializer.Decode(encoded, decoded)
+			assert.NoError(t, err)
+			assert.Equal(t, record.APIID, decoded.APIID)
+			assert.Equal(t, record.OrgID, decoded.OrgID)
+			assert.True(t, record.TimeStamp.Equal(decoded.TimeStamp))
+		})
+	}
+}
+
+func TestNewAnalyticsSerializers(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		serializers, err := NewAnalyticsSerializers(nil)
+		assert.NoError(t, err)
+		assert.Len(t, serializers, 2)
+		assert.Equal(t, "", serializers[0].GetSuffix())
+		assert.Equal(t, "_protobuf", serializers[1].GetSuffix())
+	})
+
+	t.Run("suffixes", func(t *testing.T) {
+		serializers, err := NewAnalyticsSerializers([]string{"json", "msgpack_zstd", "protobuf_snappy"})
+		assert.NoError(t, err)
+		assert.Equal(t, "_json", serializers[0].GetSuffix())
+		assert.Equal(t, "_zstd", serializers[1].GetSuffix())
+		assert.Equal(t, "_protobuf_snappy", serializers[2].GetSuffix())
+	})
+
+	t.Run("unknown serializer", func(t *testing.T) {
+		_, err := NewAnalyticsSerializers([]string{"msgpack", "xml"})
+		assert.Error(t, err)
+
+		_, err = NewAnalyticsSerializers([]string{"msgpack_lz4"})
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicated key", func(t *testing.T) {
+		_, err := NewAnalyticsSerializers([]string{"protobuf", "protobuf"})
+		assert.Error(t, err)
+	})
+}
+
+func TestName(t *testing.T) {
+	for _, name := range []string{"msgpack", "protobuf", "json", "msgpack_zstd", "protobuf_snappy"} {
+		serializers, err := NewAnalyticsSerializers([]string{name})
+		assert.NoError(t, err)
+		assert.Equal(t, name, Name(serializers[0]))
+		assert.Equal(t, serializers[0].GetSuffix(), NewAnalyticsSerializer(Name(serializers[0])).GetSuffix())
+	}
+
+	framed, err := NewFramedSerializer(&ProtobufSerializer{})
+	assert.NoError(t, err)
+	assert.Equal(t, "protobuf", Name(framed))
+}
+
+func TestDetectSerializer(t *testing.T) {
+	record := analytics.AnalyticsRecord{
+		APIID:     "api_1",
+		OrgID:     "org_1",
+		TimeStamp: time.Now().Round(0),
+	}
+
+	framed, err := NewFramedSerializer(&ProtobufSerializer{})
+	assert.NoError(t, err)
+
+	producers := map[string]AnalyticsSerializer{
+		"msgpack":         NewAnalyticsSerializer(MSGP_SERIALIZER),
+		"json":            NewAnalyticsSerializer(JSON_SERIALIZER),
+		"framed protobuf": framed,
+		"protobuf_zstd":   NewAnalyticsSerializer("protobuf_zstd"),
+		"json_snappy":     NewAnalyticsSerializer("json_snappy"),
+	}
+
+	// Every payload is read from the msgpack key, as producers sharing it would do.
+	fallback := NewAnalyticsSerializer(MSGP_SERIALIZER)
+
+	for name, producer := range producers {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := producer.Encode(&record)
+			assert.NoError(t, err)
+
+			decoded := &analytics.AnalyticsRecord{}
+			err = DetectSerializer(encoded, fallback).Decode(encoded, decoded)
+			assert.NoError(t, err)
+			assert.Equal(t, record.APIID, decoded.APIID)
+			assert.Equal(t, record.OrgID, decoded.OrgID)
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		assert.Equal(t, fallback, DetectSerializer([]byte{formatHeaderMagic, 0x0f}, fallback))
+	})
+}
+
+func TestJSONSerializer_SchemaVersion(t *testing.T) {
+	record := analytics.AnalyticsRecord{APIID: "api_1", SchemaVersion: 2}
+
+	encoded, err := NewAnalyticsSerializer(JSON_SERIALIZER).Encode(&record)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `"schema_version":2`)
+
+	decoded := analytics.AnalyticsRecord{}
+	assert.NoError(t, NewAnalyticsSerializer(JSON_SERIALIZER).Decode(encoded, &decoded))
+	assert.Equal(t, "api_1", decoded.APIID)
+	assert.Equal(t, 2, decoded.SchemaVersion)
+
+	written, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(written), "schema_version", "the records the pumps write have no schema version")
+}
+
+// randomRecord is an AnalyticsRecord that testing/quick knows how to generate, with
+// every field populated within the ranges the gateway produces.
+type randomRecord analytics.AnalyticsRecord
+
+func (randomRecord) Generate(r *rand.Rand, _ int) reflect.Value {
+	str := func() string {
+		b := make([]byte, r.Intn(16))
+		for i := range b {
+			b[i] = byte('a' + r.Intn(26))
+		}
+		return string(b)
+	}
+	strs := func() []string {
+		s := make([]string, r.Intn(4))
+		for i := range s {
+			s[i] = str()
+		}
+		return s
+	}
+	ts := time.Unix(r.Int63n(4102444800), r.Int63n(int64(time.Second))).UTC()
+
+	record := analytics.AnalyticsRecord{
+		Method:        str(),
+		Host:          str(),
+		Path:          str(),
+		RawPath:       str(),
+		ContentLength: r.Int63(),
+		UserAgent:     str(),
+		Day:           ts.Day(),
+		Month:         ts.Month(),
+		Year:          ts.Year(),
+		Hour:          ts.Hour(),
+		ResponseCode:  100 + r.Intn(500),
+		APIKey:        str(),
+		TimeStamp:     ts,
+		APIVersion:    str(),
+		APIName:       str(),
+		APIID:         str(),
+		OrgID:         str(),
+		OauthID:       str(),
+		RequestTime:   r.Int63(),
+		RawRequest:    str(),
+		RawResponse:   str(),
+		IPAddress:     str(),
+		Geo: analytics.GeoData{
+			Country:  analytics.Country{ISOCode: str()},
+			City:     analytics.City{GeoNameID: uint(r.Uint32()), Names: map[string]string{str(): str()}},
+			Location: analytics.Location{Latitude: r.Float64(), Longitude: r.Float64(), TimeZone: str()},
+		},
+		Network: analytics.NetworkStats{
+			OpenConnections:  r.Int63(),
+			ClosedConnection: r.Int63(),
+			BytesIn:          r.Int63(),
+			BytesOut:         r.Int63(),
+		},
+		Latency: analytics.Latency{
+			Total:    r.Int63(),
+			Upstream: r.Int63(),
+			Gateway:  r.Int63(),
+		},
+		Tags:           strs(),
+		Alias:          str(),
+		TrackPath:      r.Intn(2) == 0,
+		ExpireAt:       ts.Add(time.Duration(r.Int63n(int64(365 * 24 * time.Hour)))),
+		ApiSchema:      str(),
+		OriginalPath:   str(),
+		ListenPath:     str(),
+		CollectionName: str(),
+		SchemaVersion:  r.Intn(analytics.RecordSchemaVersion + 1),
+	}
+
+	if r.Intn(2) == 0 {
+		record.GraphQLStats = analytics.GraphQLStats{
+			IsGraphQL:     true,
+			Variables:     str(),
+			RootFields:    strs(),
+			Types:         map[string][]string{str(): strs()},
+			OperationType: analytics.GraphQLOperations(r.Intn(4)),
+			HasErrors:     true,
+			Errors: []analytics.GraphError{
+				// error paths are decoded from JSON, so indexes are float64
+				{Message: str(), Path: []interface{}{str(), float64(r.Intn(10)), str()}},
+				{Message: str()},
+			},
+		}
+	}
+
+	if r.Intn(2) == 0 {
+		record.MCPStats = analytics.MCPStats{
+			IsMCP:         true,
+			JSONRPCMethod: str(),
+			PrimitiveType: str(),
+			PrimitiveName: str(),
+		}
+	}
+
+	return reflect.ValueOf(randomRecord(record))
+}
+
+func TestSerializer_ProtobufMatchesMsgpack(t *testing.T) {
+	msgp := NewAnalyticsSerializer(MSGP_SERIALIZER)
+	pb := NewAnalyticsSerializer(PROTOBUF_SERIALIZER)
+
+	opts := cmp.Options{
+		cmpopts.IgnoreUnexported(analytics.AnalyticsRecord{}),
+		cmpopts.EquateEmpty(),
+		cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) }),
+	}
+
+	roundTrip := func(generated randomRecord) bool {
+		record := analytics.AnalyticsRecord(generated)
+
+		msgpEncoded, err := msgp.Encode(&record)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		fromMsgp := analytics.AnalyticsRecord{}
+		if err := msgp.Decode(msgpEncoded, &fromMsgp); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		pbEncoded, err := pb.Encode(&record)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		fromPb := analytics.AnalyticsRecord{}
+		if err := pb.Decode(pbEncoded, &fromPb); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if diff := cmp.Diff(record, fromPb, opts); diff != "" {
+			t.Log("protobuf round trip:", diff)
+			return false
+		}
+		if diff := cmp.Diff(fromMsgp, fromPb, opts); diff != "" {
+			t.Log("msgpack vs protobuf:", diff)
+			return false
+		}
+
+		return true
+	}
+
+	assert.NoError(t, quick.Check(roundTrip, &quick.Config{MaxCount: 200}))
+}
+
+func TestSerializer_ProtobufSchemaVersion1(t *testing.T) {
+	pb := &ProtobufSerializer{}
+
+	record := analytics.AnalyticsRecord{
+		APIID:   "api_1",
+		Latency: analytics.Latency{Total: 30, Upstream: 20, Gateway: 10},
+		GraphQLStats: analytics.GraphQLStats{
+			IsGraphQL: true,
+			HasErrors: true,
+			Errors:    []analytics.GraphError{{Message: "boom", Path: []interface{}{"user"}}},
+		},
+	}
+
+	// A version 1 payload: no version, no gateway latency and messages-only errors.
+	legacy := pb.TransformSingleRecordToProto(record)
+	legacy.SchemaVersion = 0
+	legacy.Latency.Gateway = 0
+	legacy.GraphQLStats.Errors = nil
+
+	encoded, err := proto.Marshal(legacy)
+	assert.NoError(t, err)
+
+	decoded := analytics.AnalyticsRecord{}
+	assert.NoError(t, pb.Decode(encoded, &decoded))
+	assert.Equal(t, 1, decoded.SchemaVersion, "version 1 payloads have version 1 records")
+	assert.NoError(t, decoded.Upcast())
+
+	assert.Equal(t, int64(10), decoded.Latency.Gateway, "gateway latency is derived for version 1 payloads")
+	assert.Equal(t, []analytics.GraphError{{Message: "boom"}}, decoded.GraphQLStats.Errors)
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminAuthHeader is the header admin API requests carry the admin secret in.
+const AdminAuthHeader = "X-Tyk-Authorization"
+
+var adminAuditPrefix = "admin-audit"
+
+// ErrPumpNotFound is returned by Admin when no pump is configured with the given name.
+var ErrPumpNotFound = errors.New("pump not found")
+
+// AdminPump is a pump as listed by the admin API.
+type AdminPump struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Paused bool   `json:"paused"`
+	// Config is the effective config of the pump, with its secrets redacted.
+	Config interface{} `json:"config"`
+}
+
+// Admin carries out the actions of the admin API.
+type Admin interface {
+	Pumps() []AdminPump
+	PausePump(name string) error
+	ResumePump(name string) error
+	SetPumpLogLevel(name, level string) error
+	// Purge triggers a purge without waiting for the next purge_delay tick.
+	Purge() error
+	// QueueDepths returns the number of records waiting in each analytics key.
+	QueueDepths(ctx context.Context) (map[string]int64, error)
+}
+
+var (
+	adminMu     sync.RWMutex
+	admin       Admin
+	adminSecret string
+)
+
+// SetAdmin enables the admin API, carrying out its actions with a and authenticating
+// its requests with secret. It must be called before ServeHealthCheck, and has no effect
+// with an empty secret.
+func SetAdmin(a Admin, secret string) {
+	adminMu.Lock()
+	defer adminMu.Unlock()
+
+	admin, adminSecret = a, secret
+}
+
+func getAdmin() (Admin, string) {
+	adminMu.RLock()
+	defer adminMu.RUnlock()
+
+	return admin, adminSecret
+}
+
+type apiStatusMessage struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// registerAdminRoutes adds the admin API to r, if it's enabled.
+func registerAdminRoutes(r *mux.Router) {
+	a, secret := getAdmin()
+	if a == nil || secret == "" {
+		return
+	}
+
+	handlers := adminHandlers{admin: a}
+	sub := r.PathPrefix("/admin").Subrouter()
+	sub.Use(adminAuth(secret))
+
+	sub.HandleFunc("/pumps", handlers.listPumps).Methods(http.MethodGet)
+	sub.HandleFunc("/pumps/{name}/pause", handlers.pausePump).Methods(http.MethodPost)
+	sub.HandleFunc("/pumps/{name}/resume", handlers.resumePump).Methods(http.MethodPost)
+	sub.HandleFunc("/pumps/{name}/log_level", handlers.setPumpLogLevel).Methods(http.MethodPut)
+	sub.HandleFunc("/purge", handlers.purge).Methods(http.MethodPost)
+	sub.HandleFunc("/queues", handlers.queueDepths).Methods(http.MethodGet)
+
+	log.WithFields(logrus.Fields{
+		"prefix": serverPrefix,
+	}).Info("Admin API enabled at /admin")
+}
+
+func adminAuth(secret string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			provided := strings.TrimPrefix(r.Header.Get(AdminAuthHeader), "Bearer ")
+			if !secureEqual(provided, secret) {
+				audit(r, "unauthorized", "", errors.New("invalid admin secret"))
+				writeJSON(rw, http.StatusForbidden, apiStatusMessage{Status: "error", Message: "attempted administrative access with invalid or missing key"})
+				return
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// audit logs every admin action, whether it succeeded or not.
+func audit(r *http.Request, action, pump string, err error) {
+	fields := logrus.Fields{
+		"prefix":      adminAuditPrefix,
+		"action":      action,
+		"remote_addr": r.RemoteAddr,
+		"path":        r.URL.Path,
+	}
+	if pump != "" {
+		fields["pump"] = pump
+	}
+
+	if err != nil {
+		log.WithFields(fields).WithError(err).Warning("Admin action failed")
+		return
+	}
+	log.WithFields(fields).Info("Admin action")
+}
+
+type adminHandlers struct {
+	admin Admin
+}
+
+func (h adminHandlers) listPumps(rw http.ResponseWriter, r *http.Request) {
+	audit(r, "list_pumps", "", nil)
+	writeJSON(rw, http.StatusOK, h.admin.Pumps())
+}
+
+func (h adminHandlers) pausePump(rw http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	err := h.admin.PausePump(name)
+	audit(r, "pause_pump", name, err)
+	writeResult(rw, err, "pump paused")
+}
+
+func (h adminHandlers) resumePump(rw http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	err := h.admin.ResumePump(name)
+	audit(r, "resume_pump", name, err)
+	writeResult(rw, err, "pump resumed")
+}
+
+func (h adminHandlers) setPumpLogLevel(rw http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	body := struct {
+		Level string `json:"level"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		audit(r, "set_log_level", name, err)
+		writeJSON(rw, http.StatusBadRequest, apiStatusMessage{Status: "error", Message: "request malformed"})
+		return
+	}
+
+	err := h.admin.SetPumpLogLevel(name, body.Level)
+	audit(r, "set_log_level:"+body.Level, name, err)
+	writeResult(rw, err, "log level set")
+}
+
+func (h adminHandlers) purge(rw http.ResponseWriter, r *http.Request) {
+	err := h.admin.Purge()
+	audit(r, "purge", "", err)
+	writeResult(rw, err, "purge triggered")
+}
+
+func (h adminHandlers) queueDepths(rw http.ResponseWriter, r *http.Request) {
+	depths, err := h.admin.QueueDepths(r.Context())
+	audit(r, "queue_depths", "", err)
+	if err != nil {
+		writeJSON(rw, http.StatusInternalServerError, apiStatusMessage{Status: "error", Message: err.Error()})
+		return
+	}
+	writeJSON(rw, http.StatusOK, depths)
+}
+
+func writeResult(rw http.ResponseWriter, err error, message string) {
+	switch {
+	case err == nil:
+		writeJSON(rw, http.StatusOK, apiStatusMessage{Status: "ok", Message: message})
+	case errors.Is(err, ErrPumpNotFound):
+		writeJSON(rw, http.StatusNotFound, apiStatusMessage{Status: "error", Message: err.Error()})
+	default:
+		writeJSON(rw, http.StatusBadRequest, apiStatusMessage{Status: "error", Message: err.Error()})
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, code int, body interface{}) {
+	rw.Header().Set("Content-type", "application/json")
+	rw.WriteHeader(code)
+	if err := json.NewEncoder(rw).Encode(body); err != nil {
+		log.WithField("prefix", serverPrefix).Error("Error writing response: ", err)
+	}
+}
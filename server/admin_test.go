@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAdmin struct {
+	paused   map[string]bool
+	levels   map[string]string
+	purges   int
+	queueErr error
+}
+
+func newFakeAdmin() *fakeAdmin {
+	return &fakeAdmin{paused: map[string]bool{"mongo": false}, levels: map[string]string{}}
+}
+
+func (f *fakeAdmin) Pumps() []AdminPump {
+	return []AdminPump{{Name: "mongo", Type: "MongoDB Pump", Paused: f.paused["mongo"]}}
+}
+
+func (f *fakeAdmin) setPaused(name string, paused bool) error {
+	if _, ok := f.paused[name]; !ok {
+		return ErrPumpNotFound
+	}
+	f.paused[name] = paused
+	return nil
+}
+
+func (f *fakeAdmin) PausePump(name string) error  { return f.setPaused(name, true) }
+func (f *fakeAdmin) ResumePump(name string) error { return f.setPaused(name, false) }
+
+func (f *fakeAdmin) SetPumpLogLevel(name, level string) error {
+	if _, ok := f.paused[name]; !ok {
+		return ErrPumpNotFound
+	}
+	if level != "debug" {
+		return errors.New("not a valid level")
+	}
+	f.levels[name] = level
+	return nil
+}
+
+func (f *fakeAdmin) Purge() error {
+	f.purges++
+	return nil
+}
+
+func (f *fakeAdmin) QueueDepths(context.Context) (map[string]int64, error) {
+	if f.queueErr != nil {
+		return nil, f.queueErr
+	}
+	return map[string]int64{"tyk-system-analytics": 3}, nil
+}
+
+func TestAdminRoutes(t *testing.T) {
+	t.Cleanup(func() { SetAdmin(nil, "") })
+
+	admin := newFakeAdmin()
+	SetAdmin(admin, "s3cret")
+	r := mux.NewRouter()
+	registerAdminRoutes(r)
+
+	tcs := []struct {
+		testName     string
+		method       string
+		path         string
+		body         string
+		secret       string
+		expectedCode int
+		expectedBody string
+	}{
+		{testName: "missing secret", method: http.MethodGet, path: "/admin/pumps", expectedCode: http.StatusForbidden},
+		{testName: "wrong secret", method: http.MethodGet, path: "/admin/pumps", secret: "wrong", expectedCode: http.StatusForbidden},
+		{
+			testName: "list pumps", method: http.MethodGet, path: "/admin/pumps", secret: "s3cret", expectedCode: http.StatusOK,
+			expectedBody: `[{"name": "mongo", "type": "MongoDB Pump", "paused": false, "config": null}]`,
+		},
+		{testName: "pause pump", method: http.MethodPost, path: "/admin/pumps/mongo/pause", secret: "s3cret", expectedCode: http.StatusOK},
+		{testName: "pause unknown pump", method: http.MethodPost, path: "/admin/pumps/csv/pause", secret: "s3cret", expectedCode: http.StatusNotFound},
+		{testName: "bearer secret", method: http.MethodPost, path: "/admin/pumps/mongo/resume", secret: "Bearer s3cret", expectedCode: http.StatusOK},
+		{testName: "set log level", method: http.MethodPut, path: "/admin/pumps/mongo/log_level", body: `{"level": "debug"}`, secret: "s3cret", expectedCode: http.StatusOK},
+		{testName: "set invalid log level", method: http.MethodPut, path: "/admin/pumps/mongo/log_level", body: `{"level": "loud"}`, secret: "s3cret", expectedCode: http.StatusBadRequest},
+		{testName: "set log level malformed", method: http.MethodPut, path: "/admin/pumps/mongo/log_level", body: `level`, secret: "s3cret", expectedCode: http.StatusBadRequest},
+		{testName: "purge", method: http.MethodPost, path: "/admin/purge", secret: "s3cret", expectedCode: http.StatusOK},
+		{
+			testName: "queue depths", method: http.MethodGet, path: "/admin/queues", secret: "s3cret", expectedCode: http.StatusOK,
+			expectedBody: `{"tyk-system-analytics": 3}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.secret != "" {
+				req.Header.Set(AdminAuthHeader, tc.secret)
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedBody != "" {
+				assert.JSONEq(t, tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+
+	assert.False(t, admin.paused["mongo"])
+	assert.Equal(t, "debug", admin.levels["mongo"])
+	assert.Equal(t, 1, admin.purges)
+}
+
+func TestAdminRoutes_Disabled(t *testing.T) {
+	t.Cleanup(func() { SetAdmin(nil, "") })
+
+	for _, secret := range []string{"", "s3cret"} {
+		var admin Admin
+		if secret == "" {
+			admin = newFakeAdmin()
+		}
+		SetAdmin(admin, secret)
+
+		r := mux.NewRouter()
+		registerAdminRoutes(r)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/pumps", nil)
+		req.Header.Set(AdminAuthHeader, secret)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestAdminRoutes_QueueDepthsError(t *testing.T) {
+	t.Cleanup(func() { SetAdmin(nil, "") })
+
+	admin := newFakeAdmin()
+	admin.queueErr = errors.New("not connected")
+	SetAdmin(admin, "s3cret")
+	r := mux.NewRouter()
+	registerAdminRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/queues", nil)
+	req.Header.Set(AdminAuthHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	msg := apiStatusMessage{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &msg))
+	assert.Equal(t, "not connected", msg.Message)
+}
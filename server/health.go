@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// healthReportTimeout bounds how long building a report, and so every check it runs,
+// may take.
+const healthReportTimeout = 5 * time.Second
+
+// Report is the detailed health of the Pump, served by the readiness endpoint and by the
+// health check endpoint when called with `?verbose`.
+type Report struct {
+	// Status is StatusOK when the Pump is ready, StatusFail otherwise.
+	Status    string         `json:"status"`
+	Storage   *StorageHealth `json:"storage,omitempty"`
+	LastPurge *time.Time     `json:"last_purge,omitempty"`
+	Pumps     []PumpHealth   `json:"pumps,omitempty"`
+	// Reasons lists why the Pump isn't ready.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+type StorageHealth struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type PumpHealth struct {
+	Name      string     `json:"name"`
+	Healthy   bool       `json:"healthy"`
+	LastWrite *time.Time `json:"last_write,omitempty"`
+	// LastWriteError is the error of the last write, empty if it succeeded.
+	LastWriteError string `json:"last_write_error,omitempty"`
+	// ErrorRate is the ratio of failed writes among the most recent ones, from 0 to 1.
+	ErrorRate float64 `json:"error_rate"`
+	Circuit   string  `json:"circuit"`
+	// CheckError is why the pump failed its health check, if it has one.
+	CheckError string `json:"check_error,omitempty"`
+}
+
+// Reporter builds health reports.
+type Reporter interface {
+	Report(ctx context.Context) Report
+}
+
+var (
+	reporterMu sync.RWMutex
+	reporter   Reporter
+)
+
+// SetReporter installs the reporter the readiness endpoint is served from. Until one is
+// installed, the Pump is reported ready.
+func SetReporter(r Reporter) {
+	reporterMu.Lock()
+	defer reporterMu.Unlock()
+
+	reporter = r
+}
+
+func currentReport(r *http.Request) Report {
+	reporterMu.RLock()
+	current := reporter
+	reporterMu.RUnlock()
+
+	if current == nil {
+		return Report{Status: StatusOK}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), healthReportTimeout)
+	defer cancel()
+
+	return current.Report(ctx)
+}
+
+// Readiness serves the health report, with a 503 status when the Pump isn't ready.
+func Readiness(rw http.ResponseWriter, r *http.Request) {
+	writeReport(rw, currentReport(r))
+}
+
+func writeReport(rw http.ResponseWriter, report Report) {
+	if report.Status == StatusOK {
+		writeJSON(rw, http.StatusOK, report)
+	} else {
+		writeJSON(rw, http.StatusServiceUnavailable, report)
+	}
+}
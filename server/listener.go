@@ -0,0 +1,151 @@
+package server
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	BasicAuth  = "basic"
+	BearerAuth = "bearer"
+)
+
+// Config secures the health check server.
+type Config struct {
+	// The address the server listens on. Defaults to every interface.
+	BindAddress string `json:"bind_address"`
+	// Serves over HTTPS rather than HTTP.
+	TLS TLSConfig `json:"tls"`
+	// Authenticates every request but the health and readiness probes.
+	Auth AuthConfig `json:"auth"`
+	// Moves the profiling and admin routes to a second listener on this port, bound to
+	// localhost and served over HTTP.
+	PrivatePort int `json:"private_port"`
+}
+
+type TLSConfig struct {
+	// The certificate and key the server is served with. TLS is enabled when they are set.
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	// The CA the client certificates are verified with.
+	CAFile string `json:"ca_file"`
+	// Rejects the clients without a certificate signed by the CA. Otherwise, it's only
+	// verified when they present one.
+	VerifyClientCert bool `json:"verify_client_cert"`
+}
+
+// Enabled reports whether the server is served over HTTPS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+type AuthConfig struct {
+	// `basic` or `bearer`. Requests aren't authenticated when empty.
+	Type string `json:"type"`
+	// The credentials of `basic`.
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// The token of `bearer`, sent in the Authorization header.
+	Token string `json:"token"`
+}
+
+func newTLSConfig(conf TLSConfig) (*tls.Config, error) {
+	if conf.CertFile == "" || conf.KeyFile == "" {
+		return nil, errors.New("TLS needs both a cert_file and a key_file")
+	}
+
+	cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load the TLS certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if conf.CAFile == "" {
+		if conf.VerifyClientCert {
+			return nil, errors.New("verifying the client certificates needs a ca_file")
+		}
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(conf.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificate found in %s", conf.CAFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if conf.VerifyClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// newAuth returns the middleware authenticating requests as conf says, or nil when they
+// aren't authenticated.
+func newAuth(conf AuthConfig) (mux.MiddlewareFunc, error) {
+	switch strings.ToLower(conf.Type) {
+	case "":
+		return nil, nil
+	case BasicAuth:
+		if conf.Username == "" || conf.Password == "" {
+			return nil, errors.New("basic auth needs a username and a password")
+		}
+		return basicAuth(conf.Username, conf.Password), nil
+	case BearerAuth:
+		if conf.Token == "" {
+			return nil, errors.New("bearer auth needs a token")
+		}
+		return bearerAuth(conf.Token), nil
+	}
+
+	return nil, fmt.Errorf("unknown auth type %q, must be %s or %s", conf.Type, BasicAuth, BearerAuth)
+}
+
+func basicAuth(username, password string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || !secureEqual(user, username) || !secureEqual(pass, password) {
+				rw.Header().Set("WWW-Authenticate", `Basic realm="tyk-pump"`)
+				writeJSON(rw, http.StatusUnauthorized, apiStatusMessage{Status: "error", Message: "authentication required"})
+				return
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func bearerAuth(token string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || !secureEqual(provided, token) {
+				rw.Header().Set("WWW-Authenticate", `Bearer realm="tyk-pump"`)
+				writeJSON(rw, http.StatusUnauthorized, apiStatusMessage{Status: "error", Message: "authentication required"})
+				return
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func secureEqual(provided, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(expected)) == 1
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuth(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		conf        AuthConfig
+		expectedNil bool
+		expectedErr bool
+	}{
+		{testName: "disabled", conf: AuthConfig{}, expectedNil: true},
+		{testName: "basic", conf: AuthConfig{Type: "basic", Username: "user", Password: "pass"}},
+		{testName: "basic without password", conf: AuthConfig{Type: "basic", Username: "user"}, expectedErr: true},
+		{testName: "bearer", conf: AuthConfig{Type: "Bearer", Token: "token"}},
+		{testName: "bearer without token", conf: AuthConfig{Type: "bearer"}, expectedErr: true},
+		{testName: "unknown", conf: AuthConfig{Type: "digest"}, expectedErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			auth, err := newAuth(tc.conf)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedNil, auth == nil)
+		})
+	}
+}
+
+func TestNewRouters_Auth(t *testing.T) {
+	t.Cleanup(func() { SetMetrics("", nil) })
+	SetMetrics("", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+
+	basic, _, err := newRouters("health", "ready", true, Config{Auth: AuthConfig{Type: BasicAuth, Username: "user", Password: "pass"}})
+	assert.NoError(t, err)
+	bearer, _, err := newRouters("health", "ready", true, Config{Auth: AuthConfig{Type: BearerAuth, Token: "token"}})
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		testName     string
+		router       http.Handler
+		path         string
+		setAuth      func(r *http.Request)
+		expectedCode int
+	}{
+		{testName: "open health probe", router: basic, path: "/health", expectedCode: http.StatusOK},
+		{testName: "open readiness probe", router: bearer, path: "/ready", expectedCode: http.StatusOK},
+		{testName: "metrics without credentials", router: basic, path: "/metrics", expectedCode: http.StatusUnauthorized},
+		{
+			testName: "metrics with wrong password", router: basic, path: "/metrics", expectedCode: http.StatusUnauthorized,
+			setAuth: func(r *http.Request) { r.SetBasicAuth("user", "wrong") },
+		},
+		{
+			testName: "metrics with basic auth", router: basic, path: "/metrics", expectedCode: http.StatusOK,
+			setAuth: func(r *http.Request) { r.SetBasicAuth("user", "pass") },
+		},
+		{testName: "profiling without token", router: bearer, path: "/debug/pprof/", expectedCode: http.StatusUnauthorized},
+		{
+			testName: "profiling with token", router: bearer, path: "/debug/pprof/", expectedCode: http.StatusOK,
+			setAuth: func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") },
+		},
+		{
+			testName: "unknown route", router: bearer, path: "/unknown", expectedCode: http.StatusNotFound,
+			setAuth: func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") },
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.setAuth != nil {
+				tc.setAuth(req)
+			}
+
+			rec := httptest.NewRecorder()
+			tc.router.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestNewRouters_PrivatePort(t *testing.T) {
+	t.Cleanup(func() { SetAdmin(nil, "") })
+	SetAdmin(newFakeAdmin(), "s3cret")
+
+	public, private, err := newRouters("health", "ready", true, Config{PrivatePort: 8084})
+	assert.NoError(t, err)
+
+	for _, path := range []string{"/debug/pprof/", "/admin/pumps"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(AdminAuthHeader, "s3cret")
+
+		rec := httptest.NewRecorder()
+		public.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "%s must only be served on the private listener", path)
+
+		rec = httptest.NewRecorder()
+		private.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+
+	rec := httptest.NewRecorder()
+	public.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	certFile, keyFile := writeTestCert(t)
+
+	tcs := []struct {
+		testName           string
+		conf               TLSConfig
+		expectedClientAuth tls.ClientAuthType
+		expectedErr        bool
+	}{
+		{testName: "cert only", conf: TLSConfig{CertFile: certFile, KeyFile: keyFile}, expectedClientAuth: tls.NoClientCert},
+		{testName: "missing key", conf: TLSConfig{CertFile: certFile}, expectedErr: true},
+		{
+			testName: "optional client cert", conf: TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: certFile},
+			expectedClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			testName: "required client cert", conf: TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: certFile, VerifyClientCert: true},
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{testName: "client cert without CA", conf: TLSConfig{CertFile: certFile, KeyFile: keyFile, VerifyClientCert: true}, expectedErr: true},
+		{testName: "CA without certificate", conf: TLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: keyFile}, expectedErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			tlsConfig, err := newTLSConfig(tc.conf)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, tlsConfig.Certificates, 1)
+			assert.Equal(t, tc.expectedClientAuth, tlsConfig.ClientAuth)
+		})
+	}
+}
+
+func TestServeHealthCheck_Shutdown(t *testing.T) {
+	t.Cleanup(func() {
+		serversMu.Lock()
+		shuttingDown = false
+		serversMu.Unlock()
+	})
+
+	certFile, keyFile := writeTestCert(t)
+	port := freePort(t)
+	done := make(chan struct{})
+	go func() {
+		ServeHealthCheck("health", "ready", port, false, Config{
+			BindAddress: "127.0.0.1",
+			TLS:         TLSConfig{CertFile: certFile, KeyFile: keyFile},
+		})
+		close(done)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	url := "https://" + net.JoinHostPort("127.0.0.1", fmt.Sprint(port)) + "/health"
+	assert.Eventually(t, func() bool {
+		resp, err := client.Get(url)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 20*time.Millisecond, "the health check must be served over TLS")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, Shutdown(ctx))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeHealthCheck should return once shut down")
+	}
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// writeTestCert writes a self-signed certificate for 127.0.0.1, and returns the paths of
+// the certificate and its key.
+func writeTestCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tyk-pump"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile = filepath.Join(dir, "pump.crt"), filepath.Join(dir, "pump.key")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
@@ -1,9 +1,16 @@
 package server
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	pprof_http "net/http/pprof"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/TykTechnologies/tyk-pump/logger"
 	"github.com/gorilla/mux"
@@ -11,40 +18,235 @@ import (
 )
 
 var defaultHealthEndpoint = "health"
+var defaultReadinessEndpoint = "ready"
+var defaultMetricsEndpoint = "metrics"
 var defaultHealthPort = 8083
 var serverPrefix = "server"
 var log = logger.GetLogger()
 
-func ServeHealthCheck(configHealthEndpoint string, configHealthPort int, enableProfiling bool) {
+// readHeaderTimeout bounds how long a client may take to send the headers of a request.
+const readHeaderTimeout = 10 * time.Second
+
+var (
+	metricsMu       sync.RWMutex
+	metricsEndpoint string
+	metricsHandler  http.Handler
+)
+
+// SetMetrics serves handler at endpoint, "metrics" if empty. It must be called before
+// ServeHealthCheck.
+func SetMetrics(endpoint string, handler http.Handler) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if endpoint == "" {
+		endpoint = defaultMetricsEndpoint
+	}
+	metricsEndpoint, metricsHandler = endpoint, handler
+}
+
+func registerMetricsRoute(r *mux.Router) {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+
+	if metricsHandler == nil {
+		return
+	}
+
+	r.Handle("/"+metricsEndpoint, metricsHandler).Methods("GET")
+	log.WithFields(logrus.Fields{
+		"prefix": serverPrefix,
+	}).Info("Serving metrics at /", metricsEndpoint)
+}
+
+var (
+	tailMu      sync.RWMutex
+	tailHandler http.Handler
+	tailSecret  string
+)
+
+// SetTail streams the records being written at /tail with handler, to the clients
+// authenticated with secret. It must be called before ServeHealthCheck, and has no effect
+// with an empty secret.
+func SetTail(handler http.Handler, secret string) {
+	tailMu.Lock()
+	defer tailMu.Unlock()
+
+	tailHandler, tailSecret = handler, secret
+}
+
+func registerTailRoute(r *mux.Router) {
+	tailMu.RLock()
+	defer tailMu.RUnlock()
+
+	if tailHandler == nil || tailSecret == "" {
+		return
+	}
+
+	r.Handle("/tail", adminAuth(tailSecret)(tailHandler)).Methods("GET")
+	log.WithFields(logrus.Fields{
+		"prefix": serverPrefix,
+	}).Info("Serving the live tail at /tail")
+}
+
+var (
+	serversMu    sync.Mutex
+	servers      []*http.Server
+	shuttingDown bool
+)
+
+// ServeHealthCheck serves the health check and readiness endpoints, along with the routes
+// set up before it's called, until Shutdown is called.
+func ServeHealthCheck(configHealthEndpoint, configReadinessEndpoint string, configHealthPort int, enableProfiling bool, conf Config) {
 	healthEndpoint := configHealthEndpoint
 	if healthEndpoint == "" {
 		healthEndpoint = defaultHealthEndpoint
 	}
+	readinessEndpoint := configReadinessEndpoint
+	if readinessEndpoint == "" {
+		readinessEndpoint = defaultReadinessEndpoint
+	}
 	healthPort := configHealthPort
 	if healthPort == 0 {
 		healthPort = defaultHealthPort
 	}
 
-	r := mux.NewRouter()
+	public, private, err := newRouters(healthEndpoint, readinessEndpoint, enableProfiling, conf)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": serverPrefix,
+		}).Fatal("Error setting up the health check server: ", err)
+	}
+
+	var tlsConfig *tls.Config
+	if conf.TLS.Enabled() {
+		if tlsConfig, err = newTLSConfig(conf.TLS); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": serverPrefix,
+			}).Fatal("Error setting up the health check server: ", err)
+		}
+	}
+
+	if private != nil {
+		privateSrv := newServer(net.JoinHostPort("127.0.0.1", fmt.Sprint(conf.PrivatePort)), private, nil)
+		go serve(privateSrv, "profiling and admin routes")
+	}
+
+	publicSrv := newServer(net.JoinHostPort(conf.BindAddress, fmt.Sprint(healthPort)), public, tlsConfig)
+	serve(publicSrv, "health check endpoint", "/"+healthEndpoint)
+}
 
-	r.HandleFunc("/"+healthEndpoint, Healthcheck).Methods("GET")
+// newRouters returns the router of the health check server and, when conf has a private
+// port, the one of the localhost listener the profiling and admin routes move to.
+func newRouters(healthEndpoint, readinessEndpoint string, enableProfiling bool, conf Config) (public, private *mux.Router, err error) {
+	auth, err := newAuth(conf.Auth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	public = mux.NewRouter()
+	// The probes are left open, for the orchestrators calling them to need no credentials.
+	public.HandleFunc("/"+healthEndpoint, Healthcheck).Methods("GET")
+	public.HandleFunc("/"+readinessEndpoint, Readiness).Methods("GET")
+
+	protected := public.NewRoute().Subrouter()
+	privateRoutes := protected
+	if conf.PrivatePort != 0 {
+		private = mux.NewRouter()
+		privateRoutes = private.NewRoute().Subrouter()
+	}
+	if auth != nil {
+		protected.Use(auth)
+		if private != nil {
+			privateRoutes.Use(auth)
+		}
+	}
+
+	registerMetricsRoute(protected)
+	registerTailRoute(protected)
+	registerAdminRoutes(privateRoutes)
 	if enableProfiling {
-		r.HandleFunc("/debug/pprof/profile", pprof_http.Profile)
-		r.HandleFunc("/debug/pprof/{_:.*}", pprof_http.Index)
+		privateRoutes.HandleFunc("/debug/pprof/profile", pprof_http.Profile)
+		privateRoutes.HandleFunc("/debug/pprof/{_:.*}", pprof_http.Index)
+	}
+
+	return public, private, nil
+}
+
+func newServer(addr string, handler http.Handler, tlsConfig *tls.Config) *http.Server {
+	// Canceled on shutdown, for the streams such as the live tail to end rather than hold
+	// it up.
+	baseCtx, cancel := context.WithCancel(context.Background())
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+	srv.RegisterOnShutdown(cancel)
+
+	return srv
+}
+
+func serve(srv *http.Server, what string, paths ...string) {
+	serversMu.Lock()
+	if shuttingDown {
+		serversMu.Unlock()
+		return
 	}
+	servers = append(servers, srv)
+	serversMu.Unlock()
 
+	scheme := "http"
+	if srv.TLSConfig != nil {
+		scheme = "https"
+	}
 	log.WithFields(logrus.Fields{
 		"prefix": serverPrefix,
-	}).Info("Serving health check endpoint at http://localhost:", healthPort, "/", healthEndpoint, " ...")
+	}).Info("Serving ", what, " at ", scheme, "://", srv.Addr, strings.Join(paths, ""), " ...")
 
-	if err := http.ListenAndServe(":"+fmt.Sprint(healthPort), r); err != nil {
+	var err error
+	if srv.TLSConfig != nil {
+		// The certificate is already in TLSConfig.
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.WithFields(logrus.Fields{
 			"prefix": serverPrefix,
-		}).Fatal("Error serving health check endpoint", err)
+		}).Fatal("Error serving ", what, ": ", err)
 	}
 }
 
+// Shutdown stops the health check server, waiting until ctx is done for the requests in
+// flight to complete.
+func Shutdown(ctx context.Context) error {
+	serversMu.Lock()
+	shuttingDown = true
+	current := servers
+	servers = nil
+	serversMu.Unlock()
+
+	var errs []error
+	for _, srv := range current {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Healthcheck reports the Pump is alive. Called with `?verbose`, it serves the same
+// report as the readiness endpoint instead.
 func Healthcheck(rw http.ResponseWriter, r *http.Request) {
+	if _, verbose := r.URL.Query()["verbose"]; verbose {
+		writeReport(rw, currentReport(r))
+		return
+	}
+
 	rw.Header().Set("Content-type", "application/json")
 	rw.WriteHeader(http.StatusOK)
 	rw.Write([]byte(`{"status": "ok"}`))
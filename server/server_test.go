@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticReporter Report
+
+func (s staticReporter) Report(context.Context) Report {
+	return Report(s)
+}
+
+func TestHealthcheck(t *testing.T) {
+	t.Cleanup(func() { SetReporter(nil) })
+	SetReporter(staticReporter{Status: StatusFail, Reasons: []string{"temporal storage unreachable"}})
+
+	rec := httptest.NewRecorder()
+	Healthcheck(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "liveness must not depend on readiness")
+	assert.JSONEq(t, `{"status": "ok"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	Healthcheck(rec, httptest.NewRequest(http.MethodGet, "/health?verbose", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	report := Report{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, StatusFail, report.Status)
+	assert.Equal(t, []string{"temporal storage unreachable"}, report.Reasons)
+}
+
+func TestReadiness(t *testing.T) {
+	t.Cleanup(func() { SetReporter(nil) })
+
+	tcs := []struct {
+		testName     string
+		reporter     Reporter
+		expectedCode int
+	}{
+		{testName: "no reporter", reporter: nil, expectedCode: http.StatusOK},
+		{testName: "ready", reporter: staticReporter{Status: StatusOK}, expectedCode: http.StatusOK},
+		{testName: "not ready", reporter: staticReporter{Status: StatusFail}, expectedCode: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			SetReporter(tc.reporter)
+
+			rec := httptest.NewRecorder()
+			Readiness(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			assert.Equal(t, tc.expectedCode, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-type"))
+		})
+	}
+}
+
+func TestRegisterMetricsRoute(t *testing.T) {
+	t.Cleanup(func() { SetMetrics("", nil) })
+
+	r := mux.NewRouter()
+	registerMetricsRoute(r)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "metrics are only served once set")
+
+	SetMetrics("", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("tyk_pump_purge_duration_seconds_count 1"))
+	}))
+	r = mux.NewRouter()
+	registerMetricsRoute(r)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "tyk_pump_purge_duration_seconds_count 1", rec.Body.String())
+}
+
+func TestRegisterTailRoute(t *testing.T) {
+	t.Cleanup(func() { SetTail(nil, "") })
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("data: {}\n\n"))
+	})
+
+	tcs := []struct {
+		testName     string
+		secret       string
+		sentSecret   string
+		expectedCode int
+	}{
+		{testName: "no secret configured", secret: "", sentSecret: "", expectedCode: http.StatusNotFound},
+		{testName: "missing secret", secret: "s3cret", sentSecret: "", expectedCode: http.StatusForbidden},
+		{testName: "valid secret", secret: "s3cret", sentSecret: "s3cret", expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			SetTail(handler, tc.secret)
+			r := mux.NewRouter()
+			registerTailRoute(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/tail", nil)
+			req.Header.Set(AdminAuthHeader, tc.sentSecret)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tc.expectedCode, rec.Code)
+		})
+	}
+}
@@ -310,6 +310,48 @@ func (r *TemporalStorageHandler) GetAndDeleteSet(keyName string, chunkSize int64
 	return intResult, nil
 }
 
+// AppendToSet appends values to the end of the list stored at keyName.
+func (r *TemporalStorageHandler) AppendToSet(keyName string, values ...[]byte) error {
+	err := r.ensureConnection()
+	if err != nil {
+		return err
+	}
+
+	return r.list.Append(ctx, true, r.fixKey(keyName), values...)
+}
+
+// GetListLength returns the number of values in the list stored at keyName.
+func (r *TemporalStorageHandler) GetListLength(lenCtx context.Context, keyName string) (int64, error) {
+	err := r.ensureConnection()
+	if err != nil {
+		return 0, err
+	}
+
+	return r.list.Length(lenCtx, r.fixKey(keyName))
+}
+
+// GetListRange returns the values of the list stored at keyName from start to stop,
+// without removing them. A negative index counts from the end of the list.
+func (r *TemporalStorageHandler) GetListRange(rangeCtx context.Context, keyName string, start, stop int64) ([]string, error) {
+	err := r.ensureConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list.Range(rangeCtx, r.fixKey(keyName), start, stop)
+}
+
+// RemoveFromList removes the first occurrence of value from the list stored at keyName.
+func (r *TemporalStorageHandler) RemoveFromList(removeCtx context.Context, keyName, value string) error {
+	err := r.ensureConnection()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.list.Remove(removeCtx, r.fixKey(keyName), 1, value)
+	return err
+}
+
 // SetKey will create (or update) a key value in the store
 func (r *TemporalStorageHandler) SetKey(keyName, session string, timeout int64) error {
 	log.Debug("[STORE] SET Raw key is: ", keyName)
@@ -328,6 +370,16 @@ func (r *TemporalStorageHandler) SetKey(keyName, session string, timeout int64)
 	return nil
 }
 
+// Ping checks the temporal storage is reachable. Unlike the other operations, it
+// doesn't try to reconnect.
+func (r *TemporalStorageHandler) Ping(pingCtx context.Context) error {
+	if connectorSingleton == nil {
+		return fmt.Errorf("not connected")
+	}
+
+	return connectorSingleton.Ping(pingCtx)
+}
+
 func (r *TemporalStorageHandler) ensureConnection() error {
 	if connectorSingleton != nil {
 		return nil
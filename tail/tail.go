@@ -0,0 +1,294 @@
+// Package tail streams the records the pumps write to the clients watching them, over
+// Server-Sent Events.
+package tail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/logger"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultRateLimit  = 100
+	defaultBufferSize = 1000
+	logPrefix         = "tail"
+)
+
+// heartbeatInterval is how often an idle stream is sent a comment, so proxies don't close
+// it.
+const heartbeatInterval = 15 * time.Second
+
+// DefaultRedactFields are the fields of the records cleared before they are streamed, when
+// none are configured.
+var DefaultRedactFields = []string{"raw_request", "raw_response", "api_key", "ip_address"}
+
+var log = logger.GetLogger()
+
+type Config struct {
+	// Enables the /tail endpoint. It's only served when a secret is set too.
+	Enabled bool `json:"enabled"`
+	// The secret the clients must send in the X-Tyk-Authorization header.
+	Secret string `json:"secret"`
+	// The maximum number of records per second streamed to each client. The ones above it
+	// are skipped. Defaults to 100.
+	RateLimit float64 `json:"rate_limit"`
+	// The number of records buffered for each client. A client falling this far behind is
+	// disconnected. Defaults to 1000.
+	BufferSize int `json:"buffer_size"`
+	// The fields of the records cleared before they are streamed, by their JSON name.
+	// Defaults to raw_request, raw_response, api_key and ip_address.
+	RedactFields []string `json:"redact_fields"`
+}
+
+// Event is a record streamed to the clients, along with the pump it was written to.
+type Event struct {
+	Pump   string                    `json:"pump"`
+	Record analytics.AnalyticsRecord `json:"record"`
+}
+
+// Filter selects the records a client is streamed. Its zero value selects them all.
+type Filter struct {
+	Pump       string
+	APIID      string
+	OrgID      string
+	PathPrefix string
+	StatusMin  int
+	StatusMax  int
+}
+
+// ParseFilter reads a Filter from the pump, api_id, org_id, path_prefix, status_min and
+// status_max query parameters.
+func ParseFilter(query url.Values) (Filter, error) {
+	f := Filter{
+		Pump:       query.Get("pump"),
+		APIID:      query.Get("api_id"),
+		OrgID:      query.Get("org_id"),
+		PathPrefix: query.Get("path_prefix"),
+	}
+
+	var err error
+	if f.StatusMin, err = parseStatus(query, "status_min"); err != nil {
+		return f, err
+	}
+	if f.StatusMax, err = parseStatus(query, "status_max"); err != nil {
+		return f, err
+	}
+	if f.StatusMax != 0 && f.StatusMin > f.StatusMax {
+		return f, errors.New("status_min can't be greater than status_max")
+	}
+
+	return f, nil
+}
+
+func parseStatus(query url.Values, param string) (int, error) {
+	value := query.Get(param)
+	if value == "" {
+		return 0, nil
+	}
+
+	status, err := strconv.Atoi(value)
+	if err != nil || status < 0 {
+		return 0, fmt.Errorf("%s must be a status code, got %q", param, value)
+	}
+
+	return status, nil
+}
+
+// Match reports whether the record written to pump is selected by f.
+func (f Filter) Match(pump string, record *analytics.AnalyticsRecord) bool {
+	switch {
+	case f.Pump != "" && f.Pump != pump:
+		return false
+	case f.APIID != "" && f.APIID != record.APIID:
+		return false
+	case f.OrgID != "" && f.OrgID != record.OrgID:
+		return false
+	case f.PathPrefix != "" && !strings.HasPrefix(record.Path, f.PathPrefix):
+		return false
+	case f.StatusMin != 0 && record.ResponseCode < f.StatusMin:
+		return false
+	case f.StatusMax != 0 && record.ResponseCode > f.StatusMax:
+		return false
+	}
+
+	return true
+}
+
+// subscriber is a connected client.
+type subscriber struct {
+	filter  Filter
+	limiter *rate.Limiter
+	events  chan Event
+	// dropped is closed when the client falls behind, for its stream to end.
+	dropped  chan struct{}
+	dropOnce sync.Once
+}
+
+func (s *subscriber) drop() {
+	s.dropOnce.Do(func() { close(s.dropped) })
+}
+
+// Hub hands the records published to it to the subscribed clients.
+type Hub struct {
+	conf Config
+
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	// count mirrors len(subscribers), for Publish to return straight away when nobody is
+	// connected without taking the lock.
+	count atomic.Int32
+}
+
+// NewHub returns a Hub streaming records as conf says.
+func NewHub(conf Config) *Hub {
+	if conf.RateLimit <= 0 {
+		conf.RateLimit = defaultRateLimit
+	}
+	if conf.BufferSize <= 0 {
+		conf.BufferSize = defaultBufferSize
+	}
+	if conf.RedactFields == nil {
+		conf.RedactFields = DefaultRedactFields
+	}
+
+	return &Hub{conf: conf, subscribers: map[*subscriber]struct{}{}}
+}
+
+func (h *Hub) subscribe(filter Filter) *subscriber {
+	burst := int(h.conf.RateLimit)
+	if burst < 1 {
+		burst = 1
+	}
+	s := &subscriber{
+		filter:  filter,
+		limiter: rate.NewLimiter(rate.Limit(h.conf.RateLimit), burst),
+		events:  make(chan Event, h.conf.BufferSize),
+		dropped: make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subscribers[s] = struct{}{}
+	h.count.Store(int32(len(h.subscribers)))
+
+	return s
+}
+
+func (h *Hub) unsubscribe(s *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers, s)
+	h.count.Store(int32(len(h.subscribers)))
+}
+
+// Publish streams the records written to pump to the clients whose filter selects them.
+// It never blocks: a client whose buffer is full is dropped. It does nothing when h is nil
+// or nobody is connected.
+func (h *Hub) Publish(pump string, records []interface{}) {
+	if h == nil || h.count.Load() == 0 {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for s := range h.subscribers {
+		h.publishTo(s, pump, records)
+	}
+}
+
+func (h *Hub) publishTo(s *subscriber, pump string, records []interface{}) {
+	select {
+	case <-s.dropped:
+		return
+	default:
+	}
+
+	for _, r := range records {
+		record, ok := r.(analytics.AnalyticsRecord)
+		if !ok || !s.filter.Match(pump, &record) || !s.limiter.Allow() {
+			continue
+		}
+
+		// record is a copy, so redacting it leaves what the pumps write untouched.
+		if len(h.conf.RedactFields) > 0 {
+			record.RemoveIgnoredFields(h.conf.RedactFields)
+		}
+
+		select {
+		case s.events <- Event{Pump: pump, Record: record}:
+		default:
+			log.WithFields(logrus.Fields{
+				"prefix": logPrefix,
+			}).Warning("Dropping a tail client: it's ", cap(s.events), " records behind")
+			s.drop()
+			return
+		}
+	}
+}
+
+// ServeHTTP streams the records selected by the filter in the query to the client, as
+// Server-Sent Events, until it disconnects or falls behind.
+func (h *Hub) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming isn't supported", http.StatusInternalServerError)
+		return
+	}
+
+	s := h.subscribe(filter)
+	defer h.unsubscribe(s)
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.dropped:
+			fmt.Fprint(rw, "event: dropped\ndata: too slow, disconnected\n\n")
+			flusher.Flush()
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(rw, ": heartbeat\n\n")
+			flusher.Flush()
+		case ev := <-s.events:
+			data, err := json.Marshal(ev)
+			if err != nil {
+				log.WithFields(logrus.Fields{
+					"prefix": logPrefix,
+				}).Error("Couldn't encode a tailed record: ", err)
+				continue
+			}
+			fmt.Fprintf(rw, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
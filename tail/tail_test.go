@@ -0,0 +1,186 @@
+package tail
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	tcs := []struct {
+		testName       string
+		query          string
+		expectedFilter Filter
+		expectedErr    bool
+	}{
+		{testName: "empty", query: "", expectedFilter: Filter{}},
+		{
+			testName:       "every filter",
+			query:          "pump=mongo&api_id=api1&org_id=org1&path_prefix=/users&status_min=500&status_max=599",
+			expectedFilter: Filter{Pump: "mongo", APIID: "api1", OrgID: "org1", PathPrefix: "/users", StatusMin: 500, StatusMax: 599},
+		},
+		{testName: "invalid status", query: "status_min=5xx", expectedErr: true},
+		{testName: "negative status", query: "status_max=-1", expectedErr: true},
+		{testName: "inverted range", query: "status_min=500&status_max=400", expectedErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			query, err := url.ParseQuery(tc.query)
+			assert.NoError(t, err)
+
+			filter, err := ParseFilter(query)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFilter, filter)
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	record := analytics.AnalyticsRecord{APIID: "api1", OrgID: "org1", Path: "/users/1", ResponseCode: 404}
+
+	tcs := []struct {
+		testName string
+		filter   Filter
+		expected bool
+	}{
+		{testName: "no filter", filter: Filter{}, expected: true},
+		{testName: "matching pump", filter: Filter{Pump: "mongo"}, expected: true},
+		{testName: "other pump", filter: Filter{Pump: "csv"}, expected: false},
+		{testName: "matching api", filter: Filter{APIID: "api1"}, expected: true},
+		{testName: "other api", filter: Filter{APIID: "api2"}, expected: false},
+		{testName: "other org", filter: Filter{OrgID: "org2"}, expected: false},
+		{testName: "matching path prefix", filter: Filter{PathPrefix: "/users"}, expected: true},
+		{testName: "other path prefix", filter: Filter{PathPrefix: "/orders"}, expected: false},
+		{testName: "in status range", filter: Filter{StatusMin: 400, StatusMax: 499}, expected: true},
+		{testName: "below status range", filter: Filter{StatusMin: 500}, expected: false},
+		{testName: "above status range", filter: Filter{StatusMax: 399}, expected: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.filter.Match("mongo", &record))
+		})
+	}
+}
+
+func TestHub_Publish(t *testing.T) {
+	hub := NewHub(Config{})
+	records := []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1", RawRequest: "cmF3", APIKey: "key"},
+		analytics.AnalyticsRecord{APIID: "api2"},
+	}
+
+	// Nobody is connected, so there's nothing to do.
+	hub.Publish("mongo", records)
+
+	s := hub.subscribe(Filter{APIID: "api1"})
+	defer hub.unsubscribe(s)
+	hub.Publish("mongo", records)
+
+	assert.Len(t, s.events, 1)
+	ev := <-s.events
+	assert.Equal(t, "mongo", ev.Pump)
+	assert.Equal(t, "api1", ev.Record.APIID)
+	assert.Empty(t, ev.Record.RawRequest, "the raw request is redacted by default")
+	assert.Empty(t, ev.Record.APIKey, "the key is redacted by default")
+	assert.Equal(t, "key", records[0].(analytics.AnalyticsRecord).APIKey, "the records written must be left untouched")
+
+	var nilHub *Hub
+	nilHub.Publish("mongo", records)
+}
+
+func TestHub_RateLimit(t *testing.T) {
+	hub := NewHub(Config{RateLimit: 2, RedactFields: []string{}})
+	s := hub.subscribe(Filter{})
+	defer hub.unsubscribe(s)
+
+	records := make([]interface{}, 5)
+	for i := range records {
+		records[i] = analytics.AnalyticsRecord{APIID: "api1"}
+	}
+	hub.Publish("mongo", records)
+
+	assert.Len(t, s.events, 2, "the records above the rate limit must be skipped")
+}
+
+func TestHub_DropsSlowClients(t *testing.T) {
+	hub := NewHub(Config{RateLimit: 100, BufferSize: 2})
+	slow := hub.subscribe(Filter{})
+	defer hub.unsubscribe(slow)
+
+	records := []interface{}{
+		analytics.AnalyticsRecord{APIID: "api1"},
+		analytics.AnalyticsRecord{APIID: "api1"},
+		analytics.AnalyticsRecord{APIID: "api1"},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		hub.Publish("mongo", records)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publishing to a slow client must not block")
+	}
+
+	select {
+	case <-slow.dropped:
+	default:
+		t.Fatal("the slow client should have been dropped")
+	}
+}
+
+func TestHub_ServeHTTP(t *testing.T) {
+	hub := NewHub(Config{})
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	rec := httptest.NewRecorder()
+	hub.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tail?status_min=x", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/tail?api_id=api1", nil)
+	assert.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The headers are only sent once subscribed.
+	hub.Publish("mongo", []interface{}{
+		analytics.AnalyticsRecord{APIID: "api2"},
+		analytics.AnalyticsRecord{APIID: "api1", Path: "/users"},
+	})
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "data: "))
+
+	ev := Event{}
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev))
+	assert.Equal(t, "mongo", ev.Pump)
+	assert.Equal(t, "/users", ev.Record.Path)
+
+	cancel()
+	assert.Eventually(t, func() bool { return hub.count.Load() == 0 }, time.Second, 10*time.Millisecond,
+		"a client must be unsubscribed once disconnected")
+}
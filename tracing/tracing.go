@@ -0,0 +1,187 @@
+// Package tracing sets up the OpenTelemetry tracing of the purge pipeline, exported over
+// OTLP.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/logger"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	GRPCExporter = "grpc"
+	HTTPExporter = "http"
+
+	AlwaysOn          = "AlwaysOn"
+	AlwaysOff         = "AlwaysOff"
+	TraceIDRatioBased = "TraceIDRatioBased"
+)
+
+const (
+	tracerName          = "github.com/TykTechnologies/tyk-pump"
+	defaultResourceName = "tyk-pump"
+	logPrefix           = "tracing"
+)
+
+var log = logger.GetLogger()
+
+type Config struct {
+	// Enables the tracing of the purge pipeline. Disabled by default.
+	Enabled bool `json:"enabled"`
+	// The protocol the spans are exported with, `grpc` or `http`. Defaults to `grpc`.
+	Exporter string `json:"exporter"`
+	// The OTLP collector the spans are exported to, as `host:port` for `grpc` and as a URL
+	// for `http`.
+	Endpoint string `json:"endpoint"`
+	// Headers sent along with the exported spans, such as an API key.
+	Headers map[string]string `json:"headers"`
+	// Exports the spans without TLS.
+	Insecure bool `json:"insecure"`
+	// The number of seconds an export may take. Defaults to 1.
+	ConnectionTimeout int `json:"connection_timeout"`
+	// The service name the spans are reported under. Defaults to `tyk-pump`.
+	ResourceName string `json:"resource_name"`
+	// Which purge cycles are traced.
+	Sampling SamplingConfig `json:"sampling"`
+}
+
+type SamplingConfig struct {
+	// `AlwaysOn`, `AlwaysOff` or `TraceIDRatioBased`. Defaults to `AlwaysOn`.
+	Type string `json:"type"`
+	// The ratio of the purge cycles traced with `TraceIDRatioBased`, from 0 to 1.
+	Rate float64 `json:"rate"`
+	// Follows the sampling decision of the parent span, when there's one.
+	ParentBased bool `json:"parent_based"`
+}
+
+// Tracer returns the tracer the Pump starts its spans with. It's a no-op until Init
+// enables tracing.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Init enables tracing as conf says, and returns the func flushing and stopping the
+// export of the spans. With tracing disabled it does nothing.
+func Init(ctx context.Context, conf Config, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !conf.Enabled {
+		return noop, nil
+	}
+
+	sampler, err := newSampler(conf.Sampling)
+	if err != nil {
+		return noop, err
+	}
+
+	exporter, err := newExporter(ctx, conf)
+	if err != nil {
+		return noop, err
+	}
+
+	resourceName := conf.ResourceName
+	if resourceName == "" {
+		resourceName = defaultResourceName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", resourceName),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return noop, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.WithFields(logrus.Fields{
+			"prefix": logPrefix,
+		}).Error("Tracing error: ", err)
+	}))
+
+	log.WithFields(logrus.Fields{
+		"prefix": logPrefix,
+	}).Info("Exporting traces to ", conf.Endpoint, " over ", exporterType(conf))
+
+	return provider.Shutdown, nil
+}
+
+func exporterType(conf Config) string {
+	if conf.Exporter == "" {
+		return GRPCExporter
+	}
+
+	return conf.Exporter
+}
+
+func newExporter(ctx context.Context, conf Config) (*otlptrace.Exporter, error) {
+	if conf.Endpoint == "" {
+		return nil, fmt.Errorf("tracing needs an endpoint")
+	}
+
+	timeout := time.Duration(conf.ConnectionTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+
+	switch exporterType(conf) {
+	case GRPCExporter:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(conf.Endpoint),
+			otlptracegrpc.WithHeaders(conf.Headers),
+			otlptracegrpc.WithTimeout(timeout),
+		}
+		if conf.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case HTTPExporter:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpointURL(conf.Endpoint),
+			otlptracehttp.WithHeaders(conf.Headers),
+			otlptracehttp.WithTimeout(timeout),
+		}
+		if conf.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	return nil, fmt.Errorf("unknown tracing exporter %q, must be %s or %s", conf.Exporter, GRPCExporter, HTTPExporter)
+}
+
+func newSampler(conf SamplingConfig) (sdktrace.Sampler, error) {
+	var sampler sdktrace.Sampler
+	switch conf.Type {
+	case "", AlwaysOn:
+		sampler = sdktrace.AlwaysSample()
+	case AlwaysOff:
+		sampler = sdktrace.NeverSample()
+	case TraceIDRatioBased:
+		sampler = sdktrace.TraceIDRatioBased(conf.Rate)
+	default:
+		return nil, fmt.Errorf("unknown sampling type %q, must be %s, %s or %s", conf.Type, AlwaysOn, AlwaysOff, TraceIDRatioBased)
+	}
+
+	if conf.ParentBased {
+		sampler = sdktrace.ParentBased(sampler)
+	}
+
+	return sampler, nil
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestNewSampler(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		conf        SamplingConfig
+		expected    string
+		expectedErr bool
+	}{
+		{testName: "default", conf: SamplingConfig{}, expected: sdktrace.AlwaysSample().Description()},
+		{testName: "always off", conf: SamplingConfig{Type: AlwaysOff}, expected: sdktrace.NeverSample().Description()},
+		{testName: "ratio", conf: SamplingConfig{Type: TraceIDRatioBased, Rate: 0.5}, expected: sdktrace.TraceIDRatioBased(0.5).Description()},
+		{
+			testName: "parent based", conf: SamplingConfig{Type: TraceIDRatioBased, Rate: 0.5, ParentBased: true},
+			expected: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.5)).Description(),
+		},
+		{testName: "unknown", conf: SamplingConfig{Type: "Sometimes"}, expectedErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			sampler, err := newSampler(tc.conf)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, sampler.Description())
+		})
+	}
+}
+
+func TestNewExporter(t *testing.T) {
+	tcs := []struct {
+		testName    string
+		conf        Config
+		expectedErr bool
+	}{
+		{testName: "no endpoint", conf: Config{Enabled: true}, expectedErr: true},
+		{testName: "grpc", conf: Config{Enabled: true, Endpoint: "localhost:4317", Insecure: true}},
+		{testName: "http", conf: Config{Enabled: true, Exporter: HTTPExporter, Endpoint: "http://localhost:4318"}},
+		{testName: "unknown exporter", conf: Config{Enabled: true, Exporter: "udp", Endpoint: "localhost:4317"}, expectedErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			exporter, err := newExporter(context.Background(), tc.conf)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, exporter.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestInit_Disabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{Endpoint: "localhost:4317"}, "test")
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, span := Tracer().Start(context.Background(), "purge")
+	assert.False(t, span.SpanContext().IsValid(), "spans must be no-ops while tracing is disabled")
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TykTechnologies/tyk-pump/analytics"
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/gocraft/health"
+	"github.com/sirupsen/logrus"
+	msgpack "gopkg.in/vmihailenco/msgpack.v2"
+)
+
+// writeUptimeToPumps decodes the uptime data and writes it to every pump with uptime
+// enabled, filtered by the uptime filters of each.
+func writeUptimeToPumps(values []interface{}, job *health.Job) {
+	if len(UptimePumps) == 0 || len(values) == 0 {
+		return
+	}
+
+	records := decodeUptimeValues(values)
+
+	var wg sync.WaitGroup
+	wg.Add(len(UptimePumps))
+	for _, pmp := range UptimePumps {
+		go execUptimePumpWriting(&wg, pmp, records, job)
+	}
+	wg.Wait()
+}
+
+func decodeUptimeValues(values []interface{}) []analytics.UptimeReportData {
+	records := make([]analytics.UptimeReportData, 0, len(values))
+	for _, v := range values {
+		decoded := analytics.UptimeReportData{}
+		if err := msgpack.Unmarshal([]byte(v.(string)), &decoded); err != nil {
+			log.WithFields(logrus.Fields{
+				"prefix": mainPrefix,
+			}).Error("Couldn't unmarshal uptime data:", err)
+			continue
+		}
+		records = append(records, decoded)
+	}
+
+	return records
+}
+
+func filterUptimeData(pmp pumps.UptimeDataPump, records []analytics.UptimeReportData) []analytics.UptimeReportData {
+	filters := pmp.GetUptimeFilters()
+	if !filters.HasFilter() {
+		return records
+	}
+
+	filtered := make([]analytics.UptimeReportData, 0, len(records))
+	for _, record := range records {
+		if !filters.ShouldFilter(record) {
+			filtered = append(filtered, record)
+		}
+	}
+
+	return filtered
+}
+
+// filterUptimeValues returns the uptime values filters keeps, still encoded for the
+// uptime pump, which decodes them itself. The values that can't be decoded are kept for
+// it to report.
+func filterUptimeValues(filters analytics.UptimeFilters, values []interface{}) []interface{} {
+	if !filters.HasFilter() {
+		return values
+	}
+
+	filtered := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		decoded := analytics.UptimeReportData{}
+		if err := msgpack.Unmarshal([]byte(v.(string)), &decoded); err == nil && filters.ShouldFilter(decoded) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+
+	return filtered
+}
+
+func execUptimePumpWriting(wg *sync.WaitGroup, pmp pumps.UptimeDataPump, records []analytics.UptimeReportData, job *health.Job) {
+	defer wg.Done()
+
+	filtered := filterUptimeData(pmp, records)
+	if len(filtered) == 0 {
+		return
+	}
+
+	log.WithFields(logrus.Fields{
+		"prefix": mainPrefix,
+	}).Debug("Writing uptime data to: ", pmp.GetName())
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout := pmp.GetTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+
+	state := getPumpState(pmp)
+	state.inUse.RLock()
+	defer state.inUse.RUnlock()
+
+	startTime := time.Now()
+	if err := pmp.WriteUptimeRecords(ctx, filtered); err != nil {
+		log.WithFields(logrus.Fields{
+			"prefix": mainPrefix,
+		}).Warning("Error Writing uptime data to: ", pmp.GetName(), " - Error:", err)
+	}
+
+	if job != nil {
+		job.Timing("purge_uptime_time_"+pmp.GetName(), time.Since(startTime).Nanoseconds())
+	}
+}
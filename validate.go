@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/TykTechnologies/tyk-pump/pumps"
+	"github.com/TykTechnologies/tyk-pump/server"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/sirupsen/logrus"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// runValidate checks the configuration file at path, writing the problems it finds to w.
+// It returns the exit code of the validate command: 1 when there are problems.
+func runValidate(path string, w io.Writer) int {
+	problems := validateConfig(path)
+	if len(problems) == 0 {
+		fmt.Fprintln(w, "The configuration is valid.")
+		return 0
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(w, problem)
+	}
+	fmt.Fprintf(w, "Found %d problem(s) in the configuration.\n", len(problems))
+	return 1
+}
+
+// validateConfig loads the configuration at path the way LoadConfig does, env var
+// overrides and KV references included, and returns the problems it has: unknown keys,
+// values of the wrong type, in the configuration and in the meta of the pumps, KV
+// references that can't be resolved and settings that don't work together.
+func validateConfig(path string) []string {
+	cfg := &TykPumpConfiguration{}
+	var problems []string
+
+	if !cfg.shouldOmitConfigFile() {
+		doc, err := readConfigFile(path)
+		if err != nil {
+			return []string{fmt.Sprintf("Couldn't read the configuration file: %v", err)}
+		}
+		if _, err := includePumps(doc, path); err != nil {
+			for _, err := range unwrapJoined(err) {
+				problems = append(problems, fmt.Sprintf("Couldn't include the pump configurations: %v", err))
+			}
+		}
+		problems = append(problems, unknownKeys(doc, reflect.TypeOf(cfg).Elem(), "")...)
+
+		if err := decodeConfig(doc, cfg); err != nil {
+			problems = append(problems, fmt.Sprintf("Invalid value: %v", err))
+		}
+	}
+
+	cfg.upperCasePumpNames()
+	if err := envconfig.Process(ENV_PREVIX, cfg); err != nil {
+		problems = append(problems, fmt.Sprintf("Invalid env var override: %v", err))
+	}
+	if err := cfg.LoadPumpsByEnv(); err != nil {
+		problems = append(problems, fmt.Sprintf("Invalid pump env var: %v", err))
+	}
+
+	stores, err := resolveKVReferences(context.Background(), cfg)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("Unresolved KV reference: %v", err))
+	}
+	pumps.SetKVResolver(stores.Resolver())
+	defer pumps.SetKVResolver(nil)
+	defer stores.Close(context.Background())
+
+	problems = append(problems, configCombinationProblems(cfg)...)
+
+	names := make([]string, 0, len(cfg.Pumps))
+	for name := range cfg.Pumps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		problems = append(problems, pumpConfigProblems(name, cfg.Pumps[name])...)
+	}
+
+	return problems
+}
+
+// unknownKeys returns the keys of doc, a decoded JSON document, that don't match a field of
+// t the way encoding/json matches them, path being where doc is in the configuration.
+func unknownKeys(doc interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+
+	var problems []string
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		fields := jsonFields(t)
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("Unknown key %q", joinPath(path, key)))
+				continue
+			}
+			problems = append(problems, unknownKeys(obj[key], field, joinPath(path, key))...)
+		}
+	case reflect.Map:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for key, value := range obj {
+			problems = append(problems, unknownKeys(value, t.Elem(), joinPath(path, key))...)
+		}
+		sort.Strings(problems)
+	case reflect.Slice, reflect.Array:
+		list, ok := doc.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, value := range list {
+			problems = append(problems, unknownKeys(value, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return problems
+}
+
+// jsonFields returns the types of the fields of t by the lower case name encoding/json
+// decodes them from, the fields of the embedded structs included.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, typ := range jsonFields(embedded) {
+					if _, ok := fields[key]; !ok {
+						fields[key] = typ
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+
+	return fields
+}
+
+// unwrapJoined returns the errors joined in err, or err itself.
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// configCombinationProblems returns the settings of cfg that don't work together.
+func configCombinationProblems(cfg *TykPumpConfiguration) []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if len(cfg.Pumps) == 0 {
+		add("No pumps configured")
+	}
+	switch strings.ToLower(cfg.LogLevel) {
+	case "", "info", "error", "warn", "debug":
+	default:
+		add("Invalid log_level %q, must be error, warn, debug or info", cfg.LogLevel)
+	}
+	if cfg.LogFile.Enabled && cfg.LogFile.Path == "" {
+		add("log_file is enabled without a path")
+	}
+	if cfg.AdminAPI.Enabled && cfg.AdminAPI.Secret == "" {
+		add("admin_api is enabled without a secret, it isn't served")
+	}
+	if cfg.Tail.Enabled && cfg.Tail.Secret == "" {
+		add("tail is enabled without a secret, it isn't served")
+	}
+
+	tls := cfg.HealthCheckServer.TLS
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		add("health_check_server.tls needs both cert_file and key_file")
+	}
+	if tls.VerifyClientCert && tls.CAFile == "" {
+		add("health_check_server.tls.verify_client_cert needs a ca_file")
+	}
+	auth := cfg.HealthCheckServer.Auth
+	switch auth.Type {
+	case "":
+	case server.BasicAuth:
+		if auth.Username == "" || auth.Password == "" {
+			add("health_check_server.auth of type basic needs a username and a password")
+		}
+	case server.BearerAuth:
+		if auth.Token == "" {
+			add("health_check_server.auth of type bearer needs a token")
+		}
+	default:
+		add("Invalid health_check_server.auth.type %q, must be basic or bearer", auth.Type)
+	}
+
+	instrumentation := cfg.Instrumentation
+	if instrumentation.StatsD.Enabled && instrumentation.StatsD.Address == "" {
+		add("instrumentation.statsd is enabled without an address")
+	}
+	if instrumentation.DogStatsD.Enabled && instrumentation.DogStatsD.Address == "" {
+		add("instrumentation.dogstatsd is enabled without an address")
+	}
+	if instrumentation.OTLP.Enabled && instrumentation.OTLP.Endpoint == "" {
+		add("instrumentation.otlp is enabled without an endpoint")
+	}
+
+	return problems
+}
+
+// pumpConfigProblems returns the problems of the configuration of the pump called name.
+func pumpConfigProblems(name string, conf PumpConfig) []string {
+	pumpType := conf.Type
+	if pumpType == "" {
+		pumpType = name
+	}
+
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf("Pump %s: "+format, append([]interface{}{name}, args...)...))
+	}
+
+	for _, err := range pumps.ValidateMeta(pumpType, conf.Meta) {
+		add("%v", err)
+	}
+
+	if conf.LogLevel != "" {
+		if _, err := logrus.ParseLevel(conf.LogLevel); err != nil {
+			add("invalid log_level: %v", err)
+		}
+	}
+	if conf.OmitDetailedRecording && (conf.DecodeRawRequest || conf.DecodeRawResponse) {
+		add("raw_request_decoded and raw_response_decoded have no effect with omit_detailed_recording")
+	}
+	if conf.Uptime {
+		if pmp, err := pumps.GetPumpByName(pumpType); err == nil {
+			if _, ok := pmp.(pumps.UptimeDataPump); !ok {
+				add("uptime is enabled but the %s pump doesn't support uptime data", pumpType)
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tcs := []struct {
+		testName string
+		config   string
+		expected []string
+	}{
+		{
+			testName: "valid",
+			config:   `{"purge_delay": 10, "pumps": {"csv": {"type": "csv", "timeout": 5, "meta": {"csv_dir": "./"}}}}`,
+		},
+		{
+			testName: "json log to stdout",
+			config:   `{"instrumentation": {"json_log": {"enabled": true}}, "pumps": {"csv": {"type": "csv", "meta": {"csv_dir": "./"}}}}`,
+		},
+		{
+			testName: "unknown keys",
+			config:   `{"purge_delai": 10, "pumps": {"csv": {"type": "csv", "timeot": 5, "meta": {"csv_dri": "./"}}}, "health_check_server": {"tls": {"cert": "a"}}}`,
+			expected: []string{
+				`Unknown key "health_check_server.tls.cert"`,
+				`Unknown key "pumps.csv.timeot"`,
+				`Unknown key "purge_delai"`,
+				`Pump CSV: unknown key "csv_dri"`,
+			},
+		},
+		{
+			testName: "type mismatch",
+			config:   `{"purge_delay": "10", "pumps": {"csv": {"type": "csv", "meta": {"csv_dir": 1}}}}`,
+			expected: []string{
+				"Invalid value: json: cannot unmarshal string into Go struct field TykPumpConfiguration.purge_delay of type int",
+				"Pump CSV: 'csv_dir' expected type 'string', got unconvertible type 'float64', value: '1'",
+			},
+		},
+		{
+			testName: "unresolved KV reference",
+			config:   `{"pumps": {"csv": {"type": "csv", "meta": {"csv_dir": "kv://vault/pump#dir"}}}}`,
+			expected: []string{"Unresolved KV reference: config contains KV references but no stores are configured"},
+		},
+		{
+			testName: "invalid combinations",
+			config: `{"admin_api": {"enabled": true}, "health_check_server": {"auth": {"type": "basic", "username": "tyk"}},
+				"pumps": {"stdout": {"type": "stdout", "omit_detailed_recording": true, "raw_request_decoded": true, "log_level": "loud"},
+					"csv": {"type": "csv", "uptime": true, "meta": {"csv_dir": "./"}}}}`,
+			expected: []string{
+				"admin_api is enabled without a secret, it isn't served",
+				"health_check_server.auth of type basic needs a username and a password",
+				"Pump CSV: uptime is enabled but the csv pump doesn't support uptime data",
+				`Pump STDOUT: invalid log_level: not a valid logrus Level: "loud"`,
+				"Pump STDOUT: raw_request_decoded and raw_response_decoded have no effect with omit_detailed_recording",
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.testName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pump.conf")
+			assert.NoError(t, os.WriteFile(path, []byte(tc.config), 0o600))
+
+			assert.Equal(t, tc.expected, validateConfig(path))
+		})
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pump.conf")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"pumps": {"csv": {"type": "csv", "meta": {"csv_dri": "./"}}}}`), 0o600))
+
+	out := &bytes.Buffer{}
+	assert.Equal(t, 1, runValidate(path, out))
+	assert.Equal(t, "Pump CSV: unknown key \"csv_dri\"\nFound 1 problem(s) in the configuration.\n", out.String())
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"pumps": {"csv": {"type": "csv", "meta": {"csv_dir": "./"}}}}`), 0o600))
+	out.Reset()
+	assert.Equal(t, 0, runValidate(path, out))
+	assert.Equal(t, "The configuration is valid.\n", out.String())
+
+	out.Reset()
+	assert.Equal(t, 1, runValidate(filepath.Join(t.TempDir(), "missing.conf"), out))
+	assert.Contains(t, out.String(), "Couldn't read the configuration file")
+}